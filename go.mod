@@ -3,6 +3,7 @@ module github.com/ahhsitt/helloagents-go
 go 1.24.0
 
 require (
+	github.com/HugoSmits86/nativewebp v0.9.3
 	github.com/google/uuid v1.6.0
 	github.com/knadh/koanf/providers/env v1.1.0
 	github.com/knadh/koanf/v2 v2.1.1
@@ -21,6 +22,8 @@ require (
 	go.opentelemetry.io/otel/sdk v1.39.0
 	go.opentelemetry.io/otel/sdk/metric v1.39.0
 	go.opentelemetry.io/otel/trace v1.39.0
+	golang.org/x/image v0.33.0
+	golang.org/x/time v0.14.0
 	google.golang.org/grpc v1.77.0
 )
 
@@ -24,6 +24,8 @@ type Output struct {
 	Response string `json:"response"`
 	// Steps 推理步骤轨迹（ReAct 等模式）
 	Steps []ReasoningStep `json:"steps,omitempty"`
+	// ToolCalls 原生工具调用（基于 function calling 的智能体返回、未由智能体执行的调用）
+	ToolCalls []message.ToolCall `json:"tool_calls,omitempty"`
 	// TokenUsage Token 使用统计
 	TokenUsage message.TokenUsage `json:"token_usage"`
 	// Duration 总执行时间
@@ -0,0 +1,218 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+)
+
+// BatchAgent 支持批量执行的智能体（可选接口）
+//
+// 评估器检测到智能体实现该接口时，将样本按批次一次性提交，并把输出按顺序分发回各样本。
+type BatchAgent interface {
+	agents.Agent
+
+	// RunBatch 批量执行，返回的输出须与 inputs 一一对应
+	RunBatch(ctx context.Context, inputs []agents.Input) ([]agents.Output, error)
+}
+
+// InputBuilder 构建样本的智能体输入
+//
+// 返回 false 表示该样本不参与批量执行（如多轮样本），回退到逐个 Run。
+type InputBuilder func(sample Sample) (agents.Input, bool)
+
+// SampleRunner 为样本执行智能体调用
+//
+// 智能体实现 BatchAgent 时按批次调度：批次内首个被评估的样本触发整批 RunBatch，
+// 其余样本等待并取用对应输出；否则逐个调用 Run。批次不归属于任何单个样本：
+// 以 WithBatchContext 设置的上下文为父上下文、WithBatchTimeout 设置的超时执行，
+// 单个样本取消或超时只影响该样本自身的等待。
+type SampleRunner struct {
+	agent        agents.Agent
+	batchCtx     context.Context
+	batchTimeout time.Duration
+
+	mu        sync.Mutex
+	batches   []*sampleBatch
+	positions map[string]batchPosition
+}
+
+// SampleRunnerOption 样本执行器配置选项
+type SampleRunnerOption func(*SampleRunner)
+
+// WithBatchContext 设置批次执行的父上下文（通常为整个评估的 ctx）
+//
+// 未设置时使用触发样本的 ctx 去除取消信号后的上下文，批次仅受 WithBatchTimeout 约束。
+func WithBatchContext(ctx context.Context) SampleRunnerOption {
+	return func(r *SampleRunner) {
+		r.batchCtx = ctx
+	}
+}
+
+// WithBatchTimeout 设置单个批次 RunBatch 的超时时间（0 表示不限制）
+func WithBatchTimeout(timeout time.Duration) SampleRunnerOption {
+	return func(r *SampleRunner) {
+		r.batchTimeout = timeout
+	}
+}
+
+// sampleBatch 一个批次的输入与执行结果
+type sampleBatch struct {
+	agent   BatchAgent
+	inputs  []agents.Input
+	done    chan struct{}
+	outputs []agents.Output
+	err     error
+}
+
+// batchPosition 样本所在批次及批内位置
+type batchPosition struct {
+	batch int
+	index int
+}
+
+// NewSampleRunner 创建逐个调用 Run 的样本执行器
+func NewSampleRunner(agent agents.Agent) *SampleRunner {
+	return &SampleRunner{agent: agent}
+}
+
+// NewBatchSampleRunner 创建按批次调度的样本执行器
+//
+// 参数:
+//   - agent: 被评估的智能体，未实现 BatchAgent 时退化为逐个调用 Run
+//   - dataset: 待评估的数据集（视图），按其顺序每 size 个可批量的样本组成一批
+//   - size: 批次大小，小于等于 1 时不启用批量
+//   - build: 样本输入构建函数，须与逐个执行时使用的输入一致
+//   - opts: 批次执行的上下文与超时（见 WithBatchContext、WithBatchTimeout）
+func NewBatchSampleRunner(agent agents.Agent, dataset Dataset, size int, build InputBuilder, opts ...SampleRunnerOption) *SampleRunner {
+	r := NewSampleRunner(agent)
+	for _, opt := range opts {
+		opt(r)
+	}
+	batchAgent, ok := agent.(BatchAgent)
+	if !ok || size <= 1 {
+		return r
+	}
+
+	r.positions = make(map[string]batchPosition)
+	var current *sampleBatch
+	for i := 0; i < dataset.Len(); i++ {
+		sample, err := dataset.Get(i)
+		if err != nil {
+			continue
+		}
+		if _, dup := r.positions[sample.ID]; dup {
+			continue
+		}
+		input, ok := build(sample)
+		if !ok {
+			continue
+		}
+		if current == nil || len(current.inputs) == size {
+			current = &sampleBatch{agent: batchAgent}
+			r.batches = append(r.batches, current)
+		}
+		r.positions[sample.ID] = batchPosition{batch: len(r.batches) - 1, index: len(current.inputs)}
+		current.inputs = append(current.inputs, input)
+	}
+	return r
+}
+
+// Agent 返回被评估的智能体
+func (r *SampleRunner) Agent() agents.Agent {
+	return r.agent
+}
+
+// Batched 返回是否启用了批量调度
+func (r *SampleRunner) Batched() bool {
+	return len(r.batches) > 0
+}
+
+// Run 执行样本对应的智能体调用
+//
+// 样本属于某个批次时等待批次执行完成并返回对应的输出，否则使用 input 调用 Run。
+// 样本从批次得到错误（整批失败或自身 ctx 结束）后脱离批次，再次执行（如失败重试）
+// 时改为逐个调用 Run。
+func (r *SampleRunner) Run(ctx context.Context, sample Sample, input agents.Input) (agents.Output, error) {
+	pos, ok := r.position(sample.ID)
+	if !ok {
+		return r.agent.Run(ctx, input)
+	}
+
+	batch := r.batches[pos.batch]
+	done := r.startBatch(ctx, batch)
+	select {
+	case <-done:
+	case <-ctx.Done():
+		r.unbatch(sample.ID)
+		return agents.Output{}, ctx.Err()
+	}
+	if batch.err != nil {
+		r.unbatch(sample.ID)
+		return agents.Output{}, batch.err
+	}
+	return batch.outputs[pos.index], nil
+}
+
+// position 返回样本所在的批次位置
+func (r *SampleRunner) position(id string) (batchPosition, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pos, ok := r.positions[id]
+	return pos, ok
+}
+
+// unbatch 使样本脱离批次，之后逐个执行
+func (r *SampleRunner) unbatch(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.positions, id)
+}
+
+// startBatch 启动批次（仅首次调用生效），返回批次完成时关闭的通道
+func (r *SampleRunner) startBatch(ctx context.Context, batch *sampleBatch) <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if batch.done != nil {
+		return batch.done
+	}
+
+	batch.done = make(chan struct{})
+	parent := r.batchCtx
+	if parent == nil {
+		parent = context.WithoutCancel(ctx)
+	}
+	go func() {
+		defer close(batch.done)
+		batchCtx, cancel := parent, context.CancelFunc(func() {})
+		if r.batchTimeout > 0 {
+			batchCtx, cancel = context.WithTimeout(parent, r.batchTimeout)
+		}
+		defer cancel()
+
+		batch.outputs, batch.err = batch.agent.RunBatch(batchCtx, batch.inputs)
+		if batch.err == nil && len(batch.outputs) != len(batch.inputs) {
+			batch.err = fmt.Errorf("批量执行返回 %d 个输出，期望 %d 个", len(batch.outputs), len(batch.inputs))
+		}
+	}()
+	return batch.done
+}
+
+// RunSample 同 Run，智能体增量输出时改为流式执行并记录耗时
+//
+// 智能体实现 StreamingAgent 且样本不属于任何批次时消费 RunStream（见 RunStreaming），
+// 在 result.Details 中记录 streamed、stream_chunks、stream_latency_ms 与
+// time_to_first_token_ms（毫秒）；否则与 Run 相同。
+func (r *SampleRunner) RunSample(ctx context.Context, sample Sample, input agents.Input, result *SampleResult) (agents.Output, error) {
+	if _, batched := r.position(sample.ID); !batched {
+		if streaming, ok := streamingAgent(r.agent); ok {
+			output, timing, err := RunStreaming(ctx, streaming, input)
+			recordStreamTiming(result, timing)
+			return output, err
+		}
+	}
+	return r.Run(ctx, sample, input)
+}
@@ -0,0 +1,115 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/config"
+)
+
+// blockingBatchAgent 批量执行阻塞到 release 关闭的测试智能体
+type blockingBatchAgent struct {
+	release chan struct{}
+
+	mu       sync.Mutex
+	batchCtx context.Context
+	runs     int
+}
+
+func (a *blockingBatchAgent) Name() string               { return "blocking" }
+func (a *blockingBatchAgent) Config() config.AgentConfig { return config.AgentConfig{} }
+
+func (a *blockingBatchAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	a.mu.Lock()
+	a.runs++
+	a.mu.Unlock()
+	return agents.Output{Response: "run: " + input.Query}, nil
+}
+
+func (a *blockingBatchAgent) RunStream(ctx context.Context, input agents.Input) (<-chan agents.StreamChunk, <-chan error) {
+	ch := make(chan agents.StreamChunk)
+	errCh := make(chan error)
+	close(ch)
+	close(errCh)
+	return ch, errCh
+}
+
+func (a *blockingBatchAgent) RunBatch(ctx context.Context, inputs []agents.Input) ([]agents.Output, error) {
+	a.mu.Lock()
+	a.batchCtx = ctx
+	a.mu.Unlock()
+
+	select {
+	case <-a.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	outputs := make([]agents.Output, len(inputs))
+	for i, input := range inputs {
+		outputs[i] = agents.Output{Response: "batch: " + input.Query}
+	}
+	return outputs, nil
+}
+
+func batchQueryInput(sample Sample) (agents.Input, bool) {
+	return agents.Input{Query: sample.Input}, true
+}
+
+func TestSampleRunner_TriggerCancelDoesNotFailBatch(t *testing.T) {
+	dataset := newSliceDataset(2)
+	agent := &blockingBatchAgent{release: make(chan struct{})}
+	runner := NewBatchSampleRunner(agent, dataset, 2, batchQueryInput,
+		WithBatchContext(context.Background()), WithBatchTimeout(time.Minute))
+
+	first, _ := dataset.Get(0)
+	second, _ := dataset.Get(1)
+
+	// 触发批次的样本在批次完成前被取消
+	triggerCtx, cancel := context.WithCancel(context.Background())
+	triggerErr := make(chan error, 1)
+	go func() {
+		_, err := runner.Run(triggerCtx, first, agents.Input{Query: first.Input})
+		triggerErr <- err
+	}()
+	for {
+		agent.mu.Lock()
+		started := agent.batchCtx != nil
+		agent.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-triggerErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("trigger Run() error = %v, want context.Canceled", err)
+	}
+
+	// 批次不归属于触发样本：其余样本仍取得批次输出
+	close(agent.release)
+	output, err := runner.Run(context.Background(), second, agents.Input{Query: second.Input})
+	if err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if output.Response != "batch: "+second.Input {
+		t.Errorf("second Response = %q, want batch output", output.Response)
+	}
+
+	// 批次使用批次级超时
+	if deadline, ok := agent.batchCtx.Deadline(); !ok || time.Until(deadline) < 30*time.Second {
+		t.Errorf("batch ctx deadline = %v (set %v), want batch timeout of 1m", deadline, ok)
+	}
+
+	// 被取消的样本脱离批次，再次执行时逐个调用 Run
+	output, err = runner.Run(context.Background(), first, agents.Input{Query: first.Input})
+	if err != nil || output.Response != "run: "+first.Input {
+		t.Errorf("retried Run() = %q, %v, want per-sample Run", output.Response, err)
+	}
+	if agent.runs != 1 {
+		t.Errorf("per-sample runs = %d, want 1", agent.runs)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
 )
@@ -41,9 +42,12 @@ type Dataset struct {
 	// dataDir BFCL 数据目录
 	dataDir string
 
-	// category 评估类别
+	// category 评估类别（多类别数据集为以 "+" 连接的类别列表）
 	category string
 
+	// categories 多类别数据集包含的类别（单类别数据集为 nil）
+	categories []string
+
 	// samples 加载的样本
 	samples []evaluation.Sample
 
@@ -52,6 +56,12 @@ type Dataset struct {
 
 	// loaded 是否已加载
 	loaded bool
+
+	// source 过滤前的源数据集（仅过滤得到的数据集非 nil）
+	source *Dataset
+
+	// filter 样本过滤条件
+	filter evaluation.SamplePredicate
 }
 
 // NewDataset 创建 BFCL 数据集
@@ -68,17 +78,79 @@ func NewDataset(dataDir, category string) *Dataset {
 	}
 }
 
+// NewMultiCategoryDataset 创建包含多个类别的 BFCL 数据集
+//
+// 依次加载各类别并按给定顺序拼接样本，每个样本的 Category 为其所属类别，
+// 各类别的 ground truth 按样本 ID 合并。不同类别出现重复样本 ID 时 Load 返回错误。
+//
+// 参数:
+//   - dataDir: BFCL 数据目录路径
+//   - categories: 评估类别列表
+func NewMultiCategoryDataset(dataDir string, categories []string) *Dataset {
+	return &Dataset{
+		dataDir:     dataDir,
+		category:    strings.Join(categories, "+"),
+		categories:  append([]string(nil), categories...),
+		samples:     make([]evaluation.Sample, 0),
+		groundTruth: make(map[string]interface{}),
+	}
+}
+
+// Filter 返回仅包含满足 pred 的样本的数据集
+//
+// 过滤后的数据集与源数据集共享 ground truth，pred 接收的样本已附加 ground truth。
+// 源数据集已加载时立即过滤，否则在 Load 时加载源数据集后过滤。
+func (d *Dataset) Filter(pred evaluation.SamplePredicate) *Dataset {
+	filtered := &Dataset{
+		dataDir:     d.dataDir,
+		category:    d.category,
+		samples:     make([]evaluation.Sample, 0),
+		groundTruth: d.groundTruth,
+		source:      d,
+		filter:      pred,
+	}
+	if d.loaded {
+		filtered.applyFilter()
+	}
+	return filtered
+}
+
+// applyFilter 从源数据集中筛选样本
+func (d *Dataset) applyFilter() {
+	d.samples = d.samples[:0]
+	d.groundTruth = d.source.groundTruth
+	for i, sample := range d.source.samples {
+		withTruth, _ := d.source.Get(i)
+		if d.filter(withTruth) {
+			d.samples = append(d.samples, sample)
+		}
+	}
+	d.loaded = true
+}
+
 // Load 加载数据集
 func (d *Dataset) Load(ctx context.Context) error {
 	if d.loaded {
 		return nil
 	}
 
+	if d.source != nil {
+		if err := d.source.Load(ctx); err != nil {
+			return err
+		}
+		d.applyFilter()
+		return nil
+	}
+
 	// 检查数据目录
 	if _, err := os.Stat(d.dataDir); os.IsNotExist(err) {
 		return fmt.Errorf("BFCL 数据目录不存在: %s\n请先克隆 BFCL 仓库：git clone --depth 1 https://github.com/ShishirPatil/gorilla.git temp_gorilla", d.dataDir)
 	}
 
+	if d.categories != nil {
+		return d.loadCategories(ctx)
+	}
+
 	// 加载评估数据
 	dataFile := filepath.Join(d.dataDir, fmt.Sprintf("BFCL_v4_%s.json", d.category))
 	if err := d.loadDataFile(ctx, dataFile); err != nil {
@@ -95,6 +167,34 @@ func (d *Dataset) Load(ctx context.Context) error {
 	return nil
 }
 
+// loadCategories 逐个加载类别并合并样本与 ground truth
+func (d *Dataset) loadCategories(ctx context.Context) error {
+	owner := make(map[string]string)
+	for _, category := range d.categories {
+		part := NewDataset(d.dataDir, category)
+		if err := part.Load(ctx); err != nil {
+			return fmt.Errorf("加载类别 %s 失败: %w", category, err)
+		}
+
+		for _, sample := range part.samples {
+			if prev, ok := owner[sample.ID]; ok {
+				return fmt.Errorf("样本 ID %s 在类别 %s 与 %s 中重复", sample.ID, prev, category)
+			}
+			owner[sample.ID] = category
+			d.samples = append(d.samples, sample)
+		}
+		// 只合并本类别样本的 ground truth，避免其他类别的同名条目覆盖
+		for id, gt := range part.groundTruth {
+			if owner[id] == category {
+				d.groundTruth[id] = gt
+			}
+		}
+	}
+
+	d.loaded = true
+	return nil
+}
+
 // loadDataFile 加载数据文件
 func (d *Dataset) loadDataFile(ctx context.Context, filePath string) error {
 	file, err := os.Open(filePath)
@@ -156,6 +256,15 @@ func (d *Dataset) parseItem(item map[string]interface{}, idx int) evaluation.Sam
 				}
 			}
 		}
+
+		// 多轮格式：每个元素为一轮的消息列表
+		if len(question) > 1 {
+			turns := make([]string, 0, len(question))
+			for _, turn := range question {
+				turns = append(turns, turnUserContent(turn))
+			}
+			sample.Metadata["turns"] = turns
+		}
 	}
 
 	// 提取工具定义
@@ -177,6 +286,29 @@ func (d *Dataset) parseItem(item map[string]interface{}, idx int) evaluation.Sam
 	return sample
 }
 
+// turnUserContent 提取单轮中所有用户消息内容
+func turnUserContent(turn interface{}) string {
+	msgs, ok := turn.([]interface{})
+	if !ok {
+		return ""
+	}
+
+	var parts []string
+	for _, m := range msgs {
+		msg, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if role := getString(msg, "role"); role != "" && role != "user" {
+			continue
+		}
+		if content := getString(msg, "content"); content != "" {
+			parts = append(parts, content)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
 // loadGroundTruth 加载 ground truth
 func (d *Dataset) loadGroundTruth(ctx context.Context, filePath string) error {
 	file, err := os.Open(filePath)
@@ -0,0 +1,160 @@
+package bfcl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+func TestDataset_Filter_KeepsGroundTruth(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"id": "simple_0", "question": "weather", "function": [{"name": "get_weather"}]}
+{"id": "simple_1", "question": "flight", "function": [{"name": "book_flight"}]}
+`
+	truth := `{"id": "simple_0", "ground_truth": [{"get_weather": {"city": ["Paris"]}}]}
+{"id": "simple_1", "ground_truth": [{"book_flight": {"to": ["Rome"]}}]}
+`
+	if err := os.WriteFile(filepath.Join(dir, "BFCL_v4_simple.json"), []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write data: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "possible_answer"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "possible_answer", "BFCL_v4_simple.json"), []byte(truth), 0644); err != nil {
+		t.Fatalf("failed to write ground truth: %v", err)
+	}
+
+	// 在源数据集加载前过滤，Load 时延迟筛选
+	filtered := NewDataset(dir, "simple").Filter(func(s evaluation.Sample) bool {
+		return len(s.Tools) > 0 && s.Tools[0].Name == "book_flight"
+	})
+	if err := filtered.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if filtered.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", filtered.Len())
+	}
+	sample, err := filtered.Get(0)
+	if err != nil {
+		t.Fatalf("Get(0) error = %v", err)
+	}
+	if sample.ID != "simple_1" || sample.Expected == nil {
+		t.Errorf("Get(0) = %s with Expected %v, want simple_1 with ground truth", sample.ID, sample.Expected)
+	}
+	if _, ok := filtered.GetGroundTruth("simple_1"); !ok {
+		t.Error("expected ground truth lookup to survive filtering")
+	}
+}
+
+func TestDataset_Split_KeepsGroundTruth(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"id": "simple_0", "question": "weather", "function": [{"name": "get_weather"}]}
+{"id": "simple_1", "question": "flight", "function": [{"name": "book_flight"}]}
+`
+	truth := `{"id": "simple_0", "ground_truth": [{"get_weather": {"city": ["Paris"]}}]}
+{"id": "simple_1", "ground_truth": [{"book_flight": {"to": ["Rome"]}}]}
+`
+	if err := os.WriteFile(filepath.Join(dir, "BFCL_v4_simple.json"), []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write data: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "possible_answer"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "possible_answer", "BFCL_v4_simple.json"), []byte(truth), 0644); err != nil {
+		t.Fatalf("failed to write ground truth: %v", err)
+	}
+
+	dataset := NewDataset(dir, "simple")
+	if err := dataset.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	train, dev, err := evaluation.SplitDataset(dataset, 0.5, 1)
+	if err != nil {
+		t.Fatalf("SplitDataset() error = %v", err)
+	}
+	for _, split := range []evaluation.Dataset{train, dev} {
+		sample, err := split.Get(0)
+		if err != nil {
+			t.Fatalf("Get(0) error = %v", err)
+		}
+		if sample.Expected == nil {
+			t.Errorf("%s: expected ground truth on %s", split.Name(), sample.ID)
+		}
+		source, ok := split.(GroundTruthSource)
+		if !ok {
+			t.Fatalf("%s: split should expose GetGroundTruth", split.Name())
+		}
+		if _, ok := source.GetGroundTruth(sample.ID); !ok {
+			t.Errorf("%s: expected ground truth lookup for %s", split.Name(), sample.ID)
+		}
+	}
+}
+
+func TestNewMultiCategoryDataset(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"BFCL_v4_simple.json": `{"id": "simple_0", "question": "weather", "function": [{"name": "get_weather"}]}
+{"id": "simple_1", "question": "flight", "function": [{"name": "book_flight"}]}
+`,
+		"BFCL_v4_multiple.json": `{"id": "multiple_0", "question": "stock", "function": [{"name": "get_stock"}, {"name": "get_news"}]}
+`,
+		filepath.Join("possible_answer", "BFCL_v4_simple.json"): `{"id": "simple_0", "ground_truth": [{"get_weather": {"city": ["Paris"]}}]}
+{"id": "simple_1", "ground_truth": [{"book_flight": {"to": ["Rome"]}}]}
+`,
+		filepath.Join("possible_answer", "BFCL_v4_multiple.json"): `{"id": "multiple_0", "ground_truth": [{"get_stock": {"symbol": ["AAPL"]}}]}
+`,
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "possible_answer"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	dataset := NewMultiCategoryDataset(dir, []string{"simple", "multiple"})
+	if err := dataset.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if dataset.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", dataset.Len())
+	}
+
+	want := []struct {
+		id, category, tool string
+	}{
+		{"simple_0", "simple", "get_weather"},
+		{"simple_1", "simple", "book_flight"},
+		{"multiple_0", "multiple", "get_stock"},
+	}
+	for i, w := range want {
+		sample, err := dataset.Get(i)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", i, err)
+		}
+		if sample.ID != w.id || sample.Category != w.category {
+			t.Errorf("Get(%d) = %s/%s, want %s/%s", i, sample.Category, sample.ID, w.category, w.id)
+		}
+		calls, ok := sample.Expected.([]interface{})
+		if !ok || len(calls) != 1 {
+			t.Fatalf("Get(%d).Expected = %v, want one call", i, sample.Expected)
+		}
+		if _, ok := calls[0].(map[string]interface{})[w.tool]; !ok {
+			t.Errorf("Get(%d).Expected = %v, want call to %s", i, calls[0], w.tool)
+		}
+	}
+	if dataset.Category() != "simple+multiple" {
+		t.Errorf("Category() = %s, want simple+multiple", dataset.Category())
+	}
+
+	duplicate := NewMultiCategoryDataset(dir, []string{"simple", "simple"})
+	if err := duplicate.Load(context.Background()); err == nil {
+		t.Error("expected error for duplicate sample IDs across categories")
+	}
+}
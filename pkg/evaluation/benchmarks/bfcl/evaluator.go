@@ -4,11 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/message"
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
 )
 
@@ -29,6 +34,50 @@ type Evaluator struct {
 
 	// mode 评估模式
 	mode EvaluationMode
+
+	// unorderedLists 列表参数是否忽略顺序比较
+	unorderedLists bool
+
+	// normalizer 比较前的参数值规范化函数
+	normalizer ValueNormalizer
+
+	// toolPrompt 工具提示词构建器
+	toolPrompt ToolPromptBuilder
+}
+
+// acceptableValues ground truth 中参数的多个可接受值
+//
+// BFCL v4 以数组列出参数的全部可接受值（列表参数本身写作嵌套数组），
+// 预测值等于其中任一值即视为匹配。
+type acceptableValues []interface{}
+
+// EvaluatorOption 评估器配置选项
+type EvaluatorOption func(*Evaluator)
+
+// ValueNormalizer 参数值规范化函数
+//
+// 接收参数名与参数值，返回用于比较的规范化值（如将 "C" 与 "celsius" 统一为同一单位）。
+type ValueNormalizer func(paramName string, v interface{}) interface{}
+
+// WithUnorderedLists 设置列表参数是否忽略顺序比较
+//
+// 开启后列表参数按多重集合比较（元素相同即可，不要求顺序一致），
+// 默认按位置逐个比较。
+func WithUnorderedLists(enabled bool) EvaluatorOption {
+	return func(e *Evaluator) {
+		e.unorderedLists = enabled
+	}
+}
+
+// WithValueNormalizer 设置比较前的参数值规范化函数
+//
+// 比较函数调用时，预测值与期望值都先经 normalizer 处理再比较，用于表达
+// "Beijing" 与 "beijing, china"、"C" 与 "celsius" 等领域内的等价关系。
+// 只作用于顶层参数，嵌套值由 normalizer 自行处理。
+func WithValueNormalizer(normalizer ValueNormalizer) EvaluatorOption {
+	return func(e *Evaluator) {
+		e.normalizer = normalizer
+	}
 }
 
 // NewEvaluator 创建 BFCL 评估器
@@ -36,14 +85,19 @@ type Evaluator struct {
 // 参数:
 //   - dataset: BFCL 数据集
 //   - mode: 评估模式（ast 或 execution）
-func NewEvaluator(dataset *Dataset, mode EvaluationMode) *Evaluator {
+//   - opts: 评估器配置选项
+func NewEvaluator(dataset *Dataset, mode EvaluationMode, opts ...EvaluatorOption) *Evaluator {
 	if mode == "" {
 		mode = ModeAST
 	}
-	return &Evaluator{
+	e := &Evaluator{
 		dataset: dataset,
 		mode:    mode,
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 // Name 返回评估器名称
@@ -52,6 +106,9 @@ func (e *Evaluator) Name() string {
 }
 
 // Evaluate 执行完整评估
+//
+// ctx 取消或 FailFast 提前终止时，返回已完成样本的结果及对应错误：TotalSamples、
+// 准确率和各项指标均只统计已完成的样本。
 func (e *Evaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...evaluation.EvalOption) (*evaluation.EvalResult, error) {
 	config := evaluation.DefaultEvalConfig()
 	config.ApplyOptions(opts...)
@@ -68,54 +125,36 @@ func (e *Evaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...ev
 		DetailedResults: make([]*evaluation.SampleResult, 0),
 		CategoryMetrics: make(map[string]*evaluation.CategoryMetrics),
 		EvaluationTime:  startTime,
+		RunConfig:       config.Summary(),
 	}
 
-	total := e.dataset.Len()
-	if config.MaxSamples > 0 && config.MaxSamples < total {
-		total = config.MaxSamples
-	}
+	// 按配置打乱、分层抽样并截断样本
+	dataset := evaluation.SelectSamples(config, e.dataset)
+	total := dataset.Len()
 	result.TotalSamples = total
 
-	// 遍历样本进行评估
-	for i := 0; i < total; i++ {
-		select {
-		case <-ctx.Done():
-			return result, ctx.Err()
-		default:
-		}
-
-		sample, err := e.dataset.Get(i)
-		if err != nil {
-			continue
-		}
-
-		// 应用超时
-		evalCtx := ctx
-		if config.Timeout > 0 {
-			var cancel context.CancelFunc
-			evalCtx, cancel = context.WithTimeout(ctx, config.Timeout)
-			defer cancel()
-		}
+	// 智能体支持批量执行时按批次调度（多轮样本逐个执行）
+	runner := evaluation.NewBatchSampleRunner(agent, dataset, config.BatchSize, e.batchInput,
+		evaluation.WithBatchContext(ctx), evaluation.WithBatchTimeout(config.Timeout))
+	if runner.Batched() {
+		result.RunConfig["batch_size"] = config.BatchSize
+	}
 
-		sampleResult, err := e.EvaluateSample(evalCtx, agent, sample)
-		if err != nil {
-			sampleResult = &evaluation.SampleResult{
-				SampleID: sample.ID,
-				Category: sample.Category,
-				Error:    err.Error(),
-				Success:  false,
-			}
-		}
+	// 并发评估样本（结果保持样本顺序）
+	sampleResults, runErr := evaluation.RunSamples(ctx, config, dataset, total,
+		func(ctx context.Context, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+			return e.evaluateSample(ctx, config, runner, sample)
+		})
 
-		result.DetailedResults = append(result.DetailedResults, sampleResult)
-		if sampleResult.Success {
+	result.DetailedResults = append(result.DetailedResults, sampleResults...)
+	for _, r := range sampleResults {
+		if r.Success {
 			result.SuccessCount++
 		}
-
-		// 进度回调
-		if config.ProgressCallback != nil {
-			config.ProgressCallback(i+1, total)
-		}
+	}
+	// 提前终止（ctx 取消或 FailFast）时只统计已完成的样本
+	if runErr != nil {
+		result.TotalSamples = len(sampleResults)
 	}
 
 	result.TotalDuration = time.Since(startTime)
@@ -129,12 +168,22 @@ func (e *Evaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...ev
 	// 计算汇总指标
 	metrics := NewMetrics()
 	result.Metrics = metrics.Compute(result.DetailedResults)
+	result.Metrics.Extra["weighted_accuracy"] = metrics.WeightedAccuracy(result.CategoryMetrics, config.CategoryWeights)
 
-	return result, nil
+	return result, runErr
 }
 
 // EvaluateSample 评估单个样本
+//
+// 智能体执行失败记录在结果的 Error 字段中，不作为错误返回。
 func (e *Evaluator) EvaluateSample(ctx context.Context, agent agents.Agent, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+	result, _ := e.evaluateSample(ctx, evaluation.DefaultEvalConfig(), evaluation.NewSampleRunner(agent), sample)
+	return result, nil
+}
+
+// evaluateSample 评估单个样本，返回结果及智能体执行错误（用于 FailFast）
+func (e *Evaluator) evaluateSample(ctx context.Context, config *evaluation.EvalConfig, runner *evaluation.SampleRunner,
+	sample evaluation.Sample) (*evaluation.SampleResult, error) {
 	startTime := time.Now()
 
 	result := &evaluation.SampleResult{
@@ -144,38 +193,116 @@ func (e *Evaluator) EvaluateSample(ctx context.Context, agent agents.Agent, samp
 		Details:  make(map[string]interface{}),
 	}
 
+	// 多轮样本逐轮回放
+	if turns := sampleTurns(sample); len(turns) > 1 {
+		return e.evaluateMultiTurn(ctx, config, runner.Agent(), sample, turns, result, startTime)
+	}
+
 	// 构建输入（包含工具定义）
 	input := e.buildAgentInput(sample)
 
 	// 调用智能体
-	output, err := agent.Run(ctx, input)
+	output, err := runner.RunSample(ctx, sample, input, result)
 	if err != nil {
 		result.Error = err.Error()
 		result.ExecutionTime = time.Since(startTime)
-		return result, nil
+		return result, err
 	}
 
-	result.AgentResponse = output.Response
+	result.AgentResponse = config.TruncateResponse(result, output.Response)
 	result.ExecutionTime = time.Since(startTime)
+	result.AddTokenUsage(evaluation.AgentTokenUsage(runner.Agent(), output))
 
-	// 从响应中提取函数调用
-	predictedCalls, err := e.extractFunctionCalls(output.Response)
+	// 提取函数调用（优先使用原生工具调用）
+	predictedCalls, err := e.outputFunctionCalls(output, result.AgentResponse, result)
 	if err != nil {
 		result.Error = fmt.Sprintf("提取函数调用失败: %v", err)
 		result.Details["extraction_error"] = err.Error()
 		return result, nil
 	}
-	result.Predicted = predictedCalls
 
-	// 获取 ground truth
-	groundTruth, ok := e.dataset.GetGroundTruth(sample.ID)
-	if !ok {
-		result.Error = "未找到 ground truth"
+	return e.scoreSample(sample, predictedCalls, result)
+}
+
+// evaluateMultiTurn 评估多轮样本
+//
+// 依次发送每一轮用户消息，并将之前各轮的对话作为历史上下文传入，
+// 汇总所有轮次的函数调用后与 ground truth 进行匹配。
+func (e *Evaluator) evaluateMultiTurn(ctx context.Context, config *evaluation.EvalConfig, agent agents.Agent, sample evaluation.Sample, turns []string,
+	result *evaluation.SampleResult, startTime time.Time) (*evaluation.SampleResult, error) {
+	var history []message.Message
+	var allCalls []evaluation.FunctionCall
+	responses := make([]string, 0, len(turns))
+	turnErrors := make(map[int]string)
+
+	for i, query := range turns {
+		input := e.buildAgentInput(sample)
+		input.Query = query
+		input.SessionID = sample.ID
+		input.Context["turn"] = i
+		input.Context["history"] = append([]message.Message(nil), history...)
+
+		output, err := agent.Run(ctx, input)
+		if err != nil {
+			result.Error = fmt.Sprintf("第 %d 轮执行失败: %v", i+1, err)
+			result.ExecutionTime = time.Since(startTime)
+			return result, err
+		}
+		response := config.TruncateResponse(result, output.Response)
+		responses = append(responses, response)
+		result.AddTokenUsage(evaluation.AgentTokenUsage(agent, output))
+
+		calls, err := e.outputFunctionCalls(output, response, result)
+		if err != nil {
+			turnErrors[i] = err.Error()
+		} else {
+			allCalls = append(allCalls, calls...)
+		}
+
+		reply := message.NewAssistantMessage(output.Response)
+		reply.ToolCalls = output.ToolCalls
+		history = append(history, message.NewUserMessage(query), reply)
+	}
+
+	result.AgentResponse = strings.Join(responses, "\n")
+	result.ExecutionTime = time.Since(startTime)
+	result.Details["turn_count"] = len(turns)
+	result.Details["turn_responses"] = responses
+	if len(turnErrors) > 0 {
+		result.Details["turn_extraction_errors"] = turnErrors
+	}
+
+	if len(allCalls) == 0 {
+		result.Error = "提取函数调用失败: 所有轮次均未返回函数调用"
 		return result, nil
 	}
 
+	return e.scoreSample(sample, allCalls, result)
+}
+
+// scoreSample 将预测的函数调用与 ground truth 匹配并填充结果
+func (e *Evaluator) scoreSample(sample evaluation.Sample, predictedCalls []evaluation.FunctionCall,
+	result *evaluation.SampleResult) (*evaluation.SampleResult, error) {
+	result.Predicted = predictedCalls
+
+	// 记录未在样本工具列表中声明的函数（幻觉调用）
+	if hallucinated := hallucinatedFunctions(sample, predictedCalls); len(hallucinated) > 0 {
+		result.Details["hallucinated_functions"] = hallucinated
+	}
+
+	// 获取 ground truth（优先使用样本上的期望值，以支持覆盖）
+	groundTruth := sample.Expected
+	if groundTruth == nil {
+		gt, ok := e.dataset.GetGroundTruth(sample.ID)
+		if !ok {
+			result.Error = "未找到 ground truth"
+			return result, nil
+		}
+		groundTruth = gt
+	}
+
 	// 评估匹配
-	success, score, details := e.evaluateMatch(predictedCalls, groundTruth)
+	success, score, details := e.evaluateMatch(predictedCalls, groundTruth, sample.Tools)
 	result.Success = success
 	result.Score = score
 	for k, v := range details {
@@ -185,34 +312,106 @@ func (e *Evaluator) EvaluateSample(ctx context.Context, agent agents.Agent, samp
 	return result, nil
 }
 
-// buildAgentInput 构建智能体输入
-func (e *Evaluator) buildAgentInput(sample evaluation.Sample) agents.Input {
-	// 构建工具描述
-	var toolsDesc strings.Builder
-	toolsDesc.WriteString("你有以下工具可以使用:\n\n")
-
+// hallucinatedFunctions 返回预测调用中未在 sample.Tools 声明的函数名
+//
+// 样本未声明任何工具时无法判断，返回 nil。
+func hallucinatedFunctions(sample evaluation.Sample, calls []evaluation.FunctionCall) []string {
+	if len(sample.Tools) == 0 {
+		return nil
+	}
+	declared := make(map[string]bool, len(sample.Tools))
 	for _, tool := range sample.Tools {
-		toolsDesc.WriteString(fmt.Sprintf("### %s\n", tool.Name))
-		toolsDesc.WriteString(fmt.Sprintf("描述: %s\n", tool.Description))
-		if len(tool.Parameters) > 0 {
-			paramsJSON, _ := json.MarshalIndent(tool.Parameters, "", "  ")
-			toolsDesc.WriteString(fmt.Sprintf("参数: %s\n", string(paramsJSON)))
+		declared[tool.Name] = true
+	}
+	var names []string
+	for _, call := range calls {
+		if !declared[call.Name] {
+			names = append(names, call.Name)
 		}
-		toolsDesc.WriteString("\n")
 	}
+	return names
+}
 
-	toolsDesc.WriteString("\n请根据用户问题调用合适的函数。返回格式为 JSON 数组:\n")
-	toolsDesc.WriteString(`[{"name": "函数名", "arguments": {"参数名": "参数值"}}]`)
+// sampleTurns 返回多轮样本的各轮用户消息
+func sampleTurns(sample evaluation.Sample) []string {
+	if sample.Metadata == nil {
+		return nil
+	}
+	switch v := sample.Metadata["turns"].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		turns := make([]string, 0, len(v))
+		for _, t := range v {
+			s, _ := t.(string)
+			turns = append(turns, s)
+		}
+		return turns
+	}
+	return nil
+}
+
+// batchInput 构建可批量执行的样本输入（多轮样本不参与批量）
+func (e *Evaluator) batchInput(sample evaluation.Sample) (agents.Input, bool) {
+	if len(sampleTurns(sample)) > 1 {
+		return agents.Input{}, false
+	}
+	return e.buildAgentInput(sample), true
+}
+
+// buildAgentInput 构建智能体输入
+//
+// 工具提示词由 ToolPromptBuilder 构建（默认 EnglishToolPrompt）。
+func (e *Evaluator) buildAgentInput(sample evaluation.Sample) agents.Input {
+	builder := e.toolPrompt
+	if builder == nil {
+		builder = EnglishToolPrompt
+	}
 
 	return agents.Input{
 		Query: sample.Input,
 		Context: map[string]interface{}{
 			"tools":        sample.Tools,
-			"tools_prompt": toolsDesc.String(),
+			"tools_prompt": builder.BuildToolPrompt(sample.Tools),
 		},
 	}
 }
 
+// outputFunctionCalls 获取智能体输出中的函数调用
+//
+// 智能体返回原生工具调用（Output.ToolCalls）时直接转换，不再解析响应文本；
+// 否则回退到 extractFunctionCalls。调用来源记录在 Details["call_source"]
+// （tool_calls 或 text，多轮样本中任一轮使用原生调用即记为 tool_calls）。
+func (e *Evaluator) outputFunctionCalls(output agents.Output, response string, result *evaluation.SampleResult) ([]evaluation.FunctionCall, error) {
+	if len(output.ToolCalls) > 0 {
+		result.Details["call_source"] = callSourceToolCalls
+		return toolCallsToFunctionCalls(output.ToolCalls), nil
+	}
+	if result.Details["call_source"] == nil {
+		result.Details["call_source"] = callSourceText
+	}
+	return e.extractFunctionCalls(response)
+}
+
+// 函数调用来源
+const (
+	callSourceToolCalls = "tool_calls"
+	callSourceText      = "text"
+)
+
+// toolCallsToFunctionCalls 将原生工具调用转换为函数调用
+func toolCallsToFunctionCalls(toolCalls []message.ToolCall) []evaluation.FunctionCall {
+	calls := make([]evaluation.FunctionCall, len(toolCalls))
+	for i, tc := range toolCalls {
+		args := tc.Arguments
+		if args == nil {
+			args = make(map[string]interface{})
+		}
+		calls[i] = evaluation.FunctionCall{Name: tc.Name, Arguments: args}
+	}
+	return calls
+}
+
 // extractFunctionCalls 从响应中提取函数调用
 func (e *Evaluator) extractFunctionCalls(response string) ([]evaluation.FunctionCall, error) {
 	response = strings.TrimSpace(response)
@@ -259,11 +458,146 @@ func (e *Evaluator) extractFunctionCalls(response string) ([]evaluation.Function
 		}
 	}
 
+	// 宽松解析：修复尾随逗号、单引号、注释等常见问题后重试（仅在严格解析全部失败时进行）
+	candidates := []string{response}
+	for _, match := range codeMatches {
+		if len(match) > 1 {
+			candidates = append(candidates, strings.TrimSpace(match[1]))
+		}
+	}
+	candidates = append(candidates, bracketSpans(response)...)
+	for _, candidate := range candidates {
+		if repaired, ok := parseFunctionCalls(repairJSON(candidate)); ok {
+			return repaired, nil
+		}
+	}
+
 	return nil, fmt.Errorf("无法从响应中提取函数调用")
 }
 
+// parseFunctionCalls 将文本解析为函数调用数组或单个函数调用对象
+func parseFunctionCalls(text string) ([]evaluation.FunctionCall, bool) {
+	var calls []evaluation.FunctionCall
+	if err := json.Unmarshal([]byte(text), &calls); err == nil && len(calls) > 0 {
+		return calls, true
+	}
+	var single evaluation.FunctionCall
+	if err := json.Unmarshal([]byte(text), &single); err == nil && single.Name != "" {
+		return []evaluation.FunctionCall{single}, true
+	}
+	return nil, false
+}
+
+// bracketSpans 返回响应中从首个 '[' 到最后一个 ']'、从首个 '{' 到最后一个 '}' 的片段
+func bracketSpans(response string) []string {
+	var spans []string
+	for _, pair := range [][2]string{{"[", "]"}, {"{", "}"}} {
+		start := strings.Index(response, pair[0])
+		end := strings.LastIndex(response, pair[1])
+		if start >= 0 && end > start {
+			spans = append(spans, response[start:end+1])
+		}
+	}
+	return spans
+}
+
+// repairJSON 修复 LLM 常见的非标准 JSON
+//
+// 仅处理字符串之外的内容：移除 // 和 /* */ 注释、将单引号字符串转换为双引号字符串、
+// 移除 ']' 或 '}' 前的尾随逗号。双引号字符串内容保持不变。
+func repairJSON(text string) string {
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	// 第一遍：移除注释、转换单引号字符串
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"':
+			end := scanString(text, i, '"')
+			sb.WriteString(text[i:end])
+			i = end - 1
+		case c == '\'':
+			end := scanString(text, i, '\'')
+			sb.WriteByte('"')
+			for j := i + 1; j < end-1; j++ {
+				switch {
+				case text[j] == '\\' && j+1 < end-1 && text[j+1] == '\'':
+					sb.WriteByte('\'')
+					j++
+				case text[j] == '\\' && j+1 < end-1:
+					sb.WriteString(text[j : j+2])
+					j++
+				case text[j] == '"':
+					sb.WriteString(`\"`)
+				default:
+					sb.WriteByte(text[j])
+				}
+			}
+			sb.WriteByte('"')
+			i = end - 1
+		case c == '/' && i+1 < len(text) && text[i+1] == '/':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			if i < len(text) {
+				sb.WriteByte('\n')
+			}
+		case c == '/' && i+1 < len(text) && text[i+1] == '*':
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				i = len(text)
+			} else {
+				i += end + 3
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	// 第二遍：移除尾随逗号
+	cleaned := sb.String()
+	sb.Reset()
+	for i := 0; i < len(cleaned); i++ {
+		c := cleaned[i]
+		if c == '"' {
+			end := scanString(cleaned, i, '"')
+			sb.WriteString(cleaned[i:end])
+			i = end - 1
+			continue
+		}
+		if c == ',' {
+			j := i + 1
+			for j < len(cleaned) && unicode.IsSpace(rune(cleaned[j])) {
+				j++
+			}
+			if j < len(cleaned) && (cleaned[j] == ']' || cleaned[j] == '}') {
+				continue
+			}
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// scanString 返回从 start 处引号开始的字符串结束位置（结束引号之后），未闭合时返回文本长度
+func scanString(text string, start int, quote byte) int {
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
 // evaluateMatch 评估函数调用匹配
-func (e *Evaluator) evaluateMatch(predicted []evaluation.FunctionCall, groundTruth interface{}) (bool, float64, map[string]interface{}) {
+//
+// tools 为样本声明的工具，用于区分必填与可选参数（见 optionalParams），可为 nil。
+func (e *Evaluator) evaluateMatch(predicted []evaluation.FunctionCall, groundTruth interface{},
+	tools []evaluation.ToolDefinition) (bool, float64, map[string]interface{}) {
 	details := make(map[string]interface{})
 
 	// 解析 ground truth
@@ -286,22 +620,26 @@ func (e *Evaluator) evaluateMatch(predicted []evaluation.FunctionCall, groundTru
 		return false, 0, details
 	}
 
-	// 计算匹配分数
+	// 计算匹配分数：预期调用与预测调用一一配对（与顺序无关），每个预测调用至多匹配一个预期调用
+	optional := optionalParams(tools)
+	scores := make([][]float64, len(expectedCalls))
+	for i, expected := range expectedCalls {
+		scores[i] = make([]float64, len(predicted))
+		for j, pred := range predicted {
+			scores[i][j] = e.compareFunctionCall(pred, expected, optional[expected.Name])
+		}
+	}
+
 	matchedCount := 0
 	totalScore := 0.0
-
-	for _, expected := range expectedCalls {
-		bestScore := 0.0
-		for _, pred := range predicted {
-			score := e.compareFunctionCall(pred, expected)
-			if score > bestScore {
-				bestScore = score
-			}
+	for i, j := range assignCalls(scores) {
+		if j < 0 {
+			continue
 		}
-		if bestScore >= 1.0 {
+		if scores[i][j] >= 1.0 {
 			matchedCount++
 		}
-		totalScore += bestScore
+		totalScore += scores[i][j]
 	}
 
 	avgScore := totalScore / float64(len(expectedCalls))
@@ -314,6 +652,87 @@ func (e *Evaluator) evaluateMatch(predicted []evaluation.FunctionCall, groundTru
 	return success, avgScore, details
 }
 
+// assignCalls 求预期调用与预测调用之间总分最高的一一配对（匈牙利算法）
+//
+// scores[i][j] 为第 i 个预期调用与第 j 个预测调用的匹配分数。
+// 返回每个预期调用配对的预测调用下标，未配对时为 -1。
+func assignCalls(scores [][]float64) []int {
+	n := len(scores)
+	m := 0
+	if n > 0 {
+		m = len(scores[0])
+	}
+	size := max(n, m)
+
+	// 补齐为方阵，按最小化代价（负分数）求解，下标从 1 开始
+	cost := func(i, j int) float64 {
+		if i <= n && j <= m {
+			return -scores[i-1][j-1]
+		}
+		return 0
+	}
+
+	inf := math.Inf(1)
+	u := make([]float64, size+1)
+	v := make([]float64, size+1)
+	p := make([]int, size+1)
+	way := make([]int, size+1)
+	for i := 1; i <= size; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, size+1)
+		for j := range minv {
+			minv[j] = inf
+		}
+		used := make([]bool, size+1)
+		for {
+			used[j0] = true
+			i0, delta, j1 := p[j0], inf, 0
+			for j := 1; j <= size; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost(i0, j) - u[i0] - v[j]; cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= size; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	assignment := make([]int, n)
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	for j := 1; j <= m; j++ {
+		if p[j] >= 1 && p[j] <= n {
+			assignment[p[j]-1] = j - 1
+		}
+	}
+	return assignment
+}
+
 // parseGroundTruth 解析 ground truth
 func (e *Evaluator) parseGroundTruth(gt interface{}) ([]evaluation.FunctionCall, error) {
 	var calls []evaluation.FunctionCall
@@ -386,8 +805,7 @@ func (e *Evaluator) parseGroundTruthItem(item interface{}) ([]evaluation.Functio
 					// 参数值可能是数组（多个可接受值）
 					for paramName, paramVal := range paramsMap {
 						if valArray, ok := paramVal.([]interface{}); ok && len(valArray) > 0 {
-							// 取第一个可接受值
-							call.Arguments[paramName] = valArray[0]
+							call.Arguments[paramName] = acceptableValues(valArray)
 						} else {
 							call.Arguments[paramName] = paramVal
 						}
@@ -449,31 +867,185 @@ func (e *Evaluator) parsePythonFunctionCall(s string) (evaluation.FunctionCall,
 }
 
 // compareFunctionCall 比较两个函数调用
-func (e *Evaluator) compareFunctionCall(predicted, expected evaluation.FunctionCall) float64 {
+//
+// 分数为匹配的参数数占计分参数数的比例。optional 中的参数仅在预测调用给出时计分
+// （给出且正确则计入匹配），省略时不扣分；其余期望参数均视为必填。
+func (e *Evaluator) compareFunctionCall(predicted, expected evaluation.FunctionCall, optional map[string]bool) float64 {
 	// 函数名必须匹配
 	if predicted.Name != expected.Name {
 		return 0
 	}
 
-	if len(expected.Arguments) == 0 {
+	// 比较参数
+	scoredParams, matchedParams := 0, 0
+	for paramName, expectedVal := range expected.Arguments {
+		predictedVal, ok := predicted.Arguments[paramName]
+		if !ok {
+			if !optional[paramName] {
+				scoredParams++
+			}
+			continue
+		}
+		scoredParams++
+		if e.normalizer != nil {
+			predictedVal = e.normalizer(paramName, predictedVal)
+			expectedVal = e.normalizeExpected(paramName, expectedVal)
+		}
+		if e.compareValues(predictedVal, expectedVal) {
+			matchedParams++
+		}
+	}
+
+	if scoredParams == 0 {
 		return 1.0
 	}
+	return float64(matchedParams) / float64(scoredParams)
+}
 
-	// 比较参数
-	matchedParams := 0
-	for paramName, expectedVal := range expected.Arguments {
-		if predictedVal, ok := predicted.Arguments[paramName]; ok {
-			if e.compareValues(predictedVal, expectedVal) {
-				matchedParams++
+// optionalParams 从工具参数 Schema 中提取各函数的可选参数
+//
+// 在 properties 中声明但不在 required 中的参数为可选参数。Schema 未给出 required
+// 时无法区分，该函数的参数全部按必填处理。
+func optionalParams(tools []evaluation.ToolDefinition) map[string]map[string]bool {
+	var result map[string]map[string]bool
+	for _, tool := range tools {
+		properties, _ := tool.Parameters["properties"].(map[string]interface{})
+		requiredList, ok := toSlice(tool.Parameters["required"])
+		if len(properties) == 0 || !ok {
+			continue
+		}
+
+		required := make(map[string]bool, len(requiredList))
+		for _, name := range requiredList {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+		optional := make(map[string]bool)
+		for name := range properties {
+			if !required[name] {
+				optional[name] = true
 			}
 		}
+		if len(optional) == 0 {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]map[string]bool)
+		}
+		result[tool.Name] = optional
 	}
+	return result
+}
 
-	return float64(matchedParams) / float64(len(expected.Arguments))
+// normalizeExpected 规范化期望值，多个可接受值逐个规范化
+func (e *Evaluator) normalizeExpected(paramName string, v interface{}) interface{} {
+	alternatives, ok := v.(acceptableValues)
+	if !ok {
+		return e.normalizer(paramName, v)
+	}
+	normalized := make(acceptableValues, len(alternatives))
+	for i, alt := range alternatives {
+		normalized[i] = e.normalizer(paramName, alt)
+	}
+	return normalized
 }
 
 // compareValues 比较两个值是否相等
+//
+// b 为多个可接受值时，a 等于其中任一值即视为相等。列表按元素逐个比较
+// （WithUnorderedLists 时忽略顺序），映射按键递归比较，仅标量回退到字符串与数值比较。
 func (e *Evaluator) compareValues(a, b interface{}) bool {
+	if alternatives, ok := b.(acceptableValues); ok {
+		for _, alt := range alternatives {
+			if e.compareValues(a, alt) {
+				return true
+			}
+		}
+		return false
+	}
+
+	aList, aIsList := toSlice(a)
+	bList, bIsList := toSlice(b)
+	if aIsList || bIsList {
+		return aIsList && bIsList && e.compareLists(aList, bList)
+	}
+
+	aMap, aIsMap := toMap(a)
+	bMap, bIsMap := toMap(b)
+	if aIsMap || bIsMap {
+		return aIsMap && bIsMap && e.compareMaps(aMap, bMap)
+	}
+
+	return compareScalars(a, b)
+}
+
+// compareLists 比较两个列表
+func (e *Evaluator) compareLists(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	if !e.unorderedLists {
+		for i := range a {
+			if !e.compareValues(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// 无序比较：为每个元素匹配一个尚未使用的等值元素
+	used := make([]bool, len(b))
+	for _, av := range a {
+		matched := false
+		for j, bv := range b {
+			if !used[j] && e.compareValues(av, bv) {
+				used[j] = true
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// compareMaps 按键递归比较两个映射
+func (e *Evaluator) compareMaps(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, av := range a {
+		bv, ok := b[key]
+		if !ok || !e.compareValues(av, bv) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareScalars 比较两个标量值
+func compareScalars(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	// 布尔值按布尔语义比较，仅接受布尔值或 "true"/"false" 字符串
+	aBool, aIsBool := a.(bool)
+	bBool, bIsBool := b.(bool)
+	if aIsBool || bIsBool {
+		if !aIsBool {
+			aBool, aIsBool = parseBoolString(a)
+		}
+		if !bIsBool {
+			bBool, bIsBool = parseBoolString(b)
+		}
+		return aIsBool && bIsBool && aBool == bBool
+	}
+
 	// 类型转换后比较
 	aStr := fmt.Sprintf("%v", a)
 	bStr := fmt.Sprintf("%v", b)
@@ -498,6 +1070,55 @@ func (e *Evaluator) compareValues(a, b interface{}) bool {
 	return false
 }
 
+// parseBoolString 将 "true"/"false" 字符串解析为布尔值
+func parseBoolString(v interface{}) (bool, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return false, false
+	}
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// toSlice 将切片或数组转换为 []interface{}
+func toSlice(v interface{}) ([]interface{}, bool) {
+	if list, ok := v.([]interface{}); ok {
+		return list, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+// toMap 将映射转换为 map[string]interface{}
+func toMap(v interface{}) (map[string]interface{}, bool) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil, false
+	}
+	m := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+	}
+	return m, true
+}
+
 // toFloat64 尝试转换为 float64
 func toFloat64(v interface{}) (float64, error) {
 	switch val := v.(type) {
@@ -507,12 +1128,12 @@ func toFloat64(v interface{}) (float64, error) {
 		return float64(val), nil
 	case int:
 		return float64(val), nil
+	case int32:
+		return float64(val), nil
 	case int64:
 		return float64(val), nil
 	case string:
-		var f float64
-		_, err := fmt.Sscanf(val, "%f", &f)
-		return f, err
+		return strconv.ParseFloat(strings.TrimSpace(val), 64)
 	default:
 		return 0, fmt.Errorf("无法转换为 float64")
 	}
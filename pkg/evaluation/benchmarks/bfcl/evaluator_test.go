@@ -2,11 +2,15 @@ package bfcl
 
 import (
 	"context"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ahhsitt/helloagents-go/pkg/agents"
 	"github.com/ahhsitt/helloagents-go/pkg/core/config"
+	"github.com/ahhsitt/helloagents-go/pkg/core/message"
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
 )
 
@@ -134,6 +138,48 @@ func TestEvaluator_CompareValues(t *testing.T) {
 	}
 }
 
+func TestEvaluator_CompareValues_Typed(t *testing.T) {
+	ordered := &Evaluator{}
+	unordered := NewEvaluator(nil, ModeAST, WithUnorderedLists(true))
+
+	tests := []struct {
+		name          string
+		a             interface{}
+		b             interface{}
+		wantOrdered   bool
+		wantUnordered bool
+	}{
+		{"相同列表", []interface{}{1.0, 2.0, 3.0}, []interface{}{1, 2, 3}, true, true},
+		{"列表顺序不同", []interface{}{1.0, 2.0, 3.0}, []interface{}{3.0, 1.0, 2.0}, false, true},
+		{"列表长度不同", []interface{}{1.0, 2.0}, []interface{}{1.0, 2.0, 2.0}, false, false},
+		{"列表元素重复", []interface{}{"a", "a", "b"}, []interface{}{"a", "b", "b"}, false, false},
+		{"列表与字符串", []interface{}{1.0, 2.0}, "[1 2]", false, false},
+		{"嵌套映射", map[string]interface{}{"unit": "celsius", "range": map[string]interface{}{"min": 1.0, "max": 5.0}},
+			map[string]interface{}{"range": map[string]interface{}{"max": 5, "min": 1}, "unit": "Celsius"}, true, true},
+		{"嵌套映射值不同", map[string]interface{}{"range": map[string]interface{}{"min": 1.0}},
+			map[string]interface{}{"range": map[string]interface{}{"min": 2.0}}, false, false},
+		{"映射缺少键", map[string]interface{}{"a": 1.0, "b": 2.0}, map[string]interface{}{"a": 1.0, "c": 2.0}, false, false},
+		{"映射中的列表", map[string]interface{}{"ids": []interface{}{"x", "y"}},
+			map[string]interface{}{"ids": []interface{}{"y", "x"}}, false, true},
+		{"布尔相同", true, true, true, true},
+		{"布尔与字符串", true, "True", true, true},
+		{"布尔与数字", true, 1.0, false, false},
+		{"布尔与数字字符串", true, "1", false, false},
+		{"数字字符串前缀", "1abc", 1.0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ordered.compareValues(tt.a, tt.b); got != tt.wantOrdered {
+				t.Errorf("ordered compareValues(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.wantOrdered)
+			}
+			if got := unordered.compareValues(tt.a, tt.b); got != tt.wantUnordered {
+				t.Errorf("unordered compareValues(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.wantUnordered)
+			}
+		})
+	}
+}
+
 func TestEvaluator_CompareFunctionCall(t *testing.T) {
 	evaluator := &Evaluator{}
 
@@ -171,7 +217,7 @@ func TestEvaluator_CompareFunctionCall(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := evaluator.compareFunctionCall(tt.predicted, tt.expected)
+			got := evaluator.compareFunctionCall(tt.predicted, tt.expected, nil)
 			if got != tt.wantScore {
 				t.Errorf("compareFunctionCall() = %v, want %v", got, tt.wantScore)
 			}
@@ -179,6 +225,93 @@ func TestEvaluator_CompareFunctionCall(t *testing.T) {
 	}
 }
 
+func TestEvaluator_CompareFunctionCall_ValueNormalizer(t *testing.T) {
+	units := map[string]string{"c": "celsius", "celsius": "celsius", "f": "fahrenheit", "fahrenheit": "fahrenheit"}
+	normalizer := func(paramName string, v interface{}) interface{} {
+		if s, ok := v.(string); ok && paramName == "unit" {
+			if unit, ok := units[strings.ToLower(strings.TrimSpace(s))]; ok {
+				return unit
+			}
+		}
+		return v
+	}
+
+	predicted := evaluation.FunctionCall{Name: "get_weather", Arguments: map[string]interface{}{"city": "Beijing", "unit": "C"}}
+	expected := evaluation.FunctionCall{Name: "get_weather", Arguments: map[string]interface{}{"city": "Beijing", "unit": "celsius"}}
+
+	if got := NewEvaluator(nil, ModeAST).compareFunctionCall(predicted, expected, nil); got != 0.5 {
+		t.Fatalf("without normalizer compareFunctionCall() = %v, want 0.5", got)
+	}
+
+	evaluator := NewEvaluator(nil, ModeAST, WithValueNormalizer(normalizer))
+	if got := evaluator.compareFunctionCall(predicted, expected, nil); got != 1.0 {
+		t.Errorf("with normalizer compareFunctionCall() = %v, want 1", got)
+	}
+
+	// 规范化后单位不同仍不匹配
+	predicted.Arguments["unit"] = "F"
+	if got := evaluator.compareFunctionCall(predicted, expected, nil); got != 0.5 {
+		t.Errorf("different units compareFunctionCall() = %v, want 0.5", got)
+	}
+}
+
+func TestEvaluator_OptionalParams(t *testing.T) {
+	evaluator := NewEvaluator(nil, ModeAST)
+	sample := evaluation.Sample{
+		ID: "optional",
+		Tools: []evaluation.ToolDefinition{{
+			Name: "get_weather",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"city": map[string]interface{}{"type": "string"},
+					"unit": map[string]interface{}{"type": "string"},
+				},
+				"required": []interface{}{"city"},
+			},
+		}},
+		Expected: []interface{}{
+			map[string]interface{}{
+				"get_weather": map[string]interface{}{
+					"city": []interface{}{"Beijing"},
+					"unit": []interface{}{"celsius"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		tools     []evaluation.ToolDefinition
+		wantScore float64
+	}{
+		{"只给出必填参数", map[string]interface{}{"city": "Beijing"}, sample.Tools, 1.0},
+		{"可选参数正确", map[string]interface{}{"city": "Beijing", "unit": "celsius"}, sample.Tools, 1.0},
+		{"可选参数错误", map[string]interface{}{"city": "Beijing", "unit": "fahrenheit"}, sample.Tools, 0.5},
+		{"缺少必填参数", map[string]interface{}{"unit": "celsius"}, sample.Tools, 0.5},
+		{"无 Schema 时全部必填", map[string]interface{}{"city": "Beijing"}, nil, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := sample
+			s.Tools = tt.tools
+			predicted := []evaluation.FunctionCall{{Name: "get_weather", Arguments: tt.args}}
+			result := &evaluation.SampleResult{SampleID: s.ID, Details: map[string]interface{}{}}
+
+			if _, err := evaluator.scoreSample(s, predicted, result); err != nil {
+				t.Fatalf("scoreSample() error = %v", err)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("Score = %v, want %v", result.Score, tt.wantScore)
+			}
+			if result.Success != (tt.wantScore == 1.0) {
+				t.Errorf("Success = %v, want %v", result.Success, tt.wantScore == 1.0)
+			}
+		})
+	}
+}
+
 func TestEvaluator_ParseGroundTruth(t *testing.T) {
 	evaluator := &Evaluator{}
 
@@ -204,9 +337,68 @@ func TestEvaluator_ParseGroundTruth(t *testing.T) {
 		t.Errorf("parseGroundTruth() got name %s, want get_weather", calls[0].Name)
 	}
 
-	// 验证参数取第一个可接受值
-	if calls[0].Arguments["city"] != "Beijing" {
-		t.Errorf("parseGroundTruth() got city %v, want Beijing", calls[0].Arguments["city"])
+	// 验证参数保留全部可接受值
+	want := acceptableValues{"Beijing", "北京"}
+	if got := calls[0].Arguments["city"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGroundTruth() got city %#v, want %#v", got, want)
+	}
+}
+
+func TestEvaluator_MultipleGoldAnswers(t *testing.T) {
+	evaluator := NewEvaluator(nil, ModeAST)
+
+	gt := []interface{}{
+		map[string]interface{}{
+			"get_weather": map[string]interface{}{
+				"city":  []interface{}{"Beijing"},
+				"unit":  []interface{}{"celsius", "C"},
+				"hours": []interface{}{[]interface{}{6.0, 12.0}},
+			},
+		},
+	}
+	expected, err := evaluator.parseGroundTruth(gt)
+	if err != nil || len(expected) != 1 {
+		t.Fatalf("parseGroundTruth() = %v, %v", expected, err)
+	}
+
+	tests := []struct {
+		name string
+		unit interface{}
+		want float64
+	}{
+		{"first alternative", "celsius", 1.0},
+		{"second alternative", "C", 1.0},
+		{"not acceptable", "F", 2.0 / 3.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			predicted := evaluation.FunctionCall{
+				Name: "get_weather",
+				Arguments: map[string]interface{}{
+					"city":  "Beijing",
+					"unit":  tt.unit,
+					"hours": []interface{}{6, 12},
+				},
+			}
+			if got := evaluator.compareFunctionCall(predicted, expected[0], nil); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("compareFunctionCall() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 规范化函数作用于每个可接受值
+	normalized := NewEvaluator(nil, ModeAST, WithValueNormalizer(func(paramName string, v interface{}) interface{} {
+		if s, ok := v.(string); ok && paramName == "unit" {
+			return strings.ToLower(s)
+		}
+		return v
+	}))
+	predicted := evaluation.FunctionCall{
+		Name:      "get_weather",
+		Arguments: map[string]interface{}{"city": "Beijing", "unit": "c", "hours": []interface{}{6, 12}},
+	}
+	if got := normalized.compareFunctionCall(predicted, expected[0], nil); got != 1.0 {
+		t.Errorf("normalized compareFunctionCall() = %v, want 1", got)
 	}
 }
 
@@ -233,3 +425,306 @@ func TestEvaluator_Name(t *testing.T) {
 		t.Errorf("Name() = %s, want %s", name, expected)
 	}
 }
+
+// scriptedAgent 按轮次返回预设响应并记录输入的 Mock Agent
+type scriptedAgent struct {
+	responses []string
+	inputs    []agents.Input
+}
+
+func (m *scriptedAgent) Name() string {
+	return "scripted"
+}
+
+func (m *scriptedAgent) Config() config.AgentConfig {
+	return config.AgentConfig{}
+}
+
+func (m *scriptedAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	idx := len(m.inputs)
+	m.inputs = append(m.inputs, input)
+	return agents.Output{Response: m.responses[idx]}, nil
+}
+
+func (m *scriptedAgent) RunStream(ctx context.Context, input agents.Input) (<-chan agents.StreamChunk, <-chan error) {
+	ch := make(chan agents.StreamChunk)
+	errCh := make(chan error)
+	close(ch)
+	close(errCh)
+	return ch, errCh
+}
+
+func TestEvaluator_EvaluateSample_MultiTurn(t *testing.T) {
+	dataset := NewDataset(t.TempDir(), "multi_turn_base")
+	item := map[string]interface{}{
+		"id": "multi_turn_base_0",
+		"question": []interface{}{
+			[]interface{}{map[string]interface{}{"role": "user", "content": "Create a folder named docs"}},
+			[]interface{}{map[string]interface{}{"role": "user", "content": "Now move into it"}},
+		},
+	}
+	sample := dataset.parseItem(item, 0)
+	sample.Expected = []interface{}{
+		map[string]interface{}{"mkdir": map[string]interface{}{"dir_name": []interface{}{"docs"}}},
+		map[string]interface{}{"cd": map[string]interface{}{"folder": []interface{}{"docs"}}},
+	}
+
+	agent := &scriptedAgent{responses: []string{
+		`[{"name": "mkdir", "arguments": {"dir_name": "docs"}}]`,
+		`[{"name": "cd", "arguments": {"folder": "docs"}}]`,
+	}}
+
+	evaluator := NewEvaluator(dataset, ModeAST)
+	result, err := evaluator.EvaluateSample(context.Background(), agent, sample)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+
+	if len(agent.inputs) != 2 {
+		t.Fatalf("expected 2 turns sent, got %d", len(agent.inputs))
+	}
+	if agent.inputs[0].Query != "Create a folder named docs" || agent.inputs[1].Query != "Now move into it" {
+		t.Errorf("unexpected turn queries: %q, %q", agent.inputs[0].Query, agent.inputs[1].Query)
+	}
+	history, ok := agent.inputs[1].Context["history"].([]message.Message)
+	if !ok || len(history) != 2 {
+		t.Fatalf("expected 2 history messages on second turn, got %v", agent.inputs[1].Context["history"])
+	}
+	if history[1].Content != agent.responses[0] {
+		t.Errorf("expected prior agent response in history, got %q", history[1].Content)
+	}
+
+	calls, ok := result.Predicted.([]evaluation.FunctionCall)
+	if !ok || len(calls) != 2 {
+		t.Fatalf("expected 2 collected calls, got %v", result.Predicted)
+	}
+	if !result.Success {
+		t.Errorf("expected multi-turn sample to succeed, details: %v", result.Details)
+	}
+}
+
+func TestEvaluator_EvaluateSample_HallucinatedFunction(t *testing.T) {
+	dataset := NewDataset(t.TempDir(), "simple")
+	sample := evaluation.Sample{
+		ID:    "simple_0",
+		Input: "What's the weather in Paris?",
+		Tools: []evaluation.ToolDefinition{{Name: "get_weather"}},
+		Expected: []interface{}{
+			map[string]interface{}{"get_weather": map[string]interface{}{"city": []interface{}{"Paris"}}},
+		},
+	}
+
+	agent := NewMockAgent("test", `[{"name": "get_weather", "arguments": {"city": "Paris"}}, {"name": "book_flight", "arguments": {"to": "Paris"}}]`)
+	evaluator := NewEvaluator(dataset, ModeAST)
+	result, err := evaluator.EvaluateSample(context.Background(), agent, sample)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+
+	hallucinated, ok := result.Details["hallucinated_functions"].([]string)
+	if !ok || len(hallucinated) != 1 || hallucinated[0] != "book_flight" {
+		t.Fatalf("hallucinated_functions = %v, want [book_flight]", result.Details["hallucinated_functions"])
+	}
+
+	summary := NewMetrics().Compute([]*evaluation.SampleResult{result})
+	if rate, _ := summary.Extra["hallucination_rate"].(float64); rate != 0.5 {
+		t.Errorf("hallucination_rate = %v, want 0.5", summary.Extra["hallucination_rate"])
+	}
+}
+
+// toolCallAgent 返回原生工具调用的 Mock Agent
+type toolCallAgent struct {
+	scriptedAgent
+	toolCalls []message.ToolCall
+}
+
+func (m *toolCallAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	output, err := m.scriptedAgent.Run(ctx, input)
+	output.ToolCalls = m.toolCalls
+	return output, err
+}
+
+func TestEvaluator_EvaluateSample_NativeToolCalls(t *testing.T) {
+	sample := evaluation.Sample{
+		ID:    "simple_0",
+		Input: "What's the weather in Paris?",
+		Tools: []evaluation.ToolDefinition{{Name: "get_weather"}},
+		Expected: []interface{}{
+			map[string]interface{}{"get_weather": map[string]interface{}{"city": []interface{}{"Paris"}}},
+		},
+	}
+
+	// 响应文本中的 JSON 指向错误的函数，只有使用原生工具调用才能匹配
+	agent := &toolCallAgent{
+		scriptedAgent: scriptedAgent{responses: []string{`I will call [{"name": "search", "arguments": {"q": "weather"}}]`}},
+		toolCalls: []message.ToolCall{
+			{ID: "call_1", Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris"}},
+		},
+	}
+	evaluator := NewEvaluator(NewDataset(t.TempDir(), "simple"), ModeAST)
+	result, err := evaluator.EvaluateSample(context.Background(), agent, sample)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+
+	if !result.Success || result.Score != 1.0 {
+		t.Errorf("Success = %v, Score = %v, want native tool call to match", result.Success, result.Score)
+	}
+	if result.Details["call_source"] != "tool_calls" {
+		t.Errorf("call_source = %v, want tool_calls", result.Details["call_source"])
+	}
+	calls, ok := result.Predicted.([]evaluation.FunctionCall)
+	if !ok || len(calls) != 1 || calls[0].Name != "get_weather" || calls[0].Arguments["city"] != "Paris" {
+		t.Errorf("Predicted = %v, want the native tool call", result.Predicted)
+	}
+
+	// 没有原生工具调用时回退到文本解析
+	agent = &toolCallAgent{scriptedAgent: scriptedAgent{responses: []string{`[{"name": "get_weather", "arguments": {"city": "Paris"}}]`}}}
+	result, err = evaluator.EvaluateSample(context.Background(), agent, sample)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if !result.Success || result.Details["call_source"] != "text" {
+		t.Errorf("fallback Success = %v, call_source = %v", result.Success, result.Details["call_source"])
+	}
+}
+
+func TestEvaluator_ExtractFunctionCalls_Repair(t *testing.T) {
+	evaluator := &Evaluator{}
+
+	tests := []struct {
+		name      string
+		response  string
+		wantName  string
+		wantArgs  map[string]interface{}
+		wantCalls int
+	}{
+		{
+			name:      "单引号与尾随逗号",
+			response:  `[{'name':'f','arguments':{'x':1,}}]`,
+			wantName:  "f",
+			wantArgs:  map[string]interface{}{"x": float64(1)},
+			wantCalls: 1,
+		},
+		{
+			name: "行注释",
+			response: `[
+  // 查询天气
+  {"name": "get_weather", "arguments": {"city": "Paris"}}, // 巴黎
+]`,
+			wantName:  "get_weather",
+			wantArgs:  map[string]interface{}{"city": "Paris"},
+			wantCalls: 1,
+		},
+		{
+			name:      "单引号字符串中的双引号与撇号",
+			response:  `{'name': 'say', 'arguments': {'text': 'he said "it\'s ok"'}}`,
+			wantName:  "say",
+			wantArgs:  map[string]interface{}{"text": `he said "it's ok"`},
+			wantCalls: 1,
+		},
+		{
+			name:      "前后带说明文字",
+			response:  "Here's the call: [{'name': 'search', 'arguments': {'query': 'go',},},] Hope it helps!",
+			wantName:  "search",
+			wantArgs:  map[string]interface{}{"query": "go"},
+			wantCalls: 1,
+		},
+		{
+			name:      "代码块内块注释",
+			response:  "```json\n[{\"name\": \"f\", /* 参数 */ \"arguments\": {\"x\": 2,}}]\n```",
+			wantName:  "f",
+			wantArgs:  map[string]interface{}{"x": float64(2)},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, err := evaluator.extractFunctionCalls(tt.response)
+			if err != nil {
+				t.Fatalf("extractFunctionCalls() error = %v", err)
+			}
+			if len(calls) != tt.wantCalls {
+				t.Fatalf("got %d calls, want %d", len(calls), tt.wantCalls)
+			}
+			if calls[0].Name != tt.wantName {
+				t.Errorf("Name = %s, want %s", calls[0].Name, tt.wantName)
+			}
+			if !reflect.DeepEqual(calls[0].Arguments, tt.wantArgs) {
+				t.Errorf("Arguments = %v, want %v", calls[0].Arguments, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRepairJSON_KeepsValidJSON(t *testing.T) {
+	valid := []string{
+		`[{"name": "f", "arguments": {"url": "http://example.com/a,b", "note": "it's // not a comment, ]"}}]`,
+		`{"name": "g", "arguments": {"list": [1, 2, 3], "quote": "say \"hi\""}}`,
+	}
+	for _, s := range valid {
+		if got := repairJSON(s); got != s {
+			t.Errorf("repairJSON() modified valid JSON:\n got  %s\n want %s", got, s)
+		}
+	}
+}
+
+func TestEvaluator_EvaluateMatch_OneToOne(t *testing.T) {
+	evaluator := &Evaluator{}
+	call := func(name string, args map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"name": name, "arguments": args}
+	}
+
+	t.Run("单个预测不重复计入多个预期调用", func(t *testing.T) {
+		groundTruth := []interface{}{
+			call("f", map[string]interface{}{"x": 1}),
+			call("f", map[string]interface{}{"x": 1}),
+		}
+		predicted := []evaluation.FunctionCall{{Name: "f", Arguments: map[string]interface{}{"x": 1}}}
+
+		success, score, details := evaluator.evaluateMatch(predicted, groundTruth, nil)
+		if success {
+			t.Error("one prediction should not satisfy two expected calls")
+		}
+		if details["matched_count"] != 1 {
+			t.Errorf("matched_count = %v, want 1", details["matched_count"])
+		}
+		if score != 0.5 {
+			t.Errorf("avg_score = %v, want 0.5", score)
+		}
+	})
+
+	t.Run("部分匹配只计一次", func(t *testing.T) {
+		groundTruth := []interface{}{
+			call("f", map[string]interface{}{"x": 1, "y": 2}),
+			call("f", map[string]interface{}{"x": 1, "y": 3}),
+		}
+		predicted := []evaluation.FunctionCall{{Name: "f", Arguments: map[string]interface{}{"x": 1, "y": 9}}}
+
+		_, score, details := evaluator.evaluateMatch(predicted, groundTruth, nil)
+		if details["matched_count"] != 0 {
+			t.Errorf("matched_count = %v, want 0", details["matched_count"])
+		}
+		if score != 0.25 {
+			t.Errorf("avg_score = %v, want 0.25", score)
+		}
+	})
+
+	t.Run("顺序无关的最优配对", func(t *testing.T) {
+		// 预测调用顺序与预期调用顺序相反
+		groundTruth := []interface{}{
+			call("f", map[string]interface{}{"x": 1, "y": 2}),
+			call("f", map[string]interface{}{"x": 1, "y": 3}),
+		}
+		predicted := []evaluation.FunctionCall{
+			{Name: "f", Arguments: map[string]interface{}{"x": 1, "y": 3}},
+			{Name: "f", Arguments: map[string]interface{}{"x": 1, "y": 2}},
+		}
+
+		success, score, details := evaluator.evaluateMatch(predicted, groundTruth, nil)
+		if !success || score != 1.0 || details["matched_count"] != 2 {
+			t.Errorf("expected full match, got success=%v score=%v matched=%v", success, score, details["matched_count"])
+		}
+	})
+}
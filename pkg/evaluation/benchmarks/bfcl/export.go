@@ -14,22 +14,50 @@ type ExportEntry struct {
 	ID           string        `json:"id"`
 	Result       interface{}   `json:"result"`
 	InferenceLog []interface{} `json:"inference_log,omitempty"`
+	GroundTruth  interface{}   `json:"ground_truth,omitempty"`
+}
+
+// GroundTruthSource ground truth 数据来源
+//
+// *Dataset 实现了该接口。
+type GroundTruthSource interface {
+	GetGroundTruth(sampleID string) (interface{}, bool)
+}
+
+// ExporterOption 导出器配置选项
+type ExporterOption func(*Exporter)
+
+// WithRawGroundTruth 在每个导出条目中附加原始 ground_truth 对象
+//
+// 用于与官方评分器比对调试，官方提交格式不包含该字段，默认关闭。
+func WithRawGroundTruth(source GroundTruthSource) ExporterOption {
+	return func(e *Exporter) {
+		e.groundTruth = source
+	}
 }
 
 // Exporter BFCL 结果导出器
 type Exporter struct {
 	// includeInferenceLog 是否包含推理日志
 	includeInferenceLog bool
+
+	// groundTruth 原始 ground truth 来源（为 nil 时不导出）
+	groundTruth GroundTruthSource
 }
 
 // NewExporter 创建导出器
 //
 // 参数:
 //   - includeInferenceLog: 是否包含推理日志
-func NewExporter(includeInferenceLog bool) *Exporter {
-	return &Exporter{
+//   - opts: 可选配置（如 WithRawGroundTruth）
+func NewExporter(includeInferenceLog bool, opts ...ExporterOption) *Exporter {
+	e := &Exporter{
 		includeInferenceLog: includeInferenceLog,
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 // Export 导出评估结果为 BFCL 官方格式
@@ -60,6 +88,12 @@ func (e *Exporter) Export(result *evaluation.EvalResult, outputPath string) erro
 			entry.InferenceLog = e.buildInferenceLog(sr)
 		}
 
+		if e.groundTruth != nil {
+			if gt, ok := e.groundTruth.GetGroundTruth(sr.SampleID); ok {
+				entry.GroundTruth = gt
+			}
+		}
+
 		if err := encoder.Encode(entry); err != nil {
 			return fmt.Errorf("写入条目失败: %w", err)
 		}
@@ -158,6 +192,9 @@ func (e *Exporter) ExportMarkdownReport(result *evaluation.EvalResult, outputPat
 		if result.Metrics.F1Score > 0 {
 			fmt.Fprintf(file, "| F1 分数 | %.2f%% |\n", result.Metrics.F1Score*100)
 		}
+		if result.Metrics.TokenUsage != nil {
+			fmt.Fprintf(file, "| 总 Token | %d |\n", result.Metrics.TokenUsage.TotalTokens)
+		}
 	}
 	fmt.Fprintf(file, "\n")
 
@@ -173,6 +210,12 @@ func (e *Exporter) ExportMarkdownReport(result *evaluation.EvalResult, outputPat
 		fmt.Fprintf(file, "\n")
 	}
 
+	// 错误分类
+	if breakdown := evaluation.ErrorBreakdownOf(result.Metrics); breakdown != nil {
+		fmt.Fprintf(file, "## 错误分类\n\n")
+		fmt.Fprintf(file, "%s\n", breakdown.Markdown())
+	}
+
 	// 错误样本
 	var errorSamples []*evaluation.SampleResult
 	for _, sr := range result.DetailedResults {
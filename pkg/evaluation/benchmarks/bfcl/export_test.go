@@ -0,0 +1,57 @@
+package bfcl
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+func TestExporter_RawGroundTruth(t *testing.T) {
+	dataset := NewDataset(t.TempDir(), "simple_python")
+	dataset.groundTruth["simple_0"] = []interface{}{
+		map[string]interface{}{"get_weather": map[string]interface{}{"city": []interface{}{"Beijing"}}},
+	}
+
+	result := &evaluation.EvalResult{
+		DetailedResults: []*evaluation.SampleResult{
+			{SampleID: "simple_0", AgentResponse: `[{"name": "get_weather"}]`},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		opts   []ExporterOption
+		wantGT bool
+	}{
+		{name: "disabled by default", opts: nil, wantGT: false},
+		{name: "enabled", opts: []ExporterOption{WithRawGroundTruth(dataset)}, wantGT: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "result.jsonl")
+			if err := NewExporter(false, tt.opts...).Export(result, path); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &entry); err != nil {
+				t.Fatalf("failed to parse entry: %v", err)
+			}
+
+			_, hasGT := entry["ground_truth"]
+			if hasGT != tt.wantGT {
+				t.Errorf("ground_truth present = %v, want %v", hasGT, tt.wantGT)
+			}
+		})
+	}
+}
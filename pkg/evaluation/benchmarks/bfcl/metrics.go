@@ -12,14 +12,49 @@ func NewMetrics() *Metrics {
 	return &Metrics{}
 }
 
+// SampleContribution 单个样本对汇总指标的贡献
+type SampleContribution struct {
+	// SampleID 样本 ID
+	SampleID string `json:"sample_id"`
+
+	// Score 样本得分（计入平均分）
+	Score float64 `json:"score"`
+
+	// Success 是否计入成功数（准确率）
+	Success bool `json:"success"`
+
+	// Errored 是否计入错误数
+	Errored bool `json:"errored,omitempty"`
+
+	// ExpectedCalls 计入召回率分母的期望调用数
+	ExpectedCalls int `json:"expected_calls"`
+
+	// PredictedCalls 计入精确率分母的预测调用数
+	PredictedCalls int `json:"predicted_calls"`
+
+	// CorrectCalls 计入精确率/召回率分子的正确调用数
+	CorrectCalls int `json:"correct_calls"`
+
+	// HallucinatedCalls 调用了未声明函数的预测调用数
+	HallucinatedCalls int `json:"hallucinated_calls,omitempty"`
+}
+
 // Compute 计算 BFCL 评估指标
 func (m *Metrics) Compute(results []*evaluation.SampleResult) *evaluation.MetricsSummary {
+	summary, _ := m.ComputeWithBreakdown(results)
+	return summary
+}
+
+// ComputeWithBreakdown 计算 BFCL 评估指标并返回每个样本的贡献
+//
+// 返回的贡献列表与 results 一一对应，各项累加即为汇总指标中的计数。
+func (m *Metrics) ComputeWithBreakdown(results []*evaluation.SampleResult) (*evaluation.MetricsSummary, []SampleContribution) {
 	summary := &evaluation.MetricsSummary{
 		Extra: make(map[string]interface{}),
 	}
 
 	if len(results) == 0 {
-		return summary
+		return summary, nil
 	}
 
 	// 基础统计
@@ -32,29 +67,49 @@ func (m *Metrics) Compute(results []*evaluation.SampleResult) *evaluation.Metric
 	totalExpectedCalls := 0
 	totalPredictedCalls := 0
 	correctCalls := 0
+	hallucinatedCalls := 0
 
+	contributions := make([]SampleContribution, 0, len(results))
 	for _, r := range results {
-		if r.Success {
-			successCount++
-		}
-		totalScore += r.Score
-
-		if r.Error != "" {
-			errorCount++
+		contribution := SampleContribution{
+			SampleID: r.SampleID,
+			Score:    r.Score,
+			Success:  r.Success,
+			Errored:  r.Error != "",
 		}
 
 		// 提取详细信息用于计算精确率/召回率
 		if details := r.Details; details != nil {
-			if ec, ok := details["expected_count"].(int); ok {
-				totalExpectedCalls += ec
-			}
-			if mc, ok := details["matched_count"].(int); ok {
-				correctCalls += mc
+			contribution.ExpectedCalls = intDetail(details["expected_count"])
+			contribution.CorrectCalls = intDetail(details["matched_count"])
+			switch pc := details["predicted_calls"].(type) {
+			case []evaluation.FunctionCall:
+				contribution.PredictedCalls = len(pc)
+			case []interface{}:
+				// 从断点文件恢复的结果经过 JSON 反序列化
+				contribution.PredictedCalls = len(pc)
 			}
-			if pc, ok := details["predicted_calls"].([]evaluation.FunctionCall); ok {
-				totalPredictedCalls += len(pc)
+			switch hf := details["hallucinated_functions"].(type) {
+			case []string:
+				contribution.HallucinatedCalls = len(hf)
+			case []interface{}:
+				contribution.HallucinatedCalls = len(hf)
 			}
 		}
+
+		if contribution.Success {
+			successCount++
+		}
+		totalScore += contribution.Score
+		if contribution.Errored {
+			errorCount++
+		}
+		totalExpectedCalls += contribution.ExpectedCalls
+		totalPredictedCalls += contribution.PredictedCalls
+		correctCalls += contribution.CorrectCalls
+		hallucinatedCalls += contribution.HallucinatedCalls
+
+		contributions = append(contributions, contribution)
 	}
 
 	// 计算准确率
@@ -78,11 +133,24 @@ func (m *Metrics) Compute(results []*evaluation.SampleResult) *evaluation.Metric
 	summary.Extra["total_samples"] = totalSamples
 	summary.Extra["success_count"] = successCount
 	summary.Extra["error_count"] = errorCount
+	summary.Extra["timeout_count"] = evaluation.CountTimeouts(results)
+	summary.Extra[evaluation.ErrorBreakdownKey] = evaluation.ComputeErrorBreakdown(results)
 	summary.Extra["total_expected_calls"] = totalExpectedCalls
 	summary.Extra["total_predicted_calls"] = totalPredictedCalls
 	summary.Extra["correct_calls"] = correctCalls
+	summary.Extra["hallucinated_calls"] = hallucinatedCalls
 
-	return summary
+	// 幻觉率：调用未声明函数的预测调用占比
+	hallucinationRate := 0.0
+	if totalPredictedCalls > 0 {
+		hallucinationRate = float64(hallucinatedCalls) / float64(totalPredictedCalls)
+	}
+	summary.Extra["hallucination_rate"] = hallucinationRate
+
+	// Token 使用量
+	summary.TokenUsage = evaluation.SumTokenUsage(results)
+
+	return summary, contributions
 }
 
 // ComputeCategoryMetrics 计算分类别指标
@@ -119,3 +187,43 @@ func (m *Metrics) ComputeCategoryMetrics(results []*evaluation.SampleResult) map
 
 	return categoryMetrics
 }
+
+// WeightedAccuracy 计算按类别加权的准确率
+//
+// 各类别准确率按 weights 加权平均；weights 为空时各类别等权（宏平均），
+// 否则未列出或权重不大于 0 的类别不参与加权。没有可加权的类别时返回 0。
+func (m *Metrics) WeightedAccuracy(categories map[string]*evaluation.CategoryMetrics, weights map[string]float64) float64 {
+	totalWeight := 0.0
+	weighted := 0.0
+	for cat, cm := range categories {
+		if cm.Total == 0 {
+			continue
+		}
+		weight := 1.0
+		if len(weights) > 0 {
+			weight = weights[cat]
+		}
+		if weight <= 0 {
+			continue
+		}
+		accuracy := float64(cm.Success) / float64(cm.Total)
+		weighted += weight * accuracy
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return weighted / totalWeight
+}
+
+// intDetail 读取整数型详情字段（兼容 JSON 反序列化得到的 float64）
+func intDetail(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
@@ -1,6 +1,7 @@
 package bfcl
 
 import (
+	"math"
 	"testing"
 
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
@@ -59,6 +60,78 @@ func TestMetrics_Compute(t *testing.T) {
 	}
 }
 
+func TestMetrics_ComputeWithBreakdown(t *testing.T) {
+	metrics := NewMetrics()
+
+	results := []*evaluation.SampleResult{
+		{
+			SampleID: "test_001",
+			Success:  true,
+			Score:    1.0,
+			Details: map[string]interface{}{
+				"expected_count":  2,
+				"matched_count":   2,
+				"predicted_calls": []evaluation.FunctionCall{{Name: "func1"}, {Name: "func2"}},
+			},
+		},
+		{
+			SampleID: "test_002",
+			Success:  false,
+			Score:    0.5,
+			Details: map[string]interface{}{
+				"expected_count":  2,
+				"matched_count":   1,
+				"predicted_calls": []evaluation.FunctionCall{{Name: "func1"}, {Name: "func3"}, {Name: "func4"}},
+			},
+		},
+		{
+			SampleID: "test_003",
+			Success:  false,
+			Error:    "timeout",
+		},
+	}
+
+	summary, contributions := metrics.ComputeWithBreakdown(results)
+	if len(contributions) != len(results) {
+		t.Fatalf("expected %d contributions, got %d", len(results), len(contributions))
+	}
+
+	successCount, errorCount := 0, 0
+	expected, predicted, correct := 0, 0, 0
+	totalScore := 0.0
+	for i, c := range contributions {
+		if c.SampleID != results[i].SampleID {
+			t.Errorf("contribution %d: expected sample ID %s, got %s", i, results[i].SampleID, c.SampleID)
+		}
+		if c.Success {
+			successCount++
+		}
+		if c.Errored {
+			errorCount++
+		}
+		expected += c.ExpectedCalls
+		predicted += c.PredictedCalls
+		correct += c.CorrectCalls
+		totalScore += c.Score
+	}
+
+	checks := map[string]int{
+		"success_count":         successCount,
+		"error_count":           errorCount,
+		"total_expected_calls":  expected,
+		"total_predicted_calls": predicted,
+		"correct_calls":         correct,
+	}
+	for key, got := range checks {
+		if want := summary.Extra[key].(int); got != want {
+			t.Errorf("%s: contributions sum to %d, summary reports %d", key, got, want)
+		}
+	}
+	if avg := totalScore / float64(len(results)); avg != summary.AverageScore {
+		t.Errorf("expected AverageScore %f from contributions, got %f", avg, summary.AverageScore)
+	}
+}
+
 func TestMetrics_ComputeCategoryMetrics(t *testing.T) {
 	metrics := NewMetrics()
 
@@ -93,3 +166,45 @@ func TestMetrics_ComputeCategoryMetrics(t *testing.T) {
 		t.Errorf("expected multiple.Accuracy 1.0, got %f", multipleMetrics.Accuracy)
 	}
 }
+
+func TestMetrics_WeightedAccuracy(t *testing.T) {
+	metrics := NewMetrics()
+
+	// simple 类别 8 个样本 6 个成功，parallel 类别 2 个样本全部失败
+	var results []*evaluation.SampleResult
+	for i := 0; i < 8; i++ {
+		results = append(results, &evaluation.SampleResult{Category: "simple", Success: i < 6})
+	}
+	for i := 0; i < 2; i++ {
+		results = append(results, &evaluation.SampleResult{Category: "parallel"})
+	}
+	categories := metrics.ComputeCategoryMetrics(results)
+	raw := metrics.Compute(results).Accuracy
+
+	tests := []struct {
+		name    string
+		weights map[string]float64
+		want    float64
+	}{
+		{name: "默认等权", weights: nil, want: 0.375},
+		{name: "自定义权重", weights: map[string]float64{"simple": 1, "parallel": 3}, want: 0.1875},
+		{name: "未列出的类别不参与", weights: map[string]float64{"simple": 2}, want: 0.75},
+		{name: "无可加权类别", weights: map[string]float64{"multiple": 1}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := metrics.WeightedAccuracy(categories, tt.weights)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("WeightedAccuracy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if raw != 0.6 {
+		t.Fatalf("raw accuracy = %v, want 0.6", raw)
+	}
+	if weighted := metrics.WeightedAccuracy(categories, map[string]float64{"simple": 1, "parallel": 3}); weighted == raw {
+		t.Error("weighted accuracy should differ from raw accuracy")
+	}
+}
@@ -0,0 +1,124 @@
+package bfcl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+// ToolPromptBuilder 工具提示词构建器
+//
+// 将样本的工具定义渲染为提示词，写入智能体输入的 Context["tools_prompt"]。
+// 不同模型期望的工具调用格式不同，可通过 WithToolPromptBuilder 替换。
+// 智能体未返回原生工具调用（agents.Output.ToolCalls）时，评估器按 JSON 数组
+// [{"name": ..., "arguments": {...}}] 从响应文本提取函数调用，自定义构建器应要求模型按该格式输出。
+type ToolPromptBuilder interface {
+	// BuildToolPrompt 根据工具定义构建提示词
+	BuildToolPrompt(tools []evaluation.ToolDefinition) string
+}
+
+// ToolPromptBuilderFunc 函数形式的工具提示词构建器
+type ToolPromptBuilderFunc func(tools []evaluation.ToolDefinition) string
+
+// BuildToolPrompt 实现 ToolPromptBuilder 接口
+func (f ToolPromptBuilderFunc) BuildToolPrompt(tools []evaluation.ToolDefinition) string {
+	return f(tools)
+}
+
+// 工具提示词构建器
+var (
+	// EnglishToolPrompt 英文工具提示词（默认）
+	EnglishToolPrompt ToolPromptBuilder = ToolPromptBuilderFunc(buildEnglishToolPrompt)
+
+	// ChineseToolPrompt 中文工具提示词（早期版本的默认格式）
+	ChineseToolPrompt ToolPromptBuilder = ToolPromptBuilderFunc(buildChineseToolPrompt)
+
+	// OpenAIToolPrompt 以 OpenAI function schema JSON 描述工具的提示词
+	OpenAIToolPrompt ToolPromptBuilder = ToolPromptBuilderFunc(buildOpenAIToolPrompt)
+)
+
+// WithToolPromptBuilder 设置工具提示词构建器
+//
+// 默认使用 EnglishToolPrompt；需要保持早期中文提示词时使用 ChineseToolPrompt。
+// builder 为 nil 时恢复默认。
+func WithToolPromptBuilder(builder ToolPromptBuilder) EvaluatorOption {
+	return func(e *Evaluator) {
+		e.toolPrompt = builder
+	}
+}
+
+// functionCallFormat 评估器可解析的函数调用输出格式示例
+const functionCallFormat = `[{"name": "function_name", "arguments": {"param_name": "param_value"}}]`
+
+// buildEnglishToolPrompt 构建英文工具提示词
+func buildEnglishToolPrompt(tools []evaluation.ToolDefinition) string {
+	var sb strings.Builder
+	sb.WriteString("You have access to the following tools:\n\n")
+
+	for _, tool := range tools {
+		fmt.Fprintf(&sb, "### %s\n", tool.Name)
+		fmt.Fprintf(&sb, "Description: %s\n", tool.Description)
+		if len(tool.Parameters) > 0 {
+			paramsJSON, _ := json.MarshalIndent(tool.Parameters, "", "  ")
+			fmt.Fprintf(&sb, "Parameters: %s\n", string(paramsJSON))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\nCall the appropriate function(s) to answer the user's question. ")
+	sb.WriteString("Respond only with a JSON array in the following format:\n")
+	sb.WriteString(functionCallFormat)
+	return sb.String()
+}
+
+// buildChineseToolPrompt 构建中文工具提示词
+func buildChineseToolPrompt(tools []evaluation.ToolDefinition) string {
+	var sb strings.Builder
+	sb.WriteString("你有以下工具可以使用:\n\n")
+
+	for _, tool := range tools {
+		fmt.Fprintf(&sb, "### %s\n", tool.Name)
+		fmt.Fprintf(&sb, "描述: %s\n", tool.Description)
+		if len(tool.Parameters) > 0 {
+			paramsJSON, _ := json.MarshalIndent(tool.Parameters, "", "  ")
+			fmt.Fprintf(&sb, "参数: %s\n", string(paramsJSON))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n请根据用户问题调用合适的函数。返回格式为 JSON 数组:\n")
+	sb.WriteString(`[{"name": "函数名", "arguments": {"参数名": "参数值"}}]`)
+	return sb.String()
+}
+
+// buildOpenAIToolPrompt 以 OpenAI tools 字段的 JSON 结构描述工具
+func buildOpenAIToolPrompt(tools []evaluation.ToolDefinition) string {
+	type function struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description,omitempty"`
+		Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	}
+	type tool struct {
+		Type     string   `json:"type"`
+		Function function `json:"function"`
+	}
+
+	schema := make([]tool, len(tools))
+	for i, t := range tools {
+		schema[i] = tool{
+			Type:     "function",
+			Function: function{Name: t.Name, Description: t.Description, Parameters: t.Parameters},
+		}
+	}
+	schemaJSON, _ := json.MarshalIndent(schema, "", "  ")
+
+	var sb strings.Builder
+	sb.WriteString("# Tools\n\n")
+	sb.WriteString("You may call one or more of the following functions, described in OpenAI function schema format:\n\n")
+	sb.WriteString(string(schemaJSON))
+	sb.WriteString("\n\nRespond only with a JSON array of function calls in the following format:\n")
+	sb.WriteString(functionCallFormat)
+	return sb.String()
+}
@@ -0,0 +1,96 @@
+package bfcl
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+func toolPromptSample() evaluation.Sample {
+	return evaluation.Sample{
+		ID:    "simple_0",
+		Input: "What's the weather in Paris?",
+		Tools: []evaluation.ToolDefinition{{
+			Name:        "get_weather",
+			Description: "Get the current weather",
+			Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+				"required":   []interface{}{"city"},
+			},
+		}},
+		Expected: []interface{}{
+			map[string]interface{}{"get_weather": map[string]interface{}{"city": []interface{}{"Paris"}}},
+		},
+	}
+}
+
+func TestEvaluator_ToolPromptBuilder_Custom(t *testing.T) {
+	var gotTools []evaluation.ToolDefinition
+	builder := ToolPromptBuilderFunc(func(tools []evaluation.ToolDefinition) string {
+		gotTools = tools
+		return "custom prompt for " + tools[0].Name
+	})
+
+	agent := &scriptedAgent{responses: []string{`[{"name": "get_weather", "arguments": {"city": "Paris"}}]`}}
+	evaluator := NewEvaluator(NewDataset(t.TempDir(), "simple"), ModeAST, WithToolPromptBuilder(builder))
+	result, err := evaluator.EvaluateSample(context.Background(), agent, toolPromptSample())
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Success = false, details = %v", result.Details)
+	}
+
+	if len(agent.inputs) != 1 {
+		t.Fatalf("agent.Run called %d times, want 1", len(agent.inputs))
+	}
+	if got := agent.inputs[0].Context["tools_prompt"]; got != "custom prompt for get_weather" {
+		t.Errorf("tools_prompt = %q, want custom builder output", got)
+	}
+	if len(gotTools) != 1 || gotTools[0].Name != "get_weather" {
+		t.Errorf("builder received tools %v", gotTools)
+	}
+}
+
+func TestToolPromptBuilders(t *testing.T) {
+	tools := toolPromptSample().Tools
+
+	tests := []struct {
+		name    string
+		builder ToolPromptBuilder
+		want    []string
+	}{
+		{"english", EnglishToolPrompt, []string{"You have access to the following tools", "### get_weather", "Description: Get the current weather", functionCallFormat}},
+		{"chinese", ChineseToolPrompt, []string{"你有以下工具可以使用", "### get_weather", "描述: Get the current weather", "返回格式为 JSON 数组"}},
+		{"openai", OpenAIToolPrompt, []string{`"type": "function"`, `"name": "get_weather"`, `"required": [`, functionCallFormat}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := tt.builder.BuildToolPrompt(tools)
+			for _, want := range tt.want {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("prompt missing %q:\n%s", want, prompt)
+				}
+			}
+		})
+	}
+
+	// OpenAI 格式中的工具列表为合法 JSON
+	prompt := OpenAIToolPrompt.BuildToolPrompt(tools)
+	start, end := strings.Index(prompt, "["), strings.Index(prompt, "\n\nRespond")
+	var schema []map[string]interface{}
+	if err := json.Unmarshal([]byte(prompt[start:end]), &schema); err != nil || len(schema) != 1 {
+		t.Errorf("OpenAI tool schema is not valid JSON: %v", err)
+	}
+}
+
+func TestEvaluator_ToolPromptBuilder_Default(t *testing.T) {
+	input := NewEvaluator(nil, ModeAST).buildAgentInput(toolPromptSample())
+	if got := input.Context["tools_prompt"]; got != EnglishToolPrompt.BuildToolPrompt(toolPromptSample().Tools) {
+		t.Errorf("default tools_prompt = %q, want English prompt", got)
+	}
+}
@@ -1,18 +1,54 @@
 package datagen
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ahhsitt/helloagents-go/pkg/core/llm"
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation/loader"
 )
 
+// mockLLM 返回固定内容的测试 LLM 提供商
+type mockLLM struct {
+	content string
+}
+
+func (m *mockLLM) Generate(ctx context.Context, req llm.Request) (llm.Response, error) {
+	return llm.Response{Content: m.content}, nil
+}
+
+func (m *mockLLM) GenerateStream(ctx context.Context, req llm.Request) (<-chan llm.StreamChunk, <-chan error) {
+	ch := make(chan llm.StreamChunk)
+	errCh := make(chan error)
+	close(ch)
+	close(errCh)
+	return ch, errCh
+}
+
+func (m *mockLLM) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, nil
+}
+
+func (m *mockLLM) Name() string  { return "mock" }
+func (m *mockLLM) Model() string { return "mock-model" }
+func (m *mockLLM) Close() error  { return nil }
+
 func TestLLMJudge_ParseJudgeResponse(t *testing.T) {
 	judge := &LLMJudge{}
 
 	tests := []struct {
-		name      string
-		response  string
-		wantScore float64
+		name       string
+		response   string
+		wantScore  float64
+		wantParsed bool
 	}{
 		{
 			name: "标准 JSON",
@@ -23,12 +59,14 @@ func TestLLMJudge_ParseJudgeResponse(t *testing.T) {
 				"completeness": 4.0,
 				"comments": "Good quality"
 			}`,
-			wantScore: 4.0, // (4.5 + 4.0 + 3.5 + 4.0) / 4
+			wantScore:  4.0, // (4.5 + 4.0 + 3.5 + 4.0) / 4
+			wantParsed: true,
 		},
 		{
-			name:      "Markdown 代码块",
-			response:  "```json\n{\"correctness\": 5, \"clarity\": 5, \"difficulty_match\": 5, \"completeness\": 5}\n```",
-			wantScore: 5.0,
+			name:       "Markdown 代码块",
+			response:   "```json\n{\"correctness\": 5, \"clarity\": 5, \"difficulty_match\": 5, \"completeness\": 5}\n```",
+			wantScore:  5.0,
+			wantParsed: true,
 		},
 		{
 			name:      "无效响应使用默认值",
@@ -39,10 +77,13 @@ func TestLLMJudge_ParseJudgeResponse(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			score := judge.parseJudgeResponse(tt.response)
+			score, parsed := judge.parseJudgeResponse(tt.response)
 			if score.TotalScore != tt.wantScore {
 				t.Errorf("parseJudgeResponse() TotalScore = %v, want %v", score.TotalScore, tt.wantScore)
 			}
+			if parsed != tt.wantParsed {
+				t.Errorf("parseJudgeResponse() parsed = %v, want %v", parsed, tt.wantParsed)
+			}
 		})
 	}
 }
@@ -75,7 +116,7 @@ func TestLLMJudge_ComputeMetrics(t *testing.T) {
 		},
 	}
 
-	summary := judge.computeMetrics(results)
+	summary := judge.computeMetrics(results, nil)
 
 	if summary.AverageScore != 3.5 {
 		t.Errorf("computeMetrics() AverageScore = %v, want 3.5", summary.AverageScore)
@@ -88,6 +129,10 @@ func TestLLMJudge_ComputeMetrics(t *testing.T) {
 	if summary.DimensionScores["correctness"] != 3.5 {
 		t.Errorf("computeMetrics() correctness = %v, want 3.5", summary.DimensionScores["correctness"])
 	}
+
+	if h := summary.ScoreHistogram; h == nil || fmt.Sprint(h.Counts) != "[0 1 0 1 0]" {
+		t.Errorf("computeMetrics() ScoreHistogram = %+v, want judge buckets [0 1 0 1 0]", h)
+	}
 }
 
 func TestWinRateEvaluator_ParseCompareResponse(t *testing.T) {
@@ -143,6 +188,190 @@ func TestWinRateEvaluator_ParseCompareResponse(t *testing.T) {
 	}
 }
 
+// positionLLM 按位置或内容选择胜者的测试 LLM 提供商
+type positionLLM struct {
+	mockLLM
+	// preferCandidate 为 true 时选择候选题目所在位置，否则总是选择 A
+	preferCandidate bool
+}
+
+func (m *positionLLM) Generate(ctx context.Context, req llm.Request) (llm.Response, error) {
+	winner := "A"
+	if m.preferCandidate {
+		prompt := req.Messages[len(req.Messages)-1].Content
+		problemA := prompt[:strings.Index(prompt, "## 题目 B")]
+		if strings.Contains(problemA, "reference-") {
+			winner = "B"
+		}
+	}
+	return llm.Response{Content: "Winner: " + winner + "\nReason: test"}, nil
+}
+
+// writeReferenceDataset 写入 n 个参考样本的测试数据集
+func writeReferenceDataset(t *testing.T, n int) *Dataset {
+	t.Helper()
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, `{"id": "r%d", "question": "reference-%d", "answer": "%d"}`+"\n", i, i, i)
+	}
+	path := filepath.Join(t.TempDir(), "reference.jsonl")
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		t.Fatalf("failed to write dataset: %v", err)
+	}
+	return NewDataset(path)
+}
+
+func TestWinRateEvaluator_Evaluate(t *testing.T) {
+	const n = 8
+
+	// 总是偏好候选题目时胜率为 100%
+	evaluator := NewWinRateEvaluator(&positionLLM{preferCandidate: true},
+		writeJudgeDataset(t, n), writeReferenceDataset(t, n), WinRateConfig{RandomSeed: 7})
+	result, err := evaluator.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.Metrics.WinRate != 1.0 || result.Metrics.Extra["wins"] != n {
+		t.Errorf("WinRate = %v, wins = %v, want 100%% of %d", result.Metrics.WinRate, result.Metrics.Extra["wins"], n)
+	}
+	for i, r := range result.DetailedResults {
+		comparison, ok := r.Details["comparison"].(*evaluation.ComparisonResult)
+		if !ok {
+			t.Fatalf("results[%d] missing comparison details: %v", i, r.Details)
+		}
+		if comparison.ProblemAID != fmt.Sprintf("p%d", i) || comparison.ProblemBID != fmt.Sprintf("r%d", i) {
+			t.Errorf("results[%d] paired %s vs %s", i, comparison.ProblemAID, comparison.ProblemBID)
+		}
+	}
+
+	// 总是选择 A 时胜负由位置随机化决定
+	evaluator = NewWinRateEvaluator(&positionLLM{},
+		writeJudgeDataset(t, n), writeReferenceDataset(t, n), WinRateConfig{RandomSeed: 7})
+	result, err = evaluator.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	unswapped := 0
+	for _, r := range result.DetailedResults {
+		if swapped, _ := r.Details["swapped"].(bool); !swapped {
+			unswapped++
+		}
+	}
+	if want := float64(unswapped) / n; result.Metrics.WinRate != want || result.Metrics.LossRate != 1-want {
+		t.Errorf("WinRate = %v, LossRate = %v, want %v / %v", result.Metrics.WinRate, result.Metrics.LossRate, want, 1-want)
+	}
+}
+
+func TestWinRateEvaluator_DeterministicSwaps(t *testing.T) {
+	const n = 32
+	ctx := context.Background()
+
+	swapsInOrder := func(order []int) map[int]bool {
+		evaluator := NewWinRateEvaluator(&positionLLM{}, nil, nil, WinRateConfig{RandomSeed: 42})
+		swaps := make(map[int]bool, n)
+		for _, i := range order {
+			candidate := evaluation.Sample{ID: fmt.Sprintf("p%d", i), Input: "candidate"}
+			reference := evaluation.Sample{ID: fmt.Sprintf("r%d", i), Input: "reference"}
+			result, err := evaluator.CompareSamplesAt(ctx, i, candidate, reference)
+			if err != nil {
+				t.Fatalf("CompareSamplesAt() error = %v", err)
+			}
+			comparison := result.Predicted.(*evaluation.ComparisonResult)
+			if comparison.Swapped != result.Details["swapped"] {
+				t.Errorf("pair %d: ComparisonResult.Swapped disagrees with details", i)
+			}
+			swaps[i] = comparison.Swapped
+		}
+		return swaps
+	}
+
+	forward := make([]int, n)
+	reverse := make([]int, n)
+	for i := 0; i < n; i++ {
+		forward[i] = i
+		reverse[i] = n - 1 - i
+	}
+
+	first, second := swapsInOrder(forward), swapsInOrder(reverse)
+	swapped := 0
+	for i := 0; i < n; i++ {
+		if first[i] != second[i] {
+			t.Errorf("pair %d: swap differs between evaluation orders", i)
+		}
+		if first[i] {
+			swapped++
+		}
+	}
+	if swapped == 0 || swapped == n {
+		t.Errorf("expected mixed positions, got %d/%d swapped", swapped, n)
+	}
+}
+
+func TestWinRateEvaluator_CompareSamples(t *testing.T) {
+	ctx := context.Background()
+	evaluator := NewWinRateEvaluator(&positionLLM{}, nil, nil, WinRateConfig{RandomSeed: 42})
+
+	// 不带索引的比较按样本 ID 派生位置，同一对样本结果稳定
+	swaps := make(map[string]bool)
+	swapped := 0
+	for i := 0; i < 16; i++ {
+		candidate := evaluation.Sample{ID: fmt.Sprintf("p%d", i), Input: "candidate"}
+		reference := evaluation.Sample{ID: fmt.Sprintf("r%d", i), Input: "reference"}
+		for pass := 0; pass < 2; pass++ {
+			result, err := evaluator.CompareSamples(ctx, candidate, reference)
+			if err != nil {
+				t.Fatalf("CompareSamples() error = %v", err)
+			}
+			got := result.Details["swapped"].(bool)
+			if prev, ok := swaps[candidate.ID]; ok && prev != got {
+				t.Errorf("pair %s: swap differs between calls", candidate.ID)
+			}
+			swaps[candidate.ID] = got
+		}
+		if swaps[candidate.ID] {
+			swapped++
+		}
+	}
+	if swapped == 0 || swapped == 16 {
+		t.Errorf("expected mixed positions, got %d/16 swapped", swapped)
+	}
+}
+
+func TestWinRateEvaluator_DoublePass(t *testing.T) {
+	const n = 6
+
+	// 总是选择第一个位置的评委在双轮评估下两轮必然不一致
+	evaluator := NewWinRateEvaluator(&positionLLM{},
+		writeJudgeDataset(t, n), writeReferenceDataset(t, n), WinRateConfig{RandomSeed: 3, DoublePass: true})
+	result, err := evaluator.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.Metrics.TieRate != 1.0 || result.SuccessCount != 0 {
+		t.Errorf("TieRate = %v, SuccessCount = %d, want all ties", result.Metrics.TieRate, result.SuccessCount)
+	}
+	for i, r := range result.DetailedResults {
+		comparison := r.Predicted.(*evaluation.ComparisonResult)
+		if comparison.Details["first_pass_winner"] != "A" || comparison.Details["second_pass_winner"] != "A" {
+			t.Errorf("results[%d] raw verdicts = %v, want A and A", i, comparison.Details)
+		}
+		if comparison.Details["passes_agree"] != false {
+			t.Errorf("results[%d] expected passes to disagree", i)
+		}
+	}
+
+	// 偏好候选题目的评委两轮一致，仍计为胜出
+	evaluator = NewWinRateEvaluator(&positionLLM{preferCandidate: true},
+		writeJudgeDataset(t, n), writeReferenceDataset(t, n), WinRateConfig{RandomSeed: 3, DoublePass: true})
+	result, err = evaluator.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.Metrics.WinRate != 1.0 {
+		t.Errorf("WinRate = %v, want 1.0 when both passes agree", result.Metrics.WinRate)
+	}
+}
+
 func TestWinRateEvaluator_ComputeMetrics(t *testing.T) {
 	evaluator := &WinRateEvaluator{}
 
@@ -172,3 +401,335 @@ func TestNewDataset(t *testing.T) {
 		t.Errorf("NewDataset() dataPath = %s, want /tmp/data.jsonl", dataset.dataPath)
 	}
 }
+
+func TestDataset_StrictAndValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	content := `{"id": "1", "problem": "p1", "solution": "s1"}
+{"id": "2", "problem": "p2", "solution":
+{"id": "3", "content": "c3"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write data: %v", err)
+	}
+
+	lenient := NewDataset(path)
+	if err := lenient.Load(context.Background()); err != nil || lenient.Len() != 2 {
+		t.Fatalf("lenient Load() = %d samples, %v; want 2, nil", lenient.Len(), err)
+	}
+
+	strict := NewDataset(path)
+	strict.SetStrict(true)
+	var lineErr *loader.LineError
+	if err := strict.Load(context.Background()); !errors.As(err, &lineErr) || lineErr.Line != 2 {
+		t.Fatalf("strict Load() error = %v, want LineError on line 2", err)
+	}
+
+	report, err := ValidateDataset(context.Background(), path)
+	if err != nil {
+		t.Fatalf("ValidateDataset() error = %v", err)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Line != 2 {
+		t.Errorf("Errors = %v, want one error on line 2", report.Errors)
+	}
+	if report.MissingFields["answer"] != 1 || report.MissingFields["question"] != 0 {
+		t.Errorf("MissingFields = %v, want answer: 1", report.MissingFields)
+	}
+}
+
+func TestLLMJudge_DimensionFloors(t *testing.T) {
+	provider := &mockLLM{
+		content: `{"correctness": 2, "clarity": 5, "difficulty_match": 5, "completeness": 5}`,
+	}
+	sample := evaluation.Sample{ID: "q1", Input: "1+1=?", Expected: "2"}
+
+	// 无最低分时平均分 4.25 通过
+	judge := NewLLMJudge(provider, nil, JudgeConfig{})
+	result, err := judge.EvaluateSample(context.Background(), sample, nil)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected sample to pass without floors, score = %v", result.Score)
+	}
+
+	// 正确性低于最低分时高清晰度无法挽救
+	judge = NewLLMJudge(provider, nil, JudgeConfig{
+		DimensionFloors: map[string]float64{"correctness": 3, "clarity": 3},
+	})
+	result, err = judge.EvaluateSample(context.Background(), sample, nil)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if result.Success {
+		t.Error("expected sample to fail on correctness floor")
+	}
+	failed, ok := result.Details["failed_floors"].([]string)
+	if !ok || len(failed) != 1 || failed[0] != "correctness" {
+		t.Errorf("failed_floors = %v, want [correctness]", result.Details["failed_floors"])
+	}
+}
+
+func TestLLMJudge_CustomDimensions(t *testing.T) {
+	provider := &mockLLM{
+		content: "```json\n{\"accuracy\": 5, \"style\": 2, \"comments\": \"ok\"}\n```",
+	}
+	judge := NewLLMJudge(provider, nil, JudgeConfig{
+		Dimensions: []Dimension{
+			{Name: "accuracy", Description: "答案是否准确", Weight: 3},
+			{Name: "style", Description: "表述是否简洁", Weight: 1},
+		},
+	})
+
+	prompt := judge.getSystemPrompt()
+	for _, want := range []string{"accuracy", "答案是否准确", `"style": <1-5>`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("system prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "correctness") {
+		t.Errorf("system prompt should not mention default dimensions:\n%s", prompt)
+	}
+
+	sample := evaluation.Sample{ID: "q1", Input: "1+1=?", Expected: "2"}
+	result, err := judge.EvaluateSample(context.Background(), sample, nil)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+
+	// (5*3 + 2*1) / 4
+	if want := 4.25; result.Score != want {
+		t.Errorf("Score = %v, want %v", result.Score, want)
+	}
+	if result.Details["accuracy"] != 5.0 || result.Details["style"] != 2.0 {
+		t.Errorf("unexpected dimension details: %v", result.Details)
+	}
+
+	summary := judge.computeMetrics([]*evaluation.SampleResult{result}, nil)
+	if summary.DimensionScores["style"] != 2.0 || len(summary.DimensionScores) != 2 {
+		t.Errorf("DimensionScores = %v, want accuracy and style only", summary.DimensionScores)
+	}
+}
+
+func TestLLMJudge_BuildJudgePrompt_StructuredExpected(t *testing.T) {
+	judge := &LLMJudge{}
+
+	sample := evaluation.Sample{
+		ID:       "q1",
+		Input:    "查询北京天气",
+		Expected: map[string]interface{}{"name": "get_weather", "arguments": map[string]interface{}{"city": "北京"}},
+	}
+	prompt := judge.buildJudgePrompt(sample, nil)
+	if want := `**答案**: {"arguments":{"city":"北京"},"name":"get_weather"}`; !strings.Contains(prompt, want) {
+		t.Errorf("prompt missing serialized answer %q:\n%s", want, prompt)
+	}
+
+	sample.Expected = nil
+	if prompt := judge.buildJudgePrompt(sample, nil); strings.Contains(prompt, "**答案**") {
+		t.Errorf("prompt should omit nil answer:\n%s", prompt)
+	}
+
+	ref := evaluation.Sample{Input: "1+1=?", Expected: 2}
+	if prompt := judge.buildJudgePrompt(sample, &ref); !strings.Contains(prompt, "**答案**: 2") {
+		t.Errorf("prompt missing numeric reference answer:\n%s", prompt)
+	}
+}
+
+// sequenceLLM 按顺序返回预设内容并记录请求的测试 LLM 提供商
+type sequenceLLM struct {
+	mockLLM
+	contents []string
+	requests []llm.Request
+}
+
+func (m *sequenceLLM) Generate(ctx context.Context, req llm.Request) (llm.Response, error) {
+	content := m.contents[min(len(m.requests), len(m.contents)-1)]
+	m.requests = append(m.requests, req)
+	return llm.Response{Content: content}, nil
+}
+
+func TestLLMJudge_ParseRetry(t *testing.T) {
+	sample := evaluation.Sample{ID: "q1", Input: "1+1=?", Expected: "2"}
+	prose := "这道题整体质量不错，我给出较高的评分。"
+
+	provider := &sequenceLLM{contents: []string{
+		prose,
+		`{"correctness": 5, "clarity": 5, "difficulty_match": 4, "completeness": 4}`,
+	}}
+	judge := NewLLMJudge(provider, nil, JudgeConfig{})
+	result, err := judge.EvaluateSample(context.Background(), sample, nil)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if len(provider.requests) != 2 {
+		t.Fatalf("expected 2 judge calls, got %d", len(provider.requests))
+	}
+	retry := provider.requests[1].Messages
+	if last := retry[len(retry)-1].Content; last != jsonOnlyReprompt || retry[len(retry)-2].Content != prose {
+		t.Errorf("unexpected reprompt messages: %v", retry)
+	}
+	if result.Score != 4.5 {
+		t.Errorf("Score = %v, want 4.5 from second attempt", result.Score)
+	}
+	if result.Details["parse_reprompted"] != true || result.Details["parse_failed"] != false {
+		t.Errorf("unexpected parse details: %v", result.Details)
+	}
+
+	// 重试仍失败时回退默认分数并记录
+	provider = &sequenceLLM{contents: []string{prose}}
+	judge = NewLLMJudge(provider, nil, JudgeConfig{MaxParseRetries: 2})
+	result, err = judge.EvaluateSample(context.Background(), sample, nil)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if len(provider.requests) != 3 || result.Score != defaultDimensionScore {
+		t.Errorf("expected 3 calls and default score, got %d calls, score %v", len(provider.requests), result.Score)
+	}
+	if result.Details["parse_failed"] != true || result.Details["parse_retries"] != 2 {
+		t.Errorf("unexpected parse details: %v", result.Details)
+	}
+
+	// 禁用重试
+	provider = &sequenceLLM{contents: []string{prose}}
+	judge = NewLLMJudge(provider, nil, JudgeConfig{MaxParseRetries: -1})
+	if _, err := judge.EvaluateSample(context.Background(), sample, nil); err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if len(provider.requests) != 1 {
+		t.Errorf("expected no reprompt when disabled, got %d calls", len(provider.requests))
+	}
+}
+
+// slowLLM 延迟响应并回显用户提示的测试 LLM 提供商
+type slowLLM struct {
+	mockLLM
+	delay time.Duration
+}
+
+func (m *slowLLM) Generate(ctx context.Context, req llm.Request) (llm.Response, error) {
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return llm.Response{}, ctx.Err()
+	}
+	prompt := req.Messages[len(req.Messages)-1].Content
+	comments, _ := json.Marshal(prompt)
+	return llm.Response{Content: fmt.Sprintf(`{"correctness": 4, "clarity": 4, "difficulty_match": 4, "completeness": 4, "comments": %s}`, comments)}, nil
+}
+
+// writeJudgeDataset 写入 n 个样本的测试数据集
+func writeJudgeDataset(t *testing.T, n int) *Dataset {
+	t.Helper()
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, `{"id": "p%d", "question": "question-%d", "answer": "%d"}`+"\n", i, i, i)
+	}
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		t.Fatalf("failed to write dataset: %v", err)
+	}
+	return NewDataset(path)
+}
+
+func TestLLMJudge_Evaluate_Concurrency(t *testing.T) {
+	const n = 8
+	refs := make([]evaluation.Sample, n)
+	for i := range refs {
+		refs[i] = evaluation.Sample{ID: fmt.Sprintf("r%d", i), Input: fmt.Sprintf("reference-%d", i)}
+	}
+	provider := &slowLLM{delay: 50 * time.Millisecond}
+
+	var progress []int
+	judge := NewLLMJudge(provider, writeJudgeDataset(t, n), JudgeConfig{ReferenceSamples: refs})
+	start := time.Now()
+	result, err := judge.Evaluate(context.Background(),
+		evaluation.WithConcurrency(4),
+		evaluation.WithProgressCallback(func(done, total int) { progress = append(progress, done) }))
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if elapsed >= n*provider.delay {
+		t.Errorf("expected concurrent judging to beat sequential %v, took %v", n*provider.delay, elapsed)
+	}
+	if len(result.DetailedResults) != n {
+		t.Fatalf("expected %d results, got %d", n, len(result.DetailedResults))
+	}
+	for i, r := range result.DetailedResults {
+		if want := fmt.Sprintf("p%d", i); r.SampleID != want {
+			t.Errorf("results[%d].SampleID = %s, want %s", i, r.SampleID, want)
+		}
+		comments, _ := r.Details["comments"].(string)
+		if !strings.Contains(comments, fmt.Sprintf("question-%d\n", i)) || !strings.Contains(comments, fmt.Sprintf("reference-%d\n", i)) {
+			t.Errorf("results[%d] paired with wrong sample or reference: %q", i, comments)
+		}
+	}
+	if len(progress) != n || progress[n-1] != n {
+		t.Errorf("unexpected progress calls: %v", progress)
+	}
+}
+
+// cancelLLM 评估到指定问题时取消上下文的测试 LLM 提供商
+type cancelLLM struct {
+	mockLLM
+	cancelOn string
+	cancel   context.CancelFunc
+}
+
+func (m *cancelLLM) Generate(ctx context.Context, req llm.Request) (llm.Response, error) {
+	if strings.Contains(req.Messages[len(req.Messages)-1].Content, m.cancelOn) {
+		m.cancel()
+		return llm.Response{}, ctx.Err()
+	}
+	return m.mockLLM.Generate(ctx, req)
+}
+
+func TestLLMJudge_Evaluate_Canceled(t *testing.T) {
+	const n = 5
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	provider := &cancelLLM{
+		mockLLM:  mockLLM{content: `{"correctness": 4, "clarity": 4, "difficulty_match": 4, "completeness": 4}`},
+		cancelOn: "question-2\n",
+		cancel:   cancel,
+	}
+
+	judge := NewLLMJudge(provider, writeJudgeDataset(t, n), JudgeConfig{})
+	result, err := judge.Evaluate(ctx, evaluation.WithConcurrency(1))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Evaluate() error = %v, want context.Canceled", err)
+	}
+
+	// 提前终止时仍按已完成的样本计算指标
+	completed := len(result.DetailedResults)
+	if completed == 0 || completed >= n || result.TotalSamples != completed {
+		t.Fatalf("TotalSamples = %d with %d completed results, want completed count below %d",
+			result.TotalSamples, completed, n)
+	}
+	if result.Metrics == nil || result.TotalDuration <= 0 {
+		t.Fatalf("expected metrics and duration, got %+v, %v", result.Metrics, result.TotalDuration)
+	}
+	if got := result.Metrics.DimensionScores["correctness"]; got <= 0 {
+		t.Errorf("DimensionScores[correctness] = %v, want positive average", got)
+	}
+	if want := float64(result.SuccessCount) / float64(completed); result.OverallAccuracy != want {
+		t.Errorf("OverallAccuracy = %v, want %v", result.OverallAccuracy, want)
+	}
+}
+
+func TestLLMJudge_Evaluate_Timeout(t *testing.T) {
+	judge := NewLLMJudge(&slowLLM{delay: time.Second}, writeJudgeDataset(t, 2), JudgeConfig{})
+	result, err := judge.Evaluate(context.Background(),
+		evaluation.WithConcurrency(2), evaluation.WithTimeout(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	for i, r := range result.DetailedResults {
+		if r.Error == "" || !evaluation.IsTimeoutResult(r) {
+			t.Errorf("results[%d]: expected timeout error, got %+v", i, r)
+		}
+	}
+	if got := result.Metrics.Extra["timeout_count"]; got != 2 {
+		t.Errorf("timeout_count = %v, want 2", got)
+	}
+}
@@ -6,14 +6,13 @@
 package datagen
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation/loader"
 )
 
 // Dataset 数据生成评估数据集
@@ -26,12 +25,15 @@ type Dataset struct {
 
 	// loaded 是否已加载
 	loaded bool
+
+	// strict 是否严格加载（遇到无法解析的行即报错）
+	strict bool
 }
 
 // NewDataset 创建数据生成评估数据集
 //
 // 参数:
-//   - dataPath: 数据文件路径（JSONL 格式）
+//   - dataPath: 数据文件路径（JSONL、JSON 数组或带表头的 CSV，自动识别）
 func NewDataset(dataPath string) *Dataset {
 	return &Dataset{
 		dataPath: dataPath,
@@ -39,6 +41,27 @@ func NewDataset(dataPath string) *Dataset {
 	}
 }
 
+// SetStrict 设置严格加载模式
+//
+// 开启后 Load 遇到第一条无法解析的行即返回 *loader.LineError，而不是跳过；
+// 需在 Load 之前调用。
+func (d *Dataset) SetStrict(strict bool) {
+	d.strict = strict
+}
+
+// requiredFields 数据生成评估数据的必填字段
+var requiredFields = []loader.Field{
+	{Name: "question", Keys: []string{"question", "content", "problem"}},
+	{Name: "answer", Keys: []string{"answer", "solution"}},
+}
+
+// ValidateDataset 校验数据生成评估数据文件
+//
+// 报告无法解析的行号与原因，以及缺少问题或答案字段的记录数。
+func ValidateDataset(ctx context.Context, path string) (*loader.ValidationReport, error) {
+	return loader.Validate(ctx, path, requiredFields...)
+}
+
 // Load 加载数据集
 func (d *Dataset) Load(ctx context.Context) error {
 	if d.loaded {
@@ -49,40 +72,28 @@ func (d *Dataset) Load(ctx context.Context) error {
 		return fmt.Errorf("数据文件不存在: %s", d.dataPath)
 	}
 
-	file, err := os.Open(d.dataPath)
+	load := loader.LoadRecords
+	if d.strict {
+		load = loader.LoadRecordsStrict
+	}
+	records, err := load(d.dataPath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
-
-	idx := 0
-	for scanner.Scan() {
+	for idx, item := range records {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
-		line := scanner.Text()
-		if line == "" {
-			continue
-		}
-
-		var item map[string]interface{}
-		if err := json.Unmarshal([]byte(line), &item); err != nil {
-			continue
-		}
-
 		sample := d.parseItem(item, idx)
 		d.samples = append(d.samples, sample)
-		idx++
 	}
 
 	d.loaded = true
-	return scanner.Err()
+	return nil
 }
 
 // parseItem 解析单个数据项
@@ -74,6 +74,18 @@ func (e *Exporter) ExportJudgeReport(result *evaluation.EvalResult, outputPath s
 		fmt.Fprintf(file, "\n")
 	}
 
+	// 分数分布
+	if result.Metrics != nil && result.Metrics.ScoreHistogram != nil {
+		fmt.Fprintf(file, "## 分数分布\n\n")
+		fmt.Fprintf(file, "%s\n", result.Metrics.ScoreHistogram.Markdown())
+	}
+
+	// 错误分类
+	if breakdown := evaluation.ErrorBreakdownOf(result.Metrics); breakdown != nil {
+		fmt.Fprintf(file, "## 错误分类\n\n")
+		fmt.Fprintf(file, "%s\n", breakdown.Markdown())
+	}
+
 	// 低分样本
 	var lowScoreSamples []*evaluation.SampleResult
 	for _, sr := range result.DetailedResults {
@@ -185,6 +197,51 @@ func (e *Exporter) ExportWinRateReport(result *evaluation.EvalResult, outputPath
 	return nil
 }
 
+// ExportTournamentReport 导出锦标赛排名报告
+func (e *Exporter) ExportTournamentReport(result *evaluation.EvalResult, outputPath string) error {
+	// 确保目录存在
+	dir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer file.Close()
+
+	// 写入报告头
+	fmt.Fprintf(file, "# 锦标赛排名报告\n\n")
+	fmt.Fprintf(file, "## 概览\n\n")
+	fmt.Fprintf(file, "- **评估器**: %s\n", result.BenchmarkName)
+	fmt.Fprintf(file, "- **LLM**: %s\n", result.AgentName)
+	fmt.Fprintf(file, "- **评估时间**: %s\n", result.EvaluationTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(file, "- **总耗时**: %s\n", result.TotalDuration)
+	fmt.Fprintf(file, "- **对比次数**: %d\n", len(result.DetailedResults))
+	if result.Metrics != nil {
+		if k, ok := result.Metrics.Extra["k_factor"].(float64); ok {
+			fmt.Fprintf(file, "- **K 系数**: %.0f\n", k)
+		}
+	}
+	fmt.Fprintf(file, "\n")
+
+	// 排名表
+	fmt.Fprintf(file, "## Elo 排名\n\n")
+	if result.Metrics != nil {
+		if ratings, ok := result.Metrics.Extra["ratings"].([]Rating); ok {
+			fmt.Fprintf(file, "| 排名 | 数据集 | Elo | 胜 | 负 | 平 |\n")
+			fmt.Fprintf(file, "|------|--------|-----|----|----|----|\n")
+			for i, r := range ratings {
+				fmt.Fprintf(file, "| %d | %s | %.1f | %d | %d | %d |\n", i+1, r.Name, r.Rating, r.Wins, r.Losses, r.Ties)
+			}
+			fmt.Fprintf(file, "\n")
+		}
+	}
+
+	return nil
+}
+
 // ExportJSON 导出 JSON 格式结果
 func (e *Exporter) ExportJSON(result *evaluation.EvalResult, outputPath string) error {
 	// 确保目录存在
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ahhsitt/helloagents-go/pkg/core/llm"
@@ -12,10 +14,54 @@ import (
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
 )
 
+// Dimension LLM Judge 评分维度
+type Dimension struct {
+	// Name 维度名称，同时作为评委返回 JSON 中的键
+	Name string `json:"name"`
+
+	// Description 维度说明（写入系统提示）
+	Description string `json:"description"`
+
+	// Weight 计算总分时的权重（小于等于 0 时按 1 处理）
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// DefaultDimensions 默认评分维度
+var DefaultDimensions = []Dimension{
+	{Name: "correctness", Description: "正确性，题目和答案是否正确", Weight: 1},
+	{Name: "clarity", Description: "清晰度，题目描述是否清晰、无歧义", Weight: 1},
+	{Name: "difficulty_match", Description: "难度匹配，题目难度是否与标注一致", Weight: 1},
+	{Name: "completeness", Description: "完整性，题目信息是否完整", Weight: 1},
+}
+
+// defaultDimensionScore 评委未返回某维度时使用的默认分数
+const defaultDimensionScore = 3.0
+
+// defaultMaxParseRetries 评委响应解析失败时的默认重新提示次数
+const defaultMaxParseRetries = 1
+
+// jsonOnlyReprompt 解析失败后追加的重新提示
+const jsonOnlyReprompt = "你的上一条回复不是合法的 JSON。请只返回合法的 JSON 评分结果，不要包含任何其他文字。"
+
 // JudgeConfig LLM Judge 配置
 type JudgeConfig struct {
 	// ReferenceSamples 参考样本（用于对比评估）
 	ReferenceSamples []evaluation.Sample
+
+	// Dimensions 评分维度（为空时使用 DefaultDimensions）
+	//
+	// 系统提示和解析的 JSON 键按维度动态生成，总分为各维度的加权平均。
+	Dimensions []Dimension
+
+	// DimensionFloors 各维度最低分（如 {"correctness": 3}）
+	//
+	// 样本需同时满足总分阈值和所有维度最低分才视为通过。
+	DimensionFloors map[string]float64
+
+	// MaxParseRetries 评委响应无法解析为 JSON 时重新提示的最大次数
+	//
+	// 为 0 时使用默认值 1，小于 0 时不重试。重试仍失败时使用默认分数。
+	MaxParseRetries int
 }
 
 // LLMJudge LLM 评委评估器
@@ -50,6 +96,9 @@ func (j *LLMJudge) Name() string {
 }
 
 // Evaluate 执行完整评估
+//
+// ctx 取消或 FailFast 提前终止时，返回已完成样本的结果及对应错误：TotalSamples、
+// 准确率和各项指标均只统计已完成的样本。
 func (j *LLMJudge) Evaluate(ctx context.Context, opts ...evaluation.EvalOption) (*evaluation.EvalResult, error) {
 	config := evaluation.DefaultEvalConfig()
 	config.ApplyOptions(opts...)
@@ -65,6 +114,7 @@ func (j *LLMJudge) Evaluate(ctx context.Context, opts ...evaluation.EvalOption)
 		AgentName:       j.llmProvider.Name(),
 		DetailedResults: make([]*evaluation.SampleResult, 0),
 		EvaluationTime:  startTime,
+		RunConfig:       config.Summary(),
 	}
 
 	total := j.dataset.Len()
@@ -73,53 +123,37 @@ func (j *LLMJudge) Evaluate(ctx context.Context, opts ...evaluation.EvalOption)
 	}
 	result.TotalSamples = total
 
-	// 遍历样本进行评估
-	for i := 0; i < total; i++ {
-		select {
-		case <-ctx.Done():
-			return result, ctx.Err()
-		default:
-		}
-
-		sample, err := j.dataset.Get(i)
-		if err != nil {
-			continue
-		}
-
-		// 应用超时
-		evalCtx := ctx
-		if config.Timeout > 0 {
-			var cancel context.CancelFunc
-			evalCtx, cancel = context.WithTimeout(ctx, config.Timeout)
-			defer cancel()
-		}
-
-		// 获取参考样本（如果有）
-		var refSample *evaluation.Sample
-		if i < len(j.config.ReferenceSamples) {
-			ref := j.config.ReferenceSamples[i]
-			refSample = &ref
-		}
+	// 并发评估样本（结果保持样本顺序，参考样本按数据集索引配对）
+	sampleResults, runErr := evaluation.RunIndexedSamples(ctx, config, j.dataset, total,
+		func(ctx context.Context, index int, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+			// 获取参考样本（如果有）
+			var refSample *evaluation.Sample
+			if index < len(j.config.ReferenceSamples) {
+				ref := j.config.ReferenceSamples[index]
+				refSample = &ref
+			}
 
-		sampleResult, err := j.EvaluateSample(evalCtx, sample, refSample)
-		if err != nil {
-			sampleResult = &evaluation.SampleResult{
-				SampleID: sample.ID,
-				Category: sample.Category,
-				Error:    err.Error(),
-				Success:  false,
+			sampleResult, err := j.EvaluateSample(ctx, sample, refSample)
+			if err != nil {
+				return &evaluation.SampleResult{
+					SampleID: sample.ID,
+					Category: sample.Category,
+					Error:    err.Error(),
+					Success:  false,
+				}, err
 			}
-		}
+			return sampleResult, nil
+		})
 
-		result.DetailedResults = append(result.DetailedResults, sampleResult)
-		if sampleResult.Success {
+	result.DetailedResults = append(result.DetailedResults, sampleResults...)
+	for _, r := range sampleResults {
+		if r.Success {
 			result.SuccessCount++
 		}
-
-		// 进度回调
-		if config.ProgressCallback != nil {
-			config.ProgressCallback(i+1, total)
-		}
+	}
+	// 提前终止（ctx 取消或 FailFast）时只统计已完成的样本
+	if runErr != nil {
+		result.TotalSamples = len(sampleResults)
 	}
 
 	result.TotalDuration = time.Since(startTime)
@@ -128,9 +162,9 @@ func (j *LLMJudge) Evaluate(ctx context.Context, opts ...evaluation.EvalOption)
 	}
 
 	// 计算汇总指标
-	result.Metrics = j.computeMetrics(result.DetailedResults)
+	result.Metrics = j.computeMetrics(result.DetailedResults, config.ScoreHistogramEdges)
 
-	return result, nil
+	return result, runErr
 }
 
 // EvaluateSample 评估单个样本
@@ -161,53 +195,115 @@ func (j *LLMJudge) EvaluateSample(ctx context.Context, sample evaluation.Sample,
 		return result, nil
 	}
 
+	// 解析评分，失败时要求评委只返回 JSON 后重试
+	score, parsed := j.parseJudgeResponse(resp.Content)
+	reprompts := 0
+	for !parsed && reprompts < j.maxParseRetries() {
+		reprompts++
+		req.Messages = append(req.Messages,
+			message.NewAssistantMessage(resp.Content),
+			message.NewUserMessage(jsonOnlyReprompt),
+		)
+		resp, err = j.llmProvider.Generate(ctx, req)
+		if err != nil {
+			result.Error = err.Error()
+			result.ExecutionTime = time.Since(startTime)
+			return result, nil
+		}
+		score, parsed = j.parseJudgeResponse(resp.Content)
+	}
+
 	result.AgentResponse = resp.Content
 	result.ExecutionTime = time.Since(startTime)
+	result.Details["parse_reprompted"] = reprompts > 0
+	result.Details["parse_failed"] = !parsed
+	if reprompts > 0 {
+		result.Details["parse_retries"] = reprompts
+	}
 
-	// 解析评分
-	score := j.parseJudgeResponse(resp.Content)
 	result.Predicted = score
 	result.Details["judge_score"] = score
 
 	// 计算总分和成功判断
-	totalScore := (score.Correctness + score.Clarity + score.DifficultyMatch + score.Completeness) / 4.0
+	totalScore := score.TotalScore
 	result.Score = totalScore
 	result.Success = totalScore >= 3.0 // 平均分 >= 3 认为通过
 
 	result.Details["total_score"] = totalScore
-	result.Details["correctness"] = score.Correctness
-	result.Details["clarity"] = score.Clarity
-	result.Details["difficulty_match"] = score.DifficultyMatch
-	result.Details["completeness"] = score.Completeness
+	for name, value := range score.Scores {
+		result.Details[name] = value
+	}
 	result.Details["comments"] = score.Comments
 
+	// 检查维度最低分
+	if failed := j.checkDimensionFloors(score); len(failed) > 0 {
+		result.Success = false
+		result.Details["failed_floors"] = failed
+	}
+
 	return result, nil
 }
 
+// dimensions 返回生效的评分维度
+func (j *LLMJudge) dimensions() []Dimension {
+	if len(j.config.Dimensions) > 0 {
+		return j.config.Dimensions
+	}
+	return DefaultDimensions
+}
+
+// maxParseRetries 返回生效的解析失败重试次数
+func (j *LLMJudge) maxParseRetries() int {
+	switch {
+	case j.config.MaxParseRetries < 0:
+		return 0
+	case j.config.MaxParseRetries == 0:
+		return defaultMaxParseRetries
+	default:
+		return j.config.MaxParseRetries
+	}
+}
+
+// checkDimensionFloors 返回低于最低分的维度名称
+func (j *LLMJudge) checkDimensionFloors(score evaluation.JudgeScore) []string {
+	if len(j.config.DimensionFloors) == 0 {
+		return nil
+	}
+
+	var failed []string
+	for name, floor := range j.config.DimensionFloors {
+		if value, ok := score.Scores[name]; ok && value < floor {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+
+	return failed
+}
+
 // getSystemPrompt 获取系统提示
 func (j *LLMJudge) getSystemPrompt() string {
-	return `你是一个专业的题目质量评估专家。请根据以下维度对给定的题目进行评分（1-5分）：
-
-1. 正确性 (Correctness): 题目和答案是否正确
-2. 清晰度 (Clarity): 题目描述是否清晰、无歧义
-3. 难度匹配 (Difficulty Match): 题目难度是否与标注一致
-4. 完整性 (Completeness): 题目信息是否完整
-
-请以 JSON 格式返回评分结果：
-{
-  "correctness": <1-5>,
-  "clarity": <1-5>,
-  "difficulty_match": <1-5>,
-  "completeness": <1-5>,
-  "comments": "<评价说明>"
-}`
+	dims := j.dimensions()
+
+	var sb strings.Builder
+	sb.WriteString("你是一个专业的题目质量评估专家。请根据以下维度对给定的题目进行评分（1-5分）：\n\n")
+	for i, d := range dims {
+		fmt.Fprintf(&sb, "%d. %s: %s\n", i+1, d.Name, d.Description)
+	}
+	sb.WriteString("\n请以 JSON 格式返回评分结果：\n{\n")
+	for _, d := range dims {
+		fmt.Fprintf(&sb, "  \"%s\": <1-5>,\n", d.Name)
+	}
+	sb.WriteString("  \"comments\": \"<评价说明>\"\n}")
+
+	return sb.String()
 }
 
 // buildJudgePrompt 构建评估提示
 func (j *LLMJudge) buildJudgePrompt(sample evaluation.Sample, refSample *evaluation.Sample) string {
 	prompt := fmt.Sprintf("## 待评估题目\n\n**问题**: %s\n", sample.Input)
 
-	if answer, ok := sample.Expected.(string); ok && answer != "" {
+	if answer := formatExpected(sample.Expected); answer != "" {
 		prompt += fmt.Sprintf("\n**答案**: %s\n", answer)
 	}
 
@@ -217,7 +313,7 @@ func (j *LLMJudge) buildJudgePrompt(sample evaluation.Sample, refSample *evaluat
 
 	if refSample != nil {
 		prompt += fmt.Sprintf("\n---\n\n## 参考题目（用于对比）\n\n**问题**: %s\n", refSample.Input)
-		if answer, ok := refSample.Expected.(string); ok && answer != "" {
+		if answer := formatExpected(refSample.Expected); answer != "" {
 			prompt += fmt.Sprintf("\n**答案**: %s\n", answer)
 		}
 	}
@@ -227,13 +323,38 @@ func (j *LLMJudge) buildJudgePrompt(sample evaluation.Sample, refSample *evaluat
 	return prompt
 }
 
+// formatExpected 将期望答案格式化为提示文本
+//
+// 字符串原样返回，其他类型（如函数调用标注、数值对象）序列化为 JSON，nil 返回空字符串。
+func formatExpected(expected interface{}) string {
+	switch v := expected.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+
+	data, err := json.Marshal(expected)
+	if err != nil {
+		return fmt.Sprintf("%v", expected)
+	}
+	if string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
+
 // parseJudgeResponse 解析评委响应
-func (j *LLMJudge) parseJudgeResponse(response string) evaluation.JudgeScore {
+//
+// 未返回的维度使用默认分数，总分为各维度的加权平均。
+// 第二个返回值表示响应是否为合法 JSON，为 false 时所有维度均为默认分数。
+func (j *LLMJudge) parseJudgeResponse(response string) (evaluation.JudgeScore, bool) {
+	dims := j.dimensions()
 	score := evaluation.JudgeScore{
-		Correctness:     3.0, // 默认分数
-		Clarity:         3.0,
-		DifficultyMatch: 3.0,
-		Completeness:    3.0,
+		Scores: make(map[string]float64, len(dims)),
+	}
+	for _, d := range dims {
+		score.Scores[d.Name] = defaultDimensionScore
 	}
 
 	// 尝试从 Markdown 代码块中提取 JSON
@@ -250,31 +371,45 @@ func (j *LLMJudge) parseJudgeResponse(response string) evaluation.JudgeScore {
 
 	// 尝试解析 JSON
 	var parsed map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonContent), &parsed); err == nil {
-		if v, ok := parsed["correctness"].(float64); ok {
-			score.Correctness = v
-		}
-		if v, ok := parsed["clarity"].(float64); ok {
-			score.Clarity = v
-		}
-		if v, ok := parsed["difficulty_match"].(float64); ok {
-			score.DifficultyMatch = v
-		}
-		if v, ok := parsed["completeness"].(float64); ok {
-			score.Completeness = v
+	err := json.Unmarshal([]byte(jsonContent), &parsed)
+	if err == nil {
+		for _, d := range dims {
+			if v, ok := parsed[d.Name].(float64); ok {
+				score.Scores[d.Name] = v
+			}
 		}
 		if v, ok := parsed["comments"].(string); ok {
 			score.Comments = v
 		}
 	}
 
-	score.TotalScore = (score.Correctness + score.Clarity + score.DifficultyMatch + score.Completeness) / 4.0
+	// 填充默认维度的固定字段
+	score.Correctness = score.Scores["correctness"]
+	score.Clarity = score.Scores["clarity"]
+	score.DifficultyMatch = score.Scores["difficulty_match"]
+	score.Completeness = score.Scores["completeness"]
+
+	// 加权平均
+	var weighted, totalWeight float64
+	for _, d := range dims {
+		weight := d.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		weighted += weight * score.Scores[d.Name]
+		totalWeight += weight
+	}
+	if totalWeight > 0 {
+		score.TotalScore = weighted / totalWeight
+	}
 
-	return score
+	return score, err == nil
 }
 
 // computeMetrics 计算汇总指标
-func (j *LLMJudge) computeMetrics(results []*evaluation.SampleResult) *evaluation.MetricsSummary {
+//
+// histogramEdges 为空时分数直方图使用 DefaultJudgeScoreEdges。
+func (j *LLMJudge) computeMetrics(results []*evaluation.SampleResult, histogramEdges []float64) *evaluation.MetricsSummary {
 	summary := &evaluation.MetricsSummary{
 		DimensionScores: make(map[string]float64),
 		Extra:           make(map[string]interface{}),
@@ -284,24 +419,18 @@ func (j *LLMJudge) computeMetrics(results []*evaluation.SampleResult) *evaluatio
 		return summary
 	}
 
-	var totalCorrectness, totalClarity, totalDifficultyMatch, totalCompleteness float64
+	dims := j.dimensions()
+	dimensionTotals := make(map[string]float64, len(dims))
 	var totalScore float64
 	successCount := 0
 	excellentCount := 0
 
 	for _, r := range results {
 		if r.Details != nil {
-			if v, ok := r.Details["correctness"].(float64); ok {
-				totalCorrectness += v
-			}
-			if v, ok := r.Details["clarity"].(float64); ok {
-				totalClarity += v
-			}
-			if v, ok := r.Details["difficulty_match"].(float64); ok {
-				totalDifficultyMatch += v
-			}
-			if v, ok := r.Details["completeness"].(float64); ok {
-				totalCompleteness += v
+			for _, d := range dims {
+				if v, ok := r.Details[d.Name].(float64); ok {
+					dimensionTotals[d.Name] += v
+				}
 			}
 		}
 		totalScore += r.Score
@@ -321,14 +450,20 @@ func (j *LLMJudge) computeMetrics(results []*evaluation.SampleResult) *evaluatio
 	summary.Accuracy = summary.PassRate
 
 	// 各维度平均分
-	summary.DimensionScores["correctness"] = totalCorrectness / n
-	summary.DimensionScores["clarity"] = totalClarity / n
-	summary.DimensionScores["difficulty_match"] = totalDifficultyMatch / n
-	summary.DimensionScores["completeness"] = totalCompleteness / n
+	for _, d := range dims {
+		summary.DimensionScores[d.Name] = dimensionTotals[d.Name] / n
+	}
 
 	summary.Extra["total_samples"] = len(results)
 	summary.Extra["success_count"] = successCount
 	summary.Extra["excellent_count"] = excellentCount
+	summary.Extra["timeout_count"] = evaluation.CountTimeouts(results)
+	summary.Extra[evaluation.ErrorBreakdownKey] = evaluation.ComputeErrorBreakdown(results)
+
+	if len(histogramEdges) == 0 {
+		histogramEdges = evaluation.DefaultJudgeScoreEdges
+	}
+	summary.ScoreHistogram = evaluation.NewScoreHistogram(results, histogramEdges)
 
 	return summary
 }
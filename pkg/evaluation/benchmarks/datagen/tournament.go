@@ -0,0 +1,295 @@
+package datagen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/core/llm"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+// 锦标赛默认参数
+const (
+	defaultKFactor       = 32.0
+	defaultInitialRating = 1500.0
+)
+
+// ErrNoDatasets 锦标赛未提供任何数据集
+var ErrNoDatasets = errors.New("锦标赛至少需要一个数据集")
+
+// NamedDataset 带名称的数据集（用于锦标赛排名）
+type NamedDataset struct {
+	// Name 数据集名称（排名表中展示）
+	Name string
+
+	// Dataset 数据集
+	Dataset *Dataset
+}
+
+// TournamentConfig 锦标赛配置
+type TournamentConfig struct {
+	// KFactor Elo K 系数（小于等于 0 时使用默认值 32）
+	KFactor float64
+
+	// InitialRating 初始 Elo 分（小于等于 0 时使用默认值 1500）
+	InitialRating float64
+
+	// MaxComparisons 对比次数预算（小于等于 0 时不限制）
+	//
+	// 按样本索引轮转所有数据集对，预算在各数据集对之间均匀分配。
+	MaxComparisons int
+
+	// RandomSeed 随机种子（用于位置随机化，同 WinRateConfig.RandomSeed）
+	RandomSeed int64
+
+	// DoublePass 是否开启双轮评估（同 WinRateConfig.DoublePass）
+	DoublePass bool
+}
+
+// Rating 单个数据集的 Elo 排名结果
+type Rating struct {
+	// Name 数据集名称
+	Name string `json:"name"`
+
+	// Rating Elo 分
+	Rating float64 `json:"rating"`
+
+	// Wins 胜场数
+	Wins int `json:"wins"`
+
+	// Losses 负场数
+	Losses int `json:"losses"`
+
+	// Ties 平局数
+	Ties int `json:"ties"`
+}
+
+// TournamentEvaluator 多数据集锦标赛评估器
+//
+// 对多个生成数据集两两循环对比（同一索引的样本配对），按对比结果更新 Elo 分并排名。
+type TournamentEvaluator struct {
+	// llmProvider LLM 提供商
+	llmProvider llm.Provider
+
+	// datasets 参赛数据集
+	datasets []NamedDataset
+
+	// config 配置
+	config TournamentConfig
+}
+
+// NewTournamentEvaluator 创建锦标赛评估器
+//
+// 参数:
+//   - llmProvider: LLM 服务提供商
+//   - datasets: 参赛数据集
+//   - config: 评估配置
+func NewTournamentEvaluator(llmProvider llm.Provider, datasets []NamedDataset, config TournamentConfig) *TournamentEvaluator {
+	if config.KFactor <= 0 {
+		config.KFactor = defaultKFactor
+	}
+	if config.InitialRating <= 0 {
+		config.InitialRating = defaultInitialRating
+	}
+	return &TournamentEvaluator{
+		llmProvider: llmProvider,
+		datasets:    datasets,
+		config:      config,
+	}
+}
+
+// Name 返回评估器名称
+func (t *TournamentEvaluator) Name() string {
+	return "Tournament"
+}
+
+// match 一场对比：数据集 a 与 b 的第 index 个样本
+type match struct {
+	a, b  int
+	index int
+}
+
+// Evaluate 执行锦标赛评估
+//
+// 排名结果（[]Rating，按 Elo 分降序）写入 Metrics.Extra["ratings"]。
+// 只有一个数据集时不进行对比，直接返回其初始分。
+func (t *TournamentEvaluator) Evaluate(ctx context.Context, opts ...evaluation.EvalOption) (*evaluation.EvalResult, error) {
+	if len(t.datasets) == 0 {
+		return nil, ErrNoDatasets
+	}
+
+	config := evaluation.DefaultEvalConfig()
+	config.ApplyOptions(opts...)
+
+	// 确保数据集已加载
+	for _, d := range t.datasets {
+		if err := d.Dataset.Load(ctx); err != nil {
+			return nil, fmt.Errorf("加载数据集 %s 失败: %w", d.Name, err)
+		}
+	}
+
+	startTime := time.Now()
+	result := &evaluation.EvalResult{
+		BenchmarkName:   t.Name(),
+		AgentName:       t.llmProvider.Name(),
+		DetailedResults: make([]*evaluation.SampleResult, 0),
+		EvaluationTime:  startTime,
+		RunConfig:       config.Summary(),
+	}
+
+	ratings := make([]Rating, len(t.datasets))
+	for i, d := range t.datasets {
+		ratings[i] = Rating{Name: d.Name, Rating: t.config.InitialRating}
+	}
+
+	matches := t.schedule(config.MaxSamples)
+	result.TotalSamples = len(matches)
+
+	// 复用 Win Rate 的对比逻辑（位置随机化、双轮评估）
+	judge := NewWinRateEvaluator(t.llmProvider, nil, nil, WinRateConfig{
+		RandomSeed: t.config.RandomSeed,
+		DoublePass: t.config.DoublePass,
+	})
+
+	var runErr error
+	for n, m := range matches {
+		if err := ctx.Err(); err != nil {
+			runErr = err
+			break
+		}
+
+		sampleResult := t.runMatch(ctx, config, judge, n, m)
+		result.DetailedResults = append(result.DetailedResults, sampleResult)
+
+		if comp, ok := sampleResult.Predicted.(*evaluation.ComparisonResult); ok && sampleResult.Error == "" {
+			t.updateRatings(ratings, m.a, m.b, comp.ActualWinner)
+			if comp.ActualWinner != winnerTie {
+				result.SuccessCount++
+			}
+		}
+
+		config.ReportProgress(n+1, len(matches), startTime)
+	}
+
+	result.TotalDuration = time.Since(startTime)
+	result.Metrics = t.computeMetrics(ratings, len(result.DetailedResults))
+	result.Metrics.Extra["timeout_count"] = evaluation.CountTimeouts(result.DetailedResults)
+	result.Metrics.Extra[evaluation.ErrorBreakdownKey] = evaluation.ComputeErrorBreakdown(result.DetailedResults)
+
+	return result, runErr
+}
+
+// schedule 生成对比计划
+//
+// 外层按样本索引、内层按数据集对轮转，使预算截断时各数据集对的对比次数尽量均衡。
+func (t *TournamentEvaluator) schedule(maxSamples int) []match {
+	var pairs [][2]int
+	for a := 0; a < len(t.datasets); a++ {
+		for b := a + 1; b < len(t.datasets); b++ {
+			pairs = append(pairs, [2]int{a, b})
+		}
+	}
+
+	// 每对数据集的可对比样本数
+	limits := make([]int, len(pairs))
+	rounds := 0
+	for i, p := range pairs {
+		limit := min(t.datasets[p[0]].Dataset.Len(), t.datasets[p[1]].Dataset.Len())
+		if maxSamples > 0 && maxSamples < limit {
+			limit = maxSamples
+		}
+		limits[i] = limit
+		rounds = max(rounds, limit)
+	}
+
+	var matches []match
+	for index := 0; index < rounds; index++ {
+		for i, p := range pairs {
+			if index >= limits[i] {
+				continue
+			}
+			if t.config.MaxComparisons > 0 && len(matches) >= t.config.MaxComparisons {
+				return matches
+			}
+			matches = append(matches, match{a: p[0], b: p[1], index: index})
+		}
+	}
+
+	return matches
+}
+
+// runMatch 执行一场对比，数据集 a 的样本作为候选、数据集 b 的样本作为参考
+func (t *TournamentEvaluator) runMatch(ctx context.Context, config *evaluation.EvalConfig, judge *WinRateEvaluator, n int, m match) *evaluation.SampleResult {
+	sampleA, err := t.datasets[m.a].Dataset.Get(m.index)
+	if err != nil {
+		return evaluation.NewSampleLoadErrorResult(m.index, err)
+	}
+	sampleB, err := t.datasets[m.b].Dataset.Get(m.index)
+	if err != nil {
+		return evaluation.NewSampleLoadErrorResult(m.index, err)
+	}
+
+	sampleCtx, cancel := config.SampleContext(ctx)
+	defer cancel()
+
+	sampleResult, err := judge.CompareSamplesAt(sampleCtx, n, sampleA, sampleB)
+	if err != nil {
+		sampleResult = &evaluation.SampleResult{
+			SampleID: sampleA.ID,
+			Error:    err.Error(),
+			Details:  make(map[string]interface{}),
+		}
+	}
+	config.MarkSampleTimeout(ctx, sampleCtx, sampleResult)
+	sampleResult.SampleID = fmt.Sprintf("%s/%s vs %s/%s", t.datasets[m.a].Name, sampleA.ID, t.datasets[m.b].Name, sampleB.ID)
+	sampleResult.Details["dataset_a"] = t.datasets[m.a].Name
+	sampleResult.Details["dataset_b"] = t.datasets[m.b].Name
+
+	return sampleResult
+}
+
+// updateRatings 按一场对比结果更新双方 Elo 分
+func (t *TournamentEvaluator) updateRatings(ratings []Rating, a, b int, actualWinner string) {
+	var scoreA float64
+	switch actualWinner {
+	case winnerCandidate:
+		scoreA = 1
+		ratings[a].Wins++
+		ratings[b].Losses++
+	case winnerReference:
+		scoreA = 0
+		ratings[a].Losses++
+		ratings[b].Wins++
+	default:
+		scoreA = 0.5
+		ratings[a].Ties++
+		ratings[b].Ties++
+	}
+
+	expectedA := 1 / (1 + math.Pow(10, (ratings[b].Rating-ratings[a].Rating)/400))
+	delta := t.config.KFactor * (scoreA - expectedA)
+	ratings[a].Rating += delta
+	ratings[b].Rating -= delta
+}
+
+// computeMetrics 计算汇总指标
+func (t *TournamentEvaluator) computeMetrics(ratings []Rating, comparisons int) *evaluation.MetricsSummary {
+	ranked := make([]Rating, len(ratings))
+	copy(ranked, ratings)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Rating > ranked[j].Rating
+	})
+
+	return &evaluation.MetricsSummary{
+		Extra: map[string]interface{}{
+			"ratings":           ranked,
+			"k_factor":          t.config.KFactor,
+			"initial_rating":    t.config.InitialRating,
+			"total_comparisons": comparisons,
+		},
+	}
+}
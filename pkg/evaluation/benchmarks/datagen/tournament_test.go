@@ -0,0 +1,148 @@
+package datagen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/core/llm"
+)
+
+// rankLLM 按题目前缀的固定优先级选择胜者的测试 LLM 提供商
+type rankLLM struct {
+	mockLLM
+	rank map[string]int
+}
+
+func (m *rankLLM) Generate(ctx context.Context, req llm.Request) (llm.Response, error) {
+	prompt := req.Messages[len(req.Messages)-1].Content
+	split := strings.Index(prompt, "## 题目 B")
+	if m.score(prompt[:split]) >= m.score(prompt[split:]) {
+		return llm.Response{Content: "Winner: A\nReason: higher rank"}, nil
+	}
+	return llm.Response{Content: "Winner: B\nReason: higher rank"}, nil
+}
+
+func (m *rankLLM) score(section string) int {
+	for prefix, rank := range m.rank {
+		if strings.Contains(section, prefix+"-") {
+			return rank
+		}
+	}
+	return 0
+}
+
+// writeNamedDataset 写入题目带指定前缀的测试数据集
+func writeNamedDataset(t *testing.T, prefix string, n int) NamedDataset {
+	t.Helper()
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, `{"id": "%s%d", "question": "%s-%d", "answer": "%d"}`+"\n", prefix, i, prefix, i, i)
+	}
+	path := filepath.Join(t.TempDir(), prefix+".jsonl")
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		t.Fatalf("failed to write dataset: %v", err)
+	}
+	return NamedDataset{Name: prefix, Dataset: NewDataset(path)}
+}
+
+func TestTournamentEvaluator_Ranking(t *testing.T) {
+	provider := &rankLLM{rank: map[string]int{"gold": 3, "silver": 2, "bronze": 1}}
+	datasets := []NamedDataset{
+		writeNamedDataset(t, "bronze", 4),
+		writeNamedDataset(t, "gold", 4),
+		writeNamedDataset(t, "silver", 4),
+	}
+
+	evaluator := NewTournamentEvaluator(provider, datasets, TournamentConfig{RandomSeed: 11})
+	result, err := evaluator.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	// 3 对数据集 × 4 个样本
+	if result.TotalSamples != 12 || len(result.DetailedResults) != 12 {
+		t.Fatalf("expected 12 comparisons, got %d", len(result.DetailedResults))
+	}
+
+	ratings := result.Metrics.Extra["ratings"].([]Rating)
+	var names []string
+	for _, r := range ratings {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "gold,silver,bronze" {
+		t.Fatalf("ranking = %s, want gold,silver,bronze", got)
+	}
+	if ratings[0].Wins != 8 || ratings[2].Losses != 8 || ratings[1].Wins != 4 {
+		t.Errorf("unexpected win/loss counts: %+v", ratings)
+	}
+
+	// Elo 为零和更新
+	var total float64
+	for _, r := range ratings {
+		total += r.Rating
+	}
+	if diff := total - 3*defaultInitialRating; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("total rating = %v, want %v", total, 3*defaultInitialRating)
+	}
+
+	path := filepath.Join(t.TempDir(), "tournament.md")
+	if err := NewExporter().ExportTournamentReport(result, path); err != nil {
+		t.Fatalf("ExportTournamentReport() error = %v", err)
+	}
+	report, _ := os.ReadFile(path)
+	if !strings.Contains(string(report), "| 1 | gold |") || !strings.Contains(string(report), "| 3 | bronze |") {
+		t.Errorf("report missing ranking table:\n%s", report)
+	}
+}
+
+func TestTournamentEvaluator_Budget(t *testing.T) {
+	provider := &rankLLM{rank: map[string]int{"a": 2, "b": 1}}
+	datasets := []NamedDataset{
+		writeNamedDataset(t, "a", 5),
+		writeNamedDataset(t, "b", 5),
+		writeNamedDataset(t, "c", 5),
+	}
+
+	evaluator := NewTournamentEvaluator(provider, datasets, TournamentConfig{MaxComparisons: 4, KFactor: 16})
+	result, err := evaluator.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(result.DetailedResults) != 4 {
+		t.Fatalf("expected budget of 4 comparisons, got %d", len(result.DetailedResults))
+	}
+
+	// 预算按样本索引轮转，每对数据集都参与对比
+	pairs := make(map[string]bool)
+	for _, r := range result.DetailedResults {
+		pairs[fmt.Sprintf("%v-%v", r.Details["dataset_a"], r.Details["dataset_b"])] = true
+	}
+	if len(pairs) != 3 {
+		t.Errorf("expected all 3 pairs to be compared, got %v", pairs)
+	}
+	if result.Metrics.Extra["k_factor"] != 16.0 {
+		t.Errorf("k_factor = %v, want 16", result.Metrics.Extra["k_factor"])
+	}
+}
+
+func TestTournamentEvaluator_SingleDataset(t *testing.T) {
+	evaluator := NewTournamentEvaluator(&mockLLM{}, []NamedDataset{writeNamedDataset(t, "only", 3)}, TournamentConfig{InitialRating: 1200})
+	result, err := evaluator.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	ratings := result.Metrics.Extra["ratings"].([]Rating)
+	if len(ratings) != 1 || ratings[0].Rating != 1200 || len(result.DetailedResults) != 0 {
+		t.Errorf("expected unchanged single rating, got %+v with %d comparisons", ratings, len(result.DetailedResults))
+	}
+
+	_, err = NewTournamentEvaluator(&mockLLM{}, nil, TournamentConfig{}).Evaluate(context.Background())
+	if !errors.Is(err, ErrNoDatasets) {
+		t.Errorf("expected ErrNoDatasets, got %v", err)
+	}
+}
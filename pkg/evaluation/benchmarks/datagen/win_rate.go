@@ -3,7 +3,7 @@ package datagen
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"hash/fnv"
 	"regexp"
 	"strings"
 	"time"
@@ -23,7 +23,15 @@ const (
 // WinRateConfig Win Rate 配置
 type WinRateConfig struct {
 	// RandomSeed 随机种子（用于位置随机化）
+	//
+	// 每对样本的位置是否交换由 (RandomSeed, 样本索引) 的哈希决定，
+	// 与评估顺序无关，相同种子的重复运行结果一致。为 0 时使用当前时间。
 	RandomSeed int64
+
+	// DoublePass 是否开启双轮评估以消除位置偏差
+	//
+	// 开启后每对样本按相反顺序各评估一次，仅当两轮判决一致时计为胜负，不一致计为平局。
+	DoublePass bool
 }
 
 // WinRateEvaluator Win Rate 评估器
@@ -40,8 +48,8 @@ type WinRateEvaluator struct {
 	// config 配置
 	config WinRateConfig
 
-	// rand 随机数生成器
-	rand *rand.Rand
+	// seed 生效的位置随机化种子
+	seed int64
 }
 
 // NewWinRateEvaluator 创建 Win Rate 评估器
@@ -61,7 +69,7 @@ func NewWinRateEvaluator(llmProvider llm.Provider, candidateDataset, referenceDa
 		candidateDataset: candidateDataset,
 		referenceDataset: referenceDataset,
 		config:           config,
-		rand:             rand.New(rand.NewSource(seed)), //nolint:gosec // 位置随机化不需要加密安全的随机数
+		seed:             seed,
 	}
 }
 
@@ -89,6 +97,7 @@ func (w *WinRateEvaluator) Evaluate(ctx context.Context, opts ...evaluation.Eval
 		AgentName:       w.llmProvider.Name(),
 		DetailedResults: make([]*evaluation.SampleResult, 0),
 		EvaluationTime:  startTime,
+		RunConfig:       config.Summary(),
 	}
 
 	// 确定比较数量
@@ -114,28 +123,28 @@ func (w *WinRateEvaluator) Evaluate(ctx context.Context, opts ...evaluation.Eval
 
 		candidateSample, err := w.candidateDataset.Get(i)
 		if err != nil {
+			result.DetailedResults = append(result.DetailedResults, evaluation.NewSampleLoadErrorResult(i, err))
+			config.ReportProgress(i+1, total, startTime)
 			continue
 		}
 		referenceSample, err := w.referenceDataset.Get(i)
 		if err != nil {
+			result.DetailedResults = append(result.DetailedResults, evaluation.NewSampleLoadErrorResult(i, err))
+			config.ReportProgress(i+1, total, startTime)
 			continue
 		}
 
 		// 应用超时
-		evalCtx := ctx
-		if config.Timeout > 0 {
-			var cancel context.CancelFunc
-			evalCtx, cancel = context.WithTimeout(ctx, config.Timeout)
-			defer cancel()
-		}
-
-		sampleResult, err := w.CompareSamples(evalCtx, candidateSample, referenceSample)
+		sampleCtx, cancel := config.SampleContext(ctx)
+		sampleResult, err := w.CompareSamplesAt(sampleCtx, i, candidateSample, referenceSample)
 		if err != nil {
 			sampleResult = &evaluation.SampleResult{
 				SampleID: candidateSample.ID,
 				Error:    err.Error(),
 			}
 		}
+		config.MarkSampleTimeout(ctx, sampleCtx, sampleResult)
+		cancel()
 
 		result.DetailedResults = append(result.DetailedResults, sampleResult)
 
@@ -153,9 +162,7 @@ func (w *WinRateEvaluator) Evaluate(ctx context.Context, opts ...evaluation.Eval
 		}
 
 		// 进度回调
-		if config.ProgressCallback != nil {
-			config.ProgressCallback(i+1, total)
-		}
+		config.ReportProgress(i+1, total, startTime)
 	}
 
 	result.TotalDuration = time.Since(startTime)
@@ -163,12 +170,23 @@ func (w *WinRateEvaluator) Evaluate(ctx context.Context, opts ...evaluation.Eval
 
 	// 计算汇总指标
 	result.Metrics = w.computeMetrics(wins, losses, ties, total)
+	result.Metrics.Extra["timeout_count"] = evaluation.CountTimeouts(result.DetailedResults)
+	result.Metrics.Extra[evaluation.ErrorBreakdownKey] = evaluation.ComputeErrorBreakdown(result.DetailedResults)
 
 	return result, nil
 }
 
 // CompareSamples 比较两个样本
+//
+// 位置是否交换由种子与两个样本的 ID 决定；需要按样本对索引确定位置时使用 CompareSamplesAt。
 func (w *WinRateEvaluator) CompareSamples(ctx context.Context, candidate, reference evaluation.Sample) (*evaluation.SampleResult, error) {
+	return w.CompareSamplesAt(ctx, pairIndex(candidate, reference), candidate, reference)
+}
+
+// CompareSamplesAt 比较第 index 对样本
+//
+// index 为样本对的索引，用于确定该对样本的位置是否交换。
+func (w *WinRateEvaluator) CompareSamplesAt(ctx context.Context, index int, candidate, reference evaluation.Sample) (*evaluation.SampleResult, error) {
 	startTime := time.Now()
 
 	result := &evaluation.SampleResult{
@@ -176,9 +194,60 @@ func (w *WinRateEvaluator) CompareSamples(ctx context.Context, candidate, refere
 		Details:  make(map[string]interface{}),
 	}
 
-	// 随机决定位置
-	swapped := w.rand.Float32() < 0.5
+	// 按种子和索引决定位置
+	swapped := w.pairSwapped(index)
+
+	compResult, response, err := w.judgePair(ctx, candidate, reference, swapped)
+	if err != nil {
+		result.Error = err.Error()
+		result.ExecutionTime = time.Since(startTime)
+		return result, nil
+	}
+
+	// 双轮评估：交换顺序再评估一次，两轮不一致视为平局
+	if w.config.DoublePass {
+		second, secondResponse, err := w.judgePair(ctx, candidate, reference, !swapped)
+		if err != nil {
+			result.Error = err.Error()
+			result.ExecutionTime = time.Since(startTime)
+			return result, nil
+		}
+		response += "\n\n---\n\n" + secondResponse
+
+		agree := compResult.ActualWinner == second.ActualWinner
+		compResult.Details = map[string]interface{}{
+			"first_pass_winner":  compResult.Winner,
+			"second_pass_winner": second.Winner,
+			"first_pass_actual":  compResult.ActualWinner,
+			"second_pass_actual": second.ActualWinner,
+			"second_pass_reason": second.Reason,
+			"passes_agree":       agree,
+		}
+		if !agree {
+			compResult.Winner = "Tie"
+			compResult.ActualWinner = winnerTie
+		}
+	}
+
+	result.AgentResponse = response
+	result.ExecutionTime = time.Since(startTime)
+	result.Predicted = compResult
+
+	result.Details["winner"] = compResult.Winner
+	result.Details["actual_winner"] = compResult.ActualWinner
+	result.Details["reason"] = compResult.Reason
+	result.Details["swapped"] = swapped
+	result.Details["comparison"] = compResult
+	if w.config.DoublePass {
+		result.Details["double_pass"] = true
+		result.Details["passes_agree"] = compResult.Details["passes_agree"]
+	}
+
+	return result, nil
+}
 
+// judgePair 按给定位置调用评委比较一对样本，返回解析结果和原始响应
+func (w *WinRateEvaluator) judgePair(ctx context.Context, candidate, reference evaluation.Sample, swapped bool) (*evaluation.ComparisonResult, string, error) {
 	var problemA, problemB evaluation.Sample
 	if swapped {
 		problemA, problemB = reference, candidate
@@ -199,24 +268,30 @@ func (w *WinRateEvaluator) CompareSamples(ctx context.Context, candidate, refere
 
 	resp, err := w.llmProvider.Generate(ctx, req)
 	if err != nil {
-		result.Error = err.Error()
-		result.ExecutionTime = time.Since(startTime)
-		return result, nil
+		return nil, "", err
 	}
 
-	result.AgentResponse = resp.Content
-	result.ExecutionTime = time.Since(startTime)
-
-	// 解析结果
-	compResult := w.parseCompareResponse(resp.Content, candidate.ID, reference.ID, swapped)
-	result.Predicted = compResult
+	return w.parseCompareResponse(resp.Content, candidate.ID, reference.ID, swapped), resp.Content, nil
+}
 
-	result.Details["winner"] = compResult.Winner
-	result.Details["actual_winner"] = compResult.ActualWinner
-	result.Details["reason"] = compResult.Reason
-	result.Details["swapped"] = swapped
+// pairIndex 由两个样本的 ID 派生样本对索引
+func pairIndex(candidate, reference evaluation.Sample) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(candidate.ID))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(reference.ID))
+	return int(h.Sum32())
+}
 
-	return result, nil
+// pairSwapped 返回第 index 对样本是否交换展示位置
+//
+// 使用 splitmix64 混合种子与索引，结果只取决于二者而与调用顺序无关。
+func (w *WinRateEvaluator) pairSwapped(index int) bool {
+	x := uint64(w.seed) + uint64(index+1)*0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return x>>63 == 1
 }
 
 // getSystemPrompt 获取系统提示
@@ -238,13 +313,13 @@ Reason: <选择理由>`
 func (w *WinRateEvaluator) buildComparePrompt(problemA, problemB evaluation.Sample) string {
 	prompt := "## 题目 A\n\n"
 	prompt += fmt.Sprintf("**问题**: %s\n", problemA.Input)
-	if answer, ok := problemA.Expected.(string); ok && answer != "" {
+	if answer := formatExpected(problemA.Expected); answer != "" {
 		prompt += fmt.Sprintf("**答案**: %s\n", answer)
 	}
 
 	prompt += "\n---\n\n## 题目 B\n\n"
 	prompt += fmt.Sprintf("**问题**: %s\n", problemB.Input)
-	if answer, ok := problemB.Expected.(string); ok && answer != "" {
+	if answer := formatExpected(problemB.Expected); answer != "" {
 		prompt += fmt.Sprintf("**答案**: %s\n", answer)
 	}
 
@@ -258,6 +333,7 @@ func (w *WinRateEvaluator) parseCompareResponse(response, candidateID, reference
 	result := &evaluation.ComparisonResult{
 		ProblemAID: candidateID,
 		ProblemBID: referenceID,
+		Swapped:    swapped,
 	}
 
 	// 提取 Winner
@@ -7,22 +7,29 @@
 package gaia
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation/loader"
 )
 
+// ErrAttachmentNotFound 样本引用的附件文件不存在
+var ErrAttachmentNotFound = errors.New("GAIA 附件文件不存在")
+
 // Dataset GAIA 数据集
 type Dataset struct {
 	// dataDir 本地数据目录
 	dataDir string
 
+	// fileDir 已加载元数据文件所在目录（附件通常与元数据放在一起）
+	fileDir string
+
 	// level 难度级别过滤（0 表示不过滤）
 	level int
 
@@ -34,6 +41,15 @@ type Dataset struct {
 
 	// loaded 是否已加载
 	loaded bool
+
+	// source 过滤前的源数据集（仅过滤得到的数据集非 nil）
+	source *Dataset
+
+	// filter 样本过滤条件
+	filter evaluation.SamplePredicate
+
+	// strict 是否严格加载（遇到无法解析的行即报错）
+	strict bool
 }
 
 // NewDataset 创建 GAIA 数据集
@@ -54,12 +70,70 @@ func NewDataset(dataDir string, level int, split string) *Dataset {
 	}
 }
 
+// SetStrict 设置严格加载模式
+//
+// 开启后 Load 遇到第一条无法解析的行即返回 *loader.LineError，而不是跳过；
+// 需在 Load 之前调用。
+func (d *Dataset) SetStrict(strict bool) {
+	d.strict = strict
+}
+
+// requiredFields GAIA 数据的必填字段
+var requiredFields = []loader.Field{
+	{Name: "question", Keys: []string{"question", "Question"}},
+	{Name: "final_answer", Keys: []string{"final_answer", "Final answer", "expected_answer"}},
+}
+
+// ValidateDataset 校验 GAIA 数据文件
+//
+// 报告无法解析的行号与原因，以及缺少问题或答案字段的记录数。
+func ValidateDataset(ctx context.Context, path string) (*loader.ValidationReport, error) {
+	return loader.Validate(ctx, path, requiredFields...)
+}
+
+// Filter 返回仅包含满足 pred 的样本的数据集
+//
+// 源数据集已加载时立即过滤，否则在 Load 时加载源数据集后过滤。
+func (d *Dataset) Filter(pred evaluation.SamplePredicate) *Dataset {
+	filtered := &Dataset{
+		dataDir: d.dataDir,
+		level:   d.level,
+		split:   d.split,
+		samples: make([]evaluation.Sample, 0),
+		source:  d,
+		filter:  pred,
+	}
+	if d.loaded {
+		filtered.applyFilter()
+	}
+	return filtered
+}
+
+// applyFilter 从源数据集中筛选样本
+func (d *Dataset) applyFilter() {
+	d.samples = d.samples[:0]
+	for _, sample := range d.source.samples {
+		if d.filter(sample) {
+			d.samples = append(d.samples, sample)
+		}
+	}
+	d.loaded = true
+}
+
 // Load 加载数据集
 func (d *Dataset) Load(ctx context.Context) error {
 	if d.loaded {
 		return nil
 	}
 
+	if d.source != nil {
+		if err := d.source.Load(ctx); err != nil {
+			return err
+		}
+		d.applyFilter()
+		return nil
+	}
+
 	// 检查数据目录
 	if _, err := os.Stat(d.dataDir); os.IsNotExist(err) {
 		return fmt.Errorf("GAIA 数据目录不存在: %s\n请从 HuggingFace 下载: huggingface-cli download gaia-benchmark/GAIA", d.dataDir)
@@ -76,12 +150,9 @@ func (d *Dataset) Load(ctx context.Context) error {
 	var loadErr error
 	for _, filePath := range possibleFiles {
 		if _, err := os.Stat(filePath); err == nil {
-			if strings.HasSuffix(filePath, ".jsonl") {
-				loadErr = d.loadJSONL(ctx, filePath)
-			} else {
-				loadErr = d.loadJSON(ctx, filePath)
-			}
+			loadErr = d.loadFile(ctx, filePath)
 			if loadErr == nil {
+				d.fileDir = filepath.Dir(filePath)
 				break
 			}
 		}
@@ -95,35 +166,25 @@ func (d *Dataset) Load(ctx context.Context) error {
 	return nil
 }
 
-// loadJSONL 加载 JSONL 格式文件
-func (d *Dataset) loadJSONL(ctx context.Context, filePath string) error {
-	file, err := os.Open(filePath)
+// loadFile 加载数据文件（JSON、JSONL 或 CSV），按级别过滤样本
+func (d *Dataset) loadFile(ctx context.Context, filePath string) error {
+	load := loader.LoadRecords
+	if d.strict {
+		load = loader.LoadRecordsStrict
+	}
+	records, err := load(filePath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
 
 	idx := 0
-	for scanner.Scan() {
+	for _, item := range records {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
-		line := scanner.Text()
-		if line == "" {
-			continue
-		}
-
-		var item map[string]interface{}
-		if err := json.Unmarshal([]byte(line), &item); err != nil {
-			continue
-		}
-
 		sample := d.parseItem(item, idx)
 
 		// 应用级别过滤
@@ -135,39 +196,6 @@ func (d *Dataset) loadJSONL(ctx context.Context, filePath string) error {
 		idx++
 	}
 
-	return scanner.Err()
-}
-
-// loadJSON 加载 JSON 格式文件
-func (d *Dataset) loadJSON(ctx context.Context, filePath string) error {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	var items []map[string]interface{}
-	if err := json.NewDecoder(file).Decode(&items); err != nil {
-		return err
-	}
-
-	for idx, item := range items {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		sample := d.parseItem(item, idx)
-
-		// 应用级别过滤
-		if d.level > 0 && sample.Level != d.level {
-			continue
-		}
-
-		d.samples = append(d.samples, sample)
-	}
-
 	return nil
 }
 
@@ -190,13 +218,19 @@ func (d *Dataset) parseItem(item map[string]interface{}, idx int) evaluation.Sam
 		sample.Input = question
 	}
 
-	// 提取级别
-	if level, ok := item["level"].(float64); ok {
-		sample.Level = int(level)
-	} else if level, ok := item["Level"].(float64); ok {
-		sample.Level = int(level)
-	} else if level, ok := item["level"].(int); ok {
-		sample.Level = level
+	// 提取级别（CSV 等来源中为字符串）
+	for _, key := range []string{"level", "Level"} {
+		switch level := item[key].(type) {
+		case float64:
+			sample.Level = int(level)
+		case int:
+			sample.Level = level
+		case string:
+			sample.Level, _ = strconv.Atoi(strings.TrimSpace(level))
+		default:
+			continue
+		}
+		break
 	}
 
 	// 设置类别
@@ -221,6 +255,43 @@ func (d *Dataset) parseItem(item map[string]interface{}, idx int) evaluation.Sam
 	return sample
 }
 
+// ResolveFile 将样本附件文件名解析为绝对路径
+//
+// 依次在元数据文件所在目录、dataDir/<split> 和 dataDir 下查找，name 为绝对路径时直接检查。
+// 均不存在时返回 ErrAttachmentNotFound。
+func (d *Dataset) ResolveFile(name string) (string, error) {
+	root := d
+	for root.source != nil {
+		root = root.source
+	}
+
+	var candidates []string
+	if filepath.IsAbs(name) {
+		candidates = []string{name}
+	} else {
+		if root.fileDir != "" {
+			candidates = append(candidates, filepath.Join(root.fileDir, name))
+		}
+		candidates = append(candidates,
+			filepath.Join(root.dataDir, root.split, name),
+			filepath.Join(root.dataDir, name),
+		)
+	}
+
+	for _, path := range candidates {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("解析附件路径失败: %w", err)
+		}
+		return abs, nil
+	}
+	return "", fmt.Errorf("%w: %s（数据目录 %s）", ErrAttachmentNotFound, name, root.dataDir)
+}
+
 // Len 返回数据集大小
 func (d *Dataset) Len() int {
 	return len(d.samples)
@@ -0,0 +1,119 @@
+package gaia
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+func TestDataset_Filter_FilesOnly(t *testing.T) {
+	dir := t.TempDir()
+	lines := []string{
+		`{"task_id": "t0", "Question": "q0", "Level": 1, "Final answer": "a0"}`,
+		`{"task_id": "t1", "Question": "q1", "Level": 1, "Final answer": "a1", "file_name": "t1.xlsx"}`,
+		`{"task_id": "t2", "Question": "q2", "Level": 2, "Final answer": "a2"}`,
+		`{"task_id": "t3", "Question": "q3", "Level": 3, "Final answer": "a3", "file_name": "t3.png"}`,
+	}
+	if err := os.WriteFile(filepath.Join(dir, "validation.jsonl"), []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatalf("failed to write dataset: %v", err)
+	}
+
+	dataset := NewDataset(dir, 0, "validation")
+	if err := dataset.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	withFiles := dataset.Filter(func(s evaluation.Sample) bool {
+		return len(s.Files) > 0
+	})
+	if withFiles.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", withFiles.Len())
+	}
+
+	wantIDs := []string{"t1", "t3"}
+	for i, id := range wantIDs {
+		sample, err := withFiles.Get(i)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", i, err)
+		}
+		if sample.ID != id {
+			t.Errorf("Get(%d).ID = %s, want %s", i, sample.ID, id)
+		}
+	}
+	if _, err := withFiles.Get(2); err == nil {
+		t.Error("expected out-of-range error past the filtered length")
+	}
+
+	var iterated []string
+	for sample := range withFiles.Iterator() {
+		iterated = append(iterated, sample.ID)
+	}
+	if strings.Join(iterated, ",") != "t1,t3" {
+		t.Errorf("Iterator() = %v, want [t1 t3]", iterated)
+	}
+
+	if dataset.Len() != 4 {
+		t.Errorf("source dataset Len() = %d, want 4", dataset.Len())
+	}
+}
+
+func TestDataset_ResolveFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "validation.jsonl"),
+		[]byte(`{"task_id": "t0", "Question": "q0", "Level": 1, "Final answer": "a", "file_name": "doc.txt"}`), 0644); err != nil {
+		t.Fatalf("failed to write dataset: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "doc.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write attachment: %v", err)
+	}
+
+	dataset := NewDataset(dir, 0, "validation")
+	if err := dataset.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// 过滤得到的数据集沿用源数据集的目录
+	filtered := dataset.Filter(func(s evaluation.Sample) bool { return len(s.Files) > 0 })
+	path, err := filtered.ResolveFile("doc.txt")
+	if err != nil {
+		t.Fatalf("ResolveFile() error = %v", err)
+	}
+	if !filepath.IsAbs(path) || filepath.Base(path) != "doc.txt" {
+		t.Errorf("ResolveFile() = %s, want absolute path to doc.txt", path)
+	}
+
+	if _, err := dataset.ResolveFile("nope.txt"); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("expected ErrAttachmentNotFound, got %v", err)
+	}
+}
+
+func TestDataset_Load_JSONArrayStringLevel(t *testing.T) {
+	dir := t.TempDir()
+	content := `[
+  {"task_id": "t0", "Question": "q0", "Level": "1", "Final answer": "a0"},
+  {"task_id": "t1", "Question": "q1", "Level": "2", "Final answer": "a1"}
+]`
+	if err := os.WriteFile(filepath.Join(dir, "validation.json"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write dataset: %v", err)
+	}
+
+	dataset := NewDataset(dir, 2, "validation")
+	if err := dataset.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if dataset.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", dataset.Len())
+	}
+	sample, err := dataset.Get(0)
+	if err != nil {
+		t.Fatalf("Get(0) error = %v", err)
+	}
+	if sample.ID != "t1" || sample.Level != 2 {
+		t.Errorf("Get(0) = %s (level %d), want t1 (level 2)", sample.ID, sample.Level)
+	}
+}
@@ -3,7 +3,10 @@ package gaia
 import (
 	"context"
 	"fmt"
+	"math"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -15,14 +18,126 @@ import (
 // Evaluator GAIA 评估器
 type Evaluator struct {
 	// dataset 数据集
-	dataset *Dataset
+	dataset evaluation.Dataset
+
+	// absTolerance 数值答案的绝对容差
+	absTolerance float64
+
+	// relTolerance 数值答案的相对容差
+	relTolerance float64
+
+	// listDelimiters 列表答案分隔符
+	listDelimiters []string
+
+	// answerExtractor 自定义答案提取函数（nil 表示使用内置模式）
+	answerExtractor AnswerExtractor
+
+	// files 附件解析所用的数据集（nil 时按原始文件名传递）
+	files *Dataset
+
+	// inlineFileBytes 附件内容内联的大小上限（0 表示不内联）
+	inlineFileBytes int64
+
+	// partialThreshold 部分匹配的词汇覆盖率阈值（0 表示使用默认值）
+	partialThreshold float64
+
+	// partialDisabled 是否禁用部分匹配（仅统计精确匹配）
+	partialDisabled bool
+}
+
+// AnswerExtractor 从智能体响应中提取答案
+//
+// 应返回未经规范化的原始答案，评估器会在比较前统一进行规范化；
+// 无法提取时返回空字符串。
+type AnswerExtractor func(response string) string
+
+// 默认列表答案分隔符
+var defaultListDelimiters = []string{",", ";"}
+
+// defaultPartialMatchThreshold 默认部分匹配词汇覆盖率阈值
+const defaultPartialMatchThreshold = 0.7
+
+// EvaluatorOption 评估器配置选项
+type EvaluatorOption func(*Evaluator)
+
+// WithNumericTolerance 设置数值答案比较容差
+//
+// 预测答案与期望答案均可解析为数字时按数值比较，差值不超过
+// max(absTol, relTol*max(|a|, |b|)) 即视为精确匹配。默认容差为 0（数值完全相等）。
+func WithNumericTolerance(absTol, relTol float64) EvaluatorOption {
+	return func(e *Evaluator) {
+		e.absTolerance = absTol
+		e.relTolerance = relTol
+	}
+}
+
+// WithListDelimiters 设置列表答案分隔符
+//
+// 期望答案包含任一分隔符时按无序集合比较，默认分隔符为逗号和分号；
+// 不传参数时关闭列表匹配。
+func WithListDelimiters(delimiters ...string) EvaluatorOption {
+	return func(e *Evaluator) {
+		e.listDelimiters = delimiters
+	}
+}
+
+// WithAnswerExtractor 设置自定义答案提取函数
+//
+// 覆盖内置的 "FINAL ANSWER:" 等模式，用于 XML 标签、JSON 等自定义输出格式。
+func WithAnswerExtractor(extractor AnswerExtractor) EvaluatorOption {
+	return func(e *Evaluator) {
+		e.answerExtractor = extractor
+	}
+}
+
+// WithInlineFiles 将不超过 maxBytes 的附件内容读入智能体上下文
+//
+// 内容以 map[string][]byte（键为原始文件名）写入 Context["file_contents"]，
+// 超过上限的附件仍只传递路径。默认不内联。
+func WithInlineFiles(maxBytes int64) EvaluatorOption {
+	return func(e *Evaluator) {
+		e.inlineFileBytes = maxBytes
+	}
+}
+
+// WithPartialMatchThreshold 设置部分匹配的词汇覆盖率阈值
+//
+// 期望答案中至少有 threshold 比例的词出现在预测答案中时视为部分匹配。
+// threshold 须在 (0, 1] 内，否则忽略并保持默认值 0.7。
+func WithPartialMatchThreshold(threshold float64) EvaluatorOption {
+	return func(e *Evaluator) {
+		if threshold > 0 && threshold <= 1 {
+			e.partialThreshold = threshold
+		}
+	}
+}
+
+// WithPartialMatchDisabled 禁用部分匹配
+//
+// 禁用后包含、词汇覆盖和列表覆盖均不再计为部分匹配，只统计精确匹配。
+func WithPartialMatchDisabled() EvaluatorOption {
+	return func(e *Evaluator) {
+		e.partialDisabled = true
+	}
 }
 
 // NewEvaluator 创建 GAIA 评估器
-func NewEvaluator(dataset *Dataset) *Evaluator {
-	return &Evaluator{
-		dataset: dataset,
+//
+// dataset 通常为 GAIA 数据集，也可以是 evaluation.NewMemoryDataset 等任意数据集。
+// 对 GAIA 数据集，样本附件会相对数据集目录解析为绝对路径，通过 Context["files"] 传给智能体，
+// 原始文件名保留在 Context["file_names"] 中；附件不存在时样本记为错误，不调用智能体。
+// 其他数据集的附件按原始文件名传递。
+func NewEvaluator(dataset evaluation.Dataset, opts ...EvaluatorOption) *Evaluator {
+	files, _ := dataset.(*Dataset)
+	e := &Evaluator{
+		dataset:        dataset,
+		files:          files,
+		listDelimiters: defaultListDelimiters,
+	}
+	for _, opt := range opts {
+		opt(e)
 	}
+	return e
 }
 
 // Name 返回评估器名称
@@ -31,6 +146,9 @@ func (e *Evaluator) Name() string {
 }
 
 // Evaluate 执行完整评估
+//
+// ctx 取消或 FailFast 提前终止时，返回已完成样本的结果及对应错误：TotalSamples、
+// 准确率和各项指标均只统计已完成的样本。
 func (e *Evaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...evaluation.EvalOption) (*evaluation.EvalResult, error) {
 	config := evaluation.DefaultEvalConfig()
 	config.ApplyOptions(opts...)
@@ -47,54 +165,36 @@ func (e *Evaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...ev
 		DetailedResults: make([]*evaluation.SampleResult, 0),
 		LevelMetrics:    make(map[int]*evaluation.LevelMetrics),
 		EvaluationTime:  startTime,
+		RunConfig:       config.Summary(),
 	}
 
-	total := e.dataset.Len()
-	if config.MaxSamples > 0 && config.MaxSamples < total {
-		total = config.MaxSamples
-	}
+	// 按配置打乱、分层抽样并截断样本
+	dataset := evaluation.SelectSamples(config, e.dataset)
+	total := dataset.Len()
 	result.TotalSamples = total
 
-	// 遍历样本进行评估
-	for i := 0; i < total; i++ {
-		select {
-		case <-ctx.Done():
-			return result, ctx.Err()
-		default:
-		}
-
-		sample, err := e.dataset.Get(i)
-		if err != nil {
-			continue
-		}
-
-		// 应用超时
-		evalCtx := ctx
-		if config.Timeout > 0 {
-			var cancel context.CancelFunc
-			evalCtx, cancel = context.WithTimeout(ctx, config.Timeout)
-			defer cancel()
-		}
+	// 智能体支持批量执行时按批次调度
+	runner := evaluation.NewBatchSampleRunner(agent, dataset, config.BatchSize, e.buildInput,
+		evaluation.WithBatchContext(ctx), evaluation.WithBatchTimeout(config.Timeout))
+	if runner.Batched() {
+		result.RunConfig["batch_size"] = config.BatchSize
+	}
 
-		sampleResult, err := e.EvaluateSample(evalCtx, agent, sample)
-		if err != nil {
-			sampleResult = &evaluation.SampleResult{
-				SampleID: sample.ID,
-				Level:    sample.Level,
-				Error:    err.Error(),
-				Success:  false,
-			}
-		}
+	// 并发评估样本（结果保持样本顺序）
+	sampleResults, runErr := evaluation.RunSamples(ctx, config, dataset, total,
+		func(ctx context.Context, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+			return e.evaluateSample(ctx, config, runner, sample)
+		})
 
-		result.DetailedResults = append(result.DetailedResults, sampleResult)
-		if sampleResult.Success {
+	result.DetailedResults = append(result.DetailedResults, sampleResults...)
+	for _, r := range sampleResults {
+		if r.Success {
 			result.SuccessCount++
 		}
-
-		// 进度回调
-		if config.ProgressCallback != nil {
-			config.ProgressCallback(i+1, total)
-		}
+	}
+	// 提前终止（ctx 取消或 FailFast）时只统计已完成的样本
+	if runErr != nil {
+		result.TotalSamples = len(sampleResults)
 	}
 
 	result.TotalDuration = time.Since(startTime)
@@ -107,13 +207,23 @@ func (e *Evaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...ev
 
 	// 计算汇总指标
 	metrics := NewMetrics()
+	metrics.HistogramEdges = config.ScoreHistogramEdges
 	result.Metrics = metrics.Compute(result.DetailedResults)
 
-	return result, nil
+	return result, runErr
 }
 
 // EvaluateSample 评估单个样本
+//
+// 智能体执行失败记录在结果的 Error 字段中，不作为错误返回。
 func (e *Evaluator) EvaluateSample(ctx context.Context, agent agents.Agent, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+	result, _ := e.evaluateSample(ctx, evaluation.DefaultEvalConfig(), evaluation.NewSampleRunner(agent), sample)
+	return result, nil
+}
+
+// evaluateSample 评估单个样本，返回结果及智能体执行错误（用于 FailFast）
+func (e *Evaluator) evaluateSample(ctx context.Context, config *evaluation.EvalConfig, runner *evaluation.SampleRunner,
+	sample evaluation.Sample) (*evaluation.SampleResult, error) {
 	startTime := time.Now()
 
 	result := &evaluation.SampleResult{
@@ -124,27 +234,29 @@ func (e *Evaluator) EvaluateSample(ctx context.Context, agent agents.Agent, samp
 		Details:  make(map[string]interface{}),
 	}
 
-	// 构建输入
-	input := agents.Input{
-		Query: sample.Input,
-		Context: map[string]interface{}{
-			"files": sample.Files,
-		},
+	// 构建输入（解析附件）
+	input, err := e.sampleInput(sample)
+	if err != nil {
+		result.Error = err.Error()
+		result.Details["attachment_error"] = true
+		result.ExecutionTime = time.Since(startTime)
+		return result, nil
 	}
 
 	// 调用智能体
-	output, err := agent.Run(ctx, input)
+	output, err := runner.RunSample(ctx, sample, input, result)
 	if err != nil {
 		result.Error = err.Error()
 		result.ExecutionTime = time.Since(startTime)
-		return result, nil
+		return result, err
 	}
 
-	result.AgentResponse = output.Response
+	result.AgentResponse = config.TruncateResponse(result, output.Response)
 	result.ExecutionTime = time.Since(startTime)
+	result.AddTokenUsage(evaluation.AgentTokenUsage(runner.Agent(), output))
 
 	// 从响应中提取答案
-	predictedAnswer := e.extractAnswer(output.Response)
+	predictedAnswer := e.extractAnswer(result.AgentResponse)
 	result.Predicted = predictedAnswer
 	result.Details["extracted_answer"] = predictedAnswer
 
@@ -156,9 +268,11 @@ func (e *Evaluator) EvaluateSample(ctx context.Context, agent agents.Agent, samp
 	}
 
 	// 评估匹配
-	exactMatch, partialMatch := e.evaluateMatch(predictedAnswer, expectedAnswer)
+	m := e.match(predictedAnswer, expectedAnswer)
+	exactMatch, partialMatch := m.exact, m.partial
 	result.Success = exactMatch
 	result.PartialSuccess = partialMatch
+	result.Confidence = m.confidence
 
 	if exactMatch {
 		result.Score = 1.0
@@ -168,12 +282,73 @@ func (e *Evaluator) EvaluateSample(ctx context.Context, agent agents.Agent, samp
 
 	result.Details["exact_match"] = exactMatch
 	result.Details["partial_match"] = partialMatch
+	if m.reason != "" {
+		result.Details["partial_reason"] = m.reason
+	}
 
 	return result, nil
 }
 
+// buildInput 构建智能体输入（附件解析失败的样本不参与批量执行）
+func (e *Evaluator) buildInput(sample evaluation.Sample) (agents.Input, bool) {
+	input, err := e.sampleInput(sample)
+	return input, err == nil
+}
+
+// sampleInput 构建智能体输入，将附件解析为绝对路径并按配置内联小文件
+func (e *Evaluator) sampleInput(sample evaluation.Sample) (agents.Input, error) {
+	input := agents.Input{
+		Query: sample.Input,
+		Context: map[string]interface{}{
+			"files": sample.Files,
+		},
+	}
+	if len(sample.Files) == 0 || e.files == nil {
+		return input, nil
+	}
+
+	paths := make([]string, len(sample.Files))
+	var contents map[string][]byte
+	for i, name := range sample.Files {
+		path, err := e.files.ResolveFile(name)
+		if err != nil {
+			return input, err
+		}
+		paths[i] = path
+
+		if e.inlineFileBytes <= 0 {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.Size() > e.inlineFileBytes {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return input, fmt.Errorf("读取附件 %s 失败: %w", name, err)
+		}
+		if contents == nil {
+			contents = make(map[string][]byte)
+		}
+		contents[name] = data
+	}
+
+	input.Context["files"] = paths
+	input.Context["file_names"] = sample.Files
+	if contents != nil {
+		input.Context["file_contents"] = contents
+	}
+	return input, nil
+}
+
 // extractAnswer 从响应中提取答案
+//
+// 配置了 AnswerExtractor 时使用自定义提取，否则按内置模式提取。
 func (e *Evaluator) extractAnswer(response string) string {
+	if e.answerExtractor != nil {
+		return e.answerExtractor(response)
+	}
+
 	response = strings.TrimSpace(response)
 	if response == "" {
 		return ""
@@ -207,24 +382,78 @@ func (e *Evaluator) extractAnswer(response string) string {
 	return response
 }
 
+// matchResult 答案匹配结果
+type matchResult struct {
+	// exact 是否精确匹配
+	exact bool
+	// partial 是否部分匹配
+	partial bool
+	// confidence 匹配强度（0-1）：精确匹配为 1，部分匹配为覆盖率
+	confidence float64
+	// reason 部分匹配的判定依据（如 "substring"、"word_coverage=0.8"），非部分匹配时为空
+	reason string
+}
+
 // evaluateMatch 评估答案匹配
-func (e *Evaluator) evaluateMatch(predicted, expected string) (exactMatch, partialMatch bool) {
+//
+// reason 说明部分匹配（非精确匹配）的判定依据：
+//   - substring: 预测答案与期望答案互相包含
+//   - word_coverage=<覆盖率>: 期望答案的词汇覆盖率达到阈值（默认 70%）
+//   - list_coverage=<覆盖率>: 列表答案的元素覆盖率达到 50%
+func (e *Evaluator) evaluateMatch(predicted, expected string) (exactMatch, partialMatch bool, reason string) {
+	m := e.match(predicted, expected)
+	return m.exact, m.partial, m.reason
+}
+
+// match 评估答案匹配及匹配强度（禁用部分匹配时只保留精确匹配）
+func (e *Evaluator) match(predicted, expected string) matchResult {
+	m := e.compare(predicted, expected)
+	if e.partialDisabled && !m.exact {
+		m.partial = false
+		m.reason = ""
+	}
+	return m
+}
+
+// partialMatchThreshold 返回生效的词汇覆盖率阈值
+func (e *Evaluator) partialMatchThreshold() float64 {
+	if e.partialThreshold > 0 {
+		return e.partialThreshold
+	}
+	return defaultPartialMatchThreshold
+}
+
+// compare 比较预测答案与期望答案
+func (e *Evaluator) compare(predicted, expected string) matchResult {
 	// 标准化答案
 	normalizedPred := normalizeAnswer(predicted)
 	normalizedExp := normalizeAnswer(expected)
 
+	// 布尔答案规范化（yes/true 等价）
+	normalizedPred, normalizedExp = canonicalizeBooleans(normalizedPred, normalizedExp)
+
 	// 精确匹配
 	if normalizedPred == normalizedExp {
-		return true, true
+		return matchResult{exact: true, partial: true, confidence: 1.0}
 	}
 
-	// 部分匹配检查
-	// 1. 包含检查
-	if strings.Contains(normalizedPred, normalizedExp) || strings.Contains(normalizedExp, normalizedPred) {
-		return false, true
+	// 数值匹配
+	if predNum, ok := parseNumber(normalizedPred); ok {
+		if expNum, ok := parseNumber(normalizedExp); ok {
+			if e.numbersEqual(predNum, expNum) {
+				return matchResult{exact: true, partial: true, confidence: 1.0}
+			}
+			return matchResult{confidence: numericCloseness(predNum, expNum)}
+		}
 	}
 
-	// 2. 词汇覆盖检查（70% 阈值）
+	// 列表匹配（无序集合）
+	if e.isListAnswer(normalizedExp) {
+		return e.evaluateListMatch(normalizedPred, normalizedExp)
+	}
+
+	// 词汇覆盖率
+	coverage := 0.0
 	expectedWords := strings.Fields(normalizedExp)
 	if len(expectedWords) > 0 {
 		matchedCount := 0
@@ -233,13 +462,132 @@ func (e *Evaluator) evaluateMatch(predicted, expected string) (exactMatch, parti
 				matchedCount++
 			}
 		}
-		coverage := float64(matchedCount) / float64(len(expectedWords))
-		if coverage >= 0.7 {
-			return false, true
+		coverage = float64(matchedCount) / float64(len(expectedWords))
+	}
+
+	// 部分匹配检查
+	// 1. 包含检查
+	if strings.Contains(normalizedPred, normalizedExp) || strings.Contains(normalizedExp, normalizedPred) {
+		return matchResult{partial: true, confidence: coverage, reason: "substring"}
+	}
+
+	// 2. 词汇覆盖检查
+	if coverage >= e.partialMatchThreshold() {
+		return matchResult{partial: true, confidence: coverage, reason: coverageReason("word_coverage", coverage)}
+	}
+
+	return matchResult{confidence: coverage}
+}
+
+// numericCloseness 计算两个数值的接近程度（1 - 相对误差，截断到 [0, 1]）
+func numericCloseness(a, b float64) float64 {
+	scale := math.Max(math.Abs(a), math.Abs(b))
+	if scale == 0 {
+		return 1.0
+	}
+	return math.Max(0, 1-math.Abs(a-b)/scale)
+}
+
+// isListAnswer 判断答案是否为列表形式
+func (e *Evaluator) isListAnswer(answer string) bool {
+	for _, d := range e.listDelimiters {
+		if d != "" && strings.Contains(answer, d) {
+			return true
 		}
 	}
+	return false
+}
 
-	return false, false
+// splitList 将列表答案拆分为标准化后的集合
+func (e *Evaluator) splitList(answer string) map[string]bool {
+	parts := []string{answer}
+	for _, d := range e.listDelimiters {
+		if d == "" {
+			continue
+		}
+		var next []string
+		for _, p := range parts {
+			next = append(next, strings.Split(p, d)...)
+		}
+		parts = next
+	}
+
+	items := make(map[string]bool, len(parts))
+	for _, p := range parts {
+		if item := normalizeAnswer(p); item != "" {
+			items[item] = true
+		}
+	}
+	return items
+}
+
+// evaluateListMatch 按无序集合评估列表答案
+//
+// 集合完全相等为精确匹配，覆盖至少一半期望元素为部分匹配，匹配强度为期望元素覆盖率。
+func (e *Evaluator) evaluateListMatch(predicted, expected string) matchResult {
+	expItems := e.splitList(expected)
+	predItems := e.splitList(predicted)
+	if len(expItems) == 0 {
+		return matchResult{}
+	}
+
+	matched := 0
+	for item := range expItems {
+		if e.listContains(predItems, item) {
+			matched++
+		}
+	}
+
+	if matched == len(expItems) && len(predItems) == len(expItems) {
+		return matchResult{exact: true, partial: true, confidence: 1.0}
+	}
+
+	coverage := float64(matched) / float64(len(expItems))
+	if coverage < 0.5 {
+		return matchResult{confidence: coverage}
+	}
+	return matchResult{partial: true, confidence: coverage, reason: coverageReason("list_coverage", coverage)}
+}
+
+// coverageReason 生成覆盖率类部分匹配依据（覆盖率保留两位小数）
+func coverageReason(kind string, coverage float64) string {
+	return fmt.Sprintf("%s=%g", kind, math.Round(coverage*100)/100)
+}
+
+// listContains 判断集合中是否包含元素（数值元素按容差比较）
+func (e *Evaluator) listContains(items map[string]bool, item string) bool {
+	if items[item] {
+		return true
+	}
+	num, ok := parseNumber(item)
+	if !ok {
+		return false
+	}
+	for candidate := range items {
+		if v, ok := parseNumber(candidate); ok && e.numbersEqual(v, num) {
+			return true
+		}
+	}
+	return false
+}
+
+// numbersEqual 按容差比较两个数值
+func (e *Evaluator) numbersEqual(a, b float64) bool {
+	diff := math.Abs(a - b)
+	tolerance := math.Max(e.absTolerance, e.relTolerance*math.Max(math.Abs(a), math.Abs(b)))
+	return diff <= tolerance
+}
+
+// parseNumber 将标准化后的答案解析为数字（支持科学计数法）
+func parseNumber(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
 }
 
 // normalizeAnswer 标准化答案
@@ -277,6 +625,42 @@ func normalizeAnswer(answer string) string {
 	return answer
 }
 
+// booleanTokens 布尔答案词到规范形式的映射
+var booleanTokens = map[string]string{
+	"yes":       "true",
+	"true":      "true",
+	"correct":   "true",
+	"no":        "false",
+	"false":     "false",
+	"incorrect": "false",
+}
+
+// numericBooleanTokens 数字形式的布尔答案到规范形式的映射
+var numericBooleanTokens = map[string]string{
+	"1": "true",
+	"0": "false",
+}
+
+// canonicalizeBooleans 将两个已标准化的答案转为布尔规范形式
+//
+// 仅当两者都是布尔答案时才转换，否则原样返回（如 "yesterday"、"10" 不受影响）。
+// 数字 1/0 只在期望答案本身是布尔词时才视为布尔答案，期望答案为 0/1 的计数题
+// 不会与 "no"/"yes" 误判为一致。
+func canonicalizeBooleans(predicted, expected string) (string, string) {
+	canonExp, ok := booleanTokens[expected]
+	if !ok {
+		return predicted, expected
+	}
+	canonPred, ok := booleanTokens[predicted]
+	if !ok {
+		canonPred, ok = numericBooleanTokens[predicted]
+	}
+	if !ok {
+		return predicted, expected
+	}
+	return canonPred, canonExp
+}
+
 // removeNumberCommas 移除数字中的逗号
 func removeNumberCommas(s string) string {
 	// 匹配形如 1,000 或 1,000,000 的数字
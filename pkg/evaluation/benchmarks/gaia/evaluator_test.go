@@ -1,7 +1,22 @@
 package gaia
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/config"
+	"github.com/ahhsitt/helloagents-go/pkg/core/message"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
 )
 
 func TestEvaluator_ExtractAnswer(t *testing.T) {
@@ -98,7 +113,7 @@ func TestEvaluator_EvaluateMatch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotExact, gotPartial := evaluator.evaluateMatch(tt.predicted, tt.expected)
+			gotExact, gotPartial, _ := evaluator.evaluateMatch(tt.predicted, tt.expected)
 			if gotExact != tt.wantExact {
 				t.Errorf("evaluateMatch() exactMatch = %v, want %v", gotExact, tt.wantExact)
 			}
@@ -160,3 +175,1075 @@ func TestNewEvaluator(t *testing.T) {
 		t.Errorf("Name() = %s, want GAIA_validation_Level1", name)
 	}
 }
+
+// mockAgent 返回固定响应的测试智能体
+type mockAgent struct {
+	response string
+}
+
+func (m *mockAgent) Name() string {
+	return "mock"
+}
+
+func (m *mockAgent) Config() config.AgentConfig {
+	return config.AgentConfig{}
+}
+
+func (m *mockAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	return agents.Output{Response: m.response}, nil
+}
+
+func (m *mockAgent) RunStream(ctx context.Context, input agents.Input) (<-chan agents.StreamChunk, <-chan error) {
+	ch := make(chan agents.StreamChunk)
+	errCh := make(chan error)
+	go func() {
+		ch <- agents.StreamChunk{Content: m.response, Done: true}
+		close(ch)
+		close(errCh)
+	}()
+	return ch, errCh
+}
+
+// flakyDataset 指定索引 Get 失败的测试数据集
+type flakyDataset struct {
+	samples   []evaluation.Sample
+	failIndex int
+}
+
+func (d *flakyDataset) Load(ctx context.Context) error { return nil }
+func (d *flakyDataset) Len() int                       { return len(d.samples) }
+func (d *flakyDataset) Name() string                   { return "flaky" }
+
+func (d *flakyDataset) Get(index int) (evaluation.Sample, error) {
+	if index == d.failIndex {
+		return evaluation.Sample{}, errors.New("corrupt sample")
+	}
+	return d.samples[index], nil
+}
+
+func (d *flakyDataset) Iterator() <-chan evaluation.Sample {
+	ch := make(chan evaluation.Sample)
+	close(ch)
+	return ch
+}
+
+func TestEvaluator_Evaluate_DatasetGetFailure(t *testing.T) {
+	samples := make([]evaluation.Sample, 3)
+	for i := range samples {
+		samples[i] = evaluation.Sample{ID: fmt.Sprintf("q%d", i), Input: "question", Expected: "42", Level: 1}
+	}
+
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: 1}
+
+	result, err := evaluator.Evaluate(context.Background(), &mockAgent{response: "FINAL ANSWER: 42"})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if result.TotalSamples != 3 {
+		t.Errorf("TotalSamples = %d, want 3", result.TotalSamples)
+	}
+	if len(result.DetailedResults) != 3 {
+		t.Fatalf("len(DetailedResults) = %d, want 3", len(result.DetailedResults))
+	}
+	if result.SuccessCount != 2 {
+		t.Errorf("SuccessCount = %d, want 2", result.SuccessCount)
+	}
+
+	failed := result.DetailedResults[1]
+	if failed.Success || failed.Error == "" {
+		t.Errorf("expected failed result with error, got %+v", failed)
+	}
+	if failed.Details["index"] != 1 {
+		t.Errorf("expected skipped index 1 recorded, got %v", failed.Details["index"])
+	}
+}
+
+func TestEvaluator_EvaluateMatch_Numeric(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []EvaluatorOption
+		predicted string
+		expected  string
+		wantExact bool
+	}{
+		{name: "trailing zeros", predicted: "42.0", expected: "42", wantExact: true},
+		{name: "scientific notation", predicted: "1e3", expected: "1000", wantExact: true},
+		{name: "thousands separator", predicted: "1,000", expected: "1e3", wantExact: true},
+		{name: "default is exact", predicted: "3.14159", expected: "3.14", wantExact: false},
+		{
+			name:      "absolute tolerance",
+			opts:      []EvaluatorOption{WithNumericTolerance(0.01, 0)},
+			predicted: "3.14159",
+			expected:  "3.14",
+			wantExact: true,
+		},
+		{
+			name:      "relative tolerance",
+			opts:      []EvaluatorOption{WithNumericTolerance(0, 0.01)},
+			predicted: "1005",
+			expected:  "1000",
+			wantExact: true,
+		},
+		{
+			name:      "outside tolerance",
+			opts:      []EvaluatorOption{WithNumericTolerance(0.01, 0)},
+			predicted: "3.2",
+			expected:  "3.14",
+			wantExact: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewEvaluator(nil, tt.opts...)
+			exact, _, _ := evaluator.evaluateMatch(tt.predicted, tt.expected)
+			if exact != tt.wantExact {
+				t.Errorf("evaluateMatch(%q, %q) exact = %v, want %v", tt.predicted, tt.expected, exact, tt.wantExact)
+			}
+		})
+	}
+}
+
+func TestEvaluator_EvaluateMatch_PartialReason(t *testing.T) {
+	evaluator := NewEvaluator(nil)
+
+	tests := []struct {
+		name       string
+		predicted  string
+		expected   string
+		wantReason string
+	}{
+		{name: "containment", predicted: "the answer is paris", expected: "paris", wantReason: "substring"},
+		{name: "reverse containment", predicted: "paris", expected: "paris france", wantReason: "substring"},
+		{name: "70% coverage", predicted: "seven six five four three two one", expected: "one two three four five six seven eight nine ten", wantReason: "word_coverage=0.7"},
+		{name: "below coverage threshold", predicted: "gamma alpha beta zeta", expected: "alpha beta gamma delta epsilon", wantReason: ""},
+		{name: "80% coverage", predicted: "delta gamma beta alpha", expected: "alpha beta gamma delta epsilon", wantReason: "word_coverage=0.8"},
+		{name: "list coverage", predicted: "apple, banana", expected: "apple, banana, cherry", wantReason: "list_coverage=0.67"},
+		{name: "exact match", predicted: "Paris", expected: "paris", wantReason: ""},
+		{name: "no match", predicted: "apple", expected: "orange", wantReason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, reason := evaluator.evaluateMatch(tt.predicted, tt.expected)
+			if reason != tt.wantReason {
+				t.Errorf("evaluateMatch(%q, %q) reason = %q, want %q", tt.predicted, tt.expected, reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEvaluator_WithPartialMatchThreshold(t *testing.T) {
+	// 期望答案 5 个词中命中 4 个，覆盖率 0.8
+	const predicted = "delta gamma beta alpha"
+	const expected = "alpha beta gamma delta epsilon"
+
+	tests := []struct {
+		name        string
+		opts        []EvaluatorOption
+		wantPartial bool
+	}{
+		{name: "default threshold", wantPartial: true},
+		{name: "lenient threshold", opts: []EvaluatorOption{WithPartialMatchThreshold(0.5)}, wantPartial: true},
+		{name: "threshold at coverage", opts: []EvaluatorOption{WithPartialMatchThreshold(0.8)}, wantPartial: true},
+		{name: "strict threshold", opts: []EvaluatorOption{WithPartialMatchThreshold(0.9)}, wantPartial: false},
+		{name: "zero ignored", opts: []EvaluatorOption{WithPartialMatchThreshold(0)}, wantPartial: true},
+		{name: "above one ignored", opts: []EvaluatorOption{WithPartialMatchThreshold(1.5)}, wantPartial: true},
+		{name: "disabled", opts: []EvaluatorOption{WithPartialMatchDisabled()}, wantPartial: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewEvaluator(nil, tt.opts...)
+			exact, partial, reason := evaluator.evaluateMatch(predicted, expected)
+			if exact || partial != tt.wantPartial {
+				t.Errorf("evaluateMatch() = (%v, %v), want (false, %v)", exact, partial, tt.wantPartial)
+			}
+			if !partial && reason != "" {
+				t.Errorf("evaluateMatch() reason = %q, want empty without partial match", reason)
+			}
+		})
+	}
+}
+
+func TestEvaluator_WithPartialMatchDisabled(t *testing.T) {
+	evaluator := NewEvaluator(nil, WithPartialMatchDisabled())
+
+	tests := []struct {
+		predicted string
+		expected  string
+		wantExact bool
+	}{
+		{predicted: "the answer is paris", expected: "paris", wantExact: false},
+		{predicted: "apple, banana", expected: "apple, banana, cherry", wantExact: false},
+		{predicted: "banana, apple", expected: "apple, banana", wantExact: true},
+		{predicted: "Paris", expected: "paris", wantExact: true},
+	}
+
+	for _, tt := range tests {
+		exact, partial, _ := evaluator.evaluateMatch(tt.predicted, tt.expected)
+		if exact != tt.wantExact || partial != tt.wantExact {
+			t.Errorf("evaluateMatch(%q, %q) = (%v, %v), want (%v, %v)",
+				tt.predicted, tt.expected, exact, partial, tt.wantExact, tt.wantExact)
+		}
+	}
+}
+
+func TestEvaluator_EvaluateMatch_Boolean(t *testing.T) {
+	evaluator := NewEvaluator(nil)
+
+	tests := []struct {
+		predicted string
+		expected  string
+		wantExact bool
+	}{
+		{predicted: "Yes", expected: "true", wantExact: true},
+		{predicted: "No.", expected: "false", wantExact: true},
+		{predicted: "correct", expected: "Yes", wantExact: true},
+		{predicted: "incorrect", expected: "No", wantExact: true},
+		// 期望答案为布尔词时 1/0 视为布尔答案
+		{predicted: "0", expected: "incorrect", wantExact: true},
+		{predicted: "1", expected: "Yes", wantExact: true},
+		{predicted: "1", expected: "false", wantExact: false},
+		// 期望答案为数字（如计数题）时不按布尔词比较
+		{predicted: "no", expected: "0", wantExact: false},
+		{predicted: "yes", expected: "1", wantExact: false},
+		{predicted: "correct", expected: "1", wantExact: false},
+		{predicted: "yes", expected: "false", wantExact: false},
+		{predicted: "yesterday", expected: "yes", wantExact: false},
+		{predicted: "yesterday", expected: "true", wantExact: false},
+		{predicted: "10", expected: "1", wantExact: false},
+	}
+
+	for _, tt := range tests {
+		exact, _, _ := evaluator.evaluateMatch(tt.predicted, tt.expected)
+		if exact != tt.wantExact {
+			t.Errorf("evaluateMatch(%q, %q) exact = %v, want %v", tt.predicted, tt.expected, exact, tt.wantExact)
+		}
+	}
+}
+
+func TestEvaluator_EvaluateMatch_List(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []EvaluatorOption
+		predicted   string
+		expected    string
+		wantExact   bool
+		wantPartial bool
+	}{
+		{name: "reordered list", predicted: "banana,apple", expected: "apple, banana", wantExact: true, wantPartial: true},
+		{name: "semicolon list", predicted: "Paris; London", expected: "london;paris", wantExact: true, wantPartial: true},
+		{name: "numeric items", predicted: "2.0, 1", expected: "1, 2", wantExact: true, wantPartial: true},
+		{name: "significant overlap", predicted: "apple, banana", expected: "apple, banana, cherry", wantExact: false, wantPartial: true},
+		{name: "extra item", predicted: "apple, banana, cherry", expected: "apple, banana", wantExact: false, wantPartial: true},
+		{name: "little overlap", predicted: "apple, kiwi", expected: "apple, banana, cherry, date", wantExact: false, wantPartial: false},
+		{name: "single token unchanged", predicted: "apple", expected: "apple", wantExact: true, wantPartial: true},
+		{
+			name:        "custom delimiter",
+			opts:        []EvaluatorOption{WithListDelimiters("|")},
+			predicted:   "b|a",
+			expected:    "a|b",
+			wantExact:   true,
+			wantPartial: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewEvaluator(nil, tt.opts...)
+			exact, partial, _ := evaluator.evaluateMatch(tt.predicted, tt.expected)
+			if exact != tt.wantExact || partial != tt.wantPartial {
+				t.Errorf("evaluateMatch(%q, %q) = (%v, %v), want (%v, %v)",
+					tt.predicted, tt.expected, exact, partial, tt.wantExact, tt.wantPartial)
+			}
+		})
+	}
+}
+
+func TestEvaluator_Evaluate_ExpectedOverrides(t *testing.T) {
+	samples := []evaluation.Sample{
+		{ID: "q0", Input: "question", Expected: "41", Level: 1},
+	}
+
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+	agent := &mockAgent{response: "FINAL ANSWER: 42"}
+
+	result, err := evaluator.Evaluate(context.Background(), agent)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.SuccessCount != 0 {
+		t.Fatalf("expected sample to fail without override, got %d successes", result.SuccessCount)
+	}
+
+	result, err = evaluator.Evaluate(context.Background(), agent,
+		evaluation.WithExpectedOverrides(map[string]interface{}{"q0": "42"}))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.SuccessCount != 1 {
+		t.Errorf("expected override to flip sample to pass, got %d successes", result.SuccessCount)
+	}
+	if result.DetailedResults[0].Expected != "42" {
+		t.Errorf("expected result to record overridden answer, got %v", result.DetailedResults[0].Expected)
+	}
+}
+
+func TestEvaluator_Evaluate_MemoryDataset(t *testing.T) {
+	dataset := evaluation.NewMemoryDataset("custom", []evaluation.Sample{
+		{ID: "a", Input: "question 1", Expected: "1", Level: 1},
+		{ID: "b", Input: "question 2", Expected: "3", Level: 2},
+		{ID: "c", Input: "question 4", Expected: "4", Level: 2, Files: []string{"notes.txt"}},
+	})
+	evaluator := NewEvaluator(dataset)
+	if evaluator.Name() != "custom" {
+		t.Errorf("Name() = %s, want custom", evaluator.Name())
+	}
+
+	result, err := evaluator.Evaluate(context.Background(), &slowAgent{mockAgent: mockAgent{}})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.TotalSamples != 3 || result.SuccessCount != 2 {
+		t.Errorf("TotalSamples = %d, SuccessCount = %d, want 3 and 2", result.TotalSamples, result.SuccessCount)
+	}
+	for i, want := range []bool{true, false, true} {
+		if got := result.DetailedResults[i].Success; got != want {
+			t.Errorf("results[%d].Success = %v, want %v", i, got, want)
+		}
+	}
+	if result.LevelMetrics[2] == nil || result.LevelMetrics[2].Total != 2 {
+		t.Errorf("expected level 2 metrics over 2 samples, got %+v", result.LevelMetrics[2])
+	}
+
+	// 非 GAIA 数据集的附件按原始文件名传递，不做解析
+	agent := &contextAgent{}
+	sample, err := dataset.Get(2)
+	if err != nil {
+		t.Fatalf("Get(2) error = %v", err)
+	}
+	if _, err := evaluator.EvaluateSample(context.Background(), agent, sample); err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if files, _ := agent.contexts["question 4"]["files"].([]string); len(files) != 1 || files[0] != "notes.txt" {
+		t.Errorf("Context[files] = %v, want [notes.txt]", agent.contexts["question 4"]["files"])
+	}
+}
+
+// slowAgent 按输入决定延迟、回显答案的测试智能体
+type slowAgent struct {
+	mockAgent
+	delays map[string]time.Duration
+
+	mu       sync.Mutex
+	inFlight int
+	maxSeen  int
+}
+
+func (a *slowAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	a.mu.Lock()
+	a.inFlight++
+	if a.inFlight > a.maxSeen {
+		a.maxSeen = a.inFlight
+	}
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		a.inFlight--
+		a.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(a.delays[input.Query]):
+	case <-ctx.Done():
+		return agents.Output{}, ctx.Err()
+	}
+	return agents.Output{Response: "FINAL ANSWER: " + strings.TrimPrefix(input.Query, "question ")}, nil
+}
+
+func TestEvaluator_Evaluate_Concurrency(t *testing.T) {
+	const n = 8
+	samples := make([]evaluation.Sample, n)
+	delays := make(map[string]time.Duration, n)
+	for i := range samples {
+		query := fmt.Sprintf("question %d", i)
+		samples[i] = evaluation.Sample{ID: fmt.Sprintf("q%d", i), Input: query, Expected: fmt.Sprint(i), Level: 1}
+		// 靠后的样本先完成，验证结果顺序
+		delays[query] = time.Duration(n-i) * 10 * time.Millisecond
+	}
+
+	run := func(concurrency int) (*evaluation.EvalResult, *slowAgent, []int, time.Duration) {
+		evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+		evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+		agent := &slowAgent{mockAgent: mockAgent{}, delays: delays}
+
+		var progress []int
+		start := time.Now()
+		result, err := evaluator.Evaluate(context.Background(), agent,
+			evaluation.WithConcurrency(concurrency),
+			evaluation.WithProgressCallback(func(done, total int) {
+				progress = append(progress, done)
+			}),
+		)
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		return result, agent, progress, time.Since(start)
+	}
+
+	_, _, _, sequential := run(1)
+	result, agent, progress, parallel := run(4)
+
+	if parallel >= sequential*3/4 {
+		t.Errorf("expected speedup with concurrency 4: sequential %v, parallel %v", sequential, parallel)
+	}
+	if agent.maxSeen < 2 || agent.maxSeen > 4 {
+		t.Errorf("expected between 2 and 4 concurrent runs, saw %d", agent.maxSeen)
+	}
+	if result.SuccessCount != n {
+		t.Errorf("SuccessCount = %d, want %d", result.SuccessCount, n)
+	}
+	for i, r := range result.DetailedResults {
+		if want := fmt.Sprintf("q%d", i); r.SampleID != want {
+			t.Errorf("DetailedResults[%d].SampleID = %s, want %s", i, r.SampleID, want)
+		}
+	}
+	if len(progress) != n || progress[n-1] != n {
+		t.Errorf("expected %d progress callbacks ending at %d, got %v", n, n, progress)
+	}
+}
+
+func TestEvaluator_Evaluate_ConcurrencyTimeout(t *testing.T) {
+	samples := []evaluation.Sample{
+		{ID: "fast", Input: "question 1", Expected: "1", Level: 1},
+		{ID: "slow", Input: "question 2", Expected: "2", Level: 1},
+	}
+	delays := map[string]time.Duration{"question 1": 0, "question 2": time.Second}
+
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+
+	result, err := evaluator.Evaluate(context.Background(), &slowAgent{delays: delays},
+		evaluation.WithConcurrency(2),
+		evaluation.WithTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if !result.DetailedResults[0].Success {
+		t.Errorf("expected fast sample to succeed, got %+v", result.DetailedResults[0])
+	}
+	if result.DetailedResults[1].Success || !evaluation.IsTimeoutResult(result.DetailedResults[1]) {
+		t.Errorf("expected slow sample to time out, got %+v", result.DetailedResults[1])
+	}
+	if evaluation.IsTimeoutResult(result.DetailedResults[0]) {
+		t.Errorf("fast sample should not be flagged as timeout, got %+v", result.DetailedResults[0])
+	}
+	if got := result.Metrics.Extra["timeout_count"]; got != 1 {
+		t.Errorf("timeout_count = %v, want 1", got)
+	}
+}
+
+// failingAgent 对指定输入返回错误的测试智能体
+type failingAgent struct {
+	mockAgent
+	failQuery string
+	calls     int
+}
+
+func (a *failingAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	a.calls++
+	if input.Query == a.failQuery {
+		return agents.Output{}, errors.New("tool crashed")
+	}
+	return agents.Output{Response: a.response}, nil
+}
+
+func TestEvaluator_Evaluate_FailFast(t *testing.T) {
+	samples := make([]evaluation.Sample, 5)
+	for i := range samples {
+		samples[i] = evaluation.Sample{ID: fmt.Sprintf("q%d", i), Input: fmt.Sprintf("question %d", i), Expected: "42", Level: 1}
+	}
+
+	tests := []struct {
+		name        string
+		failFast    bool
+		wantResults int
+		wantErr     bool
+	}{
+		{"fail fast", true, 3, true},
+		{"default", false, 5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+			evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+			agent := &failingAgent{mockAgent: mockAgent{response: "FINAL ANSWER: 42"}, failQuery: "question 2"}
+
+			result, err := evaluator.Evaluate(context.Background(), agent, evaluation.WithFailFast(tt.failFast))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), "tool crashed") {
+				t.Errorf("expected offending error in %q", err)
+			}
+			if len(result.DetailedResults) != tt.wantResults || agent.calls != tt.wantResults {
+				t.Errorf("expected %d results and agent calls, got %d results and %d calls",
+					tt.wantResults, len(result.DetailedResults), agent.calls)
+			}
+			if failed := result.DetailedResults[2]; failed.Success || failed.Error == "" {
+				t.Errorf("expected sample 2 to record the agent error, got %+v", failed)
+			}
+
+			// 提前终止时仍按已完成的样本计算指标
+			wantAccuracy := float64(tt.wantResults-1) / float64(tt.wantResults)
+			if result.TotalSamples != tt.wantResults || result.OverallAccuracy != wantAccuracy {
+				t.Errorf("TotalSamples = %d, OverallAccuracy = %v, want %d, %v",
+					result.TotalSamples, result.OverallAccuracy, tt.wantResults, wantAccuracy)
+			}
+			if result.TotalDuration <= 0 || result.Metrics == nil || result.LevelMetrics[1] == nil {
+				t.Errorf("expected duration, metrics and level metrics, got %v, %+v, %v",
+					result.TotalDuration, result.Metrics, result.LevelMetrics)
+			}
+		})
+	}
+}
+
+// crashingAgent 在第 crashAt 次调用时取消上下文，模拟运行中途崩溃
+type crashingAgent struct {
+	mockAgent
+	crashAt int
+	cancel  context.CancelFunc
+	calls   int
+}
+
+func (a *crashingAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	a.calls++
+	if a.calls == a.crashAt {
+		a.cancel()
+		return agents.Output{}, ctx.Err()
+	}
+	return agents.Output{Response: a.response}, nil
+}
+
+func TestEvaluator_Evaluate_CheckpointResume(t *testing.T) {
+	samples := make([]evaluation.Sample, 5)
+	for i := range samples {
+		samples[i] = evaluation.Sample{ID: fmt.Sprintf("q%d", i), Input: fmt.Sprintf("question %d", i), Expected: "42", Level: 1}
+	}
+	checkpointPath := filepath.Join(t.TempDir(), "run.jsonl")
+
+	newEvaluator := func() *Evaluator {
+		evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+		evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+		return evaluator
+	}
+
+	// 第一次运行：完成 3 个样本后崩溃
+	ctx, cancel := context.WithCancel(context.Background())
+	crashing := &crashingAgent{mockAgent: mockAgent{response: "FINAL ANSWER: 42"}, crashAt: 4, cancel: cancel}
+	if _, err := newEvaluator().Evaluate(ctx, crashing, evaluation.WithCheckpoint(checkpointPath)); err == nil {
+		t.Fatal("expected first run to be interrupted")
+	}
+
+	// 模拟崩溃时写了一半的最后一行
+	f, err := os.OpenFile(checkpointPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open checkpoint: %v", err)
+	}
+	_, _ = f.WriteString(`{"sample_id":"q3","succ`)
+	_ = f.Close()
+
+	// 第二次运行：跳过已完成的样本
+	agent := &failingAgent{mockAgent: mockAgent{response: "FINAL ANSWER: 42"}}
+	result, err := newEvaluator().Evaluate(context.Background(), agent, evaluation.WithCheckpoint(checkpointPath))
+	if err != nil {
+		t.Fatalf("resumed Evaluate() error = %v", err)
+	}
+
+	if agent.calls != 2 {
+		t.Errorf("expected 2 samples evaluated on resume, got %d", agent.calls)
+	}
+	seen := make(map[string]bool)
+	for _, r := range result.DetailedResults {
+		seen[r.SampleID] = true
+	}
+	if len(result.DetailedResults) != 5 || len(seen) != 5 {
+		t.Errorf("expected 5 unique results, got %d results (%d unique)", len(result.DetailedResults), len(seen))
+	}
+	if result.SuccessCount != 5 || result.OverallAccuracy != 1.0 {
+		t.Errorf("expected metrics over all 5 samples, got SuccessCount=%d accuracy=%v",
+			result.SuccessCount, result.OverallAccuracy)
+	}
+
+	data, err := os.ReadFile(checkpointPath)
+	if err != nil {
+		t.Fatalf("failed to read checkpoint: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 5 {
+		t.Errorf("expected 5 checkpoint lines, got %d", lines)
+	}
+}
+
+// transientAgent 对每个输入首次调用返回错误、之后返回固定响应的测试智能体
+type transientAgent struct {
+	mockAgent
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (a *transientAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	a.mu.Lock()
+	a.calls[input.Query]++
+	n := a.calls[input.Query]
+	a.mu.Unlock()
+
+	if n == 1 {
+		return agents.Output{}, errors.New("503 service unavailable")
+	}
+	return agents.Output{Response: a.response}, nil
+}
+
+func TestEvaluator_Evaluate_SampleRetries(t *testing.T) {
+	samples := []evaluation.Sample{
+		{ID: "q0", Input: "question 0", Expected: "42", Level: 1},
+		{ID: "q1", Input: "question 1", Expected: "7", Level: 1},
+	}
+
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+	agent := &transientAgent{mockAgent: mockAgent{response: "FINAL ANSWER: 42"}, calls: make(map[string]int)}
+
+	result, err := evaluator.Evaluate(context.Background(), agent, evaluation.WithSampleRetries(3))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	first := result.DetailedResults[0]
+	if !first.Success || first.Error != "" {
+		t.Errorf("expected retried sample to succeed, got %+v", first)
+	}
+	if first.Details["retry_count"] != 1 {
+		t.Errorf("expected retry_count 1, got %v", first.Details["retry_count"])
+	}
+
+	// 答案错误的样本在重试后仍然失败，但不再因答案错误继续重试
+	second := result.DetailedResults[1]
+	if second.Success || second.Error != "" {
+		t.Errorf("expected wrong answer without error, got %+v", second)
+	}
+	if agent.calls["question 1"] != 2 {
+		t.Errorf("expected wrong-answer sample to stop retrying after error cleared, got %d calls", agent.calls["question 1"])
+	}
+	if result.SuccessCount != 1 {
+		t.Errorf("SuccessCount = %d, want 1", result.SuccessCount)
+	}
+}
+
+func TestEvaluator_EvaluateSample_Confidence(t *testing.T) {
+	tests := []struct {
+		name           string
+		response       string
+		expected       string
+		wantPartial    bool
+		wantConfidence float64
+	}{
+		{"exact", "FINAL ANSWER: Paris", "Paris", true, 1.0},
+		{"partial coverage", "FINAL ANSWER: quick brown fox jumps high", "quick brown fox jumps over", true, 0.8},
+		{"list coverage", "FINAL ANSWER: red, green", "red, green, blue, yellow", true, 0.5},
+		{"no match", "FINAL ANSWER: London", "Paris", false, 0},
+	}
+
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample := evaluation.Sample{ID: "q", Input: "question", Expected: tt.expected, Level: 1}
+			result, err := evaluator.EvaluateSample(context.Background(), &mockAgent{response: tt.response}, sample)
+			if err != nil {
+				t.Fatalf("EvaluateSample() error = %v", err)
+			}
+			if result.PartialSuccess != tt.wantPartial {
+				t.Errorf("PartialSuccess = %v, want %v", result.PartialSuccess, tt.wantPartial)
+			}
+			if math.Abs(result.Confidence-tt.wantConfidence) > 1e-9 {
+				t.Errorf("Confidence = %v, want %v", result.Confidence, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+// usageAgent 在输出中返回 Token 用量的测试智能体
+type usageAgent struct {
+	mockAgent
+	usage message.TokenUsage
+}
+
+func (a *usageAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	return agents.Output{Response: a.response, TokenUsage: a.usage}, nil
+}
+
+// reporterAgent 通过 UsageReporter 接口报告 Token 用量的测试智能体
+type reporterAgent struct {
+	mockAgent
+}
+
+func (a *reporterAgent) LastTokenUsage() message.TokenUsage {
+	return message.TokenUsage{PromptTokens: 7, CompletionTokens: 3}
+}
+
+func TestEvaluator_Evaluate_TokenUsage(t *testing.T) {
+	samples := make([]evaluation.Sample, 3)
+	for i := range samples {
+		samples[i] = evaluation.Sample{ID: fmt.Sprintf("q%d", i), Input: "question", Expected: "42", Level: 1}
+	}
+
+	tests := []struct {
+		name  string
+		agent agents.Agent
+		want  message.TokenUsage
+	}{
+		{
+			name: "output usage",
+			agent: &usageAgent{
+				mockAgent: mockAgent{response: "FINAL ANSWER: 42"},
+				usage:     message.TokenUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+			},
+			want: message.TokenUsage{PromptTokens: 300, CompletionTokens: 60, TotalTokens: 360},
+		},
+		{
+			name:  "usage reporter",
+			agent: &reporterAgent{mockAgent: mockAgent{response: "FINAL ANSWER: 42"}},
+			want:  message.TokenUsage{PromptTokens: 21, CompletionTokens: 9, TotalTokens: 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+			evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+
+			result, err := evaluator.Evaluate(context.Background(), tt.agent)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if result.Metrics.TokenUsage == nil {
+				t.Fatal("expected TokenUsage in metrics")
+			}
+			if *result.Metrics.TokenUsage != tt.want {
+				t.Errorf("TokenUsage = %+v, want %+v", *result.Metrics.TokenUsage, tt.want)
+			}
+			if got := result.DetailedResults[0].TotalTokens; got != tt.want.TotalTokens/3 {
+				t.Errorf("per-sample TotalTokens = %d, want %d", got, tt.want.TotalTokens/3)
+			}
+		})
+	}
+
+	// 未报告用量的智能体不产生汇总
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+	result, err := evaluator.Evaluate(context.Background(), &mockAgent{response: "FINAL ANSWER: 42"})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if result.Metrics.TokenUsage != nil {
+		t.Errorf("expected nil TokenUsage, got %+v", result.Metrics.TokenUsage)
+	}
+}
+
+// batchAgent 记录批量调用并按输入回显答案的测试智能体
+type batchAgent struct {
+	mockAgent
+	mu      sync.Mutex
+	batches [][]string
+	runs    int
+	// failBatches 前若干次 RunBatch 返回错误
+	failBatches int
+}
+
+func (a *batchAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	a.mu.Lock()
+	a.runs++
+	a.mu.Unlock()
+	return agents.Output{Response: "FINAL ANSWER: " + input.Query}, nil
+}
+
+func (a *batchAgent) RunBatch(ctx context.Context, inputs []agents.Input) ([]agents.Output, error) {
+	a.mu.Lock()
+	if a.failBatches > 0 {
+		a.failBatches--
+		a.mu.Unlock()
+		return nil, errors.New("batch backend unavailable")
+	}
+	a.mu.Unlock()
+
+	queries := make([]string, len(inputs))
+	outputs := make([]agents.Output, len(inputs))
+	for i, input := range inputs {
+		queries[i] = input.Query
+		outputs[i] = agents.Output{Response: "FINAL ANSWER: " + input.Query}
+	}
+	a.mu.Lock()
+	a.batches = append(a.batches, queries)
+	a.mu.Unlock()
+	return outputs, nil
+}
+
+func TestEvaluator_Evaluate_BatchAgent(t *testing.T) {
+	samples := make([]evaluation.Sample, 5)
+	for i := range samples {
+		answer := fmt.Sprintf("answer%d", i)
+		samples[i] = evaluation.Sample{ID: fmt.Sprintf("q%d", i), Input: answer, Expected: answer, Level: 1}
+	}
+
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+
+	agent := &batchAgent{}
+	result, err := evaluator.Evaluate(context.Background(), agent,
+		evaluation.WithBatchSize(2), evaluation.WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if agent.runs != 0 {
+		t.Errorf("expected no per-sample Run calls, got %d", agent.runs)
+	}
+	if len(agent.batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d: %v", len(agent.batches), agent.batches)
+	}
+	for _, batch := range agent.batches {
+		if len(batch) > 2 {
+			t.Errorf("batch exceeds size 2: %v", batch)
+		}
+	}
+	for i, r := range result.DetailedResults {
+		if want := fmt.Sprintf("answer%d", i); r.Predicted != want || !r.Success {
+			t.Errorf("results[%d]: Predicted = %v, Success = %v, want %s", i, r.Predicted, r.Success, want)
+		}
+	}
+	if result.RunConfig["batch_size"] != 2 {
+		t.Errorf("expected batch_size in run config, got %v", result.RunConfig["batch_size"])
+	}
+}
+
+func TestEvaluator_Evaluate_BatchWithSampleRetries(t *testing.T) {
+	samples := make([]evaluation.Sample, 4)
+	for i := range samples {
+		answer := fmt.Sprintf("answer%d", i)
+		samples[i] = evaluation.Sample{ID: fmt.Sprintf("q%d", i), Input: answer, Expected: answer, Level: 1}
+	}
+
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+
+	// 首个批次失败，其样本应在重试轮中逐个执行成功
+	agent := &batchAgent{failBatches: 1}
+	result, err := evaluator.Evaluate(context.Background(), agent,
+		evaluation.WithBatchSize(2), evaluation.WithConcurrency(1), evaluation.WithSampleRetries(1))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if agent.runs != 2 {
+		t.Errorf("expected the 2 samples of the failed batch to be retried via Run, got %d runs", agent.runs)
+	}
+	if len(agent.batches) != 1 {
+		t.Errorf("expected 1 successful batch, got %v", agent.batches)
+	}
+	for i, r := range result.DetailedResults {
+		if !r.Success || r.Error != "" {
+			t.Errorf("results[%d]: Success = %v, Error = %q", i, r.Success, r.Error)
+		}
+	}
+	if result.SuccessCount != len(samples) {
+		t.Errorf("SuccessCount = %d, want %d", result.SuccessCount, len(samples))
+	}
+}
+
+func TestEvaluator_Evaluate_BatchDisabled(t *testing.T) {
+	samples := []evaluation.Sample{{ID: "q0", Input: "a", Expected: "a", Level: 1}}
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+
+	agent := &batchAgent{}
+	if _, err := evaluator.Evaluate(context.Background(), agent, evaluation.WithBatchSize(1)); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if agent.runs != 1 || len(agent.batches) != 0 {
+		t.Errorf("expected fallback to Run, got %d runs and %d batches", agent.runs, len(agent.batches))
+	}
+}
+
+func TestEvaluator_WithAnswerExtractor(t *testing.T) {
+	extractor := func(response string) string {
+		var payload struct {
+			Answer string `json:"answer"`
+		}
+		if err := json.Unmarshal([]byte(response), &payload); err != nil {
+			return ""
+		}
+		return payload.Answer
+	}
+
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"), WithAnswerExtractor(extractor))
+	agent := &mockAgent{response: `{"reasoning": "capital of France", "answer": "The Paris"}`}
+	sample := evaluation.Sample{ID: "q", Input: "capital?", Expected: "Paris", Level: 1}
+
+	result, err := evaluator.EvaluateSample(context.Background(), agent, sample)
+	if err != nil {
+		t.Fatalf("EvaluateSample() error = %v", err)
+	}
+	if result.Predicted != "The Paris" {
+		t.Errorf("Predicted = %v, want raw extracted answer %q", result.Predicted, "The Paris")
+	}
+	if !result.Success {
+		t.Errorf("expected exact match after normalization, details: %v", result.Details)
+	}
+
+	// 默认提取按最后一行回退，无法正确匹配 JSON 响应
+	defaultResult, _ := NewEvaluator(NewDataset("", 0, "validation")).EvaluateSample(context.Background(), agent, sample)
+	if defaultResult.Success {
+		t.Error("expected default extraction to fail on JSON response")
+	}
+}
+
+func TestEvaluator_Evaluate_MaxResponseChars(t *testing.T) {
+	samples := []evaluation.Sample{{ID: "q0", Input: "question", Expected: "42", Level: 1}}
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: -1}
+
+	long := "FINAL ANSWER: 42\n" + strings.Repeat("x", 100000)
+	result, err := evaluator.Evaluate(context.Background(), &mockAgent{response: long},
+		evaluation.WithMaxResponseChars(1000))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	r := result.DetailedResults[0]
+	if len(r.AgentResponse) != 1000 {
+		t.Errorf("AgentResponse length = %d, want 1000", len(r.AgentResponse))
+	}
+	if truncated, _ := r.Details["response_truncated"].(bool); !truncated {
+		t.Errorf("expected response_truncated flag, details: %v", r.Details)
+	}
+	if r.Details["original_response_chars"] != len(long) {
+		t.Errorf("original_response_chars = %v, want %d", r.Details["original_response_chars"], len(long))
+	}
+	if !r.Success {
+		t.Errorf("expected answer before the cut to still match")
+	}
+}
+
+// contextAgent 记录每个问题收到的上下文的测试智能体
+type contextAgent struct {
+	mockAgent
+
+	mu       sync.Mutex
+	contexts map[string]map[string]interface{}
+}
+
+func (a *contextAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.contexts == nil {
+		a.contexts = make(map[string]map[string]interface{})
+	}
+	a.contexts[input.Query] = input.Context
+	return agents.Output{Response: "FINAL ANSWER: 42"}, nil
+}
+
+func TestEvaluator_Evaluate_Attachments(t *testing.T) {
+	dir := t.TempDir()
+	splitDir := filepath.Join(dir, "validation")
+	if err := os.MkdirAll(splitDir, 0755); err != nil {
+		t.Fatalf("failed to create split dir: %v", err)
+	}
+	lines := []string{
+		`{"task_id": "t0", "Question": "q0", "Level": 2, "Final answer": "42", "file_name": "sheet.csv"}`,
+		`{"task_id": "t1", "Question": "q1", "Level": 2, "Final answer": "42", "file_name": "missing.pdf"}`,
+		`{"task_id": "t2", "Question": "q2", "Level": 1, "Final answer": "42"}`,
+	}
+	if err := os.WriteFile(filepath.Join(splitDir, "metadata.jsonl"), []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatalf("failed to write dataset: %v", err)
+	}
+	attachment := filepath.Join(splitDir, "sheet.csv")
+	if err := os.WriteFile(attachment, []byte("a,b\n1,2\n"), 0644); err != nil {
+		t.Fatalf("failed to write attachment: %v", err)
+	}
+
+	agent := &contextAgent{}
+	evaluator := NewEvaluator(NewDataset(dir, 0, "validation"), WithInlineFiles(1024))
+	result, err := evaluator.Evaluate(context.Background(), agent)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	// 附件解析为绝对路径，小文件内容内联
+	ctx0 := agent.contexts["q0"]
+	if ctx0 == nil {
+		t.Fatal("expected agent to run q0")
+	}
+	wantPath, _ := filepath.Abs(attachment)
+	if files, _ := ctx0["files"].([]string); len(files) != 1 || files[0] != wantPath {
+		t.Errorf("files = %v, want [%s]", ctx0["files"], wantPath)
+	}
+	if names, _ := ctx0["file_names"].([]string); len(names) != 1 || names[0] != "sheet.csv" {
+		t.Errorf("file_names = %v, want [sheet.csv]", ctx0["file_names"])
+	}
+	contents, _ := ctx0["file_contents"].(map[string][]byte)
+	if string(contents["sheet.csv"]) != "a,b\n1,2\n" {
+		t.Errorf("file_contents = %v", ctx0["file_contents"])
+	}
+
+	// 缺失附件的样本记为错误且不调用智能体
+	if _, ran := agent.contexts["q1"]; ran {
+		t.Error("agent should not run a sample with a missing attachment")
+	}
+	missing := result.DetailedResults[1]
+	if missing.Success || !strings.Contains(missing.Error, "missing.pdf") {
+		t.Errorf("expected descriptive attachment error, got %q", missing.Error)
+	}
+	if missing.Details["attachment_error"] != true {
+		t.Errorf("expected attachment_error detail, got %v", missing.Details)
+	}
+
+	// 无附件的样本照常运行
+	if !result.DetailedResults[2].Success {
+		t.Errorf("expected sample without attachments to succeed, got %+v", result.DetailedResults[2])
+	}
+}
+
+func TestEvaluator_Evaluate_ResultSink(t *testing.T) {
+	samples := make([]evaluation.Sample, 4)
+	for i := range samples {
+		samples[i] = evaluation.Sample{ID: fmt.Sprintf("q%d", i), Input: "q", Expected: "42", Level: 1}
+	}
+	evaluator := NewEvaluator(NewDataset("", 0, "validation"))
+	evaluator.dataset = &flakyDataset{samples: samples, failIndex: 2}
+
+	var buf strings.Builder
+	result, err := evaluator.Evaluate(context.Background(), &mockAgent{response: "FINAL ANSWER: 42"},
+		evaluation.WithConcurrency(2), evaluation.WithResultSink(&buf))
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != result.TotalSamples {
+		t.Fatalf("expected %d NDJSON lines, got %d", result.TotalSamples, len(lines))
+	}
+	for _, line := range lines {
+		var r evaluation.SampleResult
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		if r.SampleID == "" {
+			t.Errorf("missing sample_id in line %q", line)
+		}
+	}
+}
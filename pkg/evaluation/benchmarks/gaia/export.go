@@ -1,10 +1,13 @@
 package gaia
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
 )
@@ -15,6 +18,9 @@ type ExportEntry struct {
 	ModelAnswer string `json:"model_answer"`
 }
 
+// ErrExportMismatch 导出文件与评估结果不一致
+var ErrExportMismatch = errors.New("导出文件与评估结果不一致")
+
 // Exporter GAIA 结果导出器
 type Exporter struct{}
 
@@ -59,6 +65,62 @@ func (e *Exporter) Export(result *evaluation.EvalResult, outputPath string) erro
 	return nil
 }
 
+// VerifyExport 校验 Export 生成的提交文件
+//
+// 检查文件行数与 DetailedResults 一致、每行的 task_id 与对应样本按顺序匹配，
+// 且存在预测的样本 model_answer 非空。不一致时返回包装 ErrExportMismatch 的错误。
+func (e *Exporter) VerifyExport(path string, result *evaluation.EvalResult) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	var entries []ExportEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry ExportEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return fmt.Errorf("%w: 第 %d 行解析失败: %v", ErrExportMismatch, lineNum, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	if len(entries) != len(result.DetailedResults) {
+		return fmt.Errorf("%w: 文件包含 %d 条记录，评估结果包含 %d 个样本",
+			ErrExportMismatch, len(entries), len(result.DetailedResults))
+	}
+
+	for i, sr := range result.DetailedResults {
+		entry := entries[i]
+		if entry.TaskID != sr.SampleID {
+			return fmt.Errorf("%w: 第 %d 条记录 task_id 为 %q，期望 %q",
+				ErrExportMismatch, i+1, entry.TaskID, sr.SampleID)
+		}
+		if hasPrediction(sr) && entry.ModelAnswer == "" {
+			return fmt.Errorf("%w: 样本 %s 存在预测但 model_answer 为空", ErrExportMismatch, sr.SampleID)
+		}
+	}
+
+	return nil
+}
+
+// hasPrediction 判断样本是否产生了可导出的预测答案
+func hasPrediction(sr *evaluation.SampleResult) bool {
+	if predicted, ok := sr.Predicted.(string); ok && predicted != "" {
+		return true
+	}
+	return sr.AgentResponse != ""
+}
+
 // ExportMarkdownReport 导出 Markdown 报告
 func (e *Exporter) ExportMarkdownReport(result *evaluation.EvalResult, outputPath string) error {
 	// 确保目录存在
@@ -94,8 +156,17 @@ func (e *Exporter) ExportMarkdownReport(result *evaluation.EvalResult, outputPat
 			fmt.Fprintf(file, "| 部分匹配率 | %.2f%% |\n", partialRate*100)
 		}
 	}
+	if result.Metrics != nil && result.Metrics.TokenUsage != nil {
+		fmt.Fprintf(file, "| 总 Token | %d |\n", result.Metrics.TokenUsage.TotalTokens)
+	}
 	fmt.Fprintf(file, "\n")
 
+	// 分数分布
+	if result.Metrics != nil && result.Metrics.ScoreHistogram != nil {
+		fmt.Fprintf(file, "## 分数分布\n\n")
+		fmt.Fprintf(file, "%s\n", result.Metrics.ScoreHistogram.Markdown())
+	}
+
 	// 分级别指标
 	if len(result.LevelMetrics) > 0 {
 		fmt.Fprintf(file, "## 分级别指标\n\n")
@@ -126,6 +197,12 @@ func (e *Exporter) ExportMarkdownReport(result *evaluation.EvalResult, outputPat
 		fmt.Fprintf(file, "\n")
 	}
 
+	// 错误分类
+	if breakdown := evaluation.ErrorBreakdownOf(result.Metrics); breakdown != nil {
+		fmt.Fprintf(file, "## 错误分类\n\n")
+		fmt.Fprintf(file, "%s\n", breakdown.Markdown())
+	}
+
 	// 错误样本
 	var errorSamples []*evaluation.SampleResult
 	for _, sr := range result.DetailedResults {
@@ -149,6 +226,9 @@ func (e *Exporter) ExportMarkdownReport(result *evaluation.EvalResult, outputPat
 			if predicted, ok := sr.Predicted.(string); ok {
 				fmt.Fprintf(file, "**预测答案**: %s\n\n", predicted)
 			}
+			if reason, ok := sr.Details["partial_reason"].(string); ok {
+				fmt.Fprintf(file, "**部分匹配依据**: %s\n\n", reason)
+			}
 			if sr.Error != "" {
 				fmt.Fprintf(file, "**错误**: %s\n\n", sr.Error)
 			}
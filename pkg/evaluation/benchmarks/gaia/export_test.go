@@ -0,0 +1,61 @@
+package gaia
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+func TestExporter_VerifyExport(t *testing.T) {
+	result := &evaluation.EvalResult{
+		DetailedResults: []*evaluation.SampleResult{
+			{SampleID: "t0", Predicted: "42"},
+			{SampleID: "t1", Predicted: "Paris"},
+			{SampleID: "t2", Error: "timeout"},
+		},
+	}
+
+	exporter := NewExporter()
+	path := filepath.Join(t.TempDir(), "submission.jsonl")
+	if err := exporter.Export(result, path); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if err := exporter.VerifyExport(path, result); err != nil {
+		t.Fatalf("VerifyExport() error = %v on a complete export", err)
+	}
+
+	// 删除第二条记录
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	dropped := strings.Join([]string{lines[0], lines[2]}, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(dropped), 0644); err != nil {
+		t.Fatalf("failed to rewrite export: %v", err)
+	}
+
+	err = exporter.VerifyExport(path, result)
+	if !errors.Is(err, ErrExportMismatch) {
+		t.Fatalf("expected ErrExportMismatch for dropped entry, got %v", err)
+	}
+}
+
+func TestExporter_VerifyExport_EmptyAnswer(t *testing.T) {
+	result := &evaluation.EvalResult{
+		DetailedResults: []*evaluation.SampleResult{{SampleID: "t0", Predicted: "42"}},
+	}
+
+	path := filepath.Join(t.TempDir(), "submission.jsonl")
+	if err := os.WriteFile(path, []byte(`{"task_id": "t0", "model_answer": ""}`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write export: %v", err)
+	}
+
+	if err := NewExporter().VerifyExport(path, result); !errors.Is(err, ErrExportMismatch) {
+		t.Fatalf("expected ErrExportMismatch for empty model_answer, got %v", err)
+	}
+}
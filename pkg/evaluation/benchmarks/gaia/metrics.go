@@ -5,7 +5,10 @@ import (
 )
 
 // Metrics GAIA 指标计算器
-type Metrics struct{}
+type Metrics struct {
+	// HistogramEdges 分数直方图的分桶下界（为空时使用 evaluation.DefaultUnitScoreEdges）
+	HistogramEdges []float64
+}
 
 // NewMetrics 创建 GAIA 指标计算器
 func NewMetrics() *Metrics {
@@ -26,6 +29,7 @@ func (m *Metrics) Compute(results []*evaluation.SampleResult) *evaluation.Metric
 	exactMatches := 0
 	partialMatches := 0
 	totalScore := 0.0
+	totalConfidence := 0.0
 	errorCount := 0
 
 	for _, r := range results {
@@ -36,6 +40,7 @@ func (m *Metrics) Compute(results []*evaluation.SampleResult) *evaluation.Metric
 			partialMatches++
 		}
 		totalScore += r.Score
+		totalConfidence += r.Confidence
 
 		if r.Error != "" {
 			errorCount++
@@ -53,6 +58,19 @@ func (m *Metrics) Compute(results []*evaluation.SampleResult) *evaluation.Metric
 	summary.Extra["exact_match_rate"] = float64(exactMatches) / float64(totalSamples)
 	summary.Extra["partial_match_rate"] = float64(partialMatches) / float64(totalSamples)
 	summary.Extra["error_count"] = errorCount
+	summary.Extra["timeout_count"] = evaluation.CountTimeouts(results)
+	summary.Extra[evaluation.ErrorBreakdownKey] = evaluation.ComputeErrorBreakdown(results)
+	summary.Extra["mean_confidence"] = totalConfidence / float64(totalSamples)
+
+	// 分数分布
+	edges := m.HistogramEdges
+	if len(edges) == 0 {
+		edges = evaluation.DefaultUnitScoreEdges
+	}
+	summary.ScoreHistogram = evaluation.NewScoreHistogram(results, edges)
+
+	// Token 使用量
+	summary.TokenUsage = evaluation.SumTokenUsage(results)
 
 	return summary
 }
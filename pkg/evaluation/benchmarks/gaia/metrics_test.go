@@ -141,3 +141,15 @@ func TestMetrics_AnalyzeDifficultyProgression(t *testing.T) {
 		t.Errorf("expected pattern expected_degradation, got %s", pattern)
 	}
 }
+
+func TestMetrics_Compute_MeanConfidence(t *testing.T) {
+	metrics := NewMetrics()
+	summary := metrics.Compute([]*evaluation.SampleResult{
+		{SampleID: "q1", Success: true, Confidence: 1.0},
+		{SampleID: "q2", PartialSuccess: true, Confidence: 0.5},
+	})
+
+	if got := summary.Extra["mean_confidence"]; got != 0.75 {
+		t.Errorf("expected mean_confidence 0.75, got %v", got)
+	}
+}
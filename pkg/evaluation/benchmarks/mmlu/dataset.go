@@ -0,0 +1,330 @@
+// Package mmlu 实现 MMLU (Massive Multitask Language Understanding) 评估
+//
+// MMLU 为覆盖 57 个学科的四选一选择题，用于评估模型的知识储备与推理能力。
+// 支持标准 CSV 格式（question,A,B,C,D,answer，按学科分文件）和 JSONL 格式。
+package mmlu
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+// Choices 选项字母
+var Choices = []string{"A", "B", "C", "D"}
+
+// 标准 MMLU 文件名的分割后缀（如 abstract_algebra_test.csv）
+var splitSuffixes = []string{"_test", "_dev", "_val", "_validation", "_train"}
+
+// Dataset MMLU 数据集
+type Dataset struct {
+	// dataPath 数据文件或目录路径
+	dataPath string
+
+	// subject 学科过滤（空表示不过滤）
+	subject string
+
+	// samples 加载的样本
+	samples []evaluation.Sample
+
+	// loaded 是否已加载
+	loaded bool
+}
+
+// NewDataset 创建 MMLU 数据集
+//
+// 参数:
+//   - dataPath: 数据文件（.csv/.jsonl）或包含这些文件的目录
+//   - subject: 学科过滤（空字符串表示全部学科）
+func NewDataset(dataPath string, subject string) *Dataset {
+	return &Dataset{
+		dataPath: dataPath,
+		subject:  subject,
+		samples:  make([]evaluation.Sample, 0),
+	}
+}
+
+// Load 加载数据集
+//
+// 目录按文件名顺序加载其中所有 .csv 和 .jsonl 文件；CSV 文件的学科取自文件名。
+// 选项数不为 4 或答案无效的题目会被跳过。
+func (d *Dataset) Load(ctx context.Context) error {
+	if d.loaded {
+		return nil
+	}
+
+	info, err := os.Stat(d.dataPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("MMLU 数据路径不存在: %s\n请从 HuggingFace 下载: huggingface-cli download cais/mmlu", d.dataPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	files := []string{d.dataPath}
+	if info.IsDir() {
+		entries, err := os.ReadDir(d.dataPath)
+		if err != nil {
+			return fmt.Errorf("读取 MMLU 数据目录失败: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if !entry.IsDir() && (ext == ".csv" || ext == ".jsonl") {
+				files = append(files, filepath.Join(d.dataPath, entry.Name()))
+			}
+		}
+		sort.Strings(files)
+	}
+
+	for _, filePath := range files {
+		var loadErr error
+		switch filepath.Ext(filePath) {
+		case ".csv":
+			loadErr = d.loadCSV(ctx, filePath)
+		case ".jsonl":
+			loadErr = d.loadJSONL(ctx, filePath)
+		default:
+			loadErr = fmt.Errorf("不支持的文件格式: %s", filePath)
+		}
+		if loadErr != nil {
+			return fmt.Errorf("加载 MMLU 文件 %s 失败: %w", filePath, loadErr)
+		}
+	}
+
+	if len(d.samples) == 0 {
+		return fmt.Errorf("无法加载 MMLU 数据: %s", d.dataPath)
+	}
+
+	d.loaded = true
+	return nil
+}
+
+// loadCSV 加载 CSV 格式文件（无表头或表头为 question,A,B,C,D,answer）
+func (d *Dataset) loadCSV(ctx context.Context, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	subject := subjectFromFile(filePath)
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	idx := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) < 6 || strings.EqualFold(strings.TrimSpace(record[5]), "answer") {
+			continue
+		}
+
+		sample, ok := d.newSample("", subject, record[0], record[1:5], record[5], idx)
+		if ok {
+			d.samples = append(d.samples, sample)
+		}
+		idx++
+	}
+
+	return nil
+}
+
+// loadJSONL 加载 JSONL 格式文件
+//
+// 每行包含 question、choices（或 A/B/C/D 字段）和 answer（选项字母或 0-3 索引），
+// 可选 subject 和 id 字段。
+func (d *Dataset) loadJSONL(ctx context.Context, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fileSubject := subjectFromFile(filePath)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+
+	idx := 0
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		var item map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			continue
+		}
+
+		subject, _ := item["subject"].(string)
+		if subject == "" {
+			subject = fileSubject
+		}
+		id, _ := item["id"].(string)
+		question, _ := item["question"].(string)
+
+		sample, ok := d.newSample(id, subject, question, parseChoices(item), parseAnswer(item["answer"]), idx)
+		if ok {
+			d.samples = append(d.samples, sample)
+		}
+		idx++
+	}
+
+	return scanner.Err()
+}
+
+// newSample 构建样本并应用学科过滤，题目无效或被过滤时返回 false
+func (d *Dataset) newSample(id, subject, question string, choices []string, answer string, idx int) (evaluation.Sample, bool) {
+	if d.subject != "" && subject != d.subject {
+		return evaluation.Sample{}, false
+	}
+
+	answer = strings.ToUpper(strings.TrimSpace(answer))
+	if question == "" || len(choices) != len(Choices) || choiceIndex(answer) < 0 {
+		return evaluation.Sample{}, false
+	}
+
+	if id == "" {
+		id = fmt.Sprintf("%s_%d", subject, idx)
+	}
+
+	return evaluation.Sample{
+		ID:       id,
+		Input:    question,
+		Expected: answer,
+		Category: subject,
+		Metadata: map[string]interface{}{
+			"subject": subject,
+			"choices": choices,
+		},
+	}, true
+}
+
+// parseChoices 解析 JSONL 数据项的选项
+func parseChoices(item map[string]interface{}) []string {
+	if raw, ok := item["choices"].([]interface{}); ok {
+		choices := make([]string, 0, len(raw))
+		for _, c := range raw {
+			choices = append(choices, fmt.Sprint(c))
+		}
+		return choices
+	}
+
+	var choices []string
+	for _, letter := range Choices {
+		c, ok := item[letter]
+		if !ok {
+			return nil
+		}
+		choices = append(choices, fmt.Sprint(c))
+	}
+	return choices
+}
+
+// parseAnswer 解析答案（选项字母或 0-3 索引）
+func parseAnswer(v interface{}) string {
+	switch a := v.(type) {
+	case string:
+		return a
+	case float64:
+		if i := int(a); float64(i) == a && i >= 0 && i < len(Choices) {
+			return Choices[i]
+		}
+	}
+	return ""
+}
+
+// choiceIndex 返回选项字母的索引，无效时返回 -1
+func choiceIndex(letter string) int {
+	for i, c := range Choices {
+		if c == letter {
+			return i
+		}
+	}
+	return -1
+}
+
+// subjectFromFile 从文件名推断学科（去除扩展名和分割后缀）
+func subjectFromFile(filePath string) string {
+	name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	for _, suffix := range splitSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return name
+}
+
+// Len 返回数据集大小
+func (d *Dataset) Len() int {
+	return len(d.samples)
+}
+
+// Get 根据索引获取样本
+func (d *Dataset) Get(index int) (evaluation.Sample, error) {
+	if index < 0 || index >= len(d.samples) {
+		return evaluation.Sample{}, fmt.Errorf("索引越界: %d", index)
+	}
+	return d.samples[index], nil
+}
+
+// Iterator 返回样本迭代器
+func (d *Dataset) Iterator() <-chan evaluation.Sample {
+	ch := make(chan evaluation.Sample)
+	go func() {
+		defer close(ch)
+		for _, sample := range d.samples {
+			ch <- sample
+		}
+	}()
+	return ch
+}
+
+// Name 返回数据集名称
+func (d *Dataset) Name() string {
+	if d.subject != "" {
+		return fmt.Sprintf("MMLU_%s", d.subject)
+	}
+	return "MMLU"
+}
+
+// Subject 返回学科过滤
+func (d *Dataset) Subject() string {
+	return d.subject
+}
+
+// GetSubjectDistribution 获取学科分布
+func (d *Dataset) GetSubjectDistribution() map[string]int {
+	dist := make(map[string]int)
+	for _, s := range d.samples {
+		dist[s.Category]++
+	}
+	return dist
+}
@@ -0,0 +1,85 @@
+package mmlu
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeMMLUDir 写入包含 CSV 和 JSONL 文件的测试数据目录
+func writeMMLUDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	csvData := strings.Join([]string{
+		`What is 2+2?,3,4,5,6,B`,
+		`What is 3*3?,6,8,9,12,C`,
+		`"Broken, only three choices",1,2,3`,
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "elementary_mathematics_test.csv"), []byte(csvData), 0644); err != nil {
+		t.Fatalf("failed to write csv: %v", err)
+	}
+
+	jsonlData := strings.Join([]string{
+		`{"question": "Who wrote Hamlet?", "subject": "literature", "choices": ["Dickens", "Austen", "Shakespeare", "Tolstoy"], "answer": 2}`,
+		`{"question": "Capital of Japan?", "subject": "geography", "A": "Seoul", "B": "Beijing", "C": "Bangkok", "D": "Tokyo", "answer": "D"}`,
+		`{"question": "Which planet is largest?", "subject": "astronomy", "choices": ["Earth", "Mars", "Jupiter", "Venus"], "answer": "C"}`,
+		`{"id": "chem_1", "question": "Chemical symbol for gold?", "subject": "chemistry", "choices": ["Ag", "Au", "Gd", "Go"], "answer": 1}`,
+		`{"question": "Invalid answer", "subject": "chemistry", "choices": ["a", "b", "c", "d"], "answer": "E"}`,
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "mixed.jsonl"), []byte(jsonlData), 0644); err != nil {
+		t.Fatalf("failed to write jsonl: %v", err)
+	}
+
+	return dir
+}
+
+func TestDataset_Load(t *testing.T) {
+	dataset := NewDataset(writeMMLUDir(t), "")
+	if err := dataset.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if dataset.Len() != 6 {
+		t.Fatalf("Len() = %d, want 6", dataset.Len())
+	}
+
+	first, _ := dataset.Get(0)
+	if first.Category != "elementary_mathematics" || first.Expected != "B" || first.ID != "elementary_mathematics_0" {
+		t.Errorf("unexpected CSV sample: %+v", first)
+	}
+	if choices, _ := first.Metadata["choices"].([]string); len(choices) != 4 || choices[1] != "4" {
+		t.Errorf("unexpected choices: %v", first.Metadata["choices"])
+	}
+
+	hamlet, _ := dataset.Get(2)
+	if hamlet.Expected != "C" || hamlet.Category != "literature" {
+		t.Errorf("numeric answer not mapped to letter: %+v", hamlet)
+	}
+	gold, _ := dataset.Get(5)
+	if gold.ID != "chem_1" || gold.Expected != "B" {
+		t.Errorf("unexpected JSONL sample: %+v", gold)
+	}
+
+	dist := dataset.GetSubjectDistribution()
+	if dist["elementary_mathematics"] != 2 || dist["chemistry"] != 1 {
+		t.Errorf("unexpected subject distribution: %v", dist)
+	}
+}
+
+func TestDataset_SubjectFilter(t *testing.T) {
+	dataset := NewDataset(writeMMLUDir(t), "geography")
+	if err := dataset.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if dataset.Len() != 1 || dataset.Name() != "MMLU_geography" {
+		t.Errorf("Len() = %d, Name() = %s, want 1 geography sample", dataset.Len(), dataset.Name())
+	}
+}
+
+func TestDataset_LoadMissing(t *testing.T) {
+	if err := NewDataset(filepath.Join(t.TempDir(), "missing"), "").Load(context.Background()); err == nil {
+		t.Error("expected error for missing data path")
+	}
+}
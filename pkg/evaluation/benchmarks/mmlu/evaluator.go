@@ -0,0 +1,228 @@
+package mmlu
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+// 选项提取模式（按优先级排列，同一模式取最后一次出现）
+var (
+	// "The answer is C"、"Answer: (B)"、"correct answer is **D**"
+	answerPattern = regexp.MustCompile(`(?i:answer)\s*(?:(?i:is)\s*)?[:：]?\s*(?:(?i:option)\s*)?\(?([A-D])\)?(?:[^A-Za-z]|$)`)
+	// "答案是 C"、"答案：B"
+	chineseAnswerPattern = regexp.MustCompile(`答案\s*(?:是|为)?\s*[:：]?\s*(?:选项)?\s*[\(（]?([A-D])`)
+	// "(B)"
+	parenPattern = regexp.MustCompile(`\(([A-D])\)`)
+	// 单独的选项字母，如 "D"、"C."、"B) Paris"
+	barePattern = regexp.MustCompile(`^\(?([A-D])(?:\)?$|[\).:])`)
+)
+
+// Evaluator MMLU 评估器
+type Evaluator struct {
+	// dataset 数据集
+	dataset evaluation.Dataset
+}
+
+// NewEvaluator 创建 MMLU 评估器
+func NewEvaluator(dataset *Dataset) *Evaluator {
+	return &Evaluator{
+		dataset: dataset,
+	}
+}
+
+// Name 返回评估器名称
+func (e *Evaluator) Name() string {
+	return e.dataset.Name()
+}
+
+// Evaluate 执行完整评估
+//
+// ctx 取消或 FailFast 提前终止时，返回已完成样本的结果及对应错误：TotalSamples、
+// 准确率和各项指标均只统计已完成的样本。
+func (e *Evaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...evaluation.EvalOption) (*evaluation.EvalResult, error) {
+	config := evaluation.DefaultEvalConfig()
+	config.ApplyOptions(opts...)
+
+	// 确保数据集已加载
+	if err := e.dataset.Load(ctx); err != nil {
+		return nil, fmt.Errorf("加载数据集失败: %w", err)
+	}
+
+	startTime := time.Now()
+	result := &evaluation.EvalResult{
+		BenchmarkName:   e.Name(),
+		AgentName:       agent.Name(),
+		DetailedResults: make([]*evaluation.SampleResult, 0),
+		EvaluationTime:  startTime,
+		RunConfig:       config.Summary(),
+	}
+
+	// 按配置打乱、分层抽样并截断样本
+	dataset := evaluation.SelectSamples(config, e.dataset)
+	total := dataset.Len()
+	result.TotalSamples = total
+
+	// 智能体支持批量执行时按批次调度
+	runner := evaluation.NewBatchSampleRunner(agent, dataset, config.BatchSize, e.buildInput,
+		evaluation.WithBatchContext(ctx), evaluation.WithBatchTimeout(config.Timeout))
+	if runner.Batched() {
+		result.RunConfig["batch_size"] = config.BatchSize
+	}
+
+	// 并发评估样本（结果保持样本顺序）
+	sampleResults, runErr := evaluation.RunSamples(ctx, config, dataset, total,
+		func(ctx context.Context, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+			return e.evaluateSample(ctx, config, runner, sample)
+		})
+
+	result.DetailedResults = append(result.DetailedResults, sampleResults...)
+	for _, r := range sampleResults {
+		if r.Success {
+			result.SuccessCount++
+		}
+	}
+	// 提前终止（ctx 取消或 FailFast）时只统计已完成的样本
+	if runErr != nil {
+		result.TotalSamples = len(sampleResults)
+	}
+
+	result.TotalDuration = time.Since(startTime)
+	if result.TotalSamples > 0 {
+		result.OverallAccuracy = float64(result.SuccessCount) / float64(result.TotalSamples)
+	}
+
+	// 计算学科指标和汇总指标
+	metrics := NewMetrics()
+	result.CategoryMetrics = metrics.ComputeCategoryMetrics(result.DetailedResults)
+	result.Metrics = metrics.Compute(result.DetailedResults)
+
+	return result, runErr
+}
+
+// EvaluateSample 评估单个样本
+//
+// 智能体执行失败记录在结果的 Error 字段中，不作为错误返回。
+func (e *Evaluator) EvaluateSample(ctx context.Context, agent agents.Agent, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+	result, _ := e.evaluateSample(ctx, evaluation.DefaultEvalConfig(), evaluation.NewSampleRunner(agent), sample)
+	return result, nil
+}
+
+// evaluateSample 评估单个样本，返回结果及智能体执行错误（用于 FailFast）
+func (e *Evaluator) evaluateSample(ctx context.Context, config *evaluation.EvalConfig, runner *evaluation.SampleRunner,
+	sample evaluation.Sample) (*evaluation.SampleResult, error) {
+	startTime := time.Now()
+
+	result := &evaluation.SampleResult{
+		SampleID: sample.ID,
+		Category: sample.Category,
+		Expected: sample.Expected,
+		Details:  make(map[string]interface{}),
+	}
+
+	// 构建输入
+	input, _ := e.buildInput(sample)
+
+	// 调用智能体
+	output, err := runner.RunSample(ctx, sample, input, result)
+	if err != nil {
+		result.Error = err.Error()
+		result.ExecutionTime = time.Since(startTime)
+		return result, err
+	}
+
+	result.AgentResponse = config.TruncateResponse(result, output.Response)
+	result.ExecutionTime = time.Since(startTime)
+	result.AddTokenUsage(evaluation.AgentTokenUsage(runner.Agent(), output))
+
+	// 从响应中提取选项
+	choice := extractChoice(result.AgentResponse)
+	result.Predicted = choice
+	result.Details["extracted_choice"] = choice
+
+	// 获取期望答案
+	expected, ok := sample.Expected.(string)
+	if !ok {
+		result.Error = "期望答案格式错误"
+		return result, nil
+	}
+
+	result.Success = choice != "" && choice == expected
+	if result.Success {
+		result.Score = 1.0
+	}
+	result.Details["exact_match"] = result.Success
+
+	return result, nil
+}
+
+// buildInput 构建智能体输入（所有 MMLU 样本均可批量执行）
+func (e *Evaluator) buildInput(sample evaluation.Sample) (agents.Input, bool) {
+	return agents.Input{
+		Query: buildPrompt(sample),
+		Context: map[string]interface{}{
+			"subject": sample.Category,
+		},
+	}, true
+}
+
+// buildPrompt 构建选择题提示
+func buildPrompt(sample evaluation.Sample) string {
+	var sb strings.Builder
+	if sample.Category != "" {
+		fmt.Fprintf(&sb, "The following is a multiple choice question about %s.\n\n",
+			strings.ReplaceAll(sample.Category, "_", " "))
+	}
+	sb.WriteString(sample.Input)
+	sb.WriteString("\n\n")
+
+	choices, _ := sample.Metadata["choices"].([]string)
+	for i, c := range choices {
+		if i < len(Choices) {
+			fmt.Fprintf(&sb, "%s. %s\n", Choices[i], c)
+		}
+	}
+
+	sb.WriteString("\nAnswer with the letter of the correct option (A, B, C, or D), e.g. \"The answer is A\".")
+	return sb.String()
+}
+
+// extractChoice 从响应中提取选项字母
+//
+// 依次尝试 "answer is X" 等显式答案、中文 "答案是 X"、括号 "(X)"、
+// 以及整个响应或最后一行仅为选项字母的情况；无法提取时返回空字符串。
+func extractChoice(response string) string {
+	response = strings.TrimSpace(strings.ReplaceAll(response, "*", ""))
+	if response == "" {
+		return ""
+	}
+
+	for _, re := range []*regexp.Regexp{answerPattern, chineseAnswerPattern, parenPattern} {
+		if matches := re.FindAllStringSubmatch(response, -1); len(matches) > 0 {
+			return matches[len(matches)-1][1]
+		}
+	}
+
+	// 整个响应或最后一个非空行以选项字母开头
+	if m := barePattern.FindStringSubmatch(response); m != nil {
+		return m[1]
+	}
+	lines := strings.Split(response, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if m := barePattern.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+		break
+	}
+
+	return ""
+}
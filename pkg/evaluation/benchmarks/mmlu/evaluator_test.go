@@ -0,0 +1,180 @@
+package mmlu
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/config"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+func TestExtractChoice(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{"answer is", "Paris is the capital of France. The answer is C", "C"},
+		{"answer is with period", "the correct answer is B.", "B"},
+		{"answer colon parenthesized", "Answer: (A)", "A"},
+		{"bold answer", "The answer is **D**.", "D"},
+		{"parenthesized", "I think (B) is right.", "B"},
+		{"bare letter", "D", "D"},
+		{"bare letter with period", " C. ", "C"},
+		{"bare with option text", "B) Paris", "B"},
+		{"last line bare", "Let me think about it.\nOption C fits best.\n\nA", "A"},
+		{"chinese answer", "经过分析，答案是 C。", "C"},
+		{"last answer wins", "The answer is A... wait, no. The answer is B", "B"},
+		{"lowercase article ignored", "A cat is an animal.", ""},
+		{"answer followed by word", "the answer is a bit unclear", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractChoice(tt.response); got != tt.want {
+				t.Errorf("extractChoice(%q) = %q, want %q", tt.response, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	sample := evaluation.Sample{
+		Input:    "What is 2+2?",
+		Category: "elementary_mathematics",
+		Metadata: map[string]interface{}{"choices": []string{"3", "4", "5", "6"}},
+	}
+	prompt := buildPrompt(sample)
+	for _, want := range []string{"about elementary mathematics", "What is 2+2?", "A. 3\n", "B. 4\n", "D. 6\n"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+// scriptedAgent 按问题返回预设响应的测试智能体
+type scriptedAgent struct {
+	responses map[string]string
+}
+
+func (a *scriptedAgent) Name() string               { return "scripted" }
+func (a *scriptedAgent) Config() config.AgentConfig { return config.AgentConfig{} }
+
+func (a *scriptedAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	for question, response := range a.responses {
+		if strings.Contains(input.Query, question) {
+			return agents.Output{Response: response}, nil
+		}
+	}
+	return agents.Output{Response: "I don't know"}, nil
+}
+
+func (a *scriptedAgent) RunStream(ctx context.Context, input agents.Input) (<-chan agents.StreamChunk, <-chan error) {
+	ch := make(chan agents.StreamChunk)
+	errCh := make(chan error)
+	close(ch)
+	close(errCh)
+	return ch, errCh
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	dataset := NewDataset(writeMMLUDir(t), "")
+	agent := &scriptedAgent{responses: map[string]string{
+		"What is 2+2?":              "The answer is B",
+		"What is 3*3?":              "(A)",
+		"Who wrote Hamlet?":         "C",
+		"Capital of Japan?":         "The answer is D",
+		"Which planet is largest?":  "Jupiter",
+		"Chemical symbol for gold?": "answer: (B)",
+	}}
+
+	result, err := NewEvaluator(dataset).Evaluate(context.Background(), agent)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if result.TotalSamples != 6 || result.SuccessCount != 4 {
+		t.Errorf("TotalSamples = %d, SuccessCount = %d, want 6 and 4", result.TotalSamples, result.SuccessCount)
+	}
+
+	mathMetrics := result.CategoryMetrics["elementary_mathematics"]
+	if mathMetrics == nil || mathMetrics.Total != 2 || mathMetrics.Success != 1 {
+		t.Errorf("elementary_mathematics metrics = %+v, want 1/2", mathMetrics)
+	}
+	lit := result.CategoryMetrics["literature"]
+	if lit == nil || lit.Accuracy != 1.0 {
+		t.Errorf("literature metrics = %+v, want accuracy 1", lit)
+	}
+	if result.Metrics.Extra["unanswered"] != 1 {
+		t.Errorf("unanswered = %v, want 1", result.Metrics.Extra["unanswered"])
+	}
+}
+
+// streamingScriptedAgent 逐字符增量输出 scriptedAgent 响应的智能体
+type streamingScriptedAgent struct {
+	*scriptedAgent
+	delay time.Duration
+}
+
+func (a *streamingScriptedAgent) Streaming() bool { return true }
+
+func (a *streamingScriptedAgent) RunStream(ctx context.Context, input agents.Input) (<-chan agents.StreamChunk, <-chan error) {
+	ch := make(chan agents.StreamChunk)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		defer close(ch)
+		output, _ := a.Run(ctx, input)
+		for _, r := range output.Response {
+			time.Sleep(a.delay)
+			ch <- agents.StreamChunk{Type: agents.ChunkTypeText, Content: string(r)}
+		}
+		ch <- agents.StreamChunk{Type: agents.ChunkTypeDone, Done: true}
+	}()
+	return ch, errCh
+}
+
+func TestEvaluator_Evaluate_Streaming(t *testing.T) {
+	dataset := NewDataset(writeMMLUDir(t), "")
+	scripted := &scriptedAgent{responses: map[string]string{
+		"What is 2+2?":      "The answer is B",
+		"Who wrote Hamlet?": "C",
+		"Capital of Japan?": "The answer is A",
+	}}
+
+	plain, err := NewEvaluator(dataset).Evaluate(context.Background(), scripted)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	streamed, err := NewEvaluator(dataset).Evaluate(context.Background(),
+		&streamingScriptedAgent{scriptedAgent: scripted, delay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if streamed.SuccessCount != plain.SuccessCount || streamed.OverallAccuracy != plain.OverallAccuracy {
+		t.Errorf("streamed success %d (%.2f), want %d (%.2f)",
+			streamed.SuccessCount, streamed.OverallAccuracy, plain.SuccessCount, plain.OverallAccuracy)
+	}
+	for i, r := range streamed.DetailedResults {
+		want := plain.DetailedResults[i]
+		if r.AgentResponse != want.AgentResponse || r.Score != want.Score {
+			t.Errorf("sample %s: streamed (%q, %v), want (%q, %v)", r.SampleID, r.AgentResponse, r.Score, want.AgentResponse, want.Score)
+		}
+		if _, ok := want.Details["time_to_first_token_ms"]; ok {
+			t.Errorf("sample %s: non-streaming result has TTFT", want.SampleID)
+		}
+		ttft, ok := r.Details["time_to_first_token_ms"].(float64)
+		if !ok || ttft <= 0 {
+			t.Errorf("sample %s: time_to_first_token_ms = %v, want > 0", r.SampleID, r.Details["time_to_first_token_ms"])
+			continue
+		}
+		if latency, _ := r.Details["stream_latency_ms"].(float64); latency < ttft {
+			t.Errorf("sample %s: stream_latency_ms = %v, want >= TTFT %v", r.SampleID, latency, ttft)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package mmlu
+
+import (
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+// Metrics MMLU 指标计算器
+type Metrics struct{}
+
+// NewMetrics 创建 MMLU 指标计算器
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Compute 计算 MMLU 评估指标
+func (m *Metrics) Compute(results []*evaluation.SampleResult) *evaluation.MetricsSummary {
+	summary := &evaluation.MetricsSummary{
+		Extra: make(map[string]interface{}),
+	}
+
+	if len(results) == 0 {
+		return summary
+	}
+
+	totalSamples := len(results)
+	correct := 0
+	unanswered := 0
+	errorCount := 0
+
+	for _, r := range results {
+		if r.Success {
+			correct++
+		}
+		if r.Error != "" {
+			errorCount++
+		} else if choice, _ := r.Predicted.(string); choice == "" {
+			unanswered++
+		}
+	}
+
+	// 计算准确率
+	summary.Accuracy = float64(correct) / float64(totalSamples)
+	summary.AverageScore = summary.Accuracy
+
+	// 额外指标
+	summary.Extra["total_samples"] = totalSamples
+	summary.Extra["correct"] = correct
+	summary.Extra["unanswered"] = unanswered
+	summary.Extra["unanswered_rate"] = float64(unanswered) / float64(totalSamples)
+	summary.Extra["error_count"] = errorCount
+	summary.Extra["timeout_count"] = evaluation.CountTimeouts(results)
+	summary.Extra[evaluation.ErrorBreakdownKey] = evaluation.ComputeErrorBreakdown(results)
+
+	// Token 使用量
+	summary.TokenUsage = evaluation.SumTokenUsage(results)
+
+	return summary
+}
+
+// ComputeCategoryMetrics 计算分学科指标
+func (m *Metrics) ComputeCategoryMetrics(results []*evaluation.SampleResult) map[string]*evaluation.CategoryMetrics {
+	categoryMetrics := make(map[string]*evaluation.CategoryMetrics)
+
+	for _, r := range results {
+		subject := r.Category
+		if subject == "" {
+			subject = "default"
+		}
+
+		if _, ok := categoryMetrics[subject]; !ok {
+			categoryMetrics[subject] = &evaluation.CategoryMetrics{
+				Category: subject,
+			}
+		}
+
+		cm := categoryMetrics[subject]
+		cm.Total++
+		if r.Success {
+			cm.Success++
+		}
+		cm.AverageScore += r.Score
+	}
+
+	// 计算每个学科的平均值
+	for _, cm := range categoryMetrics {
+		if cm.Total > 0 {
+			cm.Accuracy = float64(cm.Success) / float64(cm.Total)
+			cm.AverageScore = cm.AverageScore / float64(cm.Total)
+		}
+	}
+
+	return categoryMetrics
+}
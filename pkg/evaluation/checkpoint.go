@@ -0,0 +1,104 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Checkpoint 评估断点文件
+//
+// 以 JSONL 格式逐行记录已完成样本的 SampleResult，重新运行时跳过已记录的样本。
+type Checkpoint struct {
+	mu        sync.Mutex
+	file      *os.File
+	completed map[string]*SampleResult
+}
+
+// OpenCheckpoint 打开（或创建）断点文件并加载已完成的样本
+//
+// 崩溃时未写完的最后一行会被截断丢弃，对应样本将重新评估。
+func OpenCheckpoint(path string) (*Checkpoint, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("创建目录失败: %w", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取断点文件失败: %w", err)
+	}
+
+	// 截断未以换行结尾的残缺行，避免后续追加写入与其拼接
+	valid := data
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		valid = data[:bytes.LastIndexByte(data, '\n')+1]
+		if err := os.Truncate(path, int64(len(valid))); err != nil {
+			return nil, fmt.Errorf("截断断点文件失败: %w", err)
+		}
+	}
+
+	completed := make(map[string]*SampleResult)
+	for _, line := range bytes.Split(valid, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var result SampleResult
+		if err := json.Unmarshal(line, &result); err != nil || result.SampleID == "" {
+			continue
+		}
+		completed[result.SampleID] = &result
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开断点文件失败: %w", err)
+	}
+
+	return &Checkpoint{
+		file:      file,
+		completed: completed,
+	}, nil
+}
+
+// Lookup 查找已完成样本的结果
+func (c *Checkpoint) Lookup(sampleID string) (*SampleResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.completed[sampleID]
+	return result, ok
+}
+
+// Len 返回已记录的样本数
+func (c *Checkpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.completed)
+}
+
+// Append 追加一个已完成样本的结果
+func (c *Checkpoint) Append(result *SampleResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("序列化样本结果失败: %w", err)
+	}
+	line = append(line, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.file.Write(line); err != nil {
+		return fmt.Errorf("写入断点文件失败: %w", err)
+	}
+	c.completed[result.SampleID] = result
+	return nil
+}
+
+// Close 关闭断点文件
+func (c *Checkpoint) Close() error {
+	return c.file.Close()
+}
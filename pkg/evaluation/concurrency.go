@@ -0,0 +1,159 @@
+package evaluation
+
+import "sync"
+
+// categoryLimiter 按类别限制并发评估的样本数
+//
+// 仅对 CategoryConcurrency 中上限为正且小于全局并发数的类别生效，
+// 其余类别只受全局 worker 数限制。名额由分发方在派发样本前占用，
+// 类别名额已满的样本暂缓派发，不占用 worker。
+type categoryLimiter struct {
+	// limits 各受限类别的并发上限（创建后只读）
+	limits map[string]int
+
+	mu      sync.Mutex
+	running map[string]int
+
+	// freed 有名额释放时非阻塞写入，用于唤醒等待名额的分发方
+	freed chan struct{}
+}
+
+// newCategoryLimiter 按配置创建类别并发限制器，无需限制时返回 nil
+func newCategoryLimiter(config *EvalConfig) *categoryLimiter {
+	var limiter *categoryLimiter
+	for category, n := range config.CategoryConcurrency {
+		if n <= 0 || n >= config.Concurrency {
+			continue
+		}
+		if limiter == nil {
+			limiter = &categoryLimiter{
+				limits:  make(map[string]int),
+				running: make(map[string]int),
+				freed:   make(chan struct{}, 1),
+			}
+		}
+		limiter.limits[category] = n
+	}
+	return limiter
+}
+
+// limited 判断 category 是否受并发限制
+func (l *categoryLimiter) limited(category string) bool {
+	if l == nil {
+		return false
+	}
+	_, ok := l.limits[category]
+	return ok
+}
+
+// available 判断 category 是否还有空闲名额
+func (l *categoryLimiter) available(category string) bool {
+	if !l.limited(category) {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running[category] < l.limits[category]
+}
+
+// tryAcquire 尝试占用 category 的一个并发名额，成功时返回释放函数
+//
+// 名额已满时立即返回 false。limiter 为 nil 或类别不受限时总是成功。
+func (l *categoryLimiter) tryAcquire(category string) (func(), bool) {
+	if !l.limited(category) {
+		return func() {}, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running[category] >= l.limits[category] {
+		return nil, false
+	}
+	l.running[category]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			l.running[category]--
+			l.mu.Unlock()
+			select {
+			case l.freed <- struct{}{}:
+			default:
+			}
+		})
+	}, true
+}
+
+// released 返回名额释放通知，limiter 为 nil 时返回 nil（永不就绪）
+func (l *categoryLimiter) released() <-chan struct{} {
+	if l == nil {
+		return nil
+	}
+	return l.freed
+}
+
+// categoryQueue 按类别分组的待派发样本索引，各类别内保持原有顺序
+type categoryQueue struct {
+	categories []string
+	queues     map[string][]int
+}
+
+// newCategoryQueue 按 categoryOf 将 indices 分组，categoryOf 为 nil 时全部归为一组
+func newCategoryQueue(indices []int, categoryOf func(i int) string) *categoryQueue {
+	q := &categoryQueue{queues: make(map[string][]int)}
+	for _, i := range indices {
+		category := ""
+		if categoryOf != nil {
+			category = categoryOf(i)
+		}
+		if _, ok := q.queues[category]; !ok {
+			q.categories = append(q.categories, category)
+		}
+		q.queues[category] = append(q.queues[category], i)
+	}
+	return q
+}
+
+// empty 判断是否还有待派发的样本
+func (q *categoryQueue) empty() bool {
+	for _, queue := range q.queues {
+		if len(queue) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// next 选出所属类别仍有名额的样本中索引最小者并占用名额
+//
+// 返回样本索引、名额释放函数及是否选中；所有待派发样本的类别名额均已满时返回 false。
+// 选中的样本从队列中移除。
+func (q *categoryQueue) next(limiter *categoryLimiter) (int, func(), bool) {
+	best := ""
+	found := false
+	for _, category := range q.categories {
+		queue := q.queues[category]
+		if len(queue) == 0 {
+			continue
+		}
+		if found && queue[0] > q.queues[best][0] {
+			continue
+		}
+		if !limiter.available(category) {
+			continue
+		}
+		best, found = category, true
+	}
+	if !found {
+		return 0, nil, false
+	}
+
+	release, ok := limiter.tryAcquire(best)
+	if !ok {
+		return 0, nil, false
+	}
+	index := q.queues[best][0]
+	q.queues[best] = q.queues[best][1:]
+	return index, release, true
+}
@@ -0,0 +1,66 @@
+package evaluation
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// csvHeader CSV 导出的列
+var csvHeader = []string{
+	"sample_id", "category", "level", "success", "partial_success",
+	"score", "execution_time_ms", "error",
+}
+
+// ExportCSV 导出逐样本 CSV
+//
+// 每个 SampleResult 一行，首行为表头；包含逗号、引号或换行的字段会被自动加引号。
+func ExportCSV(result *EvalResult, outputPath string) error {
+	if result == nil {
+		return fmt.Errorf("评估结果为空")
+	}
+
+	// 确保目录存在
+	dir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("写入表头失败: %w", err)
+	}
+
+	for _, sr := range result.DetailedResults {
+		if sr == nil {
+			continue
+		}
+		record := []string{
+			sr.SampleID,
+			sr.Category,
+			strconv.Itoa(sr.Level),
+			strconv.FormatBool(sr.Success),
+			strconv.FormatBool(sr.PartialSuccess),
+			strconv.FormatFloat(sr.Score, 'f', -1, 64),
+			strconv.FormatInt(sr.ExecutionTime.Milliseconds(), 10),
+			sr.Error,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入样本 %s 失败: %w", sr.SampleID, err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("写入 CSV 失败: %w", err)
+	}
+	return nil
+}
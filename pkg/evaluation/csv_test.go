@@ -0,0 +1,51 @@
+package evaluation
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportCSV_RoundTrip(t *testing.T) {
+	result := &EvalResult{
+		DetailedResults: []*SampleResult{
+			{SampleID: "q1", Category: "simple", Level: 1, Success: true, Score: 1, ExecutionTime: 1500 * time.Millisecond},
+			{SampleID: "q2", Category: "multiple", Level: 2, PartialSuccess: true, Score: 0.5, ExecutionTime: 20 * time.Millisecond},
+			{SampleID: "q3", Level: 3, Error: "agent failed: bad input, \"retry\"\nsecond line"},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "results.csv")
+	if err := ExportCSV(result, path); err != nil {
+		t.Fatalf("ExportCSV() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open csv: %v", err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse csv: %v", err)
+	}
+
+	if len(records) != 4 {
+		t.Fatalf("expected header + 3 rows, got %d rows", len(records))
+	}
+	if records[0][0] != "sample_id" || records[0][7] != "error" {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	if records[1][3] != "true" || records[1][6] != "1500" {
+		t.Errorf("unexpected first row: %v", records[1])
+	}
+	if records[2][4] != "true" || records[2][5] != "0.5" {
+		t.Errorf("unexpected second row: %v", records[2])
+	}
+	if records[3][7] != result.DetailedResults[2].Error {
+		t.Errorf("error field not preserved: %q", records[3][7])
+	}
+}
@@ -0,0 +1,293 @@
+package evaluation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SampleDiff 单个样本在两次运行间的变化
+type SampleDiff struct {
+	// SampleID 样本 ID
+	SampleID string `json:"sample_id"`
+
+	// BaselineSuccess 基线运行是否成功
+	BaselineSuccess bool `json:"baseline_success"`
+
+	// CandidateSuccess 候选运行是否成功
+	CandidateSuccess bool `json:"candidate_success"`
+
+	// BaselineScore 基线得分
+	BaselineScore float64 `json:"baseline_score"`
+
+	// CandidateScore 候选得分
+	CandidateScore float64 `json:"candidate_score"`
+}
+
+// ScoreDelta 返回得分变化（候选 - 基线）
+func (d SampleDiff) ScoreDelta() float64 {
+	return d.CandidateScore - d.BaselineScore
+}
+
+// EvalDiff 两次评估运行的对比结果
+type EvalDiff struct {
+	// BaselineName 基线运行名称
+	BaselineName string `json:"baseline_name"`
+
+	// CandidateName 候选运行名称
+	CandidateName string `json:"candidate_name"`
+
+	// BaselineAccuracy 基线准确率
+	BaselineAccuracy float64 `json:"baseline_accuracy"`
+
+	// CandidateAccuracy 候选准确率
+	CandidateAccuracy float64 `json:"candidate_accuracy"`
+
+	// AccuracyDelta 准确率变化（候选 - 基线）
+	AccuracyDelta float64 `json:"accuracy_delta"`
+
+	// NewlyFailing 基线成功、候选失败的样本（回退）
+	NewlyFailing []SampleDiff `json:"newly_failing"`
+
+	// NewlyPassing 基线失败、候选成功的样本（改进）
+	NewlyPassing []SampleDiff `json:"newly_passing"`
+
+	// ScoreChanged 成功状态不变但得分变化的样本
+	ScoreChanged []SampleDiff `json:"score_changed"`
+
+	// OnlyInBaseline 仅出现在基线运行中的样本 ID
+	OnlyInBaseline []string `json:"only_in_baseline,omitempty"`
+
+	// OnlyInCandidate 仅出现在候选运行中的样本 ID
+	OnlyInCandidate []string `json:"only_in_candidate,omitempty"`
+
+	// Categories 按类别统计的逐样本胜负（以候选为视角，键为类别名称）
+	Categories map[string]*HeadToHead `json:"categories,omitempty"`
+}
+
+// HeadToHead 候选相对基线的逐样本胜负统计
+//
+// 成功状态不同时成功一方胜出，成功状态相同时得分高者胜出，否则为平局。
+type HeadToHead struct {
+	// Category 类别名称
+	Category string `json:"category"`
+
+	// Wins 候选胜出的样本数
+	Wins int `json:"wins"`
+
+	// Ties 平局样本数
+	Ties int `json:"ties"`
+
+	// Losses 候选落败的样本数
+	Losses int `json:"losses"`
+}
+
+// Total 返回对比的样本数
+func (h *HeadToHead) Total() int {
+	return h.Wins + h.Ties + h.Losses
+}
+
+// record 记录一个样本的对比结果
+func (h *HeadToHead) record(d SampleDiff) {
+	switch {
+	case d.CandidateSuccess != d.BaselineSuccess:
+		if d.CandidateSuccess {
+			h.Wins++
+		} else {
+			h.Losses++
+		}
+	case d.CandidateScore > d.BaselineScore:
+		h.Wins++
+	case d.CandidateScore < d.BaselineScore:
+		h.Losses++
+	default:
+		h.Ties++
+	}
+}
+
+// Overall 返回所有类别合计的胜负统计
+func (d *EvalDiff) Overall() HeadToHead {
+	total := HeadToHead{Category: "overall"}
+	for _, h := range d.Categories {
+		total.Wins += h.Wins
+		total.Ties += h.Ties
+		total.Losses += h.Losses
+	}
+	return total
+}
+
+// Diff 按 SampleID 对比两次评估运行
+//
+// 返回回退、改进、得分变化的样本以及准确率变化；仅出现在一方的样本单独列出。
+func Diff(baseline, candidate *EvalResult) (*EvalDiff, error) {
+	if baseline == nil || candidate == nil {
+		return nil, fmt.Errorf("评估结果为空")
+	}
+
+	diff := &EvalDiff{
+		BaselineName:      runName(baseline),
+		CandidateName:     runName(candidate),
+		BaselineAccuracy:  baseline.OverallAccuracy,
+		CandidateAccuracy: candidate.OverallAccuracy,
+		AccuracyDelta:     candidate.OverallAccuracy - baseline.OverallAccuracy,
+		Categories:        make(map[string]*HeadToHead),
+	}
+
+	candidateByID := make(map[string]*SampleResult, len(candidate.DetailedResults))
+	for _, r := range candidate.DetailedResults {
+		if r != nil {
+			candidateByID[r.SampleID] = r
+		}
+	}
+
+	seen := make(map[string]bool, len(baseline.DetailedResults))
+	for _, base := range baseline.DetailedResults {
+		if base == nil {
+			continue
+		}
+		seen[base.SampleID] = true
+
+		cand, ok := candidateByID[base.SampleID]
+		if !ok {
+			diff.OnlyInBaseline = append(diff.OnlyInBaseline, base.SampleID)
+			continue
+		}
+
+		sd := SampleDiff{
+			SampleID:         base.SampleID,
+			BaselineSuccess:  base.Success,
+			CandidateSuccess: cand.Success,
+			BaselineScore:    base.Score,
+			CandidateScore:   cand.Score,
+		}
+
+		category := base.Category
+		if category == "" {
+			category = "default"
+		}
+		if diff.Categories[category] == nil {
+			diff.Categories[category] = &HeadToHead{Category: category}
+		}
+		diff.Categories[category].record(sd)
+
+		switch {
+		case base.Success && !cand.Success:
+			diff.NewlyFailing = append(diff.NewlyFailing, sd)
+		case !base.Success && cand.Success:
+			diff.NewlyPassing = append(diff.NewlyPassing, sd)
+		case base.Score != cand.Score:
+			diff.ScoreChanged = append(diff.ScoreChanged, sd)
+		}
+	}
+
+	for _, r := range candidate.DetailedResults {
+		if r != nil && !seen[r.SampleID] {
+			diff.OnlyInCandidate = append(diff.OnlyInCandidate, r.SampleID)
+		}
+	}
+
+	return diff, nil
+}
+
+// runName 返回运行的展示名称
+func runName(result *EvalResult) string {
+	if result.AgentName == "" {
+		return result.BenchmarkName
+	}
+	return fmt.Sprintf("%s (%s)", result.AgentName, result.BenchmarkName)
+}
+
+// ExportDiffReport 导出两次运行对比的 Markdown 报告
+//
+// 回退样本列在最前，其次为改进样本、得分变化样本和仅出现在一方的样本。
+func ExportDiffReport(diff *EvalDiff, path string) error {
+	if diff == nil {
+		return fmt.Errorf("对比结果为空")
+	}
+
+	// 确保目录存在
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# 评估对比报告\n\n")
+	fmt.Fprintf(&sb, "- **基线**: %s\n", diff.BaselineName)
+	fmt.Fprintf(&sb, "- **候选**: %s\n", diff.CandidateName)
+	fmt.Fprintf(&sb, "- **准确率**: %.2f%% → %.2f%% (%+.2f%%)\n\n",
+		diff.BaselineAccuracy*100, diff.CandidateAccuracy*100, diff.AccuracyDelta*100)
+
+	fmt.Fprintf(&sb, "| 变化 | 样本数 |\n")
+	fmt.Fprintf(&sb, "|------|--------|\n")
+	fmt.Fprintf(&sb, "| 回退 | %d |\n", len(diff.NewlyFailing))
+	fmt.Fprintf(&sb, "| 改进 | %d |\n", len(diff.NewlyPassing))
+	fmt.Fprintf(&sb, "| 得分变化 | %d |\n\n", len(diff.ScoreChanged))
+
+	writeHeadToHeadSection(&sb, diff)
+
+	writeDiffSection(&sb, "回退样本", diff.NewlyFailing)
+	writeDiffSection(&sb, "改进样本", diff.NewlyPassing)
+	writeDiffSection(&sb, "得分变化样本", diff.ScoreChanged)
+
+	if len(diff.OnlyInBaseline) > 0 {
+		fmt.Fprintf(&sb, "## 仅在基线中的样本\n\n%s\n\n", strings.Join(diff.OnlyInBaseline, ", "))
+	}
+	if len(diff.OnlyInCandidate) > 0 {
+		fmt.Fprintf(&sb, "## 仅在候选中的样本\n\n%s\n\n", strings.Join(diff.OnlyInCandidate, ", "))
+	}
+
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	return nil
+}
+
+// writeHeadToHeadSection 写入分类别胜负统计（以候选为视角）
+func writeHeadToHeadSection(sb *strings.Builder, diff *EvalDiff) {
+	if len(diff.Categories) == 0 {
+		return
+	}
+
+	categories := make([]string, 0, len(diff.Categories))
+	for c := range diff.Categories {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+
+	fmt.Fprintf(sb, "## 分类别胜负（候选视角）\n\n")
+	fmt.Fprintf(sb, "| 类别 | 胜 | 平 | 负 |\n")
+	fmt.Fprintf(sb, "|------|----|----|----|\n")
+	for _, c := range categories {
+		h := diff.Categories[c]
+		fmt.Fprintf(sb, "| %s | %d | %d | %d |\n", c, h.Wins, h.Ties, h.Losses)
+	}
+	overall := diff.Overall()
+	fmt.Fprintf(sb, "| **合计** | %d | %d | %d |\n\n", overall.Wins, overall.Ties, overall.Losses)
+}
+
+// writeDiffSection 写入一组样本变化
+func writeDiffSection(sb *strings.Builder, title string, diffs []SampleDiff) {
+	if len(diffs) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "## %s\n\n", title)
+	fmt.Fprintf(sb, "| 样本 | 基线 | 候选 | 得分变化 |\n")
+	fmt.Fprintf(sb, "|------|------|------|----------|\n")
+	for _, d := range diffs {
+		fmt.Fprintf(sb, "| %s | %s (%.2f) | %s (%.2f) | %+.2f |\n",
+			d.SampleID, passMark(d.BaselineSuccess), d.BaselineScore,
+			passMark(d.CandidateSuccess), d.CandidateScore, d.ScoreDelta())
+	}
+	fmt.Fprintf(sb, "\n")
+}
+
+// passMark 返回成功状态标记
+func passMark(success bool) string {
+	if success {
+		return "✓"
+	}
+	return "✗"
+}
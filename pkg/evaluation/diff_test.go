@@ -0,0 +1,116 @@
+package evaluation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiff_Classification(t *testing.T) {
+	baseline := &EvalResult{
+		BenchmarkName:   "GAIA",
+		AgentName:       "v1",
+		OverallAccuracy: 0.5,
+		DetailedResults: []*SampleResult{
+			{SampleID: "a", Success: true, Score: 1.0},
+			{SampleID: "b", Success: false, Score: 0.0},
+			{SampleID: "c", Success: false, Score: 0.2},
+			{SampleID: "d", Success: true, Score: 1.0},
+			{SampleID: "old", Success: true, Score: 1.0},
+		},
+	}
+	candidate := &EvalResult{
+		BenchmarkName:   "GAIA",
+		AgentName:       "v2",
+		OverallAccuracy: 0.75,
+		DetailedResults: []*SampleResult{
+			{SampleID: "a", Success: false, Score: 0.0},
+			{SampleID: "b", Success: true, Score: 1.0},
+			{SampleID: "c", Success: false, Score: 0.6},
+			{SampleID: "d", Success: true, Score: 1.0},
+			{SampleID: "new", Success: true, Score: 1.0},
+		},
+	}
+
+	diff, err := Diff(baseline, candidate)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	if len(diff.NewlyFailing) != 1 || diff.NewlyFailing[0].SampleID != "a" {
+		t.Errorf("NewlyFailing = %+v, want [a]", diff.NewlyFailing)
+	}
+	if len(diff.NewlyPassing) != 1 || diff.NewlyPassing[0].SampleID != "b" {
+		t.Errorf("NewlyPassing = %+v, want [b]", diff.NewlyPassing)
+	}
+	if len(diff.ScoreChanged) != 1 || diff.ScoreChanged[0].SampleID != "c" {
+		t.Errorf("ScoreChanged = %+v, want [c]", diff.ScoreChanged)
+	}
+	if len(diff.OnlyInBaseline) != 1 || diff.OnlyInBaseline[0] != "old" {
+		t.Errorf("OnlyInBaseline = %v, want [old]", diff.OnlyInBaseline)
+	}
+	if len(diff.OnlyInCandidate) != 1 || diff.OnlyInCandidate[0] != "new" {
+		t.Errorf("OnlyInCandidate = %v, want [new]", diff.OnlyInCandidate)
+	}
+	if diff.AccuracyDelta < 0.2499 || diff.AccuracyDelta > 0.2501 {
+		t.Errorf("AccuracyDelta = %v, want 0.25", diff.AccuracyDelta)
+	}
+
+	path := filepath.Join(t.TempDir(), "diff.md")
+	if err := ExportDiffReport(diff, path); err != nil {
+		t.Fatalf("ExportDiffReport() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	report := string(data)
+	regressions := strings.Index(report, "## 回退样本")
+	improvements := strings.Index(report, "## 改进样本")
+	if regressions < 0 || improvements < 0 || regressions > improvements {
+		t.Errorf("expected regressions to be listed before improvements:\n%s", report)
+	}
+}
+
+func TestDiff_Categories(t *testing.T) {
+	baseline := &EvalResult{DetailedResults: []*SampleResult{
+		{SampleID: "a", Category: "math", Success: true, Score: 1.0},
+		{SampleID: "b", Category: "math", Success: false, Score: 0.2},
+		{SampleID: "c", Category: "math", Success: false, Score: 0.0},
+		{SampleID: "d", Category: "code", Success: true, Score: 1.0},
+		{SampleID: "e", Success: false, Score: 0.5},
+	}}
+	candidate := &EvalResult{DetailedResults: []*SampleResult{
+		{SampleID: "a", Category: "math", Success: false, Score: 0.0},
+		{SampleID: "b", Category: "math", Success: false, Score: 0.6},
+		{SampleID: "c", Category: "math", Success: true, Score: 1.0},
+		{SampleID: "d", Category: "code", Success: true, Score: 1.0},
+		{SampleID: "e", Success: false, Score: 0.1},
+	}}
+
+	diff, err := Diff(baseline, candidate)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	math := diff.Categories["math"]
+	if math == nil || math.Wins != 2 || math.Losses != 1 || math.Ties != 0 {
+		t.Errorf("math = %+v, want 2 wins 1 loss", math)
+	}
+	if code := diff.Categories["code"]; code == nil || code.Ties != 1 || code.Total() != 1 {
+		t.Errorf("code = %+v, want 1 tie", code)
+	}
+	if def := diff.Categories["default"]; def == nil || def.Losses != 1 {
+		t.Errorf("default = %+v, want 1 loss", def)
+	}
+	if overall := diff.Overall(); overall.Wins != 2 || overall.Ties != 1 || overall.Losses != 2 {
+		t.Errorf("Overall() = %+v, want 2/1/2", overall)
+	}
+}
+
+func TestDiff_NilResult(t *testing.T) {
+	if _, err := Diff(nil, &EvalResult{}); err == nil {
+		t.Error("expected error for nil baseline")
+	}
+}
@@ -0,0 +1,146 @@
+package evaluation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorBreakdownKey 错误分类统计在 MetricsSummary.Extra 中的键
+const ErrorBreakdownKey = "error_breakdown"
+
+// 错误分类
+const (
+	// ErrorClassNone 没有错误（包括正常作答但答案错误的样本）
+	ErrorClassNone = "no_error"
+
+	// ErrorClassTimeout 单样本超时
+	ErrorClassTimeout = "timeout"
+
+	// ErrorClassExtractionFailed 无法从响应中提取答案
+	ErrorClassExtractionFailed = "extraction_failed"
+
+	// ErrorClassAgentError 智能体执行失败及其他基础设施错误
+	ErrorClassAgentError = "agent_error"
+
+	// ErrorClassGroundTruthMissing 期望答案缺失或无法解析
+	ErrorClassGroundTruthMissing = "ground_truth_missing"
+)
+
+// errorClasses 报告中的错误分类顺序
+var errorClasses = []string{
+	ErrorClassNone,
+	ErrorClassTimeout,
+	ErrorClassExtractionFailed,
+	ErrorClassAgentError,
+	ErrorClassGroundTruthMissing,
+}
+
+// errorClassNames 错误分类的展示名称
+var errorClassNames = map[string]string{
+	ErrorClassNone:               "无错误",
+	ErrorClassTimeout:            "超时",
+	ErrorClassExtractionFailed:   "提取失败",
+	ErrorClassAgentError:         "智能体错误",
+	ErrorClassGroundTruthMissing: "缺少期望答案",
+}
+
+// ErrorBreakdown 按错误分类统计的样本数
+//
+// 用于区分真正的错误答案（no_error 中失败的样本）与基础设施错误，便于排查。
+type ErrorBreakdown map[string]int
+
+// ClassifyError 判断样本结果的错误分类
+//
+// 优先依据 Details 中的标记（timeout、extraction_error、gt_parse_error），
+// 其次依据 Error 内容；其余非空错误归为 agent_error。
+func ClassifyError(result *SampleResult) string {
+	if result == nil {
+		return ErrorClassAgentError
+	}
+	if IsTimeoutResult(result) {
+		return ErrorClassTimeout
+	}
+	if _, ok := result.Details["extraction_error"]; ok {
+		return ErrorClassExtractionFailed
+	}
+	if _, ok := result.Details["gt_parse_error"]; ok {
+		return ErrorClassGroundTruthMissing
+	}
+
+	msg := strings.ToLower(result.Error)
+	switch {
+	case msg == "":
+		return ErrorClassNone
+	case strings.Contains(msg, "超时") || strings.Contains(msg, "deadline exceeded"):
+		return ErrorClassTimeout
+	case strings.Contains(msg, "提取"):
+		return ErrorClassExtractionFailed
+	case strings.Contains(msg, "ground truth") || strings.Contains(msg, "期望答案"):
+		return ErrorClassGroundTruthMissing
+	default:
+		return ErrorClassAgentError
+	}
+}
+
+// ComputeErrorBreakdown 按错误分类统计样本数，所有分类均会出现（计数可为 0）
+func ComputeErrorBreakdown(results []*SampleResult) ErrorBreakdown {
+	breakdown := make(ErrorBreakdown, len(errorClasses))
+	for _, class := range errorClasses {
+		breakdown[class] = 0
+	}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		breakdown[ClassifyError(r)]++
+	}
+	return breakdown
+}
+
+// ErrorBreakdownOf 读取汇总指标中的错误分类统计
+//
+// 兼容从 JSON 加载的结果（值为 map[string]interface{}）。不存在时返回 nil。
+func ErrorBreakdownOf(summary *MetricsSummary) ErrorBreakdown {
+	if summary == nil {
+		return nil
+	}
+	switch v := summary.Extra[ErrorBreakdownKey].(type) {
+	case ErrorBreakdown:
+		return v
+	case map[string]int:
+		return ErrorBreakdown(v)
+	case map[string]interface{}:
+		breakdown := make(ErrorBreakdown, len(v))
+		for class, count := range v {
+			switch n := count.(type) {
+			case float64:
+				breakdown[class] = int(n)
+			case int:
+				breakdown[class] = n
+			}
+		}
+		return breakdown
+	}
+	return nil
+}
+
+// Markdown 以 Markdown 表格输出错误分类统计
+func (b ErrorBreakdown) Markdown() string {
+	total := 0
+	for _, c := range b {
+		total += c
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "| 错误分类 | 样本数 | 占比 |\n")
+	fmt.Fprintf(&sb, "|----------|--------|------|\n")
+	for _, class := range errorClasses {
+		count := b[class]
+		rate := 0.0
+		if total > 0 {
+			rate = float64(count) / float64(total)
+		}
+		fmt.Fprintf(&sb, "| %s (%s) | %d | %.2f%% |\n", errorClassNames[class], class, count, rate*100)
+	}
+	return sb.String()
+}
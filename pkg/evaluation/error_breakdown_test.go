@@ -0,0 +1,97 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func errorBreakdownResults() []*SampleResult {
+	return []*SampleResult{
+		{SampleID: "ok", Success: true},
+		{SampleID: "wrong", Predicted: "B", Expected: "A"},
+		{SampleID: "timeout", Error: "样本评估超时（5s）", Details: map[string]interface{}{"timeout": true}},
+		{SampleID: "deadline", Error: "context deadline exceeded"},
+		{SampleID: "extract", Error: "提取函数调用失败: 未找到 JSON", Details: map[string]interface{}{"extraction_error": "未找到 JSON"}},
+		{SampleID: "gt", Error: "未找到 ground truth"},
+		{SampleID: "gt_parse", Details: map[string]interface{}{"gt_parse_error": "bad json"}},
+		{SampleID: "crash", Error: "llm: connection refused"},
+		nil,
+	}
+}
+
+func TestComputeErrorBreakdown(t *testing.T) {
+	got := ComputeErrorBreakdown(errorBreakdownResults())
+	want := ErrorBreakdown{
+		ErrorClassNone:               2,
+		ErrorClassTimeout:            2,
+		ErrorClassExtractionFailed:   1,
+		ErrorClassAgentError:         1,
+		ErrorClassGroundTruthMissing: 2,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeErrorBreakdown() = %v, want %v", got, want)
+	}
+
+	// 空结果也包含全部分类
+	if empty := ComputeErrorBreakdown(nil); len(empty) != len(errorClasses) {
+		t.Errorf("empty breakdown has %d classes, want %d", len(empty), len(errorClasses))
+	}
+}
+
+func TestErrorBreakdownOf_JSONRoundTrip(t *testing.T) {
+	breakdown := ComputeErrorBreakdown(errorBreakdownResults())
+	data, err := json.Marshal(&MetricsSummary{Extra: map[string]interface{}{ErrorBreakdownKey: breakdown}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded MetricsSummary
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if got := ErrorBreakdownOf(&loaded); !reflect.DeepEqual(got, breakdown) {
+		t.Errorf("ErrorBreakdownOf() = %v, want %v", got, breakdown)
+	}
+	if got := ErrorBreakdownOf(&MetricsSummary{}); got != nil {
+		t.Errorf("ErrorBreakdownOf() without key = %v, want nil", got)
+	}
+}
+
+func TestExportFullReport_ErrorBreakdown(t *testing.T) {
+	results := errorBreakdownResults()[:8]
+	result := &EvalResult{
+		BenchmarkName:   "bench",
+		TotalSamples:    len(results),
+		SuccessCount:    1,
+		DetailedResults: results,
+		Metrics: &MetricsSummary{Extra: map[string]interface{}{
+			ErrorBreakdownKey: ComputeErrorBreakdown(results),
+		}},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.md")
+	if err := ExportFullReport(result, path); err != nil {
+		t.Fatalf("ExportFullReport() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+
+	for _, want := range []string{
+		"## 错误分类",
+		"| 无错误 (no_error) | 2 | 25.00% |",
+		"| 超时 (timeout) | 2 | 25.00% |",
+		"| 提取失败 (extraction_failed) | 1 | 12.50% |",
+		"| 智能体错误 (agent_error) | 1 | 12.50% |",
+		"| 缺少期望答案 (ground_truth_missing) | 2 | 25.00% |",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("report missing %q:\n%s", want, content)
+		}
+	}
+}
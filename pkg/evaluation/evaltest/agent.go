@@ -0,0 +1,247 @@
+// Package evaltest 提供评估器测试用的智能体桩实现
+//
+// FakeAgent 按输入返回预设响应，可模拟错误、延迟和 Token 使用量，
+// 并记录调用情况，便于编写并发、超时等评估器测试。
+package evaltest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/config"
+	"github.com/ahhsitt/helloagents-go/pkg/core/message"
+)
+
+// FakeAgent 可配置的测试智能体，实现 agents.Agent
+//
+// 响应、错误和延迟均按 Input.Query 匹配，未命中时使用默认值。
+// 所有方法并发安全。
+type FakeAgent struct {
+	name string
+
+	responses       map[string]string
+	defaultResponse string
+
+	errs       map[string]error
+	defaultErr error
+
+	latencies      map[string]time.Duration
+	defaultLatency time.Duration
+
+	usage message.TokenUsage
+
+	mu            sync.Mutex
+	inputs        []agents.Input
+	inFlight      int
+	maxConcurrent int
+}
+
+// Option FakeAgent 配置选项
+type Option func(*FakeAgent)
+
+// WithName 设置智能体名称（默认 "fake"）
+func WithName(name string) Option {
+	return func(a *FakeAgent) {
+		a.name = name
+	}
+}
+
+// WithResponse 设置指定查询的响应
+func WithResponse(query, response string) Option {
+	return func(a *FakeAgent) {
+		a.responses[query] = response
+	}
+}
+
+// WithResponses 批量设置查询到响应的映射
+func WithResponses(responses map[string]string) Option {
+	return func(a *FakeAgent) {
+		for query, response := range responses {
+			a.responses[query] = response
+		}
+	}
+}
+
+// WithDefaultResponse 设置未命中查询时的默认响应
+func WithDefaultResponse(response string) Option {
+	return func(a *FakeAgent) {
+		a.defaultResponse = response
+	}
+}
+
+// WithError 设置指定查询返回的错误
+func WithError(query string, err error) Option {
+	return func(a *FakeAgent) {
+		a.errs[query] = err
+	}
+}
+
+// WithDefaultError 设置未单独配置错误的查询返回的错误
+func WithDefaultError(err error) Option {
+	return func(a *FakeAgent) {
+		a.defaultErr = err
+	}
+}
+
+// WithLatency 设置每次调用的默认延迟
+//
+// 延迟期间上下文取消时 Run 立即返回 ctx.Err()。
+func WithLatency(d time.Duration) Option {
+	return func(a *FakeAgent) {
+		a.defaultLatency = d
+	}
+}
+
+// WithQueryLatency 设置指定查询的延迟（覆盖默认延迟）
+func WithQueryLatency(query string, d time.Duration) Option {
+	return func(a *FakeAgent) {
+		a.latencies[query] = d
+	}
+}
+
+// WithTokenUsage 设置每次调用报告的 Token 使用量
+func WithTokenUsage(usage message.TokenUsage) Option {
+	return func(a *FakeAgent) {
+		a.usage = usage
+	}
+}
+
+// NewFakeAgent 创建测试智能体
+func NewFakeAgent(opts ...Option) *FakeAgent {
+	a := &FakeAgent{
+		name:      "fake",
+		responses: make(map[string]string),
+		errs:      make(map[string]error),
+		latencies: make(map[string]time.Duration),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Run 按查询返回预设响应
+//
+// 依次模拟延迟、错误和响应；出错时 Output.Error 同时记录错误信息。
+func (a *FakeAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	start := time.Now()
+	a.begin(input)
+	defer a.end()
+
+	latency, ok := a.latencies[input.Query]
+	if !ok {
+		latency = a.defaultLatency
+	}
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return agents.Output{Error: ctx.Err().Error(), Duration: time.Since(start)}, ctx.Err()
+		}
+	}
+
+	err, ok := a.errs[input.Query]
+	if !ok {
+		err = a.defaultErr
+	}
+	if err != nil {
+		return agents.Output{Error: err.Error(), Duration: time.Since(start)}, err
+	}
+
+	response, ok := a.responses[input.Query]
+	if !ok {
+		response = a.defaultResponse
+	}
+	return agents.Output{
+		Response:   response,
+		TokenUsage: a.usage,
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// RunStream 以单个文本块加完成块的形式返回 Run 的结果
+func (a *FakeAgent) RunStream(ctx context.Context, input agents.Input) (<-chan agents.StreamChunk, <-chan error) {
+	chunkChan := make(chan agents.StreamChunk, 2)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(chunkChan)
+		defer close(errChan)
+
+		output, err := a.Run(ctx, input)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		chunkChan <- agents.StreamChunk{Type: agents.ChunkTypeText, Content: output.Response}
+		chunkChan <- agents.StreamChunk{Type: agents.ChunkTypeDone, Done: true}
+	}()
+
+	return chunkChan, errChan
+}
+
+// Name 返回智能体名称
+func (a *FakeAgent) Name() string {
+	return a.name
+}
+
+// Config 返回智能体配置
+func (a *FakeAgent) Config() config.AgentConfig {
+	return config.AgentConfig{Name: a.name}
+}
+
+// Calls 返回 Run 的累计调用次数
+func (a *FakeAgent) Calls() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.inputs)
+}
+
+// CallsFor 返回指定查询的调用次数
+func (a *FakeAgent) CallsFor(query string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	n := 0
+	for _, input := range a.inputs {
+		if input.Query == query {
+			n++
+		}
+	}
+	return n
+}
+
+// Inputs 按调用顺序返回收到的输入
+func (a *FakeAgent) Inputs() []agents.Input {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]agents.Input(nil), a.inputs...)
+}
+
+// MaxConcurrent 返回观察到的最大并发调用数
+func (a *FakeAgent) MaxConcurrent() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.maxConcurrent
+}
+
+// begin 记录一次调用开始
+func (a *FakeAgent) begin(input agents.Input) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.inputs = append(a.inputs, input)
+	a.inFlight++
+	a.maxConcurrent = max(a.maxConcurrent, a.inFlight)
+}
+
+// end 记录一次调用结束
+func (a *FakeAgent) end() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.inFlight--
+}
+
+var _ agents.Agent = (*FakeAgent)(nil)
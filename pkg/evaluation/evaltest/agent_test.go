@@ -0,0 +1,116 @@
+package evaltest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/message"
+)
+
+func TestFakeAgent_KeyedResponses(t *testing.T) {
+	usage := message.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}
+	agent := NewFakeAgent(
+		WithResponse("q1", "a1"),
+		WithResponses(map[string]string{"q2": "a2"}),
+		WithDefaultResponse("unknown"),
+		WithTokenUsage(usage),
+	)
+
+	for query, want := range map[string]string{"q1": "a1", "q2": "a2", "q3": "unknown"} {
+		output, err := agent.Run(context.Background(), agents.Input{Query: query})
+		if err != nil {
+			t.Fatalf("Run(%s) error = %v", query, err)
+		}
+		if output.Response != want {
+			t.Errorf("Run(%s).Response = %q, want %q", query, output.Response, want)
+		}
+		if output.TokenUsage != usage {
+			t.Errorf("Run(%s).TokenUsage = %+v, want %+v", query, output.TokenUsage, usage)
+		}
+	}
+
+	if agent.Calls() != 3 || agent.CallsFor("q1") != 1 {
+		t.Errorf("Calls() = %d, CallsFor(q1) = %d, want 3 and 1", agent.Calls(), agent.CallsFor("q1"))
+	}
+	if inputs := agent.Inputs(); len(inputs) != 3 {
+		t.Errorf("Inputs() = %v, want 3 inputs", inputs)
+	}
+}
+
+func TestFakeAgent_SimulatedErrors(t *testing.T) {
+	errQuery := errors.New("query failed")
+	errDefault := errors.New("default failure")
+	agent := NewFakeAgent(
+		WithResponse("ok", "fine"),
+		WithError("bad", errQuery),
+		WithError("ok", nil),
+		WithDefaultError(errDefault),
+	)
+
+	output, err := agent.Run(context.Background(), agents.Input{Query: "bad"})
+	if !errors.Is(err, errQuery) || output.Error != errQuery.Error() {
+		t.Errorf("Run(bad) = (%+v, %v), want %v", output, err, errQuery)
+	}
+
+	if _, err := agent.Run(context.Background(), agents.Input{Query: "other"}); !errors.Is(err, errDefault) {
+		t.Errorf("Run(other) error = %v, want %v", err, errDefault)
+	}
+
+	// 显式配置为 nil 错误的查询不受默认错误影响
+	output, err = agent.Run(context.Background(), agents.Input{Query: "ok"})
+	if err != nil || output.Response != "fine" {
+		t.Errorf("Run(ok) = (%+v, %v), want fine without error", output, err)
+	}
+
+	chunks, errCh := agent.RunStream(context.Background(), agents.Input{Query: "bad"})
+	for range chunks {
+		t.Error("expected no chunks on error")
+	}
+	if err := <-errCh; !errors.Is(err, errQuery) {
+		t.Errorf("RunStream(bad) error = %v, want %v", err, errQuery)
+	}
+}
+
+func TestFakeAgent_LatencyRespectsContext(t *testing.T) {
+	agent := NewFakeAgent(WithLatency(time.Second), WithQueryLatency("fast", 0), WithDefaultResponse("done"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := agent.Run(ctx, agents.Input{Query: "slow"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run(slow) error = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run(slow) took %v, expected to stop at the deadline", elapsed)
+	}
+
+	output, err := agent.Run(context.Background(), agents.Input{Query: "fast"})
+	if err != nil || output.Response != "done" {
+		t.Errorf("Run(fast) = (%+v, %v), want done", output, err)
+	}
+}
+
+func TestFakeAgent_MaxConcurrent(t *testing.T) {
+	agent := NewFakeAgent(WithLatency(30 * time.Millisecond))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = agent.Run(context.Background(), agents.Input{Query: "q"})
+		}()
+	}
+	wg.Wait()
+
+	if agent.MaxConcurrent() < 2 {
+		t.Errorf("MaxConcurrent() = %d, want >= 2", agent.MaxConcurrent())
+	}
+	if agent.CallsFor("q") != 4 {
+		t.Errorf("CallsFor(q) = %d, want 4", agent.CallsFor("q"))
+	}
+}
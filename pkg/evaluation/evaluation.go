@@ -3,12 +3,14 @@
 // 本包实现了多种评估基准测试，用于评估 Agent 的各项能力：
 // - BFCL (Berkeley Function Calling Leaderboard): 工具/函数调用能力评估
 // - GAIA (General AI Assistants): 通用 AI 助手能力评估
+// - MMLU (Massive Multitask Language Understanding): 多学科知识选择题评估
 // - LLM Judge: 使用 LLM 作为评委进行质量评估
 // - Win Rate: 成对对比计算胜率
 package evaluation
 
 import (
 	"context"
+	"time"
 
 	"github.com/ahhsitt/helloagents-go/pkg/agents"
 )
@@ -101,3 +103,11 @@ type Metrics interface {
 //   - done: 已完成数量
 //   - total: 总数量
 type ProgressCallback func(done, total int)
+
+// ProgressCallbackV2 带耗时的进度回调函数类型（可用于估算剩余时间）
+//
+// 参数:
+//   - done: 已完成数量
+//   - total: 总数量
+//   - elapsed: 自评估开始以来的耗时
+type ProgressCallbackV2 func(done, total int, elapsed time.Duration)
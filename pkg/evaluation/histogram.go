@@ -0,0 +1,97 @@
+package evaluation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// histogramBarWidth 直方图最长条形的字符数
+const histogramBarWidth = 30
+
+var (
+	// DefaultUnitScoreEdges 0-1 分数（如 GAIA 部分得分）的默认分桶下界
+	DefaultUnitScoreEdges = []float64{0, 0.25, 0.5, 0.75, 1.0}
+
+	// DefaultJudgeScoreEdges 1-5 评委分数的默认分桶下界
+	DefaultJudgeScoreEdges = []float64{1, 2, 3, 4, 5}
+)
+
+// ScoreHistogram 样本分数直方图
+//
+// Edges 为升序排列的分桶下界：第 i 个桶覆盖 [Edges[i], Edges[i+1])，
+// 最后一个桶覆盖 [Edges[len-1], +∞)，低于 Edges[0] 的分数计入第一个桶。
+type ScoreHistogram struct {
+	// Edges 分桶下界
+	Edges []float64 `json:"edges"`
+
+	// Counts 各桶样本数（与 Edges 一一对应）
+	Counts []int `json:"counts"`
+}
+
+// NewScoreHistogram 按分桶统计样本分数（SampleResult.Score）
+//
+// edges 为空时返回 nil。
+func NewScoreHistogram(results []*SampleResult, edges []float64) *ScoreHistogram {
+	if len(edges) == 0 {
+		return nil
+	}
+
+	h := &ScoreHistogram{
+		Edges:  append([]float64(nil), edges...),
+		Counts: make([]int, len(edges)),
+	}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		h.Counts[h.bucket(r.Score)]++
+	}
+	return h
+}
+
+// bucket 返回分数所在的桶索引
+func (h *ScoreHistogram) bucket(score float64) int {
+	for i := len(h.Edges) - 1; i > 0; i-- {
+		if score >= h.Edges[i] {
+			return i
+		}
+	}
+	return 0
+}
+
+// Total 返回样本总数
+func (h *ScoreHistogram) Total() int {
+	total := 0
+	for _, c := range h.Counts {
+		total += c
+	}
+	return total
+}
+
+// Label 返回第 i 个桶的区间标签（如 "[0.25, 0.5)"、"≥ 1"）
+func (h *ScoreHistogram) Label(i int) string {
+	if i == len(h.Edges)-1 {
+		return fmt.Sprintf("≥ %g", h.Edges[i])
+	}
+	return fmt.Sprintf("[%g, %g)", h.Edges[i], h.Edges[i+1])
+}
+
+// Markdown 将直方图渲染为 Markdown 表格，每个桶一行，附带 ASCII 条形图
+func (h *ScoreHistogram) Markdown() string {
+	maxCount := 0
+	for _, c := range h.Counts {
+		maxCount = max(maxCount, c)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "| 分数区间 | 样本数 | 分布 |\n")
+	fmt.Fprintf(&sb, "|----------|--------|------|\n")
+	for i, c := range h.Counts {
+		bar := ""
+		if maxCount > 0 {
+			bar = strings.Repeat("#", (c*histogramBarWidth+maxCount-1)/maxCount)
+		}
+		fmt.Fprintf(&sb, "| %s | %d | %s |\n", h.Label(i), c, bar)
+	}
+	return sb.String()
+}
@@ -0,0 +1,48 @@
+package evaluation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewScoreHistogram(t *testing.T) {
+	scores := []float64{-0.1, 0, 0.2, 0.25, 0.5, 0.6, 0.74, 0.75, 0.99, 1.0, 1.0}
+	results := make([]*SampleResult, 0, len(scores)+1)
+	for _, s := range scores {
+		results = append(results, &SampleResult{Score: s})
+	}
+	results = append(results, nil)
+
+	h := NewScoreHistogram(results, DefaultUnitScoreEdges)
+	if want := []int{3, 1, 3, 2, 2}; !reflect.DeepEqual(h.Counts, want) {
+		t.Errorf("Counts = %v, want %v", h.Counts, want)
+	}
+	if h.Total() != len(scores) {
+		t.Errorf("Total() = %d, want %d", h.Total(), len(scores))
+	}
+
+	judge := NewScoreHistogram([]*SampleResult{{Score: 1}, {Score: 2.5}, {Score: 3}, {Score: 4.5}, {Score: 5}, {Score: 5}}, DefaultJudgeScoreEdges)
+	if want := []int{1, 1, 1, 1, 2}; !reflect.DeepEqual(judge.Counts, want) {
+		t.Errorf("judge Counts = %v, want %v", judge.Counts, want)
+	}
+
+	if NewScoreHistogram(results, nil) != nil {
+		t.Error("expected nil histogram without edges")
+	}
+}
+
+func TestScoreHistogram_Markdown(t *testing.T) {
+	h := &ScoreHistogram{Edges: []float64{0, 0.5, 1}, Counts: []int{2, 0, 4}}
+	md := h.Markdown()
+
+	for _, want := range []string{
+		"| [0, 0.5) | 2 | " + strings.Repeat("#", 15) + " |",
+		"| [0.5, 1) | 0 |  |",
+		"| ≥ 1 | 4 | " + strings.Repeat("#", 30) + " |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, md)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package evaluation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// jsonStreamHeader 流式导出的头部：EvalResult 中除 DetailedResults 与 Metrics 外的字段
+//
+// 外层同名字段遮蔽内嵌的 detailed_results 与 metrics，保持为零值即被 omitempty 省略，
+// 因此 EvalResult 新增字段时无需同步修改。
+type jsonStreamHeader struct {
+	*EvalResult
+	DetailedResults []*SampleResult `json:"detailed_results,omitempty"`
+	Metrics         *MetricsSummary `json:"metrics,omitempty"`
+}
+
+// WriteJSONStream 以流式方式将评估结果写为 JSON
+//
+// 依次写出元数据头部、逐个编码的 detailed_results 数组元素和 metrics 尾部，
+// 任一时刻只持有单个样本的编码结果，适用于样本数极多的评估。输出为单个合法 JSON 对象
+// （每个样本结果占一行），解码后与 json.Marshal(result) 的解码结果一致。
+func WriteJSONStream(w io.Writer, result *EvalResult) error {
+	if result == nil {
+		return fmt.Errorf("评估结果为空")
+	}
+
+	bw := bufio.NewWriter(w)
+
+	header, err := json.Marshal(jsonStreamHeader{EvalResult: result})
+	if err != nil {
+		return fmt.Errorf("编码结果头部失败: %w", err)
+	}
+	// 去掉头部对象的右括号，后续字段接在其后（头部至少包含 benchmark_name）
+	bw.Write(header[:len(header)-1])
+
+	bw.WriteString(`,"detailed_results":`)
+	if result.DetailedResults == nil {
+		bw.WriteString("null")
+	} else {
+		bw.WriteString("[\n")
+		for i, sr := range result.DetailedResults {
+			if i > 0 {
+				bw.WriteString(",\n")
+			}
+			line, err := json.Marshal(sr)
+			if err != nil {
+				return fmt.Errorf("编码样本结果 %d 失败: %w", i, err)
+			}
+			bw.Write(line)
+		}
+		bw.WriteString("\n]")
+	}
+
+	if result.Metrics != nil {
+		metrics, err := json.Marshal(result.Metrics)
+		if err != nil {
+			return fmt.Errorf("编码汇总指标失败: %w", err)
+		}
+		bw.WriteString(`,"metrics":`)
+		bw.Write(metrics)
+	}
+	bw.WriteString("}\n")
+
+	// bufio.Writer 记录首个写入错误，在 Flush 时返回
+	return bw.Flush()
+}
+
+// ExportJSONStream 以流式方式将评估结果导出为 JSON 文件（见 WriteJSONStream）
+func ExportJSONStream(result *EvalResult, outputPath string) error {
+	// 确保目录存在
+	dir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+
+	if err := WriteJSONStream(file, result); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
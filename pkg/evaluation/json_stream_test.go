@@ -0,0 +1,109 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// decodeEvalResult 将 JSON 解码为 EvalResult
+func decodeEvalResult(t *testing.T, data []byte) *EvalResult {
+	t.Helper()
+	var result EvalResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("failed to decode result: %v\n%s", err, data)
+	}
+	return &result
+}
+
+func TestWriteJSONStream_MatchesMarshal(t *testing.T) {
+	const n = 20000
+	result := &EvalResult{
+		BenchmarkName:   "synthetic",
+		AgentName:       "agent",
+		TotalSamples:    n,
+		SuccessCount:    n / 2,
+		OverallAccuracy: 0.5,
+		CategoryMetrics: map[string]*CategoryMetrics{
+			"even": {Category: "even", Total: n / 2, Success: n / 2, Accuracy: 1},
+		},
+		LevelMetrics:   map[int]*LevelMetrics{1: {Level: 1, Total: n, ExactMatches: n / 2}},
+		TotalDuration:  3 * time.Minute,
+		EvaluationTime: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Metrics:        &MetricsSummary{Accuracy: 0.5, AverageScore: 0.5},
+		RunConfig:      map[string]interface{}{"concurrency": float64(4)},
+	}
+	for i := 0; i < n; i++ {
+		result.DetailedResults = append(result.DetailedResults, &SampleResult{
+			SampleID:      fmt.Sprintf("s%d", i),
+			Predicted:     fmt.Sprintf("answer \"%d\"\n", i),
+			Expected:      "answer",
+			Success:       i%2 == 0,
+			Score:         float64(i%2) / 2,
+			Category:      "even",
+			Level:         1,
+			ExecutionTime: time.Duration(i) * time.Millisecond,
+			Details:       map[string]interface{}{"index": float64(i)},
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSONStream(&buf, result); err != nil {
+		t.Fatalf("WriteJSONStream() error = %v", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Fatal("streamed output is not valid JSON")
+	}
+
+	want, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	got := decodeEvalResult(t, buf.Bytes())
+	if !reflect.DeepEqual(got, decodeEvalResult(t, want)) {
+		t.Error("streamed result differs from in-memory encoding")
+	}
+	if len(got.DetailedResults) != n || got.DetailedResults[n-1].SampleID != fmt.Sprintf("s%d", n-1) {
+		t.Errorf("decoded %d detailed results", len(got.DetailedResults))
+	}
+}
+
+func TestWriteJSONStream_EmptyResults(t *testing.T) {
+	for _, result := range []*EvalResult{
+		{BenchmarkName: "empty"},
+		{BenchmarkName: "empty", DetailedResults: []*SampleResult{}},
+	} {
+		var buf bytes.Buffer
+		if err := WriteJSONStream(&buf, result); err != nil {
+			t.Fatalf("WriteJSONStream() error = %v", err)
+		}
+		want, _ := json.Marshal(result)
+		if !reflect.DeepEqual(decodeEvalResult(t, buf.Bytes()), decodeEvalResult(t, want)) {
+			t.Errorf("streamed %s, want equivalent of %s", buf.Bytes(), want)
+		}
+	}
+}
+
+func TestExportJSONStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "result.json")
+	result := &EvalResult{BenchmarkName: "file", DetailedResults: []*SampleResult{{SampleID: "s0"}}}
+	if err := ExportJSONStream(result, path); err != nil {
+		t.Fatalf("ExportJSONStream() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if got := decodeEvalResult(t, data); got.DetailedResults[0].SampleID != "s0" {
+		t.Errorf("unexpected result: %+v", got)
+	}
+
+	if err := WriteJSONStream(&bytes.Buffer{}, nil); err == nil {
+		t.Error("expected error for nil result")
+	}
+}
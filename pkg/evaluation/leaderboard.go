@@ -0,0 +1,103 @@
+package evaluation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// LeaderboardEntry 排行榜中的一行
+type LeaderboardEntry struct {
+	// Benchmark 基准名称
+	Benchmark string `json:"benchmark"`
+
+	// Accuracy 总体准确率
+	Accuracy float64 `json:"accuracy"`
+
+	// Samples 样本数
+	Samples int `json:"samples"`
+
+	// Duration 评估耗时
+	Duration time.Duration `json:"duration"`
+}
+
+// LeaderboardEntries 从多基准结果生成排行榜行
+//
+// 按准确率降序排列，准确率相同时按基准名称排序；值为 nil 的结果被忽略。
+func LeaderboardEntries(results map[string]*EvalResult) []LeaderboardEntry {
+	entries := make([]LeaderboardEntry, 0, len(results))
+	for name, result := range results {
+		if result == nil {
+			continue
+		}
+		entries = append(entries, LeaderboardEntry{
+			Benchmark: name,
+			Accuracy:  result.OverallAccuracy,
+			Samples:   result.TotalSamples,
+			Duration:  result.TotalDuration,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Accuracy != entries[j].Accuracy {
+			return entries[i].Accuracy > entries[j].Accuracy
+		}
+		return entries[i].Benchmark < entries[j].Benchmark
+	})
+	return entries
+}
+
+// MacroAverage 计算排行榜的宏平均行
+//
+// 准确率为各基准准确率的算术平均（不按样本数加权），样本数与耗时为各基准之和。
+func MacroAverage(entries []LeaderboardEntry) LeaderboardEntry {
+	avg := LeaderboardEntry{Benchmark: "宏平均"}
+	if len(entries) == 0 {
+		return avg
+	}
+
+	for _, e := range entries {
+		avg.Accuracy += e.Accuracy
+		avg.Samples += e.Samples
+		avg.Duration += e.Duration
+	}
+	avg.Accuracy /= float64(len(entries))
+	return avg
+}
+
+// ExportLeaderboard 导出多基准排行榜
+//
+// 输出 Markdown 表格，每个基准一行（准确率、样本数、耗时），按准确率降序排列，
+// 末尾附加宏平均行。
+func ExportLeaderboard(results map[string]*EvalResult, outputPath string) error {
+	entries := LeaderboardEntries(results)
+	if len(entries) == 0 {
+		return fmt.Errorf("评估结果为空")
+	}
+
+	// 确保目录存在
+	dir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "# 基准排行榜\n\n")
+	fmt.Fprintf(file, "| 基准 | 准确率 | 样本数 | 耗时 |\n")
+	fmt.Fprintf(file, "|------|--------|--------|------|\n")
+	for _, e := range entries {
+		fmt.Fprintf(file, "| %s | %.2f%% | %d | %s |\n", e.Benchmark, e.Accuracy*100, e.Samples, e.Duration.Round(time.Millisecond))
+	}
+
+	avg := MacroAverage(entries)
+	fmt.Fprintf(file, "| **%s** | **%.2f%%** | %d | %s |\n", avg.Benchmark, avg.Accuracy*100, avg.Samples, avg.Duration.Round(time.Millisecond))
+
+	return nil
+}
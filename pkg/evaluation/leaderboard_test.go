@@ -0,0 +1,62 @@
+package evaluation
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportLeaderboard(t *testing.T) {
+	results := map[string]*EvalResult{
+		"GAIA_validation":   {OverallAccuracy: 0.3, TotalSamples: 50, TotalDuration: 2 * time.Minute},
+		"BFCL_simple":       {OverallAccuracy: 0.9, TotalSamples: 100, TotalDuration: 30 * time.Second},
+		"MMLU_high_school":  {OverallAccuracy: 0.6, TotalSamples: 200, TotalDuration: time.Minute},
+		"skipped_benchmark": nil,
+	}
+
+	path := filepath.Join(t.TempDir(), "leaderboard.md")
+	if err := ExportLeaderboard(results, path); err != nil {
+		t.Fatalf("ExportLeaderboard() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+
+	var rows []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "| ") && !strings.HasPrefix(line, "| 基准") {
+			rows = append(rows, line)
+		}
+	}
+	want := []string{
+		"| BFCL_simple | 90.00% | 100 | 30s |",
+		"| MMLU_high_school | 60.00% | 200 | 1m0s |",
+		"| GAIA_validation | 30.00% | 50 | 2m0s |",
+		"| **宏平均** | **60.00%** | 350 | 3m30s |",
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d:\n%s", len(want), len(rows), data)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+
+	avg := MacroAverage(LeaderboardEntries(results))
+	if math.Abs(avg.Accuracy-0.6) > 1e-9 {
+		t.Errorf("MacroAverage().Accuracy = %v, want 0.6", avg.Accuracy)
+	}
+}
+
+func TestExportLeaderboard_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leaderboard.md")
+	if err := ExportLeaderboard(map[string]*EvalResult{}, path); err == nil {
+		t.Error("expected error for empty results")
+	}
+}
@@ -0,0 +1,256 @@
+// Package loader 提供评估数据文件的通用加载功能
+//
+// 自动识别 JSON 数组、JSONL 和带表头的 CSV 三种格式，统一返回通用记录
+// （map[string]interface{}），由各基准测试的解析器映射为样本。
+package loader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format 数据文件格式
+type Format string
+
+const (
+	// FormatJSON JSON 数组，每个元素为一条记录
+	FormatJSON Format = "json"
+	// FormatJSONL JSONL，每行一条 JSON 对象记录
+	FormatJSONL Format = "jsonl"
+	// FormatCSV 带表头的 CSV，每行按表头映射为一条记录（值均为字符串）
+	FormatCSV Format = "csv"
+)
+
+// utf8BOM UTF-8 字节顺序标记
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// LoadRecords 读取数据文件并解析为通用记录
+//
+// 格式由 DetectFormat 根据扩展名与内容判断，解析规则见 ParseRecords。
+func LoadRecords(path string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	records, err := ParseRecords(data, DetectFormat(path, data))
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return records, nil
+}
+
+// DetectFormat 根据扩展名与内容判断数据格式
+//
+// .csv 文件按 CSV 解析；其余文件看内容：首个非空白字符为 '[' 时按 JSON 数组解析，
+// 为 '{' 时按 JSONL 解析，否则扩展名为 .json/.jsonl/.ndjson 时按 JSONL 解析，
+// 其他情况按 CSV 解析。
+func DetectFormat(path string, data []byte) Format {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".csv" {
+		return FormatCSV
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return FormatJSON
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return FormatJSONL
+	case ext == ".json" || ext == ".jsonl" || ext == ".ndjson":
+		return FormatJSONL
+	default:
+		return FormatCSV
+	}
+}
+
+// ParseRecords 按指定格式解析数据
+//
+// 开头的 UTF-8 BOM 会被去除，\r\n 与 \r 换行统一为 \n。
+//   - FormatJSON: 解析为对象数组，非对象元素被跳过
+//   - FormatJSONL: 逐行解析，空行与无法解析为对象的行被跳过；若没有任何行可解析，
+//     则尝试将整个内容作为单个（跨行的）JSON 对象解析
+//   - FormatCSV: 首行为表头，空表头列被忽略，缺少的字段不出现在记录中
+//
+// 被跳过的行可通过 ParseRecordsStrict 或 Validate 定位。
+func ParseRecords(data []byte, format Format) ([]map[string]interface{}, error) {
+	rows, _, err := parseRows(data, format)
+	if err != nil {
+		return nil, err
+	}
+	return recordsOf(rows), nil
+}
+
+// ParseRecordsStrict 按指定格式严格解析数据
+//
+// 与 ParseRecords 相同，但遇到第一条无法解析的行时返回 *LineError（可用
+// errors.Is(err, ErrInvalidRecord) 判断），而不是跳过。
+func ParseRecordsStrict(data []byte, format Format) ([]map[string]interface{}, error) {
+	rows, issues, err := parseRows(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		return nil, &issues[0]
+	}
+	return recordsOf(rows), nil
+}
+
+// LoadRecordsStrict 读取数据文件并严格解析，遇到第一条无法解析的行即返回错误
+func LoadRecordsStrict(path string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	records, err := ParseRecordsStrict(data, DetectFormat(path, data))
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return records, nil
+}
+
+// row 带行号的记录
+type row struct {
+	// line 行号（JSONL/CSV 为文件行号，JSON 数组为元素序号），从 1 开始
+	line int
+	// record 记录内容
+	record map[string]interface{}
+}
+
+// recordsOf 提取记录内容
+func recordsOf(rows []row) []map[string]interface{} {
+	records := make([]map[string]interface{}, len(rows))
+	for i, r := range rows {
+		records[i] = r.record
+	}
+	return records
+}
+
+// parseRows 解析数据，返回带行号的记录与无法解析的行
+func parseRows(data []byte, format Format) ([]row, []LineError, error) {
+	data = normalizeNewlines(bytes.TrimPrefix(data, utf8BOM))
+
+	switch format {
+	case FormatJSON:
+		return parseJSON(data)
+	case FormatJSONL:
+		rows, issues := parseJSONL(data)
+		return rows, issues, nil
+	case FormatCSV:
+		rows, err := parseCSV(data)
+		return rows, nil, err
+	default:
+		return nil, nil, fmt.Errorf("不支持的数据格式: %s", format)
+	}
+}
+
+// normalizeNewlines 将 \r\n 与 \r 统一为 \n
+func normalizeNewlines(data []byte) []byte {
+	if !bytes.Contains(data, []byte("\r")) {
+		return data
+	}
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+}
+
+// parseJSON 解析 JSON 数组
+func parseJSON(data []byte) ([]row, []LineError, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]row, 0, len(items))
+	var issues []LineError
+	for i, raw := range items {
+		record, err := decodeObject(raw)
+		if err != nil {
+			issues = append(issues, LineError{Line: i + 1, Reason: err.Error()})
+			continue
+		}
+		rows = append(rows, row{line: i + 1, record: record})
+	}
+	return rows, issues, nil
+}
+
+// parseJSONL 逐行解析 JSONL
+func parseJSONL(data []byte) ([]row, []LineError) {
+	rows := make([]row, 0)
+	var issues []LineError
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		record, err := decodeObject(line)
+		if err != nil {
+			issues = append(issues, LineError{Line: i + 1, Reason: err.Error()})
+			continue
+		}
+		rows = append(rows, row{line: i + 1, record: record})
+	}
+
+	// 整个内容为单个跨行对象时，逐行解析的失败不算错误
+	if len(rows) == 0 {
+		if record, err := decodeObject(data); err == nil {
+			return []row{{line: 1, record: record}}, nil
+		}
+	}
+	return rows, issues
+}
+
+// decodeObject 将 JSON 解析为对象，非对象值返回错误
+func decodeObject(data []byte) (map[string]interface{}, error) {
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errors.New("不是 JSON 对象")
+	}
+	return record, nil
+}
+
+// parseCSV 解析带表头的 CSV，出错时同时返回此前已解析的记录
+func parseCSV(data []byte) ([]row, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	rows := make([]row, 0)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return rows, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			// 返回出错前已解析的记录，供 Validate 统计
+			return rows, err
+		}
+		line, _ := reader.FieldPos(0)
+		record := make(map[string]interface{}, len(header))
+		for i, value := range fields {
+			if i >= len(header) || header[i] == "" {
+				continue
+			}
+			record[header[i]] = value
+		}
+		rows = append(rows, row{line: line, record: record})
+	}
+}
@@ -0,0 +1,87 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRecords_FormatsAgree(t *testing.T) {
+	want := []map[string]interface{}{
+		{"id": "1", "question": "q1", "answer": "a1"},
+		{"id": "2", "question": "q2, with comma", "answer": "a2"},
+	}
+
+	inputs := map[string]string{
+		"data.json": `[
+  {"id": "1", "question": "q1", "answer": "a1"},
+  42,
+  {"id": "2", "question": "q2, with comma", "answer": "a2"}
+]`,
+		"data.jsonl": "\xEF\xBB\xBF{\"id\": \"1\", \"question\": \"q1\", \"answer\": \"a1\"}\r\n" +
+			"\r\n" +
+			"not json\r\n" +
+			"{\"id\": \"2\", \"question\": \"q2, with comma\", \"answer\": \"a2\"}\r\n",
+		"data.csv": "\xEF\xBB\xBFid,question,answer,\r\n" +
+			"1,q1,a1,ignored\n" +
+			"2,\"q2, with comma\",a2,\r",
+	}
+
+	dir := t.TempDir()
+	for name, content := range inputs {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		got, err := LoadRecords(path)
+		if err != nil {
+			t.Fatalf("LoadRecords(%s) error = %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadRecords(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParseRecords_MultiLineJSONObject(t *testing.T) {
+	data := []byte("{\n  \"id\": \"1\",\n  \"question\": \"q1\"\n}\n")
+	got, err := ParseRecords(data, FormatJSONL)
+	if err != nil {
+		t.Fatalf("ParseRecords() error = %v", err)
+	}
+	want := []map[string]interface{}{{"id": "1", "question": "q1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRecords() = %v, want %v", got, want)
+	}
+}
+
+func TestParseRecords_InvalidJSONArray(t *testing.T) {
+	if _, err := ParseRecords([]byte(`[{"id": 1}`), FormatJSON); err == nil {
+		t.Error("expected error for truncated JSON array")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want Format
+	}{
+		{"a.csv", `[{"id": 1}]`, FormatCSV},
+		{"a.json", `[{"id": 1}]`, FormatJSON},
+		{"a.jsonl", "\xEF\xBB\xBF  [{\"id\": 1}]", FormatJSON},
+		{"a.json", `{"id": 1}`, FormatJSONL},
+		{"a.txt", `{"id": 1}`, FormatJSONL},
+		{"a.ndjson", ``, FormatJSONL},
+		{"a.JSONL", `id,question`, FormatJSONL},
+		{"a.txt", `id,question`, FormatCSV},
+		{"data", `id,question`, FormatCSV},
+	}
+
+	for _, tt := range tests {
+		if got := DetectFormat(tt.path, []byte(tt.data)); got != tt.want {
+			t.Errorf("DetectFormat(%q, %q) = %s, want %s", tt.path, tt.data, got, tt.want)
+		}
+	}
+}
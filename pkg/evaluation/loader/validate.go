@@ -0,0 +1,136 @@
+package loader
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrInvalidRecord 数据行无法解析为记录
+var ErrInvalidRecord = errors.New("无效的数据行")
+
+// LineError 数据文件中无法解析的行
+type LineError struct {
+	// Line 行号（JSONL/CSV 为文件行号，JSON 数组为元素序号），从 1 开始
+	Line int `json:"line"`
+
+	// Reason 失败原因
+	Reason string `json:"reason"`
+}
+
+// Error 实现 error 接口
+func (e *LineError) Error() string {
+	return fmt.Sprintf("第 %d 行: %s", e.Line, e.Reason)
+}
+
+// Unwrap 返回 ErrInvalidRecord
+func (e *LineError) Unwrap() error {
+	return ErrInvalidRecord
+}
+
+// Field 必填字段
+type Field struct {
+	// Name 字段名（报告中使用）
+	Name string
+
+	// Keys 可接受的键，任一键存在且值非空即视为满足（为空时使用 Name）
+	Keys []string
+}
+
+// present 判断记录是否包含该字段
+func (f Field) present(record map[string]interface{}) bool {
+	keys := f.Keys
+	if len(keys) == 0 {
+		keys = []string{f.Name}
+	}
+	for _, key := range keys {
+		switch v := record[key].(type) {
+		case nil:
+		case string:
+			if strings.TrimSpace(v) != "" {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationReport 数据文件校验报告
+type ValidationReport struct {
+	// Path 数据文件路径
+	Path string `json:"path"`
+
+	// Format 识别出的数据格式
+	Format Format `json:"format"`
+
+	// ValidRecords 可解析的记录数
+	ValidRecords int `json:"valid_records"`
+
+	// Errors 无法解析的行（按行号升序）
+	Errors []LineError `json:"errors,omitempty"`
+
+	// MissingFields 各必填字段缺失的记录数（仅统计可解析的记录）
+	MissingFields map[string]int `json:"missing_fields,omitempty"`
+
+	// MissingLines 各必填字段缺失的记录行号
+	MissingLines map[string][]int `json:"missing_lines,omitempty"`
+}
+
+// OK 判断数据文件是否没有任何问题
+func (r *ValidationReport) OK() bool {
+	return len(r.Errors) == 0 && len(r.MissingFields) == 0
+}
+
+// Validate 校验数据文件
+//
+// 逐行解析数据文件，记录无法解析的行号与原因，并统计各必填字段缺失的记录，
+// 而不是像 LoadRecords 那样静默跳过。CSV 语法错误会记录在 Errors 中并结束解析。
+// 仅在文件无法读取或格式整体无法解析（如 JSON 数组不完整）时返回 error。
+func Validate(ctx context.Context, path string, required ...Field) (*ValidationReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := DetectFormat(path, data)
+	report := &ValidationReport{
+		Path:          path,
+		Format:        format,
+		MissingFields: make(map[string]int),
+		MissingLines:  make(map[string][]int),
+	}
+
+	rows, issues, err := parseRows(data, format)
+	var parseErr *csv.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		issues = append(issues, LineError{Line: parseErr.Line, Reason: parseErr.Err.Error()})
+	case err != nil:
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+
+	report.ValidRecords = len(rows)
+	report.Errors = issues
+	for _, r := range rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, field := range required {
+			if !field.present(r.record) {
+				report.MissingFields[field.Name]++
+				report.MissingLines[field.Name] = append(report.MissingLines[field.Name], r.line)
+			}
+		}
+	}
+
+	if len(report.MissingFields) == 0 {
+		report.MissingFields = nil
+		report.MissingLines = nil
+	}
+	return report, nil
+}
@@ -0,0 +1,102 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFile 在临时目录中写入测试数据文件
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestValidate_JSONL(t *testing.T) {
+	path := writeFile(t, "data.jsonl", `{"question": "q1", "answer": "a1"}
+{"question": "q2"
+{"question": "q3", "answer": ""}
+
+42
+{"Question": "q6", "answer": "a6"}
+`)
+
+	report, err := Validate(context.Background(), path,
+		Field{Name: "question", Keys: []string{"question", "Question"}},
+		Field{Name: "answer"},
+	)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if report.Format != FormatJSONL || report.ValidRecords != 3 {
+		t.Errorf("Format = %s, ValidRecords = %d, want jsonl and 3", report.Format, report.ValidRecords)
+	}
+	var lines []int
+	for _, e := range report.Errors {
+		lines = append(lines, e.Line)
+		if e.Reason == "" {
+			t.Errorf("line %d has empty reason", e.Line)
+		}
+	}
+	if !reflect.DeepEqual(lines, []int{2, 5}) {
+		t.Errorf("error lines = %v, want [2 5]", lines)
+	}
+	if !reflect.DeepEqual(report.MissingFields, map[string]int{"answer": 1}) {
+		t.Errorf("MissingFields = %v, want answer: 1", report.MissingFields)
+	}
+	if !reflect.DeepEqual(report.MissingLines, map[string][]int{"answer": {3}}) {
+		t.Errorf("MissingLines = %v, want answer: [3]", report.MissingLines)
+	}
+	if report.OK() {
+		t.Error("OK() = true, want false")
+	}
+}
+
+func TestValidate_CSVParseError(t *testing.T) {
+	path := writeFile(t, "data.csv", "question,answer\nq1,a1\n\"q2,a2\n")
+
+	report, err := Validate(context.Background(), path, Field{Name: "question"})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if report.ValidRecords != 1 || len(report.Errors) != 1 || report.Errors[0].Line != 3 {
+		t.Errorf("report = %+v, want 1 record and an error on line 3", report)
+	}
+}
+
+func TestValidate_Clean(t *testing.T) {
+	path := writeFile(t, "data.json", `[{"question": "q1"}, {"question": "q2"}]`)
+
+	report, err := Validate(context.Background(), path, Field{Name: "question"})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !report.OK() || report.ValidRecords != 2 {
+		t.Errorf("report = %+v, want clean with 2 records", report)
+	}
+}
+
+func TestLoadRecordsStrict(t *testing.T) {
+	path := writeFile(t, "data.jsonl", "{\"q\": 1}\nbad\n{\"q\": 3}\nworse\n")
+
+	if records, err := LoadRecords(path); err != nil || len(records) != 2 {
+		t.Fatalf("LoadRecords() = %d records, %v; want 2, nil", len(records), err)
+	}
+
+	_, err := LoadRecordsStrict(path)
+	var lineErr *LineError
+	if !errors.As(err, &lineErr) || lineErr.Line != 2 {
+		t.Fatalf("LoadRecordsStrict() error = %v, want LineError on line 2", err)
+	}
+	if !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("error %v does not wrap ErrInvalidRecord", err)
+	}
+}
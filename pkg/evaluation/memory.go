@@ -0,0 +1,58 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+)
+
+// MemoryDataset 基于内存样本的数据集
+//
+// 适用于在代码中构造样本或从数据库等外部来源读取样本的场景，无需落盘为文件。
+type MemoryDataset struct {
+	name    string
+	samples []Sample
+}
+
+// NewMemoryDataset 创建内存数据集
+//
+// samples 会被复制，之后修改传入的切片不影响数据集。
+func NewMemoryDataset(name string, samples []Sample) *MemoryDataset {
+	copied := make([]Sample, len(samples))
+	copy(copied, samples)
+	return &MemoryDataset{name: name, samples: copied}
+}
+
+// Load 样本已在内存中，无需加载
+func (d *MemoryDataset) Load(ctx context.Context) error {
+	return nil
+}
+
+// Len 返回数据集大小
+func (d *MemoryDataset) Len() int {
+	return len(d.samples)
+}
+
+// Get 根据索引获取样本
+func (d *MemoryDataset) Get(index int) (Sample, error) {
+	if index < 0 || index >= len(d.samples) {
+		return Sample{}, fmt.Errorf("索引越界: %d", index)
+	}
+	return d.samples[index], nil
+}
+
+// Iterator 返回样本迭代器
+func (d *MemoryDataset) Iterator() <-chan Sample {
+	ch := make(chan Sample)
+	go func() {
+		defer close(ch)
+		for _, sample := range d.samples {
+			ch <- sample
+		}
+	}()
+	return ch
+}
+
+// Name 返回数据集名称
+func (d *MemoryDataset) Name() string {
+	return d.name
+}
@@ -0,0 +1,41 @@
+package evaluation
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryDataset(t *testing.T) {
+	samples := []Sample{
+		{ID: "a", Input: "question a"},
+		{ID: "b", Input: "question b"},
+	}
+	dataset := NewMemoryDataset("custom", samples)
+	samples[0].ID = "changed"
+
+	if err := dataset.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if dataset.Name() != "custom" || dataset.Len() != 2 {
+		t.Errorf("Name() = %s, Len() = %d, want custom and 2", dataset.Name(), dataset.Len())
+	}
+
+	sample, err := dataset.Get(0)
+	if err != nil {
+		t.Fatalf("Get(0) error = %v", err)
+	}
+	if sample.ID != "a" {
+		t.Errorf("Get(0).ID = %s, want a (input slice should be copied)", sample.ID)
+	}
+	if _, err := dataset.Get(2); err == nil {
+		t.Error("expected error for out-of-range index")
+	}
+
+	var ids []string
+	for s := range dataset.Iterator() {
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("Iterator() = %v, want [a b]", ids)
+	}
+}
@@ -0,0 +1,294 @@
+package evaluation
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeResults 合并分片运行的评估结果
+//
+// 各分片须来自同一基准（BenchmarkName 相同），且样本 ID 不能重复。合并规则:
+//   - DetailedResults 按分片顺序拼接，TotalSamples 与 SuccessCount 求和并重新计算 OverallAccuracy
+//   - 分类别/分级别指标按计数求和后重新计算比率，平均分按样本数加权
+//   - TotalDuration 取各分片最大值（分片并行运行），EvaluationTime 取最早时间
+//   - Metrics 见 mergeMetricsSummary
+//   - AgentName 与 RunConfig 取首个分片，RunConfig 额外记录 shards
+func MergeResults(results ...*EvalResult) (*EvalResult, error) {
+	if len(results) == 0 {
+		return nil, fmt.Errorf("没有可合并的评估结果")
+	}
+	for i, r := range results {
+		if r == nil {
+			return nil, fmt.Errorf("分片 %d 的评估结果为空", i)
+		}
+		if r.BenchmarkName != results[0].BenchmarkName {
+			return nil, fmt.Errorf("分片 %d 的基准 %q 与分片 0 的 %q 不一致", i, r.BenchmarkName, results[0].BenchmarkName)
+		}
+	}
+
+	first := results[0]
+	merged := &EvalResult{
+		BenchmarkName:  first.BenchmarkName,
+		AgentName:      first.AgentName,
+		EvaluationTime: first.EvaluationTime,
+		RunConfig:      make(map[string]interface{}, len(first.RunConfig)+1),
+	}
+	for k, v := range first.RunConfig {
+		merged.RunConfig[k] = v
+	}
+	merged.RunConfig["shards"] = len(results)
+
+	owner := make(map[string]int)
+	for i, r := range results {
+		for _, sr := range r.DetailedResults {
+			if sr == nil {
+				continue
+			}
+			if prev, dup := owner[sr.SampleID]; dup {
+				return nil, fmt.Errorf("样本 %s 同时出现在分片 %d 与分片 %d 中", sr.SampleID, prev, i)
+			}
+			owner[sr.SampleID] = i
+			merged.DetailedResults = append(merged.DetailedResults, sr)
+		}
+
+		merged.TotalSamples += r.TotalSamples
+		merged.SuccessCount += r.SuccessCount
+		if r.TotalDuration > merged.TotalDuration {
+			merged.TotalDuration = r.TotalDuration
+		}
+		if !r.EvaluationTime.IsZero() && (merged.EvaluationTime.IsZero() || r.EvaluationTime.Before(merged.EvaluationTime)) {
+			merged.EvaluationTime = r.EvaluationTime
+		}
+	}
+	if merged.TotalSamples > 0 {
+		merged.OverallAccuracy = float64(merged.SuccessCount) / float64(merged.TotalSamples)
+	}
+
+	merged.CategoryMetrics = mergeCategoryMetrics(results)
+	merged.LevelMetrics = mergeLevelMetrics(results)
+	merged.Metrics = mergeMetricsSummary(results, merged)
+
+	return merged, nil
+}
+
+// mergeCategoryMetrics 合并分类别指标，各分片均无分类别指标时返回 nil
+func mergeCategoryMetrics(results []*EvalResult) map[string]*CategoryMetrics {
+	var merged map[string]*CategoryMetrics
+	for _, r := range results {
+		for name, cm := range r.CategoryMetrics {
+			if cm == nil {
+				continue
+			}
+			if merged == nil {
+				merged = make(map[string]*CategoryMetrics)
+			}
+			m, ok := merged[name]
+			if !ok {
+				m = &CategoryMetrics{Category: cm.Category}
+				merged[name] = m
+			}
+			m.Total += cm.Total
+			m.Success += cm.Success
+			// 暂存加权总分，最后除以样本数
+			m.AverageScore += cm.AverageScore * float64(cm.Total)
+		}
+	}
+
+	for _, m := range merged {
+		if m.Total > 0 {
+			m.Accuracy = float64(m.Success) / float64(m.Total)
+			m.AverageScore /= float64(m.Total)
+		}
+	}
+	return merged
+}
+
+// mergeLevelMetrics 合并分级别指标，各分片均无分级别指标时返回 nil
+func mergeLevelMetrics(results []*EvalResult) map[int]*LevelMetrics {
+	var merged map[int]*LevelMetrics
+	for _, r := range results {
+		for level, lm := range r.LevelMetrics {
+			if lm == nil {
+				continue
+			}
+			if merged == nil {
+				merged = make(map[int]*LevelMetrics)
+			}
+			m, ok := merged[level]
+			if !ok {
+				m = &LevelMetrics{Level: lm.Level}
+				merged[level] = m
+			}
+			m.Total += lm.Total
+			m.ExactMatches += lm.ExactMatches
+			m.PartialMatches += lm.PartialMatches
+		}
+	}
+
+	for _, m := range merged {
+		if m.Total > 0 {
+			m.ExactMatchRate = float64(m.ExactMatches) / float64(m.Total)
+			m.PartialMatchRate = float64(m.PartialMatches) / float64(m.Total)
+		}
+	}
+	return merged
+}
+
+// mergeMetricsSummary 合并汇总指标，各分片均无汇总指标时返回 nil
+//
+// 汇总指标由各基准自行计算，无法通用地重新计算，因此:
+//   - Accuracy 取合并后的 OverallAccuracy
+//   - 各比率字段与 DimensionScores 按分片 TotalSamples 加权平均，F1Score 由合并后的
+//     Precision 与 Recall 重新计算
+//   - TokenUsage、ScoreHistogram（沿用首个直方图的分桶）与 Extra 中的错误分类统计
+//     按合并后的样本重新计算
+//   - Extra 中的整数（计数）求和，浮点数（比率）按样本数加权平均，其他值仅在各分片
+//     一致时保留；从 JSON 加载的结果中数值均为浮点数，计数也会按比率合并
+func mergeMetricsSummary(results []*EvalResult, merged *EvalResult) *MetricsSummary {
+	var (
+		summary *MetricsSummary
+		weight  float64
+		edges   []float64
+		extras  []map[string]interface{}
+		weights []float64
+	)
+	for _, r := range results {
+		m := r.Metrics
+		if m == nil {
+			continue
+		}
+		if summary == nil {
+			summary = &MetricsSummary{}
+		}
+
+		w := float64(r.TotalSamples)
+		weight += w
+		summary.Precision += m.Precision * w
+		summary.Recall += m.Recall * w
+		summary.AverageScore += m.AverageScore * w
+		summary.PassRate += m.PassRate * w
+		summary.ExcellentRate += m.ExcellentRate * w
+		summary.WinRate += m.WinRate * w
+		summary.LossRate += m.LossRate * w
+		summary.TieRate += m.TieRate * w
+		for dim, score := range m.DimensionScores {
+			if summary.DimensionScores == nil {
+				summary.DimensionScores = make(map[string]float64)
+			}
+			summary.DimensionScores[dim] += score * w
+		}
+		if edges == nil && m.ScoreHistogram != nil {
+			edges = m.ScoreHistogram.Edges
+		}
+		extras = append(extras, m.Extra)
+		weights = append(weights, w)
+	}
+	if summary == nil {
+		return nil
+	}
+
+	if weight > 0 {
+		summary.Precision /= weight
+		summary.Recall /= weight
+		summary.AverageScore /= weight
+		summary.PassRate /= weight
+		summary.ExcellentRate /= weight
+		summary.WinRate /= weight
+		summary.LossRate /= weight
+		summary.TieRate /= weight
+		for dim := range summary.DimensionScores {
+			summary.DimensionScores[dim] /= weight
+		}
+	}
+	if summary.Precision+summary.Recall > 0 {
+		summary.F1Score = 2 * summary.Precision * summary.Recall / (summary.Precision + summary.Recall)
+	}
+
+	summary.Accuracy = merged.OverallAccuracy
+	summary.TokenUsage = SumTokenUsage(merged.DetailedResults)
+	if edges != nil {
+		summary.ScoreHistogram = NewScoreHistogram(merged.DetailedResults, edges)
+	}
+	summary.Extra = mergeExtra(extras, weights)
+	if hasErrorBreakdown(extras) {
+		summary.Extra[ErrorBreakdownKey] = ComputeErrorBreakdown(merged.DetailedResults)
+	}
+
+	return summary
+}
+
+// mergeExtra 合并各分片汇总指标的 Extra
+func mergeExtra(extras []map[string]interface{}, weights []float64) map[string]interface{} {
+	keys := make(map[string]bool)
+	for _, extra := range extras {
+		for k := range extra {
+			keys[k] = true
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]interface{}, len(keys))
+	for key := range keys {
+		if v, ok := mergeExtraValue(key, extras, weights); ok {
+			merged[key] = v
+		}
+	}
+	return merged
+}
+
+// mergeExtraValue 合并单个 Extra 键，无法合并时返回 false
+func mergeExtraValue(key string, extras []map[string]interface{}, weights []float64) (interface{}, bool) {
+	var (
+		intSum              int
+		floatSum, weightSum float64
+		first               interface{}
+		seen, missingAny    bool
+	)
+	allInts, allFloats, allEqual := true, true, true
+	for i, extra := range extras {
+		v, ok := extra[key]
+		if !ok {
+			missingAny = true
+			continue
+		}
+		switch n := v.(type) {
+		case int:
+			intSum += n
+			allFloats = false
+		case float64:
+			floatSum += n * weights[i]
+			weightSum += weights[i]
+			allInts = false
+		default:
+			allInts, allFloats = false, false
+		}
+		if !seen {
+			first, seen = v, true
+		} else if !reflect.DeepEqual(first, v) {
+			allEqual = false
+		}
+	}
+
+	switch {
+	case allInts:
+		return intSum, true
+	case allFloats && weightSum > 0:
+		return floatSum / weightSum, true
+	case allEqual && !missingAny:
+		return first, true
+	default:
+		return nil, false
+	}
+}
+
+// hasErrorBreakdown 判断是否有分片记录了错误分类统计
+func hasErrorBreakdown(extras []map[string]interface{}) bool {
+	for _, extra := range extras {
+		if _, ok := extra[ErrorBreakdownKey]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shardResult 构造一个分片结果，successes 中为 true 的样本成功
+func shardResult(prefix string, category string, level int, successes []bool) *EvalResult {
+	result := &EvalResult{
+		BenchmarkName:   "bench",
+		AgentName:       "agent",
+		TotalSamples:    len(successes),
+		CategoryMetrics: map[string]*CategoryMetrics{},
+		LevelMetrics:    map[int]*LevelMetrics{},
+	}
+	cm := &CategoryMetrics{Category: category}
+	lm := &LevelMetrics{Level: level}
+	var scoreSum float64
+	for i, ok := range successes {
+		score := 0.0
+		if ok {
+			score = 1
+			result.SuccessCount++
+			cm.Success++
+			lm.ExactMatches++
+		}
+		scoreSum += score
+		result.DetailedResults = append(result.DetailedResults, &SampleResult{
+			SampleID: fmt.Sprintf("%s%d", prefix, i),
+			Success:  ok,
+			Score:    score,
+			Category: category,
+			Level:    level,
+		})
+	}
+	cm.Total, lm.Total = len(successes), len(successes)
+	cm.Accuracy = float64(cm.Success) / float64(cm.Total)
+	cm.AverageScore = scoreSum / float64(cm.Total)
+	lm.ExactMatchRate = float64(lm.ExactMatches) / float64(lm.Total)
+	result.CategoryMetrics[category] = cm
+	result.LevelMetrics[level] = lm
+	result.OverallAccuracy = float64(result.SuccessCount) / float64(result.TotalSamples)
+	result.Metrics = &MetricsSummary{
+		Accuracy:     result.OverallAccuracy,
+		AverageScore: cm.AverageScore,
+		Extra:        map[string]interface{}{"error_count": 1, "unanswered_rate": 0.5, "mode": "ast"},
+	}
+	return result
+}
+
+func TestMergeResults(t *testing.T) {
+	a := shardResult("a", "math", 1, []bool{true, true, false, true})
+	b := shardResult("b", "math", 2, []bool{false, true})
+	b.CategoryMetrics["history"] = &CategoryMetrics{Category: "history", Total: 2, Success: 2, Accuracy: 1}
+	b.TotalDuration, a.TotalDuration = 3*time.Second, 2*time.Second
+
+	merged, err := MergeResults(a, b)
+	if err != nil {
+		t.Fatalf("MergeResults() error = %v", err)
+	}
+
+	// 对合并后的样本重新计算准确率
+	success := 0
+	for _, r := range merged.DetailedResults {
+		if r.Success {
+			success++
+		}
+	}
+	want := float64(success) / float64(len(merged.DetailedResults))
+	if len(merged.DetailedResults) != 6 || merged.TotalSamples != 6 || merged.SuccessCount != success {
+		t.Fatalf("merged %d results, TotalSamples %d, SuccessCount %d", len(merged.DetailedResults), merged.TotalSamples, merged.SuccessCount)
+	}
+	if merged.OverallAccuracy != want || merged.Metrics.Accuracy != want {
+		t.Errorf("OverallAccuracy = %v, Metrics.Accuracy = %v, want %v", merged.OverallAccuracy, merged.Metrics.Accuracy, want)
+	}
+
+	mathMetrics := merged.CategoryMetrics["math"]
+	if mathMetrics == nil || mathMetrics.Total != 6 || mathMetrics.Success != 4 || mathMetrics.Accuracy != 4.0/6 {
+		t.Errorf("math metrics = %+v, want 4/6", mathMetrics)
+	}
+	if math.Abs(mathMetrics.AverageScore-4.0/6) > 1e-9 {
+		t.Errorf("math AverageScore = %v, want %v", mathMetrics.AverageScore, 4.0/6)
+	}
+	if h := merged.CategoryMetrics["history"]; h == nil || h.Total != 2 {
+		t.Errorf("history metrics = %+v, want carried over", h)
+	}
+	if l1, l2 := merged.LevelMetrics[1], merged.LevelMetrics[2]; l1.ExactMatchRate != 0.75 || l2.ExactMatchRate != 0.5 {
+		t.Errorf("level rates = %v, %v, want 0.75 and 0.5", l1.ExactMatchRate, l2.ExactMatchRate)
+	}
+
+	if math.Abs(merged.Metrics.AverageScore-4.0/6) > 1e-9 {
+		t.Errorf("Metrics.AverageScore = %v, want %v", merged.Metrics.AverageScore, 4.0/6)
+	}
+	extra := merged.Metrics.Extra
+	if extra["error_count"] != 2 || extra["unanswered_rate"] != 0.5 || extra["mode"] != "ast" {
+		t.Errorf("Extra = %v, want summed counts, averaged rates and shared values", extra)
+	}
+	if merged.TotalDuration != 3*time.Second || merged.RunConfig["shards"] != 2 {
+		t.Errorf("TotalDuration = %v, RunConfig = %v", merged.TotalDuration, merged.RunConfig)
+	}
+}
+
+func TestMergeResults_Errors(t *testing.T) {
+	a := shardResult("a", "math", 1, []bool{true})
+	other := shardResult("b", "math", 1, []bool{true})
+	other.BenchmarkName = "other"
+	dup := shardResult("a", "math", 1, []bool{false})
+
+	tests := []struct {
+		name    string
+		results []*EvalResult
+		wantErr string
+	}{
+		{"no shards", nil, "没有可合并"},
+		{"nil shard", []*EvalResult{a, nil}, "分片 1"},
+		{"benchmark mismatch", []*EvalResult{a, other}, "不一致"},
+		{"duplicate sample", []*EvalResult{a, dup}, "样本 a0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MergeResults(tt.results...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("MergeResults() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package evaluation
 
 import (
+	"io"
 	"time"
+	"unicode/utf8"
 )
 
 // EvalConfig 评估配置
@@ -15,6 +17,9 @@ type EvalConfig struct {
 	// ProgressCallback 进度回调函数
 	ProgressCallback ProgressCallback
 
+	// ProgressCallbackV2 带耗时的进度回调函数（与 ProgressCallback 可同时设置）
+	ProgressCallbackV2 ProgressCallbackV2
+
 	// SaveIntermediateResults 是否保存中间结果
 	SaveIntermediateResults bool
 
@@ -23,6 +28,48 @@ type EvalConfig struct {
 
 	// Verbose 是否输出详细日志
 	Verbose bool
+
+	// ExpectedOverrides 按样本 ID 覆盖期望答案
+	ExpectedOverrides map[string]interface{}
+
+	// Concurrency 并发评估的 worker 数（默认 1，即顺序评估）
+	Concurrency int
+
+	// CategoryConcurrency 各类别同时评估的最大样本数（未配置的类别只受 Concurrency 限制）
+	CategoryConcurrency map[string]int
+
+	// FailFast 首个样本出现硬错误（如智能体执行失败）时立即停止评估
+	FailFast bool
+
+	// CheckpointPath 断点文件路径（JSONL），为空表示不启用断点续跑
+	CheckpointPath string
+
+	// SampleRetries 出错样本的最大重试次数（0 表示不重试）
+	SampleRetries int
+
+	// Shuffle 是否在截断前按 ShuffleSeed 打乱样本顺序
+	Shuffle bool
+
+	// ShuffleSeed 打乱样本使用的随机种子
+	ShuffleSeed int64
+
+	// StratifiedSampling 配置 MaxSamples 时是否按类别/级别分层抽样
+	StratifiedSampling bool
+
+	// BatchSize 智能体实现 BatchAgent 时的批次大小（小于等于 1 表示逐个执行）
+	BatchSize int
+
+	// MaxResponseChars 评分前智能体响应的最大字符数（0 表示不限制）
+	MaxResponseChars int
+
+	// ResultSink 逐样本结果输出（NDJSON），为 nil 表示不输出
+	ResultSink io.Writer
+
+	// CategoryWeights 计算加权准确率时各类别的权重（为空表示各类别等权）
+	CategoryWeights map[string]float64
+
+	// ScoreHistogramEdges 分数直方图的分桶下界（为空时各评估器使用各自的默认分桶）
+	ScoreHistogramEdges []float64
 }
 
 // EvalOption 评估选项函数类型
@@ -31,13 +78,57 @@ type EvalOption func(*EvalConfig)
 // DefaultEvalConfig 返回默认评估配置
 func DefaultEvalConfig() *EvalConfig {
 	return &EvalConfig{
-		MaxSamples: 0, // 不限制
-		Timeout:    5 * time.Minute,
-		OutputDir:  "./evaluation_results",
-		Verbose:    false,
+		MaxSamples:  0, // 不限制
+		Timeout:     5 * time.Minute,
+		OutputDir:   "./evaluation_results",
+		Verbose:     false,
+		Concurrency: 1,
+		BatchSize:   8,
 	}
 }
 
+// Summary 返回用于报告的配置摘要
+func (c *EvalConfig) Summary() map[string]interface{} {
+	summary := map[string]interface{}{
+		"max_samples": c.MaxSamples,
+		"timeout":     c.Timeout.String(),
+	}
+	if len(c.ExpectedOverrides) > 0 {
+		summary["expected_overrides"] = len(c.ExpectedOverrides)
+	}
+	if c.Concurrency > 1 {
+		summary["concurrency"] = c.Concurrency
+	}
+	if len(c.CategoryConcurrency) > 0 {
+		summary["category_concurrency"] = c.CategoryConcurrency
+	}
+	if c.FailFast {
+		summary["fail_fast"] = true
+	}
+	if c.CheckpointPath != "" {
+		summary["checkpoint"] = c.CheckpointPath
+	}
+	if c.SampleRetries > 0 {
+		summary["sample_retries"] = c.SampleRetries
+	}
+	if c.Shuffle {
+		summary["shuffle_seed"] = c.ShuffleSeed
+	}
+	if c.StratifiedSampling {
+		summary["stratified_sampling"] = true
+	}
+	if c.MaxResponseChars > 0 {
+		summary["max_response_chars"] = c.MaxResponseChars
+	}
+	if len(c.CategoryWeights) > 0 {
+		summary["category_weights"] = c.CategoryWeights
+	}
+	if len(c.ScoreHistogramEdges) > 0 {
+		summary["score_histogram_edges"] = c.ScoreHistogramEdges
+	}
+	return summary
+}
+
 // ApplyOptions 应用评估选项
 func (c *EvalConfig) ApplyOptions(opts ...EvalOption) {
 	for _, opt := range opts {
@@ -75,6 +166,31 @@ func WithProgressCallback(callback ProgressCallback) EvalOption {
 	}
 }
 
+// WithProgressCallbackV2 设置带耗时的进度回调函数
+//
+// 参数:
+//   - callback: 进度回调函数，每完成一个样本调用一次，额外传入自评估开始以来的耗时
+func WithProgressCallbackV2(callback ProgressCallbackV2) EvalOption {
+	return func(c *EvalConfig) {
+		c.ProgressCallbackV2 = callback
+	}
+}
+
+// ReportProgress 调用已设置的进度回调
+//
+// 参数:
+//   - done: 已完成数量
+//   - total: 总数量
+//   - start: 评估开始时间，用于计算传给 ProgressCallbackV2 的耗时
+func (c *EvalConfig) ReportProgress(done, total int, start time.Time) {
+	if c.ProgressCallback != nil {
+		c.ProgressCallback(done, total)
+	}
+	if c.ProgressCallbackV2 != nil {
+		c.ProgressCallbackV2(done, total, time.Since(start))
+	}
+}
+
 // WithSaveIntermediateResults 设置是否保存中间结果
 //
 // 参数:
@@ -104,3 +220,179 @@ func WithVerbose(verbose bool) EvalOption {
 		c.Verbose = verbose
 	}
 }
+
+// WithConcurrency 设置并发评估的 worker 数
+//
+// 参数:
+//   - n: worker 数，小于 1 时按 1 处理；结果顺序与样本顺序保持一致
+func WithConcurrency(n int) EvalOption {
+	return func(c *EvalConfig) {
+		c.Concurrency = n
+	}
+}
+
+// WithCategoryConcurrency 设置各类别同时评估的最大样本数
+//
+// 参数:
+//   - limits: 类别（Sample.Category）到并发上限的映射；未配置或上限小于 1 的类别
+//     只受 WithConcurrency 的全局 worker 数限制。用于为不同配额的后端分别限流
+func WithCategoryConcurrency(limits map[string]int) EvalOption {
+	return func(c *EvalConfig) {
+		c.CategoryConcurrency = limits
+	}
+}
+
+// WithFailFast 设置是否在首个硬错误时停止评估
+//
+// 参数:
+//   - failFast: 开启后任一样本的智能体执行失败即停止分发新样本，
+//     Evaluate 返回已完成部分的结果及该错误
+func WithFailFast(failFast bool) EvalOption {
+	return func(c *EvalConfig) {
+		c.FailFast = failFast
+	}
+}
+
+// WithCheckpoint 设置断点文件，支持中断后续跑
+//
+// 参数:
+//   - path: JSONL 断点文件路径；每完成一个无错误的样本追加一行结果，
+//     重新运行时跳过文件中已记录的样本，最终结果合并已记录与新评估的样本
+func WithCheckpoint(path string) EvalOption {
+	return func(c *EvalConfig) {
+		c.CheckpointPath = path
+	}
+}
+
+// WithSampleRetries 设置出错样本的重试次数
+//
+// 参数:
+//   - n: 首轮评估结束后，Error 非空的样本最多重新评估 n 次，直到不再出错；
+//     答案错误（无 Error）的样本不会重试。重试次数记录在 Details["retry_count"]
+func WithSampleRetries(n int) EvalOption {
+	return func(c *EvalConfig) {
+		c.SampleRetries = n
+	}
+}
+
+// WithShuffle 设置按种子打乱样本顺序
+//
+// 参数:
+//   - seed: 随机种子；相同种子得到相同的样本顺序，打乱在 MaxSamples 截断之前进行
+func WithShuffle(seed int64) EvalOption {
+	return func(c *EvalConfig) {
+		c.Shuffle = true
+		c.ShuffleSeed = seed
+	}
+}
+
+// WithStratifiedSampling 设置是否分层抽样
+//
+// 参数:
+//   - stratified: 开启后配置 MaxSamples 时按样本 Category（BFCL 类别、GAIA 级别）
+//     的比例抽取子集，使子集分布与完整数据集一致
+func WithStratifiedSampling(stratified bool) EvalOption {
+	return func(c *EvalConfig) {
+		c.StratifiedSampling = stratified
+	}
+}
+
+// WithBatchSize 设置批量执行的批次大小
+//
+// 参数:
+//   - n: 仅当被评估的智能体实现 BatchAgent 时生效，样本按 n 个一批调用 RunBatch；
+//     小于等于 1 时关闭批量，逐个调用 Run
+func WithBatchSize(n int) EvalOption {
+	return func(c *EvalConfig) {
+		c.BatchSize = n
+	}
+}
+
+// WithMaxResponseChars 设置评分前智能体响应的最大字符数
+//
+// 参数:
+//   - n: 超过 n 个字符（按 Unicode 字符计）的响应在答案提取和评分前被截断，
+//     并在 Details["response_truncated"] 中标记；0 表示不限制
+func WithMaxResponseChars(n int) EvalOption {
+	return func(c *EvalConfig) {
+		c.MaxResponseChars = n
+	}
+}
+
+// WithResultSink 设置逐样本结果的实时输出
+//
+// 参数:
+//   - w: 每个样本得到最终结果后立即以一行 JSON（NDJSON）写入 w，并在 w 支持时 Flush；
+//     配置 SampleRetries 时出错样本在重试结束后写入。写入失败不会中断评估，
+//     错误记录在该样本的 Details["result_sink_error"] 中
+func WithResultSink(w io.Writer) EvalOption {
+	return func(c *EvalConfig) {
+		c.ResultSink = w
+	}
+}
+
+// WithCategoryWeights 设置加权准确率的类别权重
+//
+// 参数:
+//   - weights: 类别名到权重的映射；加权准确率为各类别准确率按权重的加权平均，
+//     未列出的类别不参与加权。不设置时各类别等权（宏平均）。目前由 BFCL 评估器使用
+func WithCategoryWeights(weights map[string]float64) EvalOption {
+	return func(c *EvalConfig) {
+		c.CategoryWeights = weights
+	}
+}
+
+// WithScoreHistogramEdges 设置分数直方图的分桶
+//
+// 参数:
+//   - edges: 升序排列的分桶下界，第 i 个桶覆盖 [edges[i], edges[i+1])，最后一个桶覆盖
+//     [edges[len-1], +∞)。不设置时 GAIA 使用 DefaultUnitScoreEdges，LLM Judge 使用
+//     DefaultJudgeScoreEdges
+func WithScoreHistogramEdges(edges []float64) EvalOption {
+	return func(c *EvalConfig) {
+		c.ScoreHistogramEdges = edges
+	}
+}
+
+// TruncateResponse 按 MaxResponseChars 截断智能体响应
+//
+// 发生截断时在 result.Details 中记录 response_truncated 及原始字符数。
+func (c *EvalConfig) TruncateResponse(result *SampleResult, response string) string {
+	if c.MaxResponseChars <= 0 || len(response) <= c.MaxResponseChars {
+		return response
+	}
+
+	chars := 0
+	for i := range response {
+		if chars == c.MaxResponseChars {
+			if result.Details == nil {
+				result.Details = make(map[string]interface{})
+			}
+			result.Details["response_truncated"] = true
+			result.Details["original_response_chars"] = utf8.RuneCountInString(response)
+			return response[:i]
+		}
+		chars++
+	}
+	return response
+}
+
+// WithExpectedOverrides 设置期望答案覆盖
+//
+// 参数:
+//   - overrides: 样本 ID 到期望答案的映射，命中的样本使用覆盖值代替 Sample.Expected
+func WithExpectedOverrides(overrides map[string]interface{}) EvalOption {
+	return func(c *EvalConfig) {
+		c.ExpectedOverrides = overrides
+	}
+}
+
+// ApplyExpectedOverride 对样本应用期望答案覆盖
+//
+// 未配置覆盖或样本未命中时原样返回。
+func (c *EvalConfig) ApplyExpectedOverride(sample Sample) Sample {
+	if expected, ok := c.ExpectedOverrides[sample.ID]; ok {
+		sample.Expected = expected
+	}
+	return sample
+}
@@ -82,3 +82,32 @@ func TestWithSaveIntermediateResults(t *testing.T) {
 		t.Errorf("expected SaveIntermediateResults true, got %v", config.SaveIntermediateResults)
 	}
 }
+
+func TestEvalConfig_ApplyExpectedOverride(t *testing.T) {
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithExpectedOverrides(map[string]interface{}{"s1": "fixed"}))
+
+	if got := config.ApplyExpectedOverride(Sample{ID: "s1", Expected: "wrong"}); got.Expected != "fixed" {
+		t.Errorf("expected override applied, got %v", got.Expected)
+	}
+	if got := config.ApplyExpectedOverride(Sample{ID: "s2", Expected: "keep"}); got.Expected != "keep" {
+		t.Errorf("expected sample unchanged, got %v", got.Expected)
+	}
+}
+
+func TestWithCategoryWeights(t *testing.T) {
+	config := DefaultEvalConfig()
+	if _, ok := config.Summary()["category_weights"]; ok {
+		t.Error("expected no category_weights in default summary")
+	}
+
+	weights := map[string]float64{"simple": 1, "parallel": 2}
+	config.ApplyOptions(WithCategoryWeights(weights))
+
+	if config.CategoryWeights["parallel"] != 2 {
+		t.Errorf("expected parallel weight 2, got %v", config.CategoryWeights)
+	}
+	if _, ok := config.Summary()["category_weights"]; !ok {
+		t.Error("expected category_weights in summary")
+	}
+}
@@ -0,0 +1,164 @@
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 雷达图绘制参数
+const (
+	radarSize   = 360
+	radarRadius = 120.0
+)
+
+// RadarAxis 能力雷达图的一个轴
+type RadarAxis struct {
+	// Benchmark 基准名称
+	Benchmark string `json:"benchmark"`
+
+	// Capability 能力维度名称
+	Capability string `json:"capability"`
+
+	// Value 归一化得分（0-1）
+	Value float64 `json:"value"`
+}
+
+// CapabilityAxes 从多基准结果中计算雷达图各轴数值
+//
+// 每个基准对应一个轴，取值为 OverallAccuracy 并截断到 [0, 1]。
+func CapabilityAxes(suite *SuiteResult) []RadarAxis {
+	if suite == nil {
+		return nil
+	}
+
+	names := suite.BenchmarkNames()
+	axes := make([]RadarAxis, 0, len(names))
+	for _, name := range names {
+		result := suite.Results[name]
+		value := 0.0
+		if result != nil {
+			value = clamp01(result.OverallAccuracy)
+		}
+		axes = append(axes, RadarAxis{
+			Benchmark:  name,
+			Capability: CapabilityOf(name),
+			Value:      value,
+		})
+	}
+	return axes
+}
+
+// ExportCapabilityRadar 导出能力雷达图
+//
+// 输出 Markdown 文件，包含内嵌 SVG 雷达图和归一化得分表，每个基准一个轴（0-1）。
+func ExportCapabilityRadar(suite *SuiteResult, path string) error {
+	if suite == nil {
+		return fmt.Errorf("评估结果为空")
+	}
+
+	// 确保目录存在
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer file.Close()
+
+	axes := CapabilityAxes(suite)
+
+	fmt.Fprintf(file, "# 能力雷达图\n\n")
+	fmt.Fprintf(file, "- **智能体**: %s\n", suite.AgentName)
+	fmt.Fprintf(file, "- **评估时间**: %s\n\n", suite.EvaluationTime.Format("2006-01-02 15:04:05"))
+
+	if len(axes) == 0 {
+		fmt.Fprintf(file, "暂无可用的评估结果。\n")
+		return nil
+	}
+
+	fmt.Fprintf(file, "%s\n\n", renderRadarSVG(axes))
+
+	// 归一化得分表
+	fmt.Fprintf(file, "## 归一化得分\n\n")
+	fmt.Fprintf(file, "| 能力 | 基准 | 得分 |\n")
+	fmt.Fprintf(file, "|------|------|------|\n")
+	for _, axis := range axes {
+		fmt.Fprintf(file, "| %s | %s | %.2f |\n", axis.Capability, axis.Benchmark, axis.Value)
+	}
+	fmt.Fprintf(file, "\n")
+
+	return nil
+}
+
+// renderRadarSVG 渲染雷达图 SVG
+func renderRadarSVG(axes []RadarAxis) string {
+	center := float64(radarSize) / 2
+	n := len(axes)
+
+	point := func(i int, value float64) (float64, float64) {
+		angle := -math.Pi/2 + 2*math.Pi*float64(i)/float64(n)
+		return center + radarRadius*value*math.Cos(angle), center + radarRadius*value*math.Sin(angle)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		radarSize, radarSize, radarSize, radarSize)
+	sb.WriteString("\n")
+
+	// 网格
+	for _, level := range []float64{0.25, 0.5, 0.75, 1.0} {
+		points := make([]string, n)
+		for i := range axes {
+			x, y := point(i, level)
+			points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+		}
+		fmt.Fprintf(&sb, `  <polygon points="%s" fill="none" stroke="#ccc"/>`, strings.Join(points, " "))
+		sb.WriteString("\n")
+	}
+
+	// 轴线和标签
+	for i, axis := range axes {
+		x, y := point(i, 1.0)
+		lx, ly := point(i, 1.15)
+		fmt.Fprintf(&sb, `  <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#999"/>`, center, center, x, y)
+		sb.WriteString("\n")
+		fmt.Fprintf(&sb, `  <text x="%.1f" y="%.1f" font-size="12" text-anchor="middle">%s (%.2f)</text>`,
+			lx, ly, escapeXML(axis.Capability), axis.Value)
+		sb.WriteString("\n")
+	}
+
+	// 数据多边形
+	points := make([]string, n)
+	for i, axis := range axes {
+		x, y := point(i, axis.Value)
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	fmt.Fprintf(&sb, `  <polygon points="%s" fill="rgba(54,162,235,0.3)" stroke="#36a2eb" stroke-width="2"/>`,
+		strings.Join(points, " "))
+	sb.WriteString("\n</svg>")
+
+	return sb.String()
+}
+
+// clamp01 将数值截断到 [0, 1]
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// escapeXML 转义 XML 特殊字符
+func escapeXML(s string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+	return replacer.Replace(s)
+}
@@ -0,0 +1,53 @@
+package evaluation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportCapabilityRadar(t *testing.T) {
+	suite := NewSuiteResult("TestAgent")
+	suite.Add("BFCL_simple_python_ast", &EvalResult{OverallAccuracy: 0.8})
+	suite.Add("GAIA_validation", &EvalResult{OverallAccuracy: 0.35})
+
+	path := filepath.Join(t.TempDir(), "radar.md")
+	if err := ExportCapabilityRadar(suite, path); err != nil {
+		t.Fatalf("ExportCapabilityRadar() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	content := string(data)
+
+	for _, want := range []string{
+		"<svg",
+		"function-calling (0.80)",
+		"general-assistant (0.35)",
+		"| function-calling | BFCL_simple_python_ast | 0.80 |",
+		"| general-assistant | GAIA_validation | 0.35 |",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+}
+
+func TestCapabilityAxes_Clamp(t *testing.T) {
+	suite := NewSuiteResult("TestAgent")
+	suite.Add("custom", &EvalResult{OverallAccuracy: 1.5})
+
+	axes := CapabilityAxes(suite)
+	if len(axes) != 1 {
+		t.Fatalf("expected 1 axis, got %d", len(axes))
+	}
+	if axes[0].Value != 1.0 {
+		t.Errorf("expected value clamped to 1.0, got %v", axes[0].Value)
+	}
+	if axes[0].Capability != "custom" {
+		t.Errorf("expected capability 'custom', got %q", axes[0].Capability)
+	}
+}
@@ -0,0 +1,353 @@
+package evaluation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/core/message"
+)
+
+// 完整报告中展示的失败样本数量
+const reportTopFailures = 10
+
+// LatencyPercentiles 延迟分位数
+type LatencyPercentiles struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// ComputeLatencyPercentiles 计算样本执行时间分位数
+func ComputeLatencyPercentiles(results []*SampleResult) LatencyPercentiles {
+	durations := make([]time.Duration, 0, len(results))
+	for _, r := range results {
+		if r != nil {
+			durations = append(durations, r.ExecutionTime)
+		}
+	}
+	if len(durations) == 0 {
+		return LatencyPercentiles{}
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	percentile := func(p float64) time.Duration {
+		idx := int(float64(len(durations)-1) * p)
+		return durations[idx]
+	}
+
+	return LatencyPercentiles{
+		P50: percentile(0.50),
+		P90: percentile(0.90),
+		P99: percentile(0.99),
+		Max: durations[len(durations)-1],
+	}
+}
+
+// ErrorHistogram 统计错误类型分布
+//
+// 错误类型取错误信息中第一个冒号之前的部分。
+func ErrorHistogram(results []*SampleResult) map[string]int {
+	histogram := make(map[string]int)
+	for _, r := range results {
+		if r == nil || r.Error == "" {
+			continue
+		}
+		histogram[errorKind(r.Error)]++
+	}
+	return histogram
+}
+
+// errorKind 提取错误类型
+func errorKind(msg string) string {
+	kind := msg
+	if idx := strings.IndexAny(kind, ":："); idx > 0 {
+		kind = kind[:idx]
+	}
+	kind = strings.TrimSpace(kind)
+	if runes := []rune(kind); len(runes) > 60 {
+		kind = string(runes[:60]) + "..."
+	}
+	return kind
+}
+
+// ExportFullReport 导出完整运行报告
+//
+// 在单个 Markdown 文件中汇总运行配置、总体/分类别/分级别指标、延迟分位数、
+// 错误分类、错误分布和主要失败样本。仅输出结果中存在数据的章节，适用于所有基准。
+func ExportFullReport(result *EvalResult, path string) error {
+	if result == nil {
+		return fmt.Errorf("评估结果为空")
+	}
+
+	// 确保目录存在
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建文件失败: %w", err)
+	}
+	defer file.Close()
+
+	var sb strings.Builder
+	writeReportOverview(&sb, result)
+	writeReportConfig(&sb, result)
+	writeReportMetrics(&sb, result)
+	writeReportHistogram(&sb, result)
+	writeReportCategories(&sb, result)
+	writeReportLevels(&sb, result)
+	writeReportLatency(&sb, result)
+	writeReportErrorBreakdown(&sb, result)
+	writeReportErrors(&sb, result)
+	writeReportFailures(&sb, result)
+
+	if _, err := file.WriteString(sb.String()); err != nil {
+		return fmt.Errorf("写入报告失败: %w", err)
+	}
+	return nil
+}
+
+// writeReportOverview 写入概览
+func writeReportOverview(sb *strings.Builder, result *EvalResult) {
+	fmt.Fprintf(sb, "# %s 运行报告\n\n", result.BenchmarkName)
+	fmt.Fprintf(sb, "## 概览\n\n")
+	fmt.Fprintf(sb, "- **基准**: %s\n", result.BenchmarkName)
+	fmt.Fprintf(sb, "- **智能体**: %s\n", result.AgentName)
+	fmt.Fprintf(sb, "- **评估时间**: %s\n", result.EvaluationTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(sb, "- **总耗时**: %s\n\n", result.TotalDuration)
+}
+
+// writeReportConfig 写入运行配置
+func writeReportConfig(sb *strings.Builder, result *EvalResult) {
+	fmt.Fprintf(sb, "## 运行配置\n\n")
+	if len(result.RunConfig) == 0 {
+		fmt.Fprintf(sb, "未记录运行配置。\n\n")
+		return
+	}
+
+	keys := make([]string, 0, len(result.RunConfig))
+	for k := range result.RunConfig {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(sb, "| 配置项 | 值 |\n")
+	fmt.Fprintf(sb, "|--------|----|\n")
+	for _, k := range keys {
+		fmt.Fprintf(sb, "| %s | %v |\n", k, result.RunConfig[k])
+	}
+	fmt.Fprintf(sb, "\n")
+}
+
+// writeReportMetrics 写入总体指标
+func writeReportMetrics(sb *strings.Builder, result *EvalResult) {
+	fmt.Fprintf(sb, "## 总体指标\n\n")
+	fmt.Fprintf(sb, "| 指标 | 值 |\n")
+	fmt.Fprintf(sb, "|------|----|\n")
+	fmt.Fprintf(sb, "| 总样本数 | %d |\n", result.TotalSamples)
+	fmt.Fprintf(sb, "| 成功数 | %d |\n", result.SuccessCount)
+	fmt.Fprintf(sb, "| 准确率 | %.2f%% |\n", result.OverallAccuracy*100)
+
+	if m := result.Metrics; m != nil {
+		rows := []struct {
+			name  string
+			value float64
+		}{
+			{"精确率", m.Precision},
+			{"召回率", m.Recall},
+			{"F1 分数", m.F1Score},
+			{"通过率", m.PassRate},
+			{"优秀率", m.ExcellentRate},
+			{"胜率", m.WinRate},
+			{"败率", m.LossRate},
+			{"平局率", m.TieRate},
+		}
+		for _, row := range rows {
+			if row.value > 0 {
+				fmt.Fprintf(sb, "| %s | %.2f%% |\n", row.name, row.value*100)
+			}
+		}
+		if m.AverageScore > 0 {
+			fmt.Fprintf(sb, "| 平均分 | %.2f |\n", m.AverageScore)
+		}
+		writeTokenUsageRows(sb, m.TokenUsage, result.TotalSamples)
+	}
+	fmt.Fprintf(sb, "\n")
+
+	if result.Metrics != nil && len(result.Metrics.DimensionScores) > 0 {
+		dims := make([]string, 0, len(result.Metrics.DimensionScores))
+		for d := range result.Metrics.DimensionScores {
+			dims = append(dims, d)
+		}
+		sort.Strings(dims)
+
+		fmt.Fprintf(sb, "### 维度分数\n\n")
+		fmt.Fprintf(sb, "| 维度 | 平均分 |\n")
+		fmt.Fprintf(sb, "|------|--------|\n")
+		for _, d := range dims {
+			fmt.Fprintf(sb, "| %s | %.2f |\n", d, result.Metrics.DimensionScores[d])
+		}
+		fmt.Fprintf(sb, "\n")
+	}
+}
+
+// writeReportHistogram 写入分数分布
+func writeReportHistogram(sb *strings.Builder, result *EvalResult) {
+	if result.Metrics == nil || result.Metrics.ScoreHistogram == nil {
+		return
+	}
+	fmt.Fprintf(sb, "## 分数分布\n\n")
+	fmt.Fprintf(sb, "%s\n", result.Metrics.ScoreHistogram.Markdown())
+}
+
+// writeTokenUsageRows 写入 Token 使用量行
+func writeTokenUsageRows(sb *strings.Builder, usage *message.TokenUsage, samples int) {
+	if usage == nil {
+		return
+	}
+	fmt.Fprintf(sb, "| 输入 Token | %d |\n", usage.PromptTokens)
+	fmt.Fprintf(sb, "| 输出 Token | %d |\n", usage.CompletionTokens)
+	fmt.Fprintf(sb, "| 总 Token | %d |\n", usage.TotalTokens)
+	if samples > 0 {
+		fmt.Fprintf(sb, "| 平均 Token/样本 | %.1f |\n", float64(usage.TotalTokens)/float64(samples))
+	}
+}
+
+// writeReportCategories 写入分类别指标
+func writeReportCategories(sb *strings.Builder, result *EvalResult) {
+	if len(result.CategoryMetrics) == 0 {
+		return
+	}
+
+	cats := make([]string, 0, len(result.CategoryMetrics))
+	for c := range result.CategoryMetrics {
+		cats = append(cats, c)
+	}
+	sort.Strings(cats)
+
+	fmt.Fprintf(sb, "## 分类别指标\n\n")
+	fmt.Fprintf(sb, "| 类别 | 总数 | 成功数 | 准确率 |\n")
+	fmt.Fprintf(sb, "|------|------|--------|--------|\n")
+	for _, c := range cats {
+		m := result.CategoryMetrics[c]
+		fmt.Fprintf(sb, "| %s | %d | %d | %.2f%% |\n", c, m.Total, m.Success, m.Accuracy*100)
+	}
+	fmt.Fprintf(sb, "\n")
+}
+
+// writeReportLevels 写入分级别指标
+func writeReportLevels(sb *strings.Builder, result *EvalResult) {
+	if len(result.LevelMetrics) == 0 {
+		return
+	}
+
+	levels := make([]int, 0, len(result.LevelMetrics))
+	for l := range result.LevelMetrics {
+		levels = append(levels, l)
+	}
+	sort.Ints(levels)
+
+	fmt.Fprintf(sb, "## 分级别指标\n\n")
+	fmt.Fprintf(sb, "| 级别 | 总数 | 精确匹配 | 精确匹配率 | 部分匹配率 |\n")
+	fmt.Fprintf(sb, "|------|------|----------|------------|------------|\n")
+	for _, l := range levels {
+		m := result.LevelMetrics[l]
+		fmt.Fprintf(sb, "| Level %d | %d | %d | %.2f%% | %.2f%% |\n",
+			l, m.Total, m.ExactMatches, m.ExactMatchRate*100, m.PartialMatchRate*100)
+	}
+	fmt.Fprintf(sb, "\n")
+}
+
+// writeReportLatency 写入延迟分位数
+func writeReportLatency(sb *strings.Builder, result *EvalResult) {
+	if len(result.DetailedResults) == 0 {
+		return
+	}
+
+	p := ComputeLatencyPercentiles(result.DetailedResults)
+	fmt.Fprintf(sb, "## 延迟分位数\n\n")
+	fmt.Fprintf(sb, "| P50 | P90 | P99 | Max |\n")
+	fmt.Fprintf(sb, "|-----|-----|-----|-----|\n")
+	fmt.Fprintf(sb, "| %s | %s | %s | %s |\n\n", p.P50, p.P90, p.P99, p.Max)
+}
+
+// writeReportErrorBreakdown 写入错误分类统计
+//
+// 优先使用汇总指标中已计算的统计，否则按样本结果计算。
+func writeReportErrorBreakdown(sb *strings.Builder, result *EvalResult) {
+	breakdown := ErrorBreakdownOf(result.Metrics)
+	if breakdown == nil {
+		if len(result.DetailedResults) == 0 {
+			return
+		}
+		breakdown = ComputeErrorBreakdown(result.DetailedResults)
+	}
+	fmt.Fprintf(sb, "## 错误分类\n\n")
+	fmt.Fprintf(sb, "%s\n", breakdown.Markdown())
+}
+
+// writeReportErrors 写入错误分布
+func writeReportErrors(sb *strings.Builder, result *EvalResult) {
+	histogram := ErrorHistogram(result.DetailedResults)
+	if len(histogram) == 0 {
+		return
+	}
+
+	kinds := make([]string, 0, len(histogram))
+	for k := range histogram {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if histogram[kinds[i]] != histogram[kinds[j]] {
+			return histogram[kinds[i]] > histogram[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+
+	fmt.Fprintf(sb, "## 错误分布\n\n")
+	fmt.Fprintf(sb, "| 错误类型 | 数量 |\n")
+	fmt.Fprintf(sb, "|----------|------|\n")
+	for _, k := range kinds {
+		fmt.Fprintf(sb, "| %s | %d |\n", k, histogram[k])
+	}
+	fmt.Fprintf(sb, "\n")
+}
+
+// writeReportFailures 写入主要失败样本
+func writeReportFailures(sb *strings.Builder, result *EvalResult) {
+	var failures []*SampleResult
+	for _, sr := range result.DetailedResults {
+		if sr != nil && !sr.Success {
+			failures = append(failures, sr)
+		}
+	}
+	if len(failures) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "## 失败样本（前 %d 个）\n\n", reportTopFailures)
+	for i, sr := range failures {
+		if i >= reportTopFailures {
+			break
+		}
+		fmt.Fprintf(sb, "### 样本: %s\n\n", sr.SampleID)
+		if sr.Expected != nil {
+			fmt.Fprintf(sb, "- **期望**: %v\n", sr.Expected)
+		}
+		if sr.Predicted != nil {
+			fmt.Fprintf(sb, "- **预测**: %v\n", sr.Predicted)
+		}
+		if sr.Error != "" {
+			fmt.Fprintf(sb, "- **错误**: %s\n", sr.Error)
+		}
+		fmt.Fprintf(sb, "\n")
+	}
+}
@@ -0,0 +1,79 @@
+package evaluation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportFullReport_GAIA(t *testing.T) {
+	result := &EvalResult{
+		BenchmarkName:   "GAIA_validation_level1",
+		AgentName:       "TestAgent",
+		TotalSamples:    3,
+		SuccessCount:    1,
+		OverallAccuracy: 1.0 / 3.0,
+		LevelMetrics: map[int]*LevelMetrics{
+			1: {Level: 1, Total: 3, ExactMatches: 1, ExactMatchRate: 1.0 / 3.0},
+		},
+		DetailedResults: []*SampleResult{
+			{SampleID: "q1", Success: true, Expected: "42", Predicted: "42", ExecutionTime: time.Second},
+			{SampleID: "q2", Expected: "Paris", Predicted: "London", ExecutionTime: 2 * time.Second},
+			{SampleID: "q3", Error: "context deadline exceeded", ExecutionTime: 3 * time.Second},
+		},
+		RunConfig: map[string]interface{}{"max_samples": 3, "timeout": "5m0s"},
+		Metrics:   &MetricsSummary{Accuracy: 1.0 / 3.0},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.md")
+	if err := ExportFullReport(result, path); err != nil {
+		t.Fatalf("ExportFullReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	content := string(data)
+
+	for _, section := range []string{
+		"## 概览",
+		"## 运行配置",
+		"| max_samples | 3 |",
+		"## 总体指标",
+		"## 分级别指标",
+		"## 延迟分位数",
+		"## 错误分布",
+		"| context deadline exceeded | 1 |",
+		"## 失败样本",
+		"### 样本: q2",
+	} {
+		if !strings.Contains(content, section) {
+			t.Errorf("report missing %q", section)
+		}
+	}
+
+	if strings.Contains(content, "## 分类别指标") {
+		t.Error("report should omit empty category section")
+	}
+}
+
+func TestComputeLatencyPercentiles(t *testing.T) {
+	results := make([]*SampleResult, 100)
+	for i := range results {
+		results[i] = &SampleResult{ExecutionTime: time.Duration(i+1) * time.Millisecond}
+	}
+
+	p := ComputeLatencyPercentiles(results)
+	if p.P50 != 50*time.Millisecond {
+		t.Errorf("P50 = %v, want 50ms", p.P50)
+	}
+	if p.P90 != 90*time.Millisecond {
+		t.Errorf("P90 = %v, want 90ms", p.P90)
+	}
+	if p.Max != 100*time.Millisecond {
+		t.Errorf("Max = %v, want 100ms", p.Max)
+	}
+}
@@ -0,0 +1,396 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// SampleFunc 单样本评估函数
+//
+// 返回的结果不能为 nil。返回的 error 表示智能体执行失败等硬错误
+// （失败信息同时记录在结果中），开启 FailFast 时用于提前终止评估。
+type SampleFunc func(ctx context.Context, sample Sample) (*SampleResult, error)
+
+// IndexedSampleFunc 带样本索引的单样本评估函数
+//
+// index 为样本在数据集中的索引，用于按位置关联参考数据等场景；其余约定同 SampleFunc。
+type IndexedSampleFunc func(ctx context.Context, index int, sample Sample) (*SampleResult, error)
+
+// RunSamples 按配置评估数据集的前 total 个样本
+//
+// 统一处理样本加载失败、期望答案覆盖、单样本超时、失败重试和断点续跑：
+//   - 样本分发给 config.Concurrency 个 worker 并发评估，返回结果按样本索引排序；
+//     配置 CategoryConcurrency 时同一类别同时评估的样本数另受其上限约束，名额已满的类别
+//     暂缓派发，空闲 worker 先评估其他类别的样本
+//   - 每完成一个样本调用一次 ProgressCallback/ProgressCallbackV2（调用串行，done 单调递增）
+//   - 配置 SampleRetries 时，首轮结束后对 Error 非空的样本重新评估，最多 n 次
+//   - 配置 CheckpointPath 时跳过断点文件中已完成的样本并复用其结果，
+//     新完成且无错误的样本追加写入断点文件
+//   - 配置 ResultSink 时每个样本的最终结果写入一行 JSON（调用串行）
+//   - 单样本超时（Timeout）只将该样本标记为超时失败（见 MarkSampleTimeout）并继续评估，
+//     不视为硬错误；评估函数在超时后仍未返回时放弃等待
+//   - ctx 取消时停止分发新样本，等待进行中的样本结束后返回已完成的结果及 ctx.Err()
+//   - 开启 FailFast 时首轮的首个硬错误同样停止分发，并返回已完成的结果及该错误
+func RunSamples(ctx context.Context, config *EvalConfig, dataset Dataset, total int, evaluate SampleFunc) ([]*SampleResult, error) {
+	return RunIndexedSamples(ctx, config, dataset, total, func(ctx context.Context, _ int, sample Sample) (*SampleResult, error) {
+		return evaluate(ctx, sample)
+	})
+}
+
+// RunIndexedSamples 同 RunSamples，评估函数额外接收样本索引
+func RunIndexedSamples(ctx context.Context, config *EvalConfig, dataset Dataset, total int, evaluate IndexedSampleFunc) ([]*SampleResult, error) {
+	if total <= 0 {
+		return nil, nil
+	}
+
+	var checkpoint *Checkpoint
+	if config.CheckpointPath != "" {
+		var err error
+		checkpoint, err = OpenCheckpoint(config.CheckpointPath)
+		if err != nil {
+			return nil, err
+		}
+		defer checkpoint.Close()
+	}
+
+	limiter := newCategoryLimiter(config)
+	// categoryOf 返回样本类别，用于按类别名额调度（加载失败的样本不受类别限制）
+	categoryOf := func(i int) string {
+		sample, err := dataset.Get(i)
+		if err != nil {
+			return ""
+		}
+		return sample.Category
+	}
+	startTime := time.Now()
+	results := make([]*SampleResult, total)
+	stop := make(chan struct{})
+
+	var (
+		mu       sync.Mutex
+		done     int
+		firstErr error
+	)
+
+	// fail 记录首个致命错误并停止分发（需持有 mu）
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+			close(stop)
+		}
+	}
+
+	// checkpointResult 将新完成且无错误的样本写入断点文件（需持有 mu）
+	checkpointResult := func(result *SampleResult) {
+		if checkpoint == nil || result.Error != "" {
+			return
+		}
+		if err := checkpoint.Append(result); err != nil {
+			fail(err)
+		}
+	}
+
+	// emit 将样本最终结果写入 ResultSink，每个样本只写一次（需持有 mu）
+	emitted := make([]bool, total)
+	emit := func(i int) {
+		if config.ResultSink == nil || emitted[i] || results[i] == nil {
+			return
+		}
+		emitted[i] = true
+		writeResultLine(config.ResultSink, results[i])
+	}
+
+	// 首轮评估
+	all := make([]int, total)
+	for i := range all {
+		all[i] = i
+	}
+	dispatchErr := runPool(ctx, config.Concurrency, all, stop, limiter, categoryOf, func(i int) {
+		result, resumed, err := runSample(ctx, config, dataset, checkpoint, i, evaluate)
+
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = result
+		done++
+		config.ReportProgress(done, total, startTime)
+		if !resumed {
+			checkpointResult(result)
+		}
+		// 待重试的出错样本在重试结束后写入
+		if config.SampleRetries <= 0 || result.Error == "" || isLoadError(result) {
+			emit(i)
+		}
+		if err != nil && config.FailFast {
+			fail(fmt.Errorf("样本 %s 执行失败: %w", result.SampleID, err))
+		}
+	})
+
+	// 失败重试轮：仅重试出错的样本，答案错误不重试
+	if dispatchErr == nil && firstErr == nil && config.SampleRetries > 0 {
+		var failed []int
+		for i, r := range results {
+			if r != nil && r.Error != "" && !isLoadError(r) {
+				failed = append(failed, i)
+			}
+		}
+		dispatchErr = runPool(ctx, config.Concurrency, failed, stop, limiter, categoryOf, func(i int) {
+			result := results[i]
+			retries := 0
+			for retries < config.SampleRetries && result.Error != "" && ctx.Err() == nil {
+				retries++
+				retried, _, _ := runSample(ctx, config, dataset, nil, i, evaluate)
+				result = retried
+			}
+			if result.Details == nil {
+				result.Details = make(map[string]interface{})
+			}
+			result.Details["retry_count"] = retries
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = result
+			checkpointResult(result)
+			emit(i)
+		})
+	}
+
+	// 未进入重试轮（如评估被取消）的出错样本
+	mu.Lock()
+	for i := range results {
+		emit(i)
+	}
+	mu.Unlock()
+
+	if firstErr != nil {
+		dispatchErr = firstErr
+	}
+
+	// 按索引顺序收集已完成的结果
+	completed := make([]*SampleResult, 0, total)
+	for _, r := range results {
+		if r != nil {
+			completed = append(completed, r)
+		}
+	}
+
+	return completed, dispatchErr
+}
+
+// runPool 使用 workers 个 goroutine 处理 indices
+//
+// 配置 limiter 时按 categoryOf 获取样本类别，类别名额已满的样本暂缓派发，先派发其后
+// 其他类别的样本，不让 worker 空等名额；样本处理结束后释放名额。
+// ctx 取消或 stop 关闭时停止分发，等待进行中的任务结束；因 ctx 取消而停止时返回 ctx.Err()。
+func runPool(ctx context.Context, workers int, indices []int, stop <-chan struct{},
+	limiter *categoryLimiter, categoryOf func(i int) string, handle func(i int)) error {
+	if len(indices) == 0 {
+		return nil
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(indices) {
+		workers = len(indices)
+	}
+
+	// poolJob 派发给 worker 的样本及其类别名额
+	type poolJob struct {
+		index   int
+		release func()
+	}
+
+	ch := make(chan poolJob)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range ch {
+				// 分发与停止同时就绪时可能多派发一个样本，此处丢弃
+				if ctx.Err() != nil {
+					job.release()
+					continue
+				}
+				select {
+				case <-stop:
+					job.release()
+					continue
+				default:
+				}
+				handle(job.index)
+				job.release()
+			}
+		}()
+	}
+
+	if limiter == nil {
+		categoryOf = nil
+	}
+	queue := newCategoryQueue(indices, categoryOf)
+
+	var err error
+dispatch:
+	for !queue.empty() {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		index, release, ok := queue.next(limiter)
+		if !ok {
+			// 待派发样本的类别名额均已满，等待名额释放
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				break dispatch
+			case <-stop:
+				break dispatch
+			case <-limiter.released():
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			release()
+			err = ctx.Err()
+			break dispatch
+		case <-stop:
+			release()
+			break dispatch
+		case ch <- poolJob{index: index, release: release}:
+		}
+	}
+	close(ch)
+	wg.Wait()
+
+	return err
+}
+
+// runSample 加载并评估单个样本，返回结果、是否来自断点及硬错误
+func runSample(ctx context.Context, config *EvalConfig, dataset Dataset, checkpoint *Checkpoint,
+	index int, evaluate IndexedSampleFunc) (*SampleResult, bool, error) {
+	sample, err := dataset.Get(index)
+	if err != nil {
+		return NewSampleLoadErrorResult(index, err), false, nil
+	}
+	sample = config.ApplyExpectedOverride(sample)
+
+	if checkpoint != nil {
+		if result, ok := checkpoint.Lookup(sample.ID); ok {
+			return result, true, nil
+		}
+	}
+
+	// 应用超时
+	sampleCtx, cancel := config.SampleContext(ctx)
+	defer cancel()
+
+	// 评估函数可能忽略 ctx 而长时间阻塞：单样本超时后最多再等待 sampleTimeoutGrace，
+	// 父上下文取消时则等待其返回
+	done := make(chan sampleOutcome, 1)
+	go func() {
+		result, err := evaluate(sampleCtx, index, sample)
+		done <- sampleOutcome{result: result, err: err}
+	}()
+
+	var outcome sampleOutcome
+	select {
+	case outcome = <-done:
+	case <-sampleCtx.Done():
+		if ctx.Err() != nil {
+			outcome = <-done
+			break
+		}
+		select {
+		case outcome = <-done:
+		case <-time.After(sampleTimeoutGrace):
+			outcome = sampleOutcome{
+				result: &SampleResult{
+					SampleID: sample.ID,
+					Level:    sample.Level,
+					Category: sample.Category,
+					Expected: sample.Expected,
+					Error:    sampleCtx.Err().Error(),
+					Details:  make(map[string]interface{}),
+				},
+				err: sampleCtx.Err(),
+			}
+		}
+	}
+
+	if config.MarkSampleTimeout(ctx, sampleCtx, outcome.result) {
+		return outcome.result, false, nil
+	}
+	return outcome.result, false, outcome.err
+}
+
+// sampleTimeoutGrace 单样本超时后等待评估函数返回的最长时间
+const sampleTimeoutGrace = 200 * time.Millisecond
+
+// sampleOutcome 单样本评估函数的返回值
+type sampleOutcome struct {
+	result *SampleResult
+	err    error
+}
+
+// MarkSampleTimeout 将因单样本超时而失败的结果标记为超时
+//
+// 仅当 sampleCtx 已超时、父上下文 ctx 仍有效且结果已记录错误时生效：将 Error 改为
+// 统一的超时描述，并设置 Details["timeout"] = true。返回是否进行了标记。
+// 父上下文取消导致的失败不做标记，由调用方终止整个评估。
+func (c *EvalConfig) MarkSampleTimeout(ctx, sampleCtx context.Context, result *SampleResult) bool {
+	if result == nil || result.Error == "" || ctx.Err() != nil ||
+		!errors.Is(sampleCtx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+	result.Success = false
+	result.Error = fmt.Sprintf("样本评估超时（%s）", c.Timeout)
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	result.Details["timeout"] = true
+	return true
+}
+
+// writeResultLine 将结果以一行 JSON 写入 w 并尝试 Flush
+//
+// 写入失败记录在 result.Details["result_sink_error"] 中，不返回错误。
+func writeResultLine(w io.Writer, result *SampleResult) {
+	line, err := json.Marshal(result)
+	if err == nil {
+		_, err = w.Write(append(line, '\n'))
+	}
+	if err == nil {
+		switch f := w.(type) {
+		case interface{ Flush() error }:
+			err = f.Flush()
+		case interface{ Flush() }:
+			f.Flush()
+		}
+	}
+	if err != nil {
+		if result.Details == nil {
+			result.Details = make(map[string]interface{})
+		}
+		result.Details["result_sink_error"] = err.Error()
+	}
+}
+
+// isLoadError 判断结果是否为样本加载失败
+func isLoadError(result *SampleResult) bool {
+	loadErr, _ := result.Details["load_error"].(bool)
+	return loadErr
+}
+
+// SampleContext 返回单个样本评估使用的上下文（应用 Timeout）
+//
+// 调用方需在样本评估结束后调用返回的 cancel。
+func (c *EvalConfig) SampleContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Timeout > 0 {
+		return context.WithTimeout(ctx, c.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,358 @@
+package evaluation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sliceDataset 基于切片的测试数据集
+type sliceDataset struct {
+	samples []Sample
+}
+
+func newSliceDataset(n int) *sliceDataset {
+	samples := make([]Sample, n)
+	for i := range samples {
+		samples[i] = Sample{ID: fmt.Sprintf("s%d", i), Input: fmt.Sprintf("input %d", i)}
+	}
+	return &sliceDataset{samples: samples}
+}
+
+func (d *sliceDataset) Load(ctx context.Context) error { return nil }
+func (d *sliceDataset) Len() int                       { return len(d.samples) }
+func (d *sliceDataset) Name() string                   { return "slice" }
+
+func (d *sliceDataset) Get(index int) (Sample, error) {
+	if index < 0 || index >= len(d.samples) {
+		return Sample{}, fmt.Errorf("index out of range: %d", index)
+	}
+	return d.samples[index], nil
+}
+
+func (d *sliceDataset) Iterator() <-chan Sample {
+	ch := make(chan Sample, len(d.samples))
+	for _, s := range d.samples {
+		ch <- s
+	}
+	close(ch)
+	return ch
+}
+
+func TestRunSamples_Ordering(t *testing.T) {
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithConcurrency(3))
+
+	results, err := RunSamples(context.Background(), config, newSliceDataset(10), 10, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		return &SampleResult{SampleID: sample.ID}, nil
+	})
+	if err != nil {
+		t.Fatalf("RunSamples() error = %v", err)
+	}
+	if len(results) != 10 {
+		t.Fatalf("expected 10 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if want := fmt.Sprintf("s%d", i); r.SampleID != want {
+			t.Errorf("results[%d].SampleID = %s, want %s", i, r.SampleID, want)
+		}
+	}
+}
+
+func TestRunSamples_CancelStopsDispatch(t *testing.T) {
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithConcurrency(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var started int32
+	results, err := RunSamples(ctx, config, newSliceDataset(100), 100, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		if atomic.AddInt32(&started, 1) == 3 {
+			cancel()
+		}
+		return &SampleResult{SampleID: sample.ID}, nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(results) >= 100 || int32(len(results)) != atomic.LoadInt32(&started) {
+		t.Errorf("expected partial results matching started samples, got %d results for %d started",
+			len(results), started)
+	}
+}
+
+func TestRunSamples_SampleTimeoutContinues(t *testing.T) {
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithTimeout(50*time.Millisecond), WithFailFast(true))
+
+	// s1 忽略 ctx 并一直阻塞，其余样本立即返回
+	hang := make(chan struct{})
+	defer close(hang)
+	results, err := RunSamples(context.Background(), config, newSliceDataset(3), 3, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		if sample.ID == "s1" {
+			<-hang
+		}
+		return &SampleResult{SampleID: sample.ID, Success: true}, nil
+	})
+	if err != nil {
+		t.Fatalf("RunSamples() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if want := i == 1; IsTimeoutResult(r) != want {
+			t.Errorf("results[%d] timeout = %v, want %v (%+v)", i, !want, want, r)
+		}
+	}
+	if results[1].Success || !strings.Contains(results[1].Error, "超时") {
+		t.Errorf("expected timed out sample to fail with timeout error, got %+v", results[1])
+	}
+	if got := CountTimeouts(results); got != 1 {
+		t.Errorf("CountTimeouts() = %d, want 1", got)
+	}
+}
+
+func TestRunSamples_ParentCancelNotTimeout(t *testing.T) {
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithTimeout(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := RunSamples(ctx, config, newSliceDataset(3), 3, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		cancel()
+		<-ctx.Done()
+		return &SampleResult{SampleID: sample.ID, Error: ctx.Err().Error()}, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if CountTimeouts(results) != 0 {
+		t.Errorf("parent cancellation should not be recorded as timeout: %+v", results)
+	}
+}
+
+func TestRunSamples_ResultSink(t *testing.T) {
+	var buf bytes.Buffer
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithConcurrency(3), WithSampleRetries(1), WithResultSink(&buf))
+
+	// s1 首次出错、重试成功，只应以最终结果写入一次
+	var attempts int32
+	results, err := RunSamples(context.Background(), config, newSliceDataset(6), 6, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		if sample.ID == "s1" && atomic.AddInt32(&attempts, 1) == 1 {
+			return &SampleResult{SampleID: sample.ID, Error: "transient"}, errors.New("transient")
+		}
+		return &SampleResult{SampleID: sample.ID, Success: true}, nil
+	})
+	if err != nil {
+		t.Fatalf("RunSamples() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(results) {
+		t.Fatalf("expected %d NDJSON lines, got %d: %q", len(results), len(lines), buf.String())
+	}
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		var r SampleResult
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		if seen[r.SampleID] {
+			t.Errorf("sample %s written twice", r.SampleID)
+		}
+		seen[r.SampleID] = true
+		if r.SampleID == "s1" && (r.Error != "" || !r.Success) {
+			t.Errorf("expected final retried result for s1, got %+v", r)
+		}
+	}
+}
+
+// failingWriter 总是写入失败的 io.Writer
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestRunSamples_ResultSinkWriteError(t *testing.T) {
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithResultSink(failingWriter{}))
+
+	results, err := RunSamples(context.Background(), config, newSliceDataset(3), 3, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		return &SampleResult{SampleID: sample.ID, Success: true}, nil
+	})
+	if err != nil {
+		t.Fatalf("sink write errors should not abort the run, got %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Details["result_sink_error"] != "disk full" {
+			t.Errorf("expected sink error recorded on %s, got %v", r.SampleID, r.Details)
+		}
+	}
+}
+
+func TestRunSamples_ProgressCallbackV2(t *testing.T) {
+	var (
+		legacy   []int
+		dones    []int
+		elapsed  []time.Duration
+		progress = func(done, total int) { legacy = append(legacy, done) }
+	)
+	config := DefaultEvalConfig()
+	config.ApplyOptions(
+		WithConcurrency(2),
+		WithProgressCallback(progress),
+		WithProgressCallbackV2(func(done, total int, d time.Duration) {
+			if total != 5 {
+				t.Errorf("total = %d, want 5", total)
+			}
+			dones = append(dones, done)
+			elapsed = append(elapsed, d)
+		}),
+	)
+
+	_, err := RunSamples(context.Background(), config, newSliceDataset(5), 5, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		time.Sleep(2 * time.Millisecond)
+		return &SampleResult{SampleID: sample.ID}, nil
+	})
+	if err != nil {
+		t.Fatalf("RunSamples() error = %v", err)
+	}
+
+	if len(elapsed) != 5 || len(legacy) != 5 {
+		t.Fatalf("expected 5 callbacks of each kind, got %d V2 and %d legacy", len(elapsed), len(legacy))
+	}
+	for i := range elapsed {
+		if dones[i] != i+1 {
+			t.Errorf("callback %d: done = %d, want %d", i, dones[i], i+1)
+		}
+		if elapsed[i] <= 0 {
+			t.Errorf("callback %d: elapsed = %v, want > 0", i, elapsed[i])
+		}
+		if i > 0 && elapsed[i] < elapsed[i-1] {
+			t.Errorf("elapsed decreased: %v after %v", elapsed[i], elapsed[i-1])
+		}
+	}
+}
+
+func TestRunSamples_CategoryConcurrency(t *testing.T) {
+	dataset := newSliceDataset(24)
+	for i := range dataset.samples {
+		dataset.samples[i].Category = []string{"slow", "fast"}[i%2]
+	}
+
+	config := DefaultEvalConfig()
+	config.ApplyOptions(
+		WithConcurrency(4),
+		WithCategoryConcurrency(map[string]int{"slow": 1, "fast": 2}),
+	)
+
+	var (
+		mu      sync.Mutex
+		running = make(map[string]int)
+		peak    = make(map[string]int)
+		total   int
+		peakAll int
+	)
+	results, err := RunSamples(context.Background(), config, dataset, 24, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		mu.Lock()
+		running[sample.Category]++
+		total++
+		if running[sample.Category] > peak[sample.Category] {
+			peak[sample.Category] = running[sample.Category]
+		}
+		if total > peakAll {
+			peakAll = total
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running[sample.Category]--
+		total--
+		mu.Unlock()
+		return &SampleResult{SampleID: sample.ID, Category: sample.Category}, nil
+	})
+	if err != nil {
+		t.Fatalf("RunSamples() error = %v", err)
+	}
+	if len(results) != 24 {
+		t.Fatalf("expected 24 results, got %d", len(results))
+	}
+	if peak["slow"] > 1 || peak["fast"] > 2 || peakAll > 4 {
+		t.Errorf("peak concurrency = %v (all %d), want slow <= 1, fast <= 2, all <= 4", peak, peakAll)
+	}
+	if peak["fast"] < 2 {
+		t.Errorf("peak fast concurrency = %d, want the cap of 2 to be reached", peak["fast"])
+	}
+}
+
+func TestRunSamples_CategoryConcurrency_SkipsSaturatedCategory(t *testing.T) {
+	// 连续的受限类别样本排在其他类别之前
+	dataset := newSliceDataset(12)
+	for i := range dataset.samples {
+		dataset.samples[i].Category = "A"
+		if i >= 6 {
+			dataset.samples[i].Category = "B"
+		}
+	}
+
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithConcurrency(4), WithCategoryConcurrency(map[string]int{"A": 1}))
+
+	var (
+		mu         sync.Mutex
+		seq        int
+		lastAStart int
+		lastBEnd   int
+		running    int
+		peakA      int
+	)
+	_, err := RunSamples(context.Background(), config, dataset, 12, func(ctx context.Context, sample Sample) (*SampleResult, error) {
+		mu.Lock()
+		seq++
+		if sample.Category == "A" {
+			lastAStart = seq
+			running++
+			if running > peakA {
+				peakA = running
+			}
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		seq++
+		if sample.Category == "A" {
+			running--
+		} else {
+			lastBEnd = seq
+		}
+		mu.Unlock()
+		return &SampleResult{SampleID: sample.ID, Category: sample.Category}, nil
+	})
+	if err != nil {
+		t.Fatalf("RunSamples() error = %v", err)
+	}
+	if peakA > 1 {
+		t.Errorf("peak A concurrency = %d, want <= 1", peakA)
+	}
+	// 类别 A 名额已满时空闲 worker 先处理后面的 B 样本，而不是等待 A 的名额
+	if lastBEnd > lastAStart {
+		t.Errorf("B samples finished at step %d after the last A sample started at step %d; workers waited on the saturated category",
+			lastBEnd, lastAStart)
+	}
+}
@@ -0,0 +1,151 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// SelectSamples 按配置从数据集中选出待评估的样本
+//
+// 依次应用 Shuffle（按种子可复现地打乱顺序）、StratifiedSampling（按分层比例抽取）
+// 和 MaxSamples 截断，返回仅包含选中样本的数据集视图。数据集需已加载。
+func SelectSamples(config *EvalConfig, dataset Dataset) Dataset {
+	total := dataset.Len()
+	order := make([]int, total)
+	for i := range order {
+		order[i] = i
+	}
+
+	if config.Shuffle {
+		rng := rand.New(rand.NewSource(config.ShuffleSeed))
+		rng.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+
+	n := total
+	if config.MaxSamples > 0 && config.MaxSamples < total {
+		n = config.MaxSamples
+	}
+
+	if n < total {
+		if config.StratifiedSampling {
+			order = stratify(dataset, order, n)
+		} else {
+			order = order[:n]
+		}
+	}
+
+	return &indexedDataset{base: dataset, indices: order}
+}
+
+// stratify 按样本分层比例从 order 中抽取 n 个样本，保持 order 中的相对顺序
+//
+// 各层配额按最大余数法分配；同一层内按 order 顺序取前若干个。
+func stratify(dataset Dataset, order []int, n int) []int {
+	total := len(order)
+
+	// 统计各层样本数（按首次出现顺序记录层，保证结果确定）
+	keys := make([]string, total)
+	counts := make(map[string]int)
+	var strata []string
+	for pos, idx := range order {
+		key := ""
+		if sample, err := dataset.Get(idx); err == nil {
+			key = sampleStratum(sample)
+		}
+		keys[pos] = key
+		if counts[key] == 0 {
+			strata = append(strata, key)
+		}
+		counts[key]++
+	}
+
+	// 最大余数法分配配额
+	quotas := make(map[string]int, len(strata))
+	remainders := make([]float64, len(strata))
+	allocated := 0
+	for i, key := range strata {
+		exact := float64(n) * float64(counts[key]) / float64(total)
+		quotas[key] = int(exact)
+		remainders[i] = exact - float64(quotas[key])
+		allocated += quotas[key]
+	}
+	ranked := make([]int, len(strata))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return remainders[ranked[a]] > remainders[ranked[b]]
+	})
+	for i := 0; allocated < n; i++ {
+		quotas[strata[ranked[i%len(ranked)]]]++
+		allocated++
+	}
+
+	selected := make([]int, 0, n)
+	for pos, idx := range order {
+		if quotas[keys[pos]] > 0 {
+			quotas[keys[pos]]--
+			selected = append(selected, idx)
+		}
+	}
+	return selected
+}
+
+// sampleStratum 返回样本的分层键：优先使用 Category（GAIA 为 level_N），否则使用 Level
+func sampleStratum(sample Sample) string {
+	if sample.Category != "" {
+		return sample.Category
+	}
+	if sample.Level > 0 {
+		return "level_" + strconv.Itoa(sample.Level)
+	}
+	return ""
+}
+
+// indexedDataset 按索引列表呈现底层数据集的视图
+type indexedDataset struct {
+	base    Dataset
+	indices []int
+}
+
+// Load 加载底层数据集
+func (d *indexedDataset) Load(ctx context.Context) error {
+	return d.base.Load(ctx)
+}
+
+// Len 返回选中的样本数
+func (d *indexedDataset) Len() int {
+	return len(d.indices)
+}
+
+// Get 根据视图索引获取样本
+func (d *indexedDataset) Get(index int) (Sample, error) {
+	if index < 0 || index >= len(d.indices) {
+		return Sample{}, fmt.Errorf("索引越界: %d", index)
+	}
+	return d.base.Get(d.indices[index])
+}
+
+// Iterator 按视图顺序返回样本迭代器
+func (d *indexedDataset) Iterator() <-chan Sample {
+	ch := make(chan Sample)
+	go func() {
+		defer close(ch)
+		for _, idx := range d.indices {
+			if sample, err := d.base.Get(idx); err == nil {
+				ch <- sample
+			}
+		}
+	}()
+	return ch
+}
+
+// Name 返回底层数据集名称
+func (d *indexedDataset) Name() string {
+	return d.base.Name()
+}
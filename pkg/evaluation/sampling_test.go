@@ -0,0 +1,86 @@
+package evaluation
+
+import (
+	"fmt"
+	"testing"
+)
+
+// sampleIDs 返回数据集视图中的样本 ID 顺序
+func sampleIDs(t *testing.T, d Dataset) []string {
+	t.Helper()
+	ids := make([]string, d.Len())
+	for i := range ids {
+		sample, err := d.Get(i)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", i, err)
+		}
+		ids[i] = sample.ID
+	}
+	return ids
+}
+
+func TestSelectSamples_ShuffleDeterministic(t *testing.T) {
+	dataset := newSliceDataset(20)
+
+	first := DefaultEvalConfig()
+	first.ApplyOptions(WithShuffle(42), WithMaxSamples(10))
+	second := DefaultEvalConfig()
+	second.ApplyOptions(WithShuffle(42), WithMaxSamples(10))
+	other := DefaultEvalConfig()
+	other.ApplyOptions(WithShuffle(7), WithMaxSamples(10))
+
+	a := sampleIDs(t, SelectSamples(first, dataset))
+	b := sampleIDs(t, SelectSamples(second, dataset))
+	c := sampleIDs(t, SelectSamples(other, dataset))
+
+	if len(a) != 10 {
+		t.Fatalf("expected 10 samples, got %d", len(a))
+	}
+	if fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Errorf("same seed produced different orders: %v vs %v", a, b)
+	}
+	if fmt.Sprint(a) == fmt.Sprint(c) {
+		t.Errorf("different seeds produced the same order: %v", a)
+	}
+	if fmt.Sprint(a) == fmt.Sprint(sampleIDs(t, SelectSamples(DefaultEvalConfig(), dataset))[:10]) {
+		t.Errorf("expected shuffled order to differ from file order: %v", a)
+	}
+}
+
+func TestSelectSamples_StratifiedPreservesLevels(t *testing.T) {
+	// 60% level 1、30% level 2、10% level 3，按级别分块排列
+	dataset := &sliceDataset{}
+	for i := 0; i < 100; i++ {
+		level := 1
+		switch {
+		case i >= 90:
+			level = 3
+		case i >= 60:
+			level = 2
+		}
+		dataset.samples = append(dataset.samples, Sample{
+			ID:       fmt.Sprintf("s%d", i),
+			Level:    level,
+			Category: fmt.Sprintf("level_%d", level),
+		})
+	}
+
+	config := DefaultEvalConfig()
+	config.ApplyOptions(WithMaxSamples(20), WithStratifiedSampling(true), WithShuffle(1))
+	selected := SelectSamples(config, dataset)
+	if selected.Len() != 20 {
+		t.Fatalf("expected 20 samples, got %d", selected.Len())
+	}
+
+	counts := make(map[int]int)
+	for i := 0; i < selected.Len(); i++ {
+		sample, _ := selected.Get(i)
+		counts[sample.Level]++
+	}
+	want := map[int]int{1: 12, 2: 6, 3: 2}
+	for level, n := range want {
+		if counts[level] != n {
+			t.Errorf("level %d: got %d samples, want %d (counts %v)", level, counts[level], n, counts)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package evaluation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// groundTruthSource 支持按样本 ID 查询 ground truth 的数据集（如 BFCL）
+type groundTruthSource interface {
+	GetGroundTruth(sampleID string) (interface{}, bool)
+}
+
+// ShuffleDataset 返回按种子可复现地打乱顺序的数据集视图
+//
+// 数据集需已加载。
+func ShuffleDataset(dataset Dataset, seed int64) Dataset {
+	return &splitDataset{
+		indexedDataset: indexedDataset{base: dataset, indices: shuffledIndices(dataset.Len(), seed)},
+		name:           dataset.Name(),
+	}
+}
+
+// SplitDataset 将数据集按比例划分为两个互不重叠的数据集视图（如 train/dev）
+//
+// 按 seed 打乱样本索引后，前 round(ratio*n) 个样本划入第一个数据集，其余划入第二个；
+// 相同种子得到相同划分。ratio 需在 (0, 1) 之间，且两个划分均不能为空。
+// 划分后的数据集通过底层数据集获取样本，BFCL 等附加的 ground truth 保持可用，
+// 并可通过 GetGroundTruth 查询。数据集需已加载。
+func SplitDataset(dataset Dataset, ratio float64, seed int64) (Dataset, Dataset, error) {
+	if ratio <= 0 || ratio >= 1 || math.IsNaN(ratio) {
+		return nil, nil, fmt.Errorf("划分比例必须在 0 到 1 之间: %v", ratio)
+	}
+
+	total := dataset.Len()
+	n := int(math.Round(ratio * float64(total)))
+	if n == 0 || n == total {
+		return nil, nil, fmt.Errorf("数据集样本数 %d 不足以按比例 %v 划分", total, ratio)
+	}
+
+	order := shuffledIndices(total, seed)
+	first := &splitDataset{
+		indexedDataset: indexedDataset{base: dataset, indices: order[:n:n]},
+		name:           dataset.Name() + "_train",
+	}
+	second := &splitDataset{
+		indexedDataset: indexedDataset{base: dataset, indices: order[n:]},
+		name:           dataset.Name() + "_dev",
+	}
+	return first, second, nil
+}
+
+// shuffledIndices 返回按种子打乱的 [0, n) 索引
+func shuffledIndices(n int, seed int64) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(n, func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+	return order
+}
+
+// splitDataset 打乱或划分得到的数据集视图
+type splitDataset struct {
+	indexedDataset
+	name string
+}
+
+// Name 返回划分后的数据集名称
+func (d *splitDataset) Name() string {
+	return d.name
+}
+
+// GetGroundTruth 从底层数据集查询 ground truth（底层数据集不支持时返回 false）
+func (d *splitDataset) GetGroundTruth(sampleID string) (interface{}, bool) {
+	source, ok := d.base.(groundTruthSource)
+	if !ok {
+		return nil, false
+	}
+	return source.GetGroundTruth(sampleID)
+}
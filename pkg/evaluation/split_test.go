@@ -0,0 +1,84 @@
+package evaluation
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSplitDataset(t *testing.T) {
+	dataset := newSliceDataset(25)
+
+	train, dev, err := SplitDataset(dataset, 0.8, 42)
+	if err != nil {
+		t.Fatalf("SplitDataset() error = %v", err)
+	}
+	if train.Len() != 20 || dev.Len() != 5 {
+		t.Fatalf("sizes = %d/%d, want 20/5", train.Len(), dev.Len())
+	}
+
+	// 两个划分互不重叠且覆盖全部样本
+	seen := make(map[string]bool)
+	for _, d := range []Dataset{train, dev} {
+		for _, id := range sampleIDs(t, d) {
+			if seen[id] {
+				t.Errorf("sample %s appears in both splits", id)
+			}
+			seen[id] = true
+		}
+	}
+	if len(seen) != dataset.Len() {
+		t.Errorf("splits cover %d samples, want %d", len(seen), dataset.Len())
+	}
+
+	// Iterator 与 Get 顺序一致
+	var iterated []string
+	for sample := range dev.Iterator() {
+		iterated = append(iterated, sample.ID)
+	}
+	if fmt.Sprint(iterated) != fmt.Sprint(sampleIDs(t, dev)) {
+		t.Errorf("Iterator() = %v, want %v", iterated, sampleIDs(t, dev))
+	}
+
+	if train.Name() != "slice_train" || dev.Name() != "slice_dev" {
+		t.Errorf("names = %s/%s", train.Name(), dev.Name())
+	}
+	if _, err := dev.Get(dev.Len()); err == nil {
+		t.Error("expected out-of-range error")
+	}
+}
+
+func TestSplitDataset_Reproducible(t *testing.T) {
+	dataset := newSliceDataset(30)
+
+	trainA, devA, err := SplitDataset(dataset, 0.7, 7)
+	if err != nil {
+		t.Fatalf("SplitDataset() error = %v", err)
+	}
+	trainB, devB, _ := SplitDataset(dataset, 0.7, 7)
+	trainC, _, _ := SplitDataset(dataset, 0.7, 8)
+
+	if fmt.Sprint(sampleIDs(t, trainA)) != fmt.Sprint(sampleIDs(t, trainB)) ||
+		fmt.Sprint(sampleIDs(t, devA)) != fmt.Sprint(sampleIDs(t, devB)) {
+		t.Error("same seed produced different splits")
+	}
+	if fmt.Sprint(sampleIDs(t, trainA)) == fmt.Sprint(sampleIDs(t, trainC)) {
+		t.Error("different seeds produced identical splits")
+	}
+
+	shuffled := ShuffleDataset(dataset, 7)
+	if shuffled.Len() != dataset.Len() {
+		t.Errorf("ShuffleDataset() Len = %d, want %d", shuffled.Len(), dataset.Len())
+	}
+	if fmt.Sprint(sampleIDs(t, shuffled)[:trainA.Len()]) != fmt.Sprint(sampleIDs(t, trainA)) {
+		t.Error("split should take the prefix of the same-seed shuffle")
+	}
+}
+
+func TestSplitDataset_InvalidRatio(t *testing.T) {
+	dataset := newSliceDataset(10)
+	for _, ratio := range []float64{0, 1, -0.5, 1.5, 0.01} {
+		if _, _, err := SplitDataset(dataset, ratio, 1); err == nil {
+			t.Errorf("ratio %v: expected error", ratio)
+		}
+	}
+}
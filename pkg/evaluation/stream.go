@@ -0,0 +1,123 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+)
+
+// StreamingAgent 增量输出响应的智能体（可选接口）
+//
+// agents.Agent 都带有 RunStream，但多数实现只是在 Run 结束后一次性转发结果。
+// 智能体实现该接口且 Streaming 返回 true 时，评估器改为消费 RunStream，
+// 记录首 token 时间与总耗时，并以拼接的文本块作为最终响应评分。
+type StreamingAgent interface {
+	agents.Agent
+
+	// Streaming 返回 RunStream 是否增量输出
+	Streaming() bool
+}
+
+// StreamTiming 一次流式执行的耗时统计
+type StreamTiming struct {
+	// TimeToFirstToken 首个非空文本块的到达时间（未收到文本块时为 0）
+	TimeToFirstToken time.Duration
+
+	// Total 流结束的总耗时
+	Total time.Duration
+
+	// Chunks 收到的文本块数
+	Chunks int
+}
+
+// RunStreaming 以流式方式执行智能体，并将输出块组装为 agents.Output
+//
+// 文本块按顺序拼接为 Response，推理步骤块收集到 Steps；错误块或错误通道中的错误
+// 作为 error 返回。ctx 取消时立即返回 ctx.Err()。
+func RunStreaming(ctx context.Context, agent agents.Agent, input agents.Input) (agents.Output, StreamTiming, error) {
+	start := time.Now()
+	chunks, errs := agent.RunStream(ctx, input)
+
+	var (
+		output   agents.Output
+		timing   StreamTiming
+		response strings.Builder
+		chunkErr error
+	)
+	for chunks != nil {
+		select {
+		case <-ctx.Done():
+			return agents.Output{}, timing, ctx.Err()
+		case chunk, ok := <-chunks:
+			if !ok {
+				chunks = nil
+				break
+			}
+			switch chunk.Type {
+			case agents.ChunkTypeText:
+				if chunk.Content == "" {
+					continue
+				}
+				if timing.Chunks == 0 {
+					timing.TimeToFirstToken = time.Since(start)
+				}
+				timing.Chunks++
+				response.WriteString(chunk.Content)
+			case agents.ChunkTypeStep:
+				if chunk.Step != nil {
+					output.Steps = append(output.Steps, *chunk.Step)
+				}
+			case agents.ChunkTypeError:
+				if chunkErr == nil {
+					chunkErr = errors.New(chunk.Content)
+				}
+			}
+		}
+	}
+
+	// 输出块通道关闭后错误通道至多还有一个错误
+	select {
+	case err := <-errs:
+		if err != nil {
+			chunkErr = err
+		}
+	case <-ctx.Done():
+		return agents.Output{}, timing, ctx.Err()
+	}
+
+	timing.Total = time.Since(start)
+	output.Response = response.String()
+	output.Duration = timing.Total
+	if chunkErr != nil {
+		output.Error = chunkErr.Error()
+		return output, timing, chunkErr
+	}
+	return output, timing, nil
+}
+
+// streamingAgent 返回启用了流式输出的智能体
+func streamingAgent(agent agents.Agent) (StreamingAgent, bool) {
+	streaming, ok := agent.(StreamingAgent)
+	return streaming, ok && streaming.Streaming()
+}
+
+// recordStreamTiming 将流式耗时写入样本结果的 Details
+func recordStreamTiming(result *SampleResult, timing StreamTiming) {
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	result.Details["streamed"] = true
+	result.Details["stream_chunks"] = timing.Chunks
+	result.Details["stream_latency_ms"] = durationMillis(timing.Total)
+	if timing.Chunks > 0 {
+		result.Details["time_to_first_token_ms"] = durationMillis(timing.TimeToFirstToken)
+	}
+}
+
+// durationMillis 将时长转换为毫秒（保留小数）
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,81 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/config"
+)
+
+// chunkAgent 依次发送预设输出块的流式智能体
+type chunkAgent struct {
+	chunks []agents.StreamChunk
+	err    error
+}
+
+func (a *chunkAgent) Name() string               { return "chunks" }
+func (a *chunkAgent) Config() config.AgentConfig { return config.AgentConfig{} }
+func (a *chunkAgent) Streaming() bool            { return true }
+
+func (a *chunkAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	return agents.Output{}, errors.New("Run should not be called")
+}
+
+func (a *chunkAgent) RunStream(ctx context.Context, input agents.Input) (<-chan agents.StreamChunk, <-chan error) {
+	ch := make(chan agents.StreamChunk, len(a.chunks))
+	errCh := make(chan error, 1)
+	for _, c := range a.chunks {
+		ch <- c
+	}
+	if a.err != nil {
+		errCh <- a.err
+	}
+	close(ch)
+	close(errCh)
+	return ch, errCh
+}
+
+func TestRunStreaming(t *testing.T) {
+	step := agents.ReasoningStep{}
+	agent := &chunkAgent{chunks: []agents.StreamChunk{
+		{Type: agents.ChunkTypeStep, Step: &step},
+		{Type: agents.ChunkTypeText, Content: ""},
+		{Type: agents.ChunkTypeText, Content: "The answer "},
+		{Type: agents.ChunkTypeText, Content: "is 42"},
+		{Type: agents.ChunkTypeDone, Done: true},
+	}}
+
+	output, timing, err := RunStreaming(context.Background(), agent, agents.Input{Query: "q"})
+	if err != nil {
+		t.Fatalf("RunStreaming() error = %v", err)
+	}
+	if output.Response != "The answer is 42" || len(output.Steps) != 1 {
+		t.Errorf("output = %+v, want assembled response and one step", output)
+	}
+	if timing.Chunks != 2 || timing.Total < timing.TimeToFirstToken {
+		t.Errorf("timing = %+v, want 2 chunks and Total >= TTFT", timing)
+	}
+
+	agent.err = errors.New("backend failed")
+	if _, _, err := RunStreaming(context.Background(), agent, agents.Input{Query: "q"}); err == nil || err.Error() != "backend failed" {
+		t.Errorf("RunStreaming() error = %v, want backend failed", err)
+	}
+}
+
+func TestSampleRunner_RunSample_Streaming(t *testing.T) {
+	agent := &chunkAgent{chunks: []agents.StreamChunk{{Type: agents.ChunkTypeText, Content: "ok"}}}
+	result := &SampleResult{SampleID: "s0"}
+
+	output, err := NewSampleRunner(agent).RunSample(context.Background(), Sample{ID: "s0"}, agents.Input{Query: "q"}, result)
+	if err != nil || output.Response != "ok" {
+		t.Fatalf("RunSample() = %q, %v; want ok", output.Response, err)
+	}
+	if result.Details["streamed"] != true || result.Details["stream_chunks"] != 1 {
+		t.Errorf("Details = %v, want streamed with 1 chunk", result.Details)
+	}
+	if _, ok := result.Details["time_to_first_token_ms"].(float64); !ok {
+		t.Errorf("time_to_first_token_ms missing: %v", result.Details)
+	}
+}
@@ -0,0 +1,82 @@
+package evaluation
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SuiteResult 多基准评估结果
+//
+// 汇总同一个智能体在多个基准上的评估结果，键为基准名称。
+type SuiteResult struct {
+	// AgentName 智能体名称
+	AgentName string `json:"agent_name"`
+
+	// Results 各基准评估结果
+	Results map[string]*EvalResult `json:"results"`
+
+	// Errors 各基准的失败原因（评估未完成的基准）
+	Errors map[string]string `json:"errors,omitempty"`
+
+	// TotalDuration 总执行时间
+	TotalDuration time.Duration `json:"total_duration"`
+
+	// EvaluationTime 评估时间戳
+	EvaluationTime time.Time `json:"evaluation_time"`
+}
+
+// NewSuiteResult 创建多基准评估结果
+func NewSuiteResult(agentName string) *SuiteResult {
+	return &SuiteResult{
+		AgentName:      agentName,
+		Results:        make(map[string]*EvalResult),
+		Errors:         make(map[string]string),
+		EvaluationTime: time.Now(),
+	}
+}
+
+// Add 添加一个基准的评估结果
+func (s *SuiteResult) Add(name string, result *EvalResult) {
+	if s.Results == nil {
+		s.Results = make(map[string]*EvalResult)
+	}
+	s.Results[name] = result
+}
+
+// AddError 记录一个基准的评估失败
+func (s *SuiteResult) AddError(name string, err error) {
+	if s.Errors == nil {
+		s.Errors = make(map[string]string)
+	}
+	s.Errors[name] = err.Error()
+}
+
+// BenchmarkNames 返回已完成评估的基准名称（按字母排序）
+func (s *SuiteResult) BenchmarkNames() []string {
+	names := make([]string, 0, len(s.Results))
+	for name := range s.Results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CapabilityOf 返回基准对应的能力维度名称
+//
+// 已知基准映射到能力维度（如 BFCL → function-calling），未知基准返回原名称。
+func CapabilityOf(benchmarkName string) string {
+	lower := strings.ToLower(benchmarkName)
+	switch {
+	case strings.HasPrefix(lower, "bfcl"):
+		return "function-calling"
+	case strings.HasPrefix(lower, "gaia"):
+		return "general-assistant"
+	case strings.HasPrefix(lower, "llmjudge"), strings.HasPrefix(lower, "llm_judge"),
+		strings.HasPrefix(lower, "winrate"), strings.HasPrefix(lower, "win_rate"),
+		strings.HasPrefix(lower, "datagen"):
+		return "data-quality"
+	default:
+		return benchmarkName
+	}
+}
@@ -0,0 +1,191 @@
+package evaluation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TableProgress 命令行实时进度表
+//
+// 包装 ProgressCallback，并根据定期提供的分类别指标快照重绘进度表。
+// 输出目标为终端时使用 ANSI 光标移动原地重绘；否则（重定向到文件、管道或缓冲区）
+// 退化为逐行追加的纯文本，每次进度更新输出一行。
+type TableProgress struct {
+	mu         sync.Mutex
+	w          io.Writer
+	next       ProgressCallback
+	tty        bool
+	done       int
+	total      int
+	categories map[string]*CategoryMetrics
+	drawn      int
+}
+
+// NewTableProgress 创建实时进度表
+//
+// 参数:
+//   - w: 输出目标
+//   - next: 被包装的进度回调（可为 nil），每次进度更新时先调用
+func NewTableProgress(w io.Writer, next ProgressCallback) *TableProgress {
+	return &TableProgress{
+		w:    w,
+		next: next,
+		tty:  isTerminal(w),
+	}
+}
+
+// Callback 返回可传给 WithProgressCallback 的进度回调
+func (p *TableProgress) Callback() ProgressCallback {
+	return p.Progress
+}
+
+// Progress 更新完成数并重绘进度表
+func (p *TableProgress) Progress(done, total int) {
+	if p.next != nil {
+		p.next(done, total)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done, p.total = done, total
+	p.render()
+}
+
+// Update 更新分类别指标快照
+//
+// 快照会被复制，调用方可在之后继续修改传入的指标。终端模式下立即重绘；
+// 纯文本模式下快照在下一次进度更新时输出，避免重复追加相同的进度行。
+func (p *TableProgress) Update(categories map[string]*CategoryMetrics) {
+	snapshot := make(map[string]*CategoryMetrics, len(categories))
+	for name, cm := range categories {
+		if cm == nil {
+			continue
+		}
+		c := *cm
+		snapshot[name] = &c
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.categories = snapshot
+	if p.tty {
+		p.render()
+	}
+}
+
+// Finish 输出最终的完整进度表与合计
+//
+// 终端模式下在原位置重绘最后一次；纯文本模式下追加完整的表格。
+func (p *TableProgress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lines := p.tableLines()
+	if p.tty {
+		p.redraw(lines)
+	} else {
+		fmt.Fprint(p.w, strings.Join(lines, "\n")+"\n")
+	}
+	p.drawn = 0
+}
+
+// render 按输出模式刷新进度
+func (p *TableProgress) render() {
+	if p.tty {
+		p.redraw(p.tableLines())
+		return
+	}
+	fmt.Fprintln(p.w, p.plainLine())
+}
+
+// redraw 将光标移回上次绘制的起始行，清除其后内容并重新绘制
+func (p *TableProgress) redraw(lines []string) {
+	var sb strings.Builder
+	if p.drawn > 0 {
+		fmt.Fprintf(&sb, "\x1b[%dF\x1b[J", p.drawn)
+	}
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	io.WriteString(p.w, sb.String())
+	p.drawn = len(lines)
+}
+
+// tableLines 生成进度表各行
+func (p *TableProgress) tableLines() []string {
+	lines := []string{p.progressText()}
+	names := p.sortedCategories()
+	if len(names) == 0 {
+		return lines
+	}
+
+	width := utf8.RuneCountInString("合计")
+	for _, name := range names {
+		if n := utf8.RuneCountInString(name); n > width {
+			width = n
+		}
+	}
+
+	lines = append(lines, fmt.Sprintf("%-*s %6s %6s %8s", width, "类别", "总数", "成功数", "准确率"))
+	var total, success int
+	for _, name := range names {
+		cm := p.categories[name]
+		total += cm.Total
+		success += cm.Success
+		lines = append(lines, fmt.Sprintf("%-*s %6d %6d %7.2f%%", width, name, cm.Total, cm.Success, cm.Accuracy*100))
+	}
+	lines = append(lines, fmt.Sprintf("%-*s %6d %6d %7.2f%%", width, "合计", total, success, ratio(success, total)*100))
+	return lines
+}
+
+// plainLine 生成纯文本模式下的单行进度
+func (p *TableProgress) plainLine() string {
+	parts := []string{p.progressText()}
+	for _, name := range p.sortedCategories() {
+		cm := p.categories[name]
+		parts = append(parts, fmt.Sprintf("%s %d/%d (%.2f%%)", name, cm.Success, cm.Total, cm.Accuracy*100))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// progressText 生成完成进度文本
+func (p *TableProgress) progressText() string {
+	return fmt.Sprintf("进度 %d/%d (%.1f%%)", p.done, p.total, ratio(p.done, p.total)*100)
+}
+
+// sortedCategories 返回按名称排序的类别
+func (p *TableProgress) sortedCategories() []string {
+	names := make([]string, 0, len(p.categories))
+	for name := range p.categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ratio 计算比率，分母为 0 时返回 0
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// isTerminal 判断输出目标是否为终端
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
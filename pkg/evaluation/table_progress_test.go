@@ -0,0 +1,83 @@
+package evaluation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTableProgress_PlainFallback(t *testing.T) {
+	var buf bytes.Buffer
+	var calls int
+	p := NewTableProgress(&buf, func(done, total int) { calls++ })
+	if p.tty {
+		t.Fatal("bytes.Buffer should not be detected as a terminal")
+	}
+
+	p.Progress(1, 4)
+	p.Update(map[string]*CategoryMetrics{
+		"math": {Category: "math", Total: 1, Success: 1, Accuracy: 1},
+	})
+	p.Progress(2, 4)
+	p.Update(map[string]*CategoryMetrics{
+		"math":    {Category: "math", Total: 2, Success: 1, Accuracy: 0.5},
+		"history": {Category: "history", Total: 2, Success: 2, Accuracy: 1},
+	})
+	p.Progress(4, 4)
+	p.Finish()
+
+	out := buf.String()
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("plain output should not contain ANSI escapes: %q", out)
+	}
+	if calls != 3 {
+		t.Errorf("wrapped callback calls = %d, want 3", calls)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	wantPrefix := []string{
+		"进度 1/4 (25.0%)",
+		"进度 2/4 (50.0%) | math 1/1 (100.00%)",
+		"进度 4/4 (100.0%) | history 2/2 (100.00%) | math 1/2 (50.00%)",
+	}
+	if len(lines) < len(wantPrefix) {
+		t.Fatalf("got %d lines, want at least %d:\n%s", len(lines), len(wantPrefix), out)
+	}
+	for i, want := range wantPrefix {
+		if lines[i] != want {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want)
+		}
+	}
+
+	table := lines[len(wantPrefix):]
+	if len(table) != 5 {
+		t.Fatalf("final table has %d lines, want 5:\n%s", len(table), strings.Join(table, "\n"))
+	}
+	if table[0] != "进度 4/4 (100.0%)" {
+		t.Errorf("table header = %q", table[0])
+	}
+	total := strings.Fields(table[len(table)-1])
+	if want := []string{"合计", "4", "3", "75.00%"}; strings.Join(total, " ") != strings.Join(want, " ") {
+		t.Errorf("totals row = %v, want %v", total, want)
+	}
+}
+
+func TestTableProgress_TerminalRedraw(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewTableProgress(&buf, nil)
+	p.tty = true
+
+	p.Progress(1, 2)
+	p.Update(map[string]*CategoryMetrics{
+		"math": {Category: "math", Total: 1, Success: 1, Accuracy: 1},
+	})
+
+	out := buf.String()
+	// 首次绘制 1 行，第二次绘制前须回退这 1 行
+	if !strings.Contains(out, "\x1b[1F\x1b[J") {
+		t.Errorf("expected cursor to move back over the previous frame: %q", out)
+	}
+	if p.drawn != 4 {
+		t.Errorf("drawn = %d, want 4", p.drawn)
+	}
+}
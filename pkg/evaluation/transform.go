@@ -0,0 +1,116 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SampleTransform 样本变换函数
+//
+// 返回变换后的样本及是否保留该样本（false 表示丢弃）。
+type SampleTransform func(Sample) (Sample, bool)
+
+// mappedDataset 对底层数据集惰性应用变换的包装
+type mappedDataset struct {
+	base Dataset
+	fn   SampleTransform
+
+	mu      sync.Mutex
+	indices []int // 保留样本在底层数据集中的索引，nil 表示尚未构建
+}
+
+// MapDataset 返回对数据集惰性应用变换的包装
+//
+// 变换在 Get/Iterator 时执行，不修改底层数据；fn 返回 false 的样本被丢弃，
+// Len 返回保留的样本数。可多次嵌套以串联多个变换。
+func MapDataset(d Dataset, fn SampleTransform) Dataset {
+	return &mappedDataset{base: d, fn: fn}
+}
+
+// SamplePredicate 样本过滤条件
+type SamplePredicate func(Sample) bool
+
+// FilterDataset 返回仅呈现满足 pred 的样本的数据集包装
+//
+// 过滤惰性执行，Get/Len/Iterator 的结果保持一致，Get 索引连续。
+func FilterDataset(d Dataset, pred SamplePredicate) Dataset {
+	return MapDataset(d, func(s Sample) (Sample, bool) {
+		return s, pred(s)
+	})
+}
+
+// Load 加载底层数据集并重置索引
+func (m *mappedDataset) Load(ctx context.Context) error {
+	if err := m.base.Load(ctx); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.indices = nil
+	m.mu.Unlock()
+	return nil
+}
+
+// Len 返回保留的样本数
+func (m *mappedDataset) Len() int {
+	return len(m.keptIndices())
+}
+
+// Get 根据索引获取变换后的样本
+func (m *mappedDataset) Get(index int) (Sample, error) {
+	indices := m.keptIndices()
+	if index < 0 || index >= len(indices) {
+		return Sample{}, fmt.Errorf("索引越界: %d", index)
+	}
+
+	sample, err := m.base.Get(indices[index])
+	if err != nil {
+		return Sample{}, err
+	}
+	sample, _ = m.fn(sample)
+	return sample, nil
+}
+
+// Iterator 返回变换后的样本迭代器
+func (m *mappedDataset) Iterator() <-chan Sample {
+	ch := make(chan Sample)
+	go func() {
+		defer close(ch)
+		for sample := range m.base.Iterator() {
+			if mapped, keep := m.fn(sample); keep {
+				ch <- mapped
+			}
+		}
+	}()
+	return ch
+}
+
+// Name 返回底层数据集名称
+func (m *mappedDataset) Name() string {
+	return m.base.Name()
+}
+
+// keptIndices 返回保留样本的底层索引（首次调用时构建）
+//
+// 底层 Get 失败的样本予以保留，由 Get 返回原始错误。
+func (m *mappedDataset) keptIndices() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.indices != nil {
+		return m.indices
+	}
+
+	indices := make([]int, 0, m.base.Len())
+	for i := 0; i < m.base.Len(); i++ {
+		sample, err := m.base.Get(i)
+		if err == nil {
+			if _, keep := m.fn(sample); !keep {
+				continue
+			}
+		}
+		indices = append(indices, i)
+	}
+	m.indices = indices
+	return indices
+}
@@ -0,0 +1,70 @@
+package evaluation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapDataset(t *testing.T) {
+	base := newSliceDataset(4)
+
+	upper := MapDataset(base, func(s Sample) (Sample, bool) {
+		s.Input = strings.ToUpper(s.Input)
+		return s, s.ID != "s1"
+	})
+	templated := MapDataset(upper, func(s Sample) (Sample, bool) {
+		s.Input = "Q: " + s.Input
+		return s, true
+	})
+
+	if templated.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", templated.Len())
+	}
+
+	wantIDs := []string{"s0", "s2", "s3"}
+	for i, id := range wantIDs {
+		sample, err := templated.Get(i)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", i, err)
+		}
+		if sample.ID != id {
+			t.Errorf("Get(%d).ID = %s, want %s", i, sample.ID, id)
+		}
+		if !strings.HasPrefix(sample.Input, "Q: INPUT") {
+			t.Errorf("Get(%d).Input = %q, want transformed input", i, sample.Input)
+		}
+	}
+
+	if _, err := templated.Get(3); err == nil {
+		t.Error("expected out-of-range error")
+	}
+
+	count := 0
+	for range templated.Iterator() {
+		count++
+	}
+	if count != 3 {
+		t.Errorf("Iterator yielded %d samples, want 3", count)
+	}
+
+	// 底层数据不被修改
+	if original, _ := base.Get(0); original.Input != "input 0" {
+		t.Errorf("base dataset mutated: %q", original.Input)
+	}
+}
+
+func TestFilterDataset(t *testing.T) {
+	filtered := FilterDataset(newSliceDataset(5), func(s Sample) bool {
+		return s.ID != "s0" && s.ID != "s3"
+	})
+
+	if filtered.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", filtered.Len())
+	}
+	for i, id := range []string{"s1", "s2", "s4"} {
+		sample, err := filtered.Get(i)
+		if err != nil || sample.ID != id {
+			t.Errorf("Get(%d) = %s, %v; want %s", i, sample.ID, err, id)
+		}
+	}
+}
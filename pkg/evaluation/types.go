@@ -1,7 +1,10 @@
 package evaluation
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/core/message"
 )
 
 // Sample 评估样本
@@ -65,6 +68,9 @@ type SampleResult struct {
 	// Score 评分（0-1 或其他范围）
 	Score float64 `json:"score"`
 
+	// Confidence 匹配置信度（0-1），反映预测与期望的接近程度
+	Confidence float64 `json:"confidence,omitempty"`
+
 	// Category 样本类别
 	Category string `json:"category,omitempty"`
 
@@ -82,6 +88,51 @@ type SampleResult struct {
 
 	// AgentResponse 智能体原始响应
 	AgentResponse string `json:"agent_response,omitempty"`
+
+	// PromptTokens 输入 Token 数
+	PromptTokens int `json:"prompt_tokens,omitempty"`
+
+	// CompletionTokens 输出 Token 数
+	CompletionTokens int `json:"completion_tokens,omitempty"`
+
+	// TotalTokens 总 Token 数
+	TotalTokens int `json:"total_tokens,omitempty"`
+}
+
+// NewSampleLoadErrorResult 创建样本加载失败的结果
+//
+// 数据集 Get 失败时用于记录失败样本，使准确率分母与样本总数保持一致，
+// 并在结果中保留失败的索引。
+func NewSampleLoadErrorResult(index int, err error) *SampleResult {
+	return &SampleResult{
+		SampleID: fmt.Sprintf("index_%d", index),
+		Success:  false,
+		Error:    fmt.Sprintf("加载样本失败: %v", err),
+		Details: map[string]interface{}{
+			"index":      index,
+			"load_error": true,
+		},
+	}
+}
+
+// IsTimeoutResult 判断结果是否因单样本超时而失败
+func IsTimeoutResult(result *SampleResult) bool {
+	if result == nil {
+		return false
+	}
+	timeout, _ := result.Details["timeout"].(bool)
+	return timeout
+}
+
+// CountTimeouts 统计因单样本超时而失败的样本数
+func CountTimeouts(results []*SampleResult) int {
+	count := 0
+	for _, r := range results {
+		if IsTimeoutResult(r) {
+			count++
+		}
+	}
+	return count
 }
 
 // EvalResult 完整评估结果
@@ -118,6 +169,9 @@ type EvalResult struct {
 
 	// Metrics 汇总指标
 	Metrics *MetricsSummary `json:"metrics,omitempty"`
+
+	// RunConfig 运行配置摘要
+	RunConfig map[string]interface{} `json:"run_config,omitempty"`
 }
 
 // CategoryMetrics 分类别指标
@@ -194,6 +248,12 @@ type MetricsSummary struct {
 	// DimensionScores 各维度分数（用于 LLM Judge）
 	DimensionScores map[string]float64 `json:"dimension_scores,omitempty"`
 
+	// TokenUsage Token 使用量汇总（智能体未报告用量时为 nil）
+	TokenUsage *message.TokenUsage `json:"token_usage,omitempty"`
+
+	// ScoreHistogram 样本分数分布（用于 LLM Judge、GAIA 部分得分）
+	ScoreHistogram *ScoreHistogram `json:"score_histogram,omitempty"`
+
 	// Extra 额外指标
 	Extra map[string]interface{} `json:"extra,omitempty"`
 }
@@ -221,6 +281,9 @@ type JudgeScore struct {
 	// Completeness 完整性评分
 	Completeness float64 `json:"completeness"`
 
+	// Scores 各维度评分（按维度名称索引，包含自定义维度）
+	Scores map[string]float64 `json:"scores,omitempty"`
+
 	// TotalScore 总分
 	TotalScore float64 `json:"total_score"`
 
@@ -242,9 +305,15 @@ type ComparisonResult struct {
 	// ActualWinner 实际胜者（考虑位置随机化后）
 	ActualWinner string `json:"actual_winner"`
 
+	// Swapped 是否交换了展示位置（为 true 时候选样本展示为题目 B）
+	Swapped bool `json:"swapped"`
+
 	// Reason 理由
 	Reason string `json:"reason"`
 
+	// Details 附加信息（如双轮评估的各轮原始判决）
+	Details map[string]interface{} `json:"details,omitempty"`
+
 	// ExecutionTime 执行时间
 	ExecutionTime time.Duration `json:"execution_time"`
 }
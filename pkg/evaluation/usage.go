@@ -0,0 +1,57 @@
+package evaluation
+
+import (
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/message"
+)
+
+// UsageReporter 可报告 Token 使用量的智能体（可选接口）
+//
+// 智能体输出未携带 TokenUsage 时，评估器通过该接口获取最近一次 Run 的用量。
+// 并发评估时该接口的结果可能与调用错位，应优先在 Output.TokenUsage 中返回用量。
+type UsageReporter interface {
+	// LastTokenUsage 返回最近一次 Run 的 Token 使用量
+	LastTokenUsage() message.TokenUsage
+}
+
+// AgentTokenUsage 获取一次智能体调用的 Token 使用量
+//
+// 优先使用 Output.TokenUsage，为空时回退到 UsageReporter。
+func AgentTokenUsage(agent agents.Agent, output agents.Output) message.TokenUsage {
+	usage := output.TokenUsage
+	if usage == (message.TokenUsage{}) {
+		if reporter, ok := agent.(UsageReporter); ok {
+			usage = reporter.LastTokenUsage()
+		}
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return usage
+}
+
+// AddTokenUsage 将 Token 使用量累加到样本结果
+func (r *SampleResult) AddTokenUsage(usage message.TokenUsage) {
+	r.PromptTokens += usage.PromptTokens
+	r.CompletionTokens += usage.CompletionTokens
+	r.TotalTokens += usage.TotalTokens
+}
+
+// SumTokenUsage 汇总样本结果的 Token 使用量
+//
+// 所有样本均无用量时返回 nil。
+func SumTokenUsage(results []*SampleResult) *message.TokenUsage {
+	var total message.TokenUsage
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		total.PromptTokens += r.PromptTokens
+		total.CompletionTokens += r.CompletionTokens
+		total.TotalTokens += r.TotalTokens
+	}
+	if total == (message.TokenUsage{}) {
+		return nil
+	}
+	return &total
+}
@@ -0,0 +1,126 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError 提供商 HTTP 接口返回的错误
+//
+// Err 为映射后的框架错误（如 ErrQuotaExceeded），errors.Is 与 IsRetryable、IsFatal
+// 通过 Unwrap 照常判断；其余字段保留 HTTP 层面的元数据，可通过 errors.As 获取。
+type APIError struct {
+	// StatusCode HTTP 状态码
+	StatusCode int
+
+	// ProviderMessage 提供商返回的原始错误信息
+	ProviderMessage string
+
+	// RequestID 提供商返回的请求 ID，便于向厂商排查问题
+	RequestID string
+
+	// RetryAfter 服务端建议的重试等待时间（来自 Retry-After 响应头，未提供时为 0）
+	RetryAfter time.Duration
+
+	// Err 映射后的框架错误
+	Err error
+}
+
+// Error 实现 error 接口
+func (e *APIError) Error() string {
+	msg := e.Err.Error()
+	if e.ProviderMessage != "" && !strings.Contains(msg, e.ProviderMessage) {
+		msg += ": " + e.ProviderMessage
+	}
+	msg += fmt.Sprintf(" (status %d", e.StatusCode)
+	if e.RequestID != "" {
+		msg += ", request id " + e.RequestID
+	}
+	return msg + ")"
+}
+
+// Unwrap 返回映射后的框架错误
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError 根据 HTTP 响应构造 APIError
+//
+// requestID 为空时依次尝试 X-Request-Id、Request-Id 响应头，最后回退为请求 context
+// 中通过 WithRequestID 设置的请求 ID。
+func newAPIError(httpResp *http.Response, message, requestID string, err error) *APIError {
+	if requestID == "" {
+		requestID = httpResp.Header.Get("X-Request-Id")
+	}
+	if requestID == "" {
+		requestID = httpResp.Header.Get("Request-Id")
+	}
+	if requestID == "" && httpResp.Request != nil {
+		requestID, _ = RequestIDFromContext(httpResp.Request.Context())
+	}
+	return &APIError{
+		StatusCode:      httpResp.StatusCode,
+		ProviderMessage: message,
+		RequestID:       requestID,
+		RetryAfter:      parseRetryAfter(httpResp.Header, time.Now()),
+		Err:             err,
+	}
+}
+
+// statusError 将没有错误详情的 HTTP 状态码映射为框架错误
+func statusError(statusCode int) error {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrInvalidAPIKey
+	case statusCode == http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case statusCode >= 500:
+		return ErrProviderUnavailable
+	default:
+		return WrapError(ErrGenerationFailed, fmt.Sprintf("unexpected status code: %d", statusCode))
+	}
+}
+
+// parseRetryAfter 解析 Retry-After 响应头
+//
+// 支持秒数与 HTTP 日期两种格式，以及部分厂商使用的 retry-after-ms（毫秒）；
+// 缺失或无法解析时返回 0。
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	if ms := header.Get("Retry-After-Ms"); ms != "" {
+		if v, err := strconv.ParseFloat(ms, 64); err == nil && v > 0 {
+			return time.Duration(v * float64(time.Millisecond))
+		}
+	}
+
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// retryDelay 返回下次重试前的等待时间
+//
+// 错误携带 RetryAfter 时以服务端建议为准，否则使用指数退避计算的 backoff。
+func retryDelay(err error, backoff time.Duration) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	return backoff
+}
@@ -0,0 +1,71 @@
+package image
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult 批量生成中单个请求的结果
+type BatchResult struct {
+	// Index 请求在输入切片中的下标
+	Index int
+	// Response 生成结果（Error 为 nil 时有效）
+	Response ImageResponse
+	// Error 生成错误
+	Error error
+}
+
+// GenerateBatch 批量生成图像
+//
+// 最多 concurrency 个请求并行执行（小于 1 时按 1 处理），结果与输入下标一一对应。
+// 所有工作协程调用同一个 provider，因此通过 WithRateLimit 配置的限流器全局生效，
+// 而不是按协程分别计算。单个请求失败不会中止整个批次；ctx 取消后不再派发新请求，
+// 未派发的请求以 ctx.Err() 作为错误，此时函数同样返回 ctx.Err()；所有请求均已派发时
+// 返回 nil，即使 ctx 在派发完成后才被取消。
+func GenerateBatch(ctx context.Context, provider ImageProvider, reqs []ImageRequest, concurrency int) ([]BatchResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BatchResult, len(reqs))
+	for i := range results {
+		results[i].Index = i
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency && w < len(reqs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				resp, err := provider.Generate(ctx, reqs[i])
+				results[i].Response = resp
+				results[i].Error = err
+			}
+		}()
+	}
+
+	// skipErr 非 nil 表示有请求因 ctx 取消未被派发
+	var skipErr error
+	for i := range reqs {
+		// 先检查取消，避免两个分支同时就绪时 select 随机选择派发
+		if skipErr = ctx.Err(); skipErr == nil {
+			select {
+			case <-ctx.Done():
+				skipErr = ctx.Err()
+			case indices <- i:
+				continue
+			}
+		}
+		for j := i; j < len(reqs); j++ {
+			results[j].Error = skipErr
+		}
+		break
+	}
+	close(indices)
+	wg.Wait()
+
+	return results, skipErr
+}
@@ -0,0 +1,45 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// GenerateBytes 生成图像并返回每张图像解码后的原始字节
+//
+// 调用方未指定 ResponseFormat 时，若提供商支持 Base64 响应（或未报告能力）则强制请求
+// Base64，省去额外下载；返回 URL 的图像会被下载（内置提供商使用其自身的 HTTP 客户端）。结果与 ImageResponse.Images 一一对应。
+func GenerateBytes(ctx context.Context, provider ImageProvider, req ImageRequest) ([][]byte, error) {
+	if req.ResponseFormat == "" && prefersBase64(provider) {
+		req.ResponseFormat = FormatBase64
+	}
+
+	resp, err := provider.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Images) == 0 {
+		return nil, WrapError(ErrInvalidResponse, "no images in response")
+	}
+
+	client := downloadClientOf(provider)
+	images := make([][]byte, len(resp.Images))
+	for i, img := range resp.Images {
+		data, err := img.data(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+		images[i] = data
+	}
+	return images, nil
+}
+
+// prefersBase64 判断是否应向提供商请求 Base64 响应
+func prefersBase64(provider ImageProvider) bool {
+	reporter, ok := provider.(CapabilityReporter)
+	if !ok {
+		return true
+	}
+	return slices.Contains(reporter.Capabilities().ResponseFormats, FormatBase64)
+}
@@ -0,0 +1,89 @@
+package image
+
+import "sort"
+
+// ProviderCapabilities 提供商（及当前模型）支持的功能
+//
+// 描述的是本包各客户端的实际行为：例如某厂商 API 支持风格参数，但客户端未传递时
+// Styles 为空。
+type ProviderCapabilities struct {
+	// NegativePrompt 是否支持负面提示词
+	NegativePrompt bool `json:"negative_prompt"`
+
+	// Seed 是否支持指定随机种子
+	Seed bool `json:"seed"`
+
+	// Edit 是否支持基于已有图像编辑（图生图）
+	Edit bool `json:"edit"`
+
+	// Variations 是否支持生成已有图像的变体
+	Variations bool `json:"variations"`
+
+	// MaxImages 单次请求最多生成的图像数量
+	MaxImages int `json:"max_images"`
+
+	// MaxPromptLength 提示词最大长度（字符数，0 表示客户端不限制）
+	MaxPromptLength int `json:"max_prompt_length,omitempty"`
+
+	// Styles 支持的风格预设，为空表示不支持 Style 参数
+	Styles []ImageStyle `json:"styles,omitempty"`
+
+	// Qualities 支持的质量等级，为空表示不支持 Quality 参数
+	Qualities []ImageQuality `json:"qualities,omitempty"`
+
+	// ResponseFormats 支持的响应格式
+	ResponseFormats []ResponseFormat `json:"response_formats"`
+
+	// ExtraParams 可识别的 ImageRequest.Extra 参数，为空表示不读取 Extra
+	ExtraParams []ExtraParam `json:"extra_params,omitempty"`
+}
+
+// SupportsStyle 判断是否支持指定风格
+func (c ProviderCapabilities) SupportsStyle(style ImageStyle) bool {
+	for _, s := range c.Styles {
+		if s == style {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsQuality 判断是否支持指定质量等级
+func (c ProviderCapabilities) SupportsQuality(quality ImageQuality) bool {
+	for _, q := range c.Qualities {
+		if q == quality {
+			return true
+		}
+	}
+	return false
+}
+
+// CapabilityReporter 可报告自身功能的提供商
+type CapabilityReporter interface {
+	// Capabilities 返回当前模型支持的功能
+	Capabilities() ProviderCapabilities
+}
+
+// CapabilityMatrix 返回各提供商默认模型支持的功能，便于 UI 展示或预先校验
+//
+// 客户端指定了非默认模型时，以客户端 Capabilities() 的结果为准。
+func CapabilityMatrix() map[ProviderType]ProviderCapabilities {
+	return map[ProviderType]ProviderCapabilities{
+		ProviderOpenAI:    openAICapabilities(ModelDALLE3),
+		ProviderStability: stabilityCapabilities(ModelSD35Large),
+		ProviderDashScope: dashScopeCapabilities(ModelWanx21Turbo),
+		ProviderERNIE:     ernieCapabilities(ModelERNIEViLG2),
+		ProviderHunyuan:   hunyuanCapabilities(ModelHunyuanImage),
+		ProviderGoogle:    googleCapabilities(ModelImagen3),
+	}
+}
+
+// styleKeys 返回风格映射表中的风格，按名称排序
+func styleKeys(styles map[ImageStyle]string) []ImageStyle {
+	keys := make([]ImageStyle, 0, len(styles))
+	for style := range styles {
+		keys = append(keys, style)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,136 @@
+package image
+
+import (
+	"bytes"
+	"encoding/base64"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// sniffLen 内容类型嗅探读取的字节数（http.DetectContentType 最多使用 512 字节）
+const sniffLen = 512
+
+// contentTypeExtensions 图像内容类型对应的文件扩展名
+var contentTypeExtensions = map[string]string{
+	"image/png":     ".png",
+	"image/jpeg":    ".jpg",
+	"image/jpg":     ".jpg",
+	"image/pjpeg":   ".jpg",
+	"image/webp":    ".webp",
+	"image/svg+xml": ".svg",
+	"image/gif":     ".gif",
+}
+
+// extensionContentTypes 文件扩展名对应的图像内容类型
+var extensionContentTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".webp": "image/webp",
+	".svg":  "image/svg+xml",
+	".gif":  "image/gif",
+}
+
+// ExtensionForContentType 返回图像内容类型对应的文件扩展名
+//
+// 支持 png、jpeg、webp、svg 和 gif，忽略参数与大小写（如 "image/JPEG; q=0.9"）；
+// 无法识别时返回空字符串。
+func ExtensionForContentType(ct string) string {
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(ct))
+	}
+	return contentTypeExtensions[mediaType]
+}
+
+// contentTypeAliases 非标准图像内容类型对应的标准写法
+var contentTypeAliases = map[string]string{
+	"image/jpg":   "image/jpeg",
+	"image/pjpeg": "image/jpeg",
+	"image/x-png": "image/png",
+	"image/svg":   "image/svg+xml",
+}
+
+// genericContentTypes 不携带图像格式信息的通用内容类型
+var genericContentTypes = map[string]bool{
+	"application/octet-stream": true,
+	"binary/octet-stream":      true,
+	"application/binary":       true,
+}
+
+// NormalizeContentType 规范化提供商返回的图像内容类型
+//
+// 去除参数并转为小写，将 "image/jpg" 等非标准写法映射为标准 MIME 类型；
+// "application/octet-stream" 等通用类型无法说明图像格式，返回空字符串。
+func NormalizeContentType(ct string) string {
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(ct))
+	}
+	if genericContentTypes[mediaType] {
+		return ""
+	}
+	if alias, ok := contentTypeAliases[mediaType]; ok {
+		return alias
+	}
+	return mediaType
+}
+
+// DetectContentType 根据图像数据的起始字节嗅探内容类型
+//
+// 支持 PNG、JPEG、WebP、GIF 等常见格式及 SVG，无法识别为图像时返回空字符串。
+func DetectContentType(data []byte) string {
+	if len(data) > sniffLen {
+		data = data[:sniffLen]
+	}
+	ct := http.DetectContentType(data)
+	if strings.HasPrefix(ct, "image/") {
+		return ct
+	}
+	// http.DetectContentType 不识别 SVG
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("<svg")) ||
+		(bytes.HasPrefix(trimmed, []byte("<?xml")) && bytes.Contains(trimmed, []byte("<svg"))) {
+		return "image/svg+xml"
+	}
+	return ""
+}
+
+// detectImageContentType 推断单张图像的内容类型
+//
+// 优先嗅探 Base64 数据的起始字节，其次根据 URL 路径扩展名推断，均失败时保留
+// 规范化后的原值（通用类型如 "application/octet-stream" 置空）。
+func detectImageContentType(img GeneratedImage) string {
+	if img.Base64 != "" {
+		// 仅解码前缀，长度取 4 的倍数
+		prefix := img.Base64
+		if n := base64.StdEncoding.EncodedLen(sniffLen); len(prefix) > n {
+			prefix = prefix[:n]
+		}
+		if data, err := base64.StdEncoding.DecodeString(prefix); err == nil {
+			if ct := DetectContentType(data); ct != "" {
+				return ct
+			}
+		}
+	}
+	if img.URL != "" {
+		if u, err := url.Parse(img.URL); err == nil {
+			if ct, ok := extensionContentTypes[strings.ToLower(path.Ext(u.Path))]; ok {
+				return ct
+			}
+		}
+	}
+	return NormalizeContentType(img.ContentType)
+}
+
+// fillContentTypes 校正响应中每张图像的内容类型
+//
+// 提供商按默认值填写的 ContentType 可能与实际格式不符，以嗅探结果为准。
+func fillContentTypes(resp *ImageResponse) {
+	for i := range resp.Images {
+		resp.Images[i].ContentType = detectImageContentType(resp.Images[i])
+	}
+}
@@ -0,0 +1,73 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// URLUploader 图像上传接口
+//
+// 用于将 Base64 图像数据上传到对象存储等位置并返回可访问的 URL。
+type URLUploader interface {
+	// Upload 上传图像数据，返回图像 URL
+	Upload(ctx context.Context, data []byte, contentType string) (string, error)
+}
+
+// URLUploaderFunc 函数形式的 URLUploader
+type URLUploaderFunc func(ctx context.Context, data []byte, contentType string) (string, error)
+
+// Upload 实现 URLUploader 接口
+func (f URLUploaderFunc) Upload(ctx context.Context, data []byte, contentType string) (string, error) {
+	return f(ctx, data, contentType)
+}
+
+// NormalizeResponseFormat 将响应中的图像转换为期望的格式
+//
+// 期望 URL 但只有 Base64 时，通过 uploader 上传并填充 URL；
+// 期望 Base64 但只有 URL 时，下载图像（超时为 DefaultDownloadTimeout）并进行 Base64 编码。
+// 已满足期望格式的图像保持不变，返回的响应不会修改原响应的图像切片。
+func NormalizeResponseFormat(ctx context.Context, resp ImageResponse, want ResponseFormat, uploader URLUploader) (ImageResponse, error) {
+	images := make([]GeneratedImage, len(resp.Images))
+	copy(images, resp.Images)
+
+	for i := range images {
+		img := &images[i]
+
+		switch want {
+		case FormatURL:
+			if img.URL != "" || img.Base64 == "" {
+				continue
+			}
+			if uploader == nil {
+				return ImageResponse{}, errors.New("uploader is required to convert base64 to URL")
+			}
+			data, err := base64.StdEncoding.DecodeString(img.Base64)
+			if err != nil {
+				return ImageResponse{}, WrapError(err, fmt.Sprintf("failed to decode image %d", i))
+			}
+			url, err := uploader.Upload(ctx, data, img.ContentType)
+			if err != nil {
+				return ImageResponse{}, WrapError(err, fmt.Sprintf("failed to upload image %d", i))
+			}
+			img.URL = url
+
+		case FormatBase64:
+			if img.Base64 != "" || img.URL == "" {
+				continue
+			}
+			data, contentType, err := downloadImage(ctx, nil, img.URL)
+			if err != nil {
+				return ImageResponse{}, WrapError(err, fmt.Sprintf("failed to download image %d", i))
+			}
+			img.Base64 = base64.StdEncoding.EncodeToString(data)
+			if img.ContentType == "" {
+				img.ContentType = contentType
+			}
+		}
+	}
+
+	resp.Images = images
+	return resp, nil
+}
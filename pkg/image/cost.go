@@ -0,0 +1,119 @@
+package image
+
+import (
+	"fmt"
+)
+
+// 计价货币
+const (
+	CurrencyUSD = "USD"
+	CurrencyCNY = "CNY"
+)
+
+// CostUnitPerImage 按张计价
+const CostUnitPerImage = "per-image"
+
+// Cost 图像生成费用估算
+type Cost struct {
+	// Amount 估算总金额
+	Amount float64 `json:"amount"`
+	// Currency 货币单位，如 "USD"、"CNY"
+	Currency string `json:"currency"`
+	// Unit 计价单位，如 "per-image"
+	Unit string `json:"unit"`
+}
+
+// CostEstimator 费用估算接口
+//
+// 各提供商根据官方公开价格表估算请求费用，价格可能随厂商调整而变化，仅供参考。
+type CostEstimator interface {
+	// EstimateCost 估算单个请求的费用
+	EstimateCost(req ImageRequest) (Cost, error)
+}
+
+// EstimateTotalCost 汇总多个请求的费用
+//
+// 任一请求无法估算时返回错误。
+func EstimateTotalCost(estimator CostEstimator, reqs []ImageRequest) (Cost, error) {
+	var total Cost
+	for i, req := range reqs {
+		cost, err := estimator.EstimateCost(req)
+		if err != nil {
+			return Cost{}, WrapError(err, fmt.Sprintf("request %d", i))
+		}
+		if total.Currency == "" {
+			total.Currency = cost.Currency
+			total.Unit = cost.Unit
+		} else if total.Currency != cost.Currency {
+			return Cost{}, fmt.Errorf("currency mismatch: %s vs %s", total.Currency, cost.Currency)
+		}
+		total.Amount += cost.Amount
+	}
+	return total, nil
+}
+
+// priceKey 价格表键
+//
+// Size 或 Quality 为零值时表示该维度不影响价格。
+type priceKey struct {
+	Model   string
+	Size    ImageSize
+	Quality ImageQuality
+}
+
+// priceTable 提供商价格表（单张价格）
+type priceTable struct {
+	currency string
+	prices   map[priceKey]float64
+}
+
+// estimate 按模型、尺寸和质量查询单价并计算费用
+func (t priceTable) estimate(model string, size ImageSize, quality ImageQuality, count int) (Cost, error) {
+	candidates := []priceKey{
+		{Model: model, Size: size, Quality: quality},
+		{Model: model, Size: size},
+		{Model: model, Quality: quality},
+		{Model: model},
+	}
+
+	for _, key := range candidates {
+		if price, ok := t.prices[key]; ok {
+			return Cost{
+				Amount:   price * float64(count),
+				Currency: t.currency,
+				Unit:     CostUnitPerImage,
+			}, nil
+		}
+	}
+
+	return Cost{}, WrapError(ErrUnsupportedSize,
+		fmt.Sprintf("no pricing for model %s, size %dx%d, quality %s", model, size.Width, size.Height, quality))
+}
+
+// resolveSize 返回请求尺寸，未设置时使用默认尺寸
+func resolveSize(size, defaultSize ImageSize) ImageSize {
+	if size.Width == 0 || size.Height == 0 {
+		return defaultSize
+	}
+	return size
+}
+
+// containsSize 判断尺寸是否在列表中
+func containsSize(sizes []ImageSize, size ImageSize) bool {
+	for _, s := range sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// imageCount 返回请求实际生成的图像数量
+//
+// max 为单次请求上限，N 超出范围时按 1 张计算（与各提供商构建请求的逻辑一致）。
+func imageCount(n, max int) int {
+	if n > 0 && (max <= 0 || n <= max) {
+		return n
+	}
+	return 1
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -32,6 +33,14 @@ const (
 	dashScopeTaskEndpoint   = "/tasks"
 )
 
+// 异步任务轮询参数
+const (
+	// dashScopeMaxPollInterval 轮询间隔上限
+	dashScopeMaxPollInterval = 5 * time.Second
+	// dashScopeMaxPollWait 单个任务的最长轮询时间（ctx 未设置更早的截止时间时生效）
+	dashScopeMaxPollWait = 2 * time.Minute
+)
+
 // DashScope 支持的尺寸
 var dashScopeSizes = []ImageSize{
 	{Width: 1024, Height: 1024},
@@ -39,6 +48,16 @@ var dashScopeSizes = []ImageSize{
 	{Width: 1280, Height: 720},
 }
 
+// DashScope 价格表（人民币/张）
+var dashScopePricing = priceTable{
+	currency: CurrencyCNY,
+	prices: map[priceKey]float64{
+		{Model: ModelWanxV1}:      0.16,
+		{Model: ModelWanx21Turbo}: 0.14,
+		{Model: ModelWanx21Pro}:   0.20,
+	},
+}
+
 // DashScope 风格映射
 var dashScopeStyleMap = map[ImageStyle]string{
 	StylePhotographic: "<photography>",
@@ -68,9 +87,7 @@ func NewDashScope(opts ...Option) (*DashScopeClient, error) {
 
 	httpClient := options.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: options.Timeout,
-		}
+		httpClient = NewHTTPClient(options)
 	}
 
 	return &DashScopeClient{
@@ -94,6 +111,23 @@ func (c *DashScopeClient) SupportedSizes() []ImageSize {
 	return dashScopeSizes
 }
 
+// Capabilities 返回当前模型支持的功能
+func (c *DashScopeClient) Capabilities() ProviderCapabilities {
+	return dashScopeCapabilities(c.options.Model)
+}
+
+// dashScopeCapabilities 返回 DashScope 指定模型支持的功能
+func dashScopeCapabilities(model string) ProviderCapabilities {
+	return ProviderCapabilities{
+		NegativePrompt:  true,
+		Seed:            true,
+		MaxImages:       4,
+		MaxPromptLength: 800,
+		Styles:          styleKeys(dashScopeStyleMap),
+		ResponseFormats: []ResponseFormat{FormatURL},
+	}
+}
+
 // Close 关闭客户端连接
 func (c *DashScopeClient) Close() error {
 	return nil
@@ -101,17 +135,44 @@ func (c *DashScopeClient) Close() error {
 
 // Generate 生成图像
 func (c *DashScopeClient) Generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
-	// 验证请求
-	if req.Prompt == "" {
-		return ImageResponse{}, ErrInvalidPrompt
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return ImageResponse{}, err
 	}
+	return c.generatePrepared(ctx, prepared)
+}
 
-	// 执行请求（带重试）
-	var resp ImageResponse
+// prepare 按客户端配置规范化并验证请求
+func (c *DashScopeClient) prepare(req ImageRequest) (preparedRequest, error) {
+	return c.options.prepareRequest(req, c.Name(), c.SupportedSizes(), c.Capabilities())
+}
+
+// generatePrepared 使用规范化后的请求生成图像
+func (c *DashScopeClient) generatePrepared(ctx context.Context, prepared preparedRequest) (ImageResponse, error) {
+	req := prepared.req
+	resp, err := generateWithFilterRetry(ctx, c.options, req, c.generate)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	prepared.applyTo(&resp)
+	fillContentTypes(&resp)
+	fillSeeds(&resp, req)
+	return resp, nil
+}
+
+// generate 执行单次生成
+//
+// 提交任务带重试；任务提交成功后轮询结果，轮询超时或任务失败不会重新提交任务。
+func (c *DashScopeClient) generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
+	// 提交任务（带重试）
+	var apiResp dashScopeResponse
 	var err error
 
 	err = c.retry(ctx, func() error {
-		resp, err = c.doRequest(ctx, req)
+		if err := c.options.waitRateLimit(ctx); err != nil {
+			return err
+		}
+		apiResp, err = c.submitTask(ctx, req)
 		return err
 	})
 
@@ -119,10 +180,54 @@ func (c *DashScopeClient) Generate(ctx context.Context, req ImageRequest) (Image
 		return ImageResponse{}, err
 	}
 
+	var resp ImageResponse
+	if apiResp.Output.TaskID != "" && apiResp.Output.TaskStatus != "SUCCEEDED" {
+		// 异步任务，轮询结果
+		resp, err = c.pollTaskResult(ctx, apiResp.Output.TaskID)
+		if err != nil {
+			return ImageResponse{}, err
+		}
+	} else {
+		// 同步响应
+		resp = c.parseResponse(apiResp)
+	}
+
 	resp.Model = c.options.Model
 	return resp, nil
 }
 
+// GenerateStream 以事件流方式生成图像
+func (c *DashScopeClient) GenerateStream(ctx context.Context, req ImageRequest) (<-chan GenerationEvent, error) {
+	// 与 Generate 相同地先规范化再验证
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	return StreamFromGenerate(ctx, prepared.req, func(ctx context.Context, _ ImageRequest) (ImageResponse, error) {
+		return c.generatePrepared(ctx, prepared)
+	}), nil
+}
+
+// GenerateToWriter 生成图像并将第一张图像流式写入 w
+func (c *DashScopeClient) GenerateToWriter(ctx context.Context, req ImageRequest, w io.Writer) (ImageResponse, error) {
+	return generateToWriter(ctx, req, w, c.Generate, c.httpClient)
+}
+
+// downloadClient 返回下载生成图像时使用的 HTTP 客户端
+func (c *DashScopeClient) downloadClient() *http.Client {
+	return c.httpClient
+}
+
+// EstimateCost 估算请求费用
+func (c *DashScopeClient) EstimateCost(req ImageRequest) (Cost, error) {
+	size := resolveSize(req.Size, c.options.DefaultSize)
+	if !containsSize(dashScopeSizes, size) {
+		return Cost{}, ErrUnsupportedSize
+	}
+
+	return dashScopePricing.estimate(c.options.Model, ImageSize{}, "", imageCount(req.N, 0))
+}
+
 // dashScopeRequest DashScope 图像生成请求
 type dashScopeRequest struct {
 	Model      string              `json:"model"`
@@ -149,7 +254,8 @@ type dashScopeResponse struct {
 		TaskID     string `json:"task_id"`
 		TaskStatus string `json:"task_status"`
 		Results    []struct {
-			URL string `json:"url"`
+			URL  string `json:"url"`
+			Seed *int64 `json:"seed,omitempty"`
 		} `json:"results"`
 	} `json:"output"`
 	Usage struct {
@@ -166,8 +272,11 @@ type dashScopeTaskResponse struct {
 		TaskID     string `json:"task_id"`
 		TaskStatus string `json:"task_status"`
 		Results    []struct {
-			URL string `json:"url"`
+			URL  string `json:"url"`
+			Seed *int64 `json:"seed,omitempty"`
 		} `json:"results"`
+		Code        string `json:"code,omitempty"`
+		Message     string `json:"message,omitempty"`
 		TaskMetrics struct {
 			Total     int `json:"TOTAL"`
 			Succeeded int `json:"SUCCEEDED"`
@@ -178,24 +287,25 @@ type dashScopeTaskResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
-// doRequest 执行 HTTP 请求
-func (c *DashScopeClient) doRequest(ctx context.Context, req ImageRequest) (ImageResponse, error) {
+// submitTask 提交生成任务，返回提交响应（异步模式下包含任务 ID）
+func (c *DashScopeClient) submitTask(ctx context.Context, req ImageRequest) (dashScopeResponse, error) {
 	// 构建请求
 	apiReq := c.buildRequest(req)
 
 	// 序列化请求
 	body, err := json.Marshal(apiReq)
 	if err != nil {
-		return ImageResponse{}, WrapError(err, "failed to marshal request")
+		return dashScopeResponse{}, WrapError(err, "failed to marshal request")
 	}
 
 	// 创建 HTTP 请求
 	url := c.options.BaseURL + dashScopeImageEndpoint
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return ImageResponse{}, WrapError(err, "failed to create request")
+		return dashScopeResponse{}, WrapError(err, "failed to create request")
 	}
 
+	setRequestIDHeader(httpReq)
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+c.options.APIKey)
 	httpReq.Header.Set("X-DashScope-Async", "enable") // 启用异步模式
@@ -204,60 +314,71 @@ func (c *DashScopeClient) doRequest(ctx context.Context, req ImageRequest) (Imag
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		if ctx.Err() != nil {
-			return ImageResponse{}, ErrTimeout
+			return dashScopeResponse{}, ErrTimeout
 		}
-		return ImageResponse{}, WrapError(err, "request failed")
+		return dashScopeResponse{}, WrapError(err, "request failed")
 	}
 	defer httpResp.Body.Close()
 
 	// 读取响应
 	respBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		return ImageResponse{}, WrapError(err, "failed to read response")
+		return dashScopeResponse{}, WrapError(err, "failed to read response")
 	}
 
 	// 解析响应
 	var apiResp dashScopeResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return ImageResponse{}, WrapError(err, "failed to parse response")
+		if httpResp.StatusCode != http.StatusOK {
+			return dashScopeResponse{}, newAPIError(httpResp, "", "", statusError(httpResp.StatusCode))
+		}
+		return dashScopeResponse{}, WrapError(err, "failed to parse response")
 	}
 
 	// 检查错误
 	if apiResp.Code != "" {
-		return ImageResponse{}, c.mapError(httpResp.StatusCode, apiResp.Code, apiResp.Message)
+		return dashScopeResponse{}, newAPIError(httpResp, apiResp.Message, apiResp.RequestID,
+			c.mapError(httpResp.StatusCode, apiResp.Code, apiResp.Message))
 	}
 
 	if httpResp.StatusCode != http.StatusOK {
-		return ImageResponse{}, WrapError(ErrGenerationFailed,
-			fmt.Sprintf("unexpected status code: %d", httpResp.StatusCode))
+		return dashScopeResponse{}, newAPIError(httpResp, "", apiResp.RequestID, statusError(httpResp.StatusCode))
 	}
 
-	// 如果是异步任务，需要轮询结果
-	if apiResp.Output.TaskID != "" && apiResp.Output.TaskStatus != "SUCCEEDED" {
-		return c.pollTaskResult(ctx, apiResp.Output.TaskID)
-	}
-
-	// 同步响应
-	return c.parseResponse(apiResp), nil
+	return apiResp, nil
 }
 
 // pollTaskResult 轮询任务结果
+//
+// 首次等待 RetryDelay 后查询任务状态，此后间隔按指数退避增长（上限 dashScopeMaxPollInterval），
+// 直到任务 SUCCEEDED 或 FAILED。ctx 超时或累计等待超过 dashScopeMaxPollWait 时返回 ErrTimeout；
+// 单次查询的网络或解析错误不中断轮询。
 func (c *DashScopeClient) pollTaskResult(ctx context.Context, taskID string) (ImageResponse, error) {
 	url := c.options.BaseURL + dashScopeTaskEndpoint + "/" + taskID
 
-	maxAttempts := 60 // 最多等待 60 秒
-	for i := 0; i < maxAttempts; i++ {
+	interval := c.options.RetryDelay
+	if interval <= 0 {
+		interval = time.Second
+	}
+	deadline := time.Now().Add(dashScopeMaxPollWait)
+
+	for time.Now().Before(deadline) {
 		select {
 		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ImageResponse{}, WrapError(ErrTimeout, fmt.Sprintf("task %s did not finish", taskID))
+			}
 			return ImageResponse{}, ctx.Err()
-		case <-time.After(time.Second):
+		case <-time.After(interval):
 		}
+		interval = min(interval*2, dashScopeMaxPollInterval)
 
 		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
 			return ImageResponse{}, WrapError(err, "failed to create poll request")
 		}
 
+		setRequestIDHeader(httpReq)
 		httpReq.Header.Set("Authorization", "Bearer "+c.options.APIKey)
 
 		httpResp, err := c.httpClient.Do(httpReq)
@@ -277,22 +398,40 @@ func (c *DashScopeClient) pollTaskResult(ctx context.Context, taskID string) (Im
 		}
 
 		if taskResp.Code != "" {
-			return ImageResponse{}, c.mapError(httpResp.StatusCode, taskResp.Code, taskResp.Message)
+			return ImageResponse{}, newAPIError(httpResp, taskResp.Message, taskResp.RequestID,
+				c.mapError(httpResp.StatusCode, taskResp.Code, taskResp.Message))
 		}
 
 		switch taskResp.Output.TaskStatus {
 		case "SUCCEEDED":
 			return c.parseTaskResponse(taskResp), nil
-		case "FAILED":
-			return ImageResponse{}, WrapError(ErrGenerationFailed, "task failed")
-		case "PENDING", "RUNNING":
-			continue
-		default:
+		case "FAILED", "CANCELED", "UNKNOWN":
+			return ImageResponse{}, c.taskError(taskID, taskResp)
+		default: // PENDING、RUNNING
 			continue
 		}
 	}
 
-	return ImageResponse{}, WrapError(ErrTimeout, "task polling timeout")
+	return ImageResponse{}, WrapError(ErrTimeout, fmt.Sprintf("task %s polling timeout", taskID))
+}
+
+// taskError 构造任务失败（FAILED、CANCELED、UNKNOWN）的错误，附带任务 ID 与失败原因
+func (c *DashScopeClient) taskError(taskID string, taskResp dashScopeTaskResponse) error {
+	output := taskResp.Output
+	reason := output.TaskStatus
+	if output.Code != "" {
+		reason += " " + output.Code
+	}
+	if output.Message != "" {
+		reason += ": " + output.Message
+	}
+
+	// 复用提交阶段的错误码映射（如内容审核失败映射为 ErrContentFiltered）
+	err := ErrGenerationFailed
+	if mapped := c.mapError(http.StatusOK, output.Code, output.Message); !errors.Is(mapped, ErrGenerationFailed) {
+		err = mapped
+	}
+	return WrapError(err, fmt.Sprintf("task %s %s", taskID, reason))
 }
 
 // buildRequest 构建 DashScope 请求
@@ -369,6 +508,7 @@ func (c *DashScopeClient) parseResponse(resp dashScopeResponse) ImageResponse {
 	for i, img := range resp.Output.Results {
 		result.Images[i] = GeneratedImage{
 			URL:         img.URL,
+			Seed:        img.Seed,
 			ContentType: "image/png",
 		}
 	}
@@ -386,6 +526,7 @@ func (c *DashScopeClient) parseTaskResponse(resp dashScopeTaskResponse) ImageRes
 	for i, img := range resp.Output.Results {
 		result.Images[i] = GeneratedImage{
 			URL:         img.URL,
+			Seed:        img.Seed,
 			ContentType: "image/png",
 		}
 	}
@@ -444,6 +585,7 @@ func (c *DashScopeClient) retry(ctx context.Context, fn func() error) error {
 			if delay > 30*time.Second {
 				delay = 30 * time.Second
 			}
+			delay = retryDelay(err, delay)
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -456,4 +598,8 @@ func (c *DashScopeClient) retry(ctx context.Context, fn func() error) error {
 }
 
 // compile-time interface check
-var _ ImageProvider = (*DashScopeClient)(nil)
+var (
+	_ ImageProvider   = (*DashScopeClient)(nil)
+	_ CostEstimator   = (*DashScopeClient)(nil)
+	_ WriterGenerator = (*DashScopeClient)(nil)
+)
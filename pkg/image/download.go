@@ -0,0 +1,70 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultDownloadTimeout 未使用提供商 HTTP 客户端时下载图像的超时时间
+const DefaultDownloadTimeout = 60 * time.Second
+
+// defaultDownloadClient 未使用提供商 HTTP 客户端时下载图像的客户端
+var defaultDownloadClient = &http.Client{Timeout: DefaultDownloadTimeout}
+
+// downloadClientProvider 可提供下载图像所用 HTTP 客户端的提供商
+//
+// 内置提供商返回其请求所用的客户端，下载图像时同样遵循 WithTimeout、WithHTTPClient
+// 与 WithTransportTuning 的配置。
+type downloadClientProvider interface {
+	downloadClient() *http.Client
+}
+
+// downloadClientOf 返回下载 provider 生成的图像时使用的 HTTP 客户端
+func downloadClientOf(provider ImageProvider) *http.Client {
+	if p, ok := provider.(downloadClientProvider); ok {
+		return p.downloadClient()
+	}
+	return defaultDownloadClient
+}
+
+// openImageURL 请求图像 URL，返回状态码为 200 的响应，调用方负责关闭响应体
+//
+// client 为 nil 时使用带 DefaultDownloadTimeout 超时的默认客户端。
+func openImageURL(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	if client == nil {
+		client = defaultDownloadClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// downloadImage 下载图像数据，返回数据及响应的 Content-Type
+func downloadImage(ctx context.Context, client *http.Client, url string) ([]byte, string, error) {
+	resp, err := openImageURL(ctx, client, url)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
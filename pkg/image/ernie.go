@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -46,6 +47,14 @@ var ernieSizes = []ImageSize{
 	{Width: 2048, Height: 2048},
 }
 
+// ERNIE 价格表（人民币/张）
+var erniePricing = priceTable{
+	currency: CurrencyCNY,
+	prices: map[priceKey]float64{
+		{Model: ModelERNIEViLG2}: 0.06,
+	},
+}
+
 // ERNIE 风格映射
 var ernieStyleMap = map[ImageStyle]string{
 	StylePhotographic: "写实风格",
@@ -75,9 +84,7 @@ func NewERNIE(opts ...Option) (*ERNIEClient, error) {
 
 	httpClient := options.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: options.Timeout,
-		}
+		httpClient = NewHTTPClient(options)
 	}
 
 	return &ERNIEClient{
@@ -101,6 +108,22 @@ func (c *ERNIEClient) SupportedSizes() []ImageSize {
 	return ernieSizes
 }
 
+// Capabilities 返回当前模型支持的功能
+func (c *ERNIEClient) Capabilities() ProviderCapabilities {
+	return ernieCapabilities(c.options.Model)
+}
+
+// ernieCapabilities 返回 ERNIE 指定模型支持的功能
+func ernieCapabilities(model string) ProviderCapabilities {
+	return ProviderCapabilities{
+		NegativePrompt:  true,
+		MaxImages:       6,
+		MaxPromptLength: 200,
+		Styles:          styleKeys(ernieStyleMap),
+		ResponseFormats: []ResponseFormat{FormatURL},
+	}
+}
+
 // Close 关闭客户端连接
 func (c *ERNIEClient) Close() error {
 	return nil
@@ -108,11 +131,32 @@ func (c *ERNIEClient) Close() error {
 
 // Generate 生成图像
 func (c *ERNIEClient) Generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
-	// 验证请求
-	if req.Prompt == "" {
-		return ImageResponse{}, ErrInvalidPrompt
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return ImageResponse{}, err
 	}
+	return c.generatePrepared(ctx, prepared)
+}
 
+// prepare 按客户端配置规范化并验证请求
+func (c *ERNIEClient) prepare(req ImageRequest) (preparedRequest, error) {
+	return c.options.prepareRequest(req, c.Name(), c.SupportedSizes(), c.Capabilities())
+}
+
+// generatePrepared 使用规范化后的请求生成图像
+func (c *ERNIEClient) generatePrepared(ctx context.Context, prepared preparedRequest) (ImageResponse, error) {
+	req := prepared.req
+	resp, err := generateWithFilterRetry(ctx, c.options, req, c.generate)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	prepared.applyTo(&resp)
+	fillContentTypes(&resp)
+	return resp, nil
+}
+
+// generate 执行单次生成（带重试）
+func (c *ERNIEClient) generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
 	// 确保有有效的 access token
 	if err := c.ensureAccessToken(ctx); err != nil {
 		return ImageResponse{}, err
@@ -123,6 +167,9 @@ func (c *ERNIEClient) Generate(ctx context.Context, req ImageRequest) (ImageResp
 	var err error
 
 	err = c.retry(ctx, func() error {
+		if err := c.options.waitRateLimit(ctx); err != nil {
+			return err
+		}
 		resp, err = c.doRequest(ctx, req)
 		return err
 	})
@@ -135,6 +182,38 @@ func (c *ERNIEClient) Generate(ctx context.Context, req ImageRequest) (ImageResp
 	return resp, nil
 }
 
+// GenerateStream 以事件流方式生成图像
+func (c *ERNIEClient) GenerateStream(ctx context.Context, req ImageRequest) (<-chan GenerationEvent, error) {
+	// 与 Generate 相同地先规范化再验证
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	return StreamFromGenerate(ctx, prepared.req, func(ctx context.Context, _ ImageRequest) (ImageResponse, error) {
+		return c.generatePrepared(ctx, prepared)
+	}), nil
+}
+
+// GenerateToWriter 生成图像并将第一张图像流式写入 w
+func (c *ERNIEClient) GenerateToWriter(ctx context.Context, req ImageRequest, w io.Writer) (ImageResponse, error) {
+	return generateToWriter(ctx, req, w, c.Generate, c.httpClient)
+}
+
+// downloadClient 返回下载生成图像时使用的 HTTP 客户端
+func (c *ERNIEClient) downloadClient() *http.Client {
+	return c.httpClient
+}
+
+// EstimateCost 估算请求费用
+func (c *ERNIEClient) EstimateCost(req ImageRequest) (Cost, error) {
+	size := resolveSize(req.Size, c.options.DefaultSize)
+	if !containsSize(ernieSizes, size) {
+		return Cost{}, ErrUnsupportedSize
+	}
+
+	return erniePricing.estimate(c.options.Model, ImageSize{}, "", imageCount(req.N, 6))
+}
+
 // ensureAccessToken 确保有有效的 access token
 func (c *ERNIEClient) ensureAccessToken(ctx context.Context) error {
 	c.tokenMu.RLock()
@@ -169,6 +248,7 @@ func (c *ERNIEClient) refreshAccessToken(ctx context.Context) error {
 	if err != nil {
 		return WrapError(err, "failed to create token request")
 	}
+	setRequestIDHeader(httpReq)
 
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -245,6 +325,7 @@ func (c *ERNIEClient) doRequest(ctx context.Context, req ImageRequest) (ImageRes
 		return ImageResponse{}, WrapError(err, "failed to create request")
 	}
 
+	setRequestIDHeader(httpReq)
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	// 执行请求
@@ -271,7 +352,12 @@ func (c *ERNIEClient) doRequest(ctx context.Context, req ImageRequest) (ImageRes
 
 	// 检查错误
 	if apiResp.ErrorCode != 0 {
-		return ImageResponse{}, c.mapError(apiResp.ErrorCode, apiResp.ErrorMsg)
+		var requestID string
+		if apiResp.LogID != 0 {
+			requestID = strconv.FormatInt(apiResp.LogID, 10)
+		}
+		return ImageResponse{}, newAPIError(httpResp, apiResp.ErrorMsg, requestID,
+			c.mapError(apiResp.ErrorCode, apiResp.ErrorMsg))
 	}
 
 	// 如果是异步任务，需要轮询结果
@@ -307,6 +393,7 @@ func (c *ERNIEClient) pollTaskResult(ctx context.Context, taskID string) (ImageR
 			continue
 		}
 
+		setRequestIDHeader(httpReq)
 		httpReq.Header.Set("Content-Type", "application/json")
 
 		httpResp, err := c.httpClient.Do(httpReq)
@@ -337,7 +424,8 @@ func (c *ERNIEClient) pollTaskResult(ctx context.Context, taskID string) (ImageR
 		}
 
 		if taskResp.ErrorCode != 0 {
-			return ImageResponse{}, c.mapError(taskResp.ErrorCode, taskResp.ErrorMsg)
+			return ImageResponse{}, newAPIError(httpResp, taskResp.ErrorMsg, "",
+				c.mapError(taskResp.ErrorCode, taskResp.ErrorMsg))
 		}
 
 		// status: 0=init, 1=running, 2=success, 3=failed
@@ -485,6 +573,7 @@ func (c *ERNIEClient) retry(ctx context.Context, fn func() error) error {
 			if delay > 30*time.Second {
 				delay = 30 * time.Second
 			}
+			delay = retryDelay(err, delay)
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -497,4 +586,8 @@ func (c *ERNIEClient) retry(ctx context.Context, fn func() error) error {
 }
 
 // compile-time interface check
-var _ ImageProvider = (*ERNIEClient)(nil)
+var (
+	_ ImageProvider   = (*ERNIEClient)(nil)
+	_ CostEstimator   = (*ERNIEClient)(nil)
+	_ WriterGenerator = (*ERNIEClient)(nil)
+)
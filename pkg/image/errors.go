@@ -10,6 +10,12 @@ var (
 	// ErrInvalidSize 图像尺寸无效
 	ErrInvalidSize = errors.New("invalid image size")
 
+	// ErrInvalidImageCount 生成数量无效
+	ErrInvalidImageCount = errors.New("invalid image count: n must be between 1 and 10")
+
+	// ErrInvalidAspectRatio 宽高比格式无效
+	ErrInvalidAspectRatio = errors.New("invalid aspect ratio")
+
 	// ErrUnsupportedSize 不支持的图像尺寸
 	ErrUnsupportedSize = errors.New("unsupported image size for this provider")
 
@@ -25,6 +31,9 @@ var (
 	// ErrInvalidAPIKey API 密钥无效
 	ErrInvalidAPIKey = errors.New("invalid API key")
 
+	// ErrMissingEndpoint 缺少服务端点配置（如 Google 未设置 BaseURL 或项目 ID）
+	ErrMissingEndpoint = errors.New("missing endpoint: set a base URL or project ID")
+
 	// ErrProviderUnavailable 提供商不可用
 	ErrProviderUnavailable = errors.New("image provider unavailable")
 
@@ -36,6 +45,21 @@ var (
 
 	// ErrModelNotSupported 模型不支持
 	ErrModelNotSupported = errors.New("model not supported")
+
+	// ErrMissingInitImage 图生图缺少初始图像
+	ErrMissingInitImage = errors.New("missing init image for image-to-image")
+
+	// ErrInvalidImageStrength 图生图强度无效
+	ErrInvalidImageStrength = errors.New("invalid image strength: must be between 0 and 1")
+
+	// ErrTemplateVarMissing 提示词模板变量缺失（严格模式）
+	ErrTemplateVarMissing = errors.New("prompt template variable missing")
+
+	// ErrUnsupportedTranscode 不支持的转码源格式或目标格式
+	ErrUnsupportedTranscode = errors.New("unsupported transcode format")
+
+	// ErrAnimatedImage 动图无法转码为静态图像
+	ErrAnimatedImage = errors.New("animated image cannot be transcoded")
 )
 
 // IsRetryable 判断错误是否可重试
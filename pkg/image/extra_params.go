@@ -0,0 +1,210 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidExtra ImageRequest.Extra 包含未知参数或类型错误的参数
+var ErrInvalidExtra = errors.New("invalid extra parameter")
+
+// ExtraParamType 厂商特定参数的取值类型
+type ExtraParamType string
+
+const (
+	// ExtraParamString 字符串
+	ExtraParamString ExtraParamType = "string"
+	// ExtraParamNumber 数值（任意整数或浮点类型）
+	ExtraParamNumber ExtraParamType = "number"
+	// ExtraParamBool 布尔值
+	ExtraParamBool ExtraParamType = "bool"
+	// ExtraParamBytes 二进制数据（[]byte）或文件路径（string）
+	ExtraParamBytes ExtraParamType = "bytes"
+)
+
+// ExtraParam 提供商可识别的 ImageRequest.Extra 参数
+type ExtraParam struct {
+	// Name 参数名（Extra 的键）
+	Name string `json:"name"`
+
+	// Type 取值类型
+	Type ExtraParamType `json:"type"`
+
+	// Values 允许的取值，为空表示不限制（仅用于字符串参数）
+	Values []string `json:"values,omitempty"`
+}
+
+// ExtraParamError 单个 Extra 参数的校验错误
+//
+// 可用 errors.Is(err, ErrInvalidExtra) 判断。
+type ExtraParamError struct {
+	// Provider 提供商名称
+	Provider string
+
+	// Name 参数名
+	Name string
+
+	// Reason 错误原因
+	Reason string
+}
+
+// Error 实现 error 接口
+func (e *ExtraParamError) Error() string {
+	return fmt.Sprintf("%s extra %q: %s", e.Provider, e.Name, e.Reason)
+}
+
+// Unwrap 返回 ErrInvalidExtra
+func (e *ExtraParamError) Unwrap() error {
+	return ErrInvalidExtra
+}
+
+// WithStrictExtra 设置是否严格校验 ImageRequest.Extra（默认关闭）
+//
+// 开启后 Extra 包含提供商无法识别的键或类型错误的值时，Generate 在发起请求前返回错误；
+// 关闭时仅通过 slog 记录警告，参数原样保留。
+func WithStrictExtra(strict bool) Option {
+	return func(o *Options) {
+		o.StrictExtra = strict
+	}
+}
+
+// ValidateExtra 按参数表校验 Extra
+//
+// 对每个未知键或类型错误的参数生成一个 *ExtraParamError（按参数名排序），
+// 多个错误通过 errors.Join 合并；没有问题时返回 nil。
+func ValidateExtra(provider string, params []ExtraParam, extra map[string]interface{}) error {
+	if len(extra) == 0 {
+		return nil
+	}
+
+	known := make(map[string]ExtraParam, len(params))
+	for _, p := range params {
+		known[p.Name] = p
+	}
+
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		param, ok := known[name]
+		if !ok {
+			errs = append(errs, &ExtraParamError{Provider: provider, Name: name, Reason: unknownExtraReason(params)})
+			continue
+		}
+		if reason := param.check(extra[name]); reason != "" {
+			errs = append(errs, &ExtraParamError{Provider: provider, Name: name, Reason: reason})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// unknownExtraReason 返回未知参数的错误原因，列出可识别的参数
+func unknownExtraReason(params []ExtraParam) string {
+	if len(params) == 0 {
+		return "unknown parameter (provider accepts no extra parameters)"
+	}
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Name
+	}
+	return "unknown parameter (known: " + strings.Join(names, ", ") + ")"
+}
+
+// check 校验参数值，返回错误原因（为空表示通过）
+func (p ExtraParam) check(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	switch p.Type {
+	case ExtraParamString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("must be a string, got %T", value)
+		}
+		if len(p.Values) > 0 && !containsString(p.Values, s) {
+			return fmt.Sprintf("must be one of %s, got %q", strings.Join(p.Values, "/"), s)
+		}
+	case ExtraParamNumber:
+		if _, ok := extraNumber(value); !ok {
+			return fmt.Sprintf("must be a number, got %T", value)
+		}
+	case ExtraParamBool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("must be a bool, got %T", value)
+		}
+	case ExtraParamBytes:
+		switch value.(type) {
+		case []byte, string:
+		default:
+			return fmt.Sprintf("must be []byte or a file path, got %T", value)
+		}
+	}
+	return ""
+}
+
+// containsString 判断 values 中是否包含 s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// checkExtra 按配置校验请求的 Extra：严格模式返回错误，否则记录警告后放行
+func (o *Options) checkExtra(provider string, params []ExtraParam, extra map[string]interface{}) error {
+	err := ValidateExtra(provider, params, extra)
+	if err == nil || o.StrictExtra {
+		return err
+	}
+	slog.Warn("image request extra parameters may be ignored by provider",
+		"provider", provider,
+		"error", err,
+	)
+	return nil
+}
+
+// extraNumber 将 Extra 中的数值转换为 float64
+func extraNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
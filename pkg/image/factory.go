@@ -20,6 +20,8 @@ const (
 	ProviderERNIE ProviderType = "ernie"
 	// ProviderHunyuan 腾讯混元
 	ProviderHunyuan ProviderType = "hunyuan"
+	// ProviderGoogle Google Imagen（Vertex AI）
+	ProviderGoogle ProviderType = "google"
 )
 
 // NewImageProvider 根据提供商类型创建图像生成客户端
@@ -35,6 +37,8 @@ func NewImageProvider(providerType ProviderType, opts ...Option) (ImageProvider,
 		return NewERNIE(opts...)
 	case ProviderHunyuan:
 		return NewHunyuan(opts...)
+	case ProviderGoogle:
+		return NewGoogle(opts...)
 	default:
 		return nil, fmt.Errorf("unknown provider type: %s", providerType)
 	}
@@ -52,6 +56,10 @@ type ProviderConfig struct {
 	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
 	// Model 模型名称
 	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+	// ProjectID 云项目 ID（Google Vertex AI 需要）
+	ProjectID string `json:"project_id,omitempty" yaml:"project_id,omitempty"`
+	// Region 服务区域（Google Vertex AI 需要）
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
 	// TimeoutSeconds 超时秒数
 	TimeoutSeconds int `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"`
 	// MaxRetries 最大重试次数
@@ -73,6 +81,12 @@ func NewImageProviderFromConfig(cfg ProviderConfig) (ImageProvider, error) {
 	if cfg.Model != "" {
 		opts = append(opts, WithModel(cfg.Model))
 	}
+	if cfg.ProjectID != "" {
+		opts = append(opts, WithProject(cfg.ProjectID))
+	}
+	if cfg.Region != "" {
+		opts = append(opts, WithRegion(cfg.Region))
+	}
 	if cfg.TimeoutSeconds > 0 {
 		opts = append(opts, WithTimeout(
 			time.Duration(cfg.TimeoutSeconds)*time.Second,
@@ -98,6 +112,8 @@ func ParseProviderType(s string) (ProviderType, error) {
 		return ProviderERNIE, nil
 	case "hunyuan", "tencent":
 		return ProviderHunyuan, nil
+	case "google", "imagen", "vertex":
+		return ProviderGoogle, nil
 	default:
 		return "", fmt.Errorf("unknown provider: %s", s)
 	}
@@ -111,5 +127,6 @@ func SupportedProviders() []ProviderType {
 		ProviderDashScope,
 		ProviderERNIE,
 		ProviderHunyuan,
+		ProviderGoogle,
 	}
 }
@@ -0,0 +1,52 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PromptRewriter 提示词改写函数
+type PromptRewriter func(prompt string) string
+
+// WithFilterRetry 设置内容过滤重试
+//
+// 生成结果被内容安全系统过滤（ErrContentFiltered）时，使用 rewrite 改写提示词后
+// 重试，最多重试 n 次；rewrite 为 nil 时使用原提示词重试。
+// 所有重试均被过滤时视为持续过滤，返回 ErrContentFiltered。
+func WithFilterRetry(n int, rewrite PromptRewriter) Option {
+	return func(o *Options) {
+		o.FilterRetries = n
+		o.PromptRewriter = rewrite
+	}
+}
+
+// generateWithFilterRetry 执行生成，内容被过滤时按配置改写提示词并重试
+func generateWithFilterRetry(ctx context.Context, o *Options, req ImageRequest,
+	generate func(context.Context, ImageRequest) (ImageResponse, error)) (ImageResponse, error) {
+	resp, err := generate(ctx, req)
+	if err == nil || o.FilterRetries <= 0 || !errors.Is(err, ErrContentFiltered) {
+		return resp, err
+	}
+
+	for attempt := 1; attempt <= o.FilterRetries; attempt++ {
+		if ctx.Err() != nil {
+			return ImageResponse{}, ctx.Err()
+		}
+
+		if o.PromptRewriter != nil {
+			req.Prompt = o.PromptRewriter(req.Prompt)
+		}
+
+		resp, err = generate(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if !errors.Is(err, ErrContentFiltered) {
+			return ImageResponse{}, err
+		}
+	}
+
+	// 持续被过滤
+	return ImageResponse{}, WrapError(err, fmt.Sprintf("content filtered after %d attempts", o.FilterRetries+1))
+}
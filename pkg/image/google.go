@@ -0,0 +1,485 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GoogleClient Google Imagen 图像生成客户端
+//
+// 通过 Vertex AI 调用 Imagen 系列模型，使用 WithAPIKey 传入 OAuth2 access token。
+type GoogleClient struct {
+	httpClient *http.Client
+	options    *Options
+}
+
+// Google Imagen 支持的模型
+const (
+	ModelImagen3     = "imagen-3.0-generate-002"
+	ModelImagen3V1   = "imagen-3.0-generate-001"
+	ModelImagen3Fast = "imagen-3.0-fast-generate-001"
+	ModelImagen4     = "imagen-4.0-generate-001"
+)
+
+// Google Vertex AI 默认区域
+const defaultGoogleRegion = "us-central1"
+
+// Google Imagen 支持的宽高比及对应尺寸
+var googleAspectRatioSizes = map[string]ImageSize{
+	"1:1":  {Width: 1024, Height: 1024},
+	"3:4":  {Width: 896, Height: 1280},
+	"4:3":  {Width: 1280, Height: 896},
+	"9:16": {Width: 768, Height: 1408},
+	"16:9": {Width: 1408, Height: 768},
+}
+
+// Google Imagen 支持的尺寸
+var googleSizes = []ImageSize{
+	{Width: 1024, Height: 1024},
+	{Width: 896, Height: 1280},
+	{Width: 1280, Height: 896},
+	{Width: 768, Height: 1408},
+	{Width: 1408, Height: 768},
+}
+
+// Google Imagen 价格表（美元/张）
+var googlePricing = priceTable{
+	currency: CurrencyUSD,
+	prices: map[priceKey]float64{
+		{Model: ModelImagen3}:     0.04,
+		{Model: ModelImagen3V1}:   0.04,
+		{Model: ModelImagen3Fast}: 0.02,
+		{Model: ModelImagen4}:     0.04,
+	},
+}
+
+// NewGoogle 创建 Google Imagen 图像生成客户端
+//
+// 未设置 BaseURL 时根据 WithProject 和 WithRegion 构建 Vertex AI 端点，
+// 二者均未设置时返回 ErrMissingEndpoint。
+func NewGoogle(opts ...Option) (*GoogleClient, error) {
+	options := DefaultOptions()
+	ApplyOptions(options, opts...)
+
+	if options.APIKey == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if options.Model == "" {
+		options.Model = ModelImagen3
+	}
+
+	if options.Region == "" {
+		options.Region = defaultGoogleRegion
+	}
+
+	if options.BaseURL == "" {
+		if options.ProjectID == "" {
+			return nil, ErrMissingEndpoint
+		}
+		options.BaseURL = fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1/projects/%s/locations/%s",
+			options.Region, options.ProjectID, options.Region)
+	}
+
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = NewHTTPClient(options)
+	}
+
+	return &GoogleClient{
+		httpClient: httpClient,
+		options:    options,
+	}, nil
+}
+
+// Name 返回提供商名称
+func (c *GoogleClient) Name() string {
+	return "google"
+}
+
+// Model 返回当前模型名称
+func (c *GoogleClient) Model() string {
+	return c.options.Model
+}
+
+// SupportedSizes 返回支持的图像尺寸
+func (c *GoogleClient) SupportedSizes() []ImageSize {
+	return googleSizes
+}
+
+// Capabilities 返回当前模型支持的功能
+func (c *GoogleClient) Capabilities() ProviderCapabilities {
+	return googleCapabilities(c.options.Model)
+}
+
+// googleNegativePromptModels 接受 negativePrompt 参数的 Imagen 模型
+//
+// imagen-3.0-generate-002 与 Imagen 4 已不再支持负面提示词。
+var googleNegativePromptModels = map[string]bool{
+	ModelImagen3V1:   true,
+	ModelImagen3Fast: true,
+}
+
+// googleCapabilities 返回 Imagen 指定模型支持的功能
+//
+// Imagen 按 token 数限制提示词长度，客户端不按字符数限制（MaxPromptLength 为 0）。
+func googleCapabilities(model string) ProviderCapabilities {
+	return ProviderCapabilities{
+		NegativePrompt:  googleNegativePromptModels[model],
+		Seed:            true,
+		MaxImages:       4,
+		ResponseFormats: []ResponseFormat{FormatBase64},
+	}
+}
+
+// Close 关闭客户端连接
+func (c *GoogleClient) Close() error {
+	return nil
+}
+
+// Generate 生成图像
+func (c *GoogleClient) Generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	return c.generatePrepared(ctx, prepared)
+}
+
+// prepare 按客户端配置规范化并验证请求
+func (c *GoogleClient) prepare(req ImageRequest) (preparedRequest, error) {
+	return c.options.prepareRequest(req, c.Name(), c.SupportedSizes(), c.Capabilities())
+}
+
+// generatePrepared 使用规范化后的请求生成图像
+func (c *GoogleClient) generatePrepared(ctx context.Context, prepared preparedRequest) (ImageResponse, error) {
+	req := prepared.req
+	resp, err := generateWithFilterRetry(ctx, c.options, req, c.generate)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	prepared.applyTo(&resp)
+	fillContentTypes(&resp)
+	fillSeeds(&resp, req)
+	return resp, nil
+}
+
+// generate 执行单次生成（带重试）
+func (c *GoogleClient) generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
+	// 执行请求（带重试）
+	var resp ImageResponse
+	var err error
+
+	err = c.retry(ctx, func() error {
+		if err := c.options.waitRateLimit(ctx); err != nil {
+			return err
+		}
+		resp, err = c.doRequest(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		return ImageResponse{}, err
+	}
+
+	resp.Model = c.options.Model
+	return resp, nil
+}
+
+// GenerateStream 以事件流方式生成图像
+func (c *GoogleClient) GenerateStream(ctx context.Context, req ImageRequest) (<-chan GenerationEvent, error) {
+	// 与 Generate 相同地先规范化再验证
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	return StreamFromGenerate(ctx, prepared.req, func(ctx context.Context, _ ImageRequest) (ImageResponse, error) {
+		return c.generatePrepared(ctx, prepared)
+	}), nil
+}
+
+// GenerateToWriter 生成图像并将第一张图像流式写入 w
+func (c *GoogleClient) GenerateToWriter(ctx context.Context, req ImageRequest, w io.Writer) (ImageResponse, error) {
+	return generateToWriter(ctx, req, w, c.Generate, c.httpClient)
+}
+
+// downloadClient 返回下载生成图像时使用的 HTTP 客户端
+func (c *GoogleClient) downloadClient() *http.Client {
+	return c.httpClient
+}
+
+// EstimateCost 估算请求费用
+func (c *GoogleClient) EstimateCost(req ImageRequest) (Cost, error) {
+	if _, ok := googleAspectRatioSizes[req.AspectRatio]; !ok {
+		size := resolveSize(req.Size, c.options.DefaultSize)
+		if !containsSize(googleSizes, size) {
+			return Cost{}, ErrUnsupportedSize
+		}
+	}
+
+	return googlePricing.estimate(c.options.Model, ImageSize{}, "", imageCount(req.N, 4))
+}
+
+// googleRequest Imagen predict 请求
+type googleRequest struct {
+	Instances  []googleInstance `json:"instances"`
+	Parameters googleParameters `json:"parameters"`
+}
+
+type googleInstance struct {
+	Prompt string `json:"prompt"`
+}
+
+type googleParameters struct {
+	SampleCount    int    `json:"sampleCount"`
+	AspectRatio    string `json:"aspectRatio,omitempty"`
+	NegativePrompt string `json:"negativePrompt,omitempty"`
+	Seed           *int64 `json:"seed,omitempty"`
+	AddWatermark   *bool  `json:"addWatermark,omitempty"`
+}
+
+// googleResponse Imagen predict 响应
+type googleResponse struct {
+	Predictions []struct {
+		BytesBase64Encoded string `json:"bytesBase64Encoded,omitempty"`
+		MimeType           string `json:"mimeType,omitempty"`
+		RAIFilteredReason  string `json:"raiFilteredReason,omitempty"`
+		// Score 候选图像质量评分（部分模型返回）
+		Score *float64 `json:"score,omitempty"`
+	} `json:"predictions"`
+	Error *googleError `json:"error,omitempty"`
+}
+
+type googleError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+// doRequest 执行 HTTP 请求
+func (c *GoogleClient) doRequest(ctx context.Context, req ImageRequest) (ImageResponse, error) {
+	// 构建请求
+	apiReq := c.buildRequest(req)
+
+	// 序列化请求
+	body, err := json.Marshal(apiReq)
+	if err != nil {
+		return ImageResponse{}, WrapError(err, "failed to marshal request")
+	}
+
+	// 创建 HTTP 请求
+	url := strings.TrimRight(c.options.BaseURL, "/") + "/publishers/google/models/" + c.options.Model + ":predict"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return ImageResponse{}, WrapError(err, "failed to create request")
+	}
+
+	setRequestIDHeader(httpReq)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.options.APIKey)
+
+	// 执行请求
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ImageResponse{}, ErrTimeout
+		}
+		return ImageResponse{}, WrapError(err, "request failed")
+	}
+	defer httpResp.Body.Close()
+
+	// 读取响应
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return ImageResponse{}, WrapError(err, "failed to read response")
+	}
+
+	// 解析响应
+	var apiResp googleResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		if httpResp.StatusCode != http.StatusOK {
+			return ImageResponse{}, newAPIError(httpResp, "", "", statusError(httpResp.StatusCode))
+		}
+		return ImageResponse{}, WrapError(err, "failed to parse response")
+	}
+
+	// 检查错误
+	if apiResp.Error != nil {
+		return ImageResponse{}, newAPIError(httpResp, apiResp.Error.Message, "",
+			c.mapError(httpResp.StatusCode, apiResp.Error))
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return ImageResponse{}, newAPIError(httpResp, "", "", statusError(httpResp.StatusCode))
+	}
+
+	return c.parseResponse(apiResp)
+}
+
+// buildRequest 构建 Imagen 请求
+func (c *GoogleClient) buildRequest(req ImageRequest) googleRequest {
+	apiReq := googleRequest{
+		Instances: []googleInstance{{Prompt: req.Prompt}},
+	}
+
+	// 不支持负面提示词的模型不发送 negativePrompt（可通过 WithNegativePromptEmulation 追加到提示词）
+	if googleNegativePromptModels[c.options.Model] {
+		apiReq.Parameters.NegativePrompt = req.NegativePrompt
+	}
+
+	// 设置生成数量（Imagen 最多 4 张）
+	if req.N > 0 && req.N <= 4 {
+		apiReq.Parameters.SampleCount = req.N
+	} else {
+		apiReq.Parameters.SampleCount = 1
+	}
+
+	// 设置宽高比
+	apiReq.Parameters.AspectRatio = c.mapAspectRatio(req)
+
+	// 设置种子（Imagen 要求关闭水印才能使用种子）
+	if req.Seed != nil {
+		apiReq.Parameters.Seed = req.Seed
+		addWatermark := false
+		apiReq.Parameters.AddWatermark = &addWatermark
+	}
+
+	return apiReq
+}
+
+// mapAspectRatio 映射尺寸或宽高比到 Imagen 支持的宽高比
+func (c *GoogleClient) mapAspectRatio(req ImageRequest) string {
+	if _, ok := googleAspectRatioSizes[req.AspectRatio]; ok {
+		return req.AspectRatio
+	}
+
+	size := req.Size
+	if size.Width == 0 || size.Height == 0 {
+		size = c.options.DefaultSize
+	}
+
+	targetRatio := size.AspectRatio()
+	closestAR := "1:1"
+	minDiff := 999.0
+
+	for ar, s := range googleAspectRatioSizes {
+		diff := absFloat(s.AspectRatio() - targetRatio)
+		if diff < minDiff {
+			minDiff = diff
+			closestAR = ar
+		}
+	}
+
+	return closestAR
+}
+
+// parseResponse 解析 Imagen 响应
+func (c *GoogleClient) parseResponse(resp googleResponse) (ImageResponse, error) {
+	result := ImageResponse{
+		Created: time.Now().Unix(),
+		Images:  make([]GeneratedImage, 0, len(resp.Predictions)),
+	}
+
+	filtered := false
+	for _, pred := range resp.Predictions {
+		if pred.BytesBase64Encoded == "" {
+			if pred.RAIFilteredReason != "" {
+				filtered = true
+			}
+			continue
+		}
+		contentType := pred.MimeType
+		if contentType == "" {
+			contentType = "image/png"
+		}
+		result.Images = append(result.Images, GeneratedImage{
+			Base64:      pred.BytesBase64Encoded,
+			ContentType: contentType,
+			Score:       pred.Score,
+		})
+	}
+
+	if len(result.Images) == 0 {
+		if filtered {
+			return ImageResponse{}, ErrContentFiltered
+		}
+		return ImageResponse{}, ErrInvalidResponse
+	}
+
+	return result, nil
+}
+
+// mapError 映射 Google 错误到框架错误
+func (c *GoogleClient) mapError(statusCode int, apiErr *googleError) error {
+	switch statusCode {
+	case 401, 403:
+		return ErrInvalidAPIKey
+	case 429:
+		return ErrQuotaExceeded
+	case 400:
+		msg := strings.ToLower(apiErr.Message)
+		if strings.Contains(msg, "safety") || strings.Contains(msg, "responsible ai") {
+			return ErrContentFiltered
+		}
+		return WrapError(ErrGenerationFailed, apiErr.Message)
+	case 404:
+		return WrapError(ErrModelNotSupported, apiErr.Message)
+	case 500, 502, 503:
+		return ErrProviderUnavailable
+	default:
+		return WrapError(ErrGenerationFailed, apiErr.Message)
+	}
+}
+
+// retry 执行带重试的操作
+func (c *GoogleClient) retry(ctx context.Context, fn func() error) error {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.options.MaxRetries; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+
+		if !IsRetryable(err) {
+			return err
+		}
+
+		if attempt < c.options.MaxRetries {
+			// #nosec G115 - attempt is bounded by MaxRetries (typically < 10)
+			delay := c.options.RetryDelay * time.Duration(1<<uint(attempt))
+			if delay > 30*time.Second {
+				delay = 30 * time.Second
+			}
+			delay = retryDelay(err, delay)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	return lastErr
+}
+
+// compile-time interface check
+var (
+	_ ImageProvider   = (*GoogleClient)(nil)
+	_ CostEstimator   = (*GoogleClient)(nil)
+	_ WriterGenerator = (*GoogleClient)(nil)
+)
@@ -28,7 +28,6 @@ const (
 // Hunyuan API 端点
 const (
 	defaultHunyuanHost = "hunyuan.tencentcloudapi.com"
-	hunyuanService     = "hunyuan"
 	hunyuanAction      = "TextToImage"
 	hunyuanVersion     = "2023-09-01"
 	hunyuanRegion      = "ap-guangzhou"
@@ -42,6 +41,14 @@ var hunyuanSizes = []ImageSize{
 	{Width: 1024, Height: 1024},
 }
 
+// Hunyuan 价格表（人民币/张）
+var hunyuanPricing = priceTable{
+	currency: CurrencyCNY,
+	prices: map[priceKey]float64{
+		{Model: ModelHunyuanImage}: 0.5,
+	},
+}
+
 // NewHunyuan 创建腾讯混元图像生成客户端
 func NewHunyuan(opts ...Option) (*HunyuanClient, error) {
 	options := DefaultOptions()
@@ -58,9 +65,7 @@ func NewHunyuan(opts ...Option) (*HunyuanClient, error) {
 
 	httpClient := options.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: options.Timeout,
-		}
+		httpClient = NewHTTPClient(options)
 	}
 
 	return &HunyuanClient{
@@ -84,6 +89,22 @@ func (c *HunyuanClient) SupportedSizes() []ImageSize {
 	return hunyuanSizes
 }
 
+// Capabilities 返回当前模型支持的功能
+func (c *HunyuanClient) Capabilities() ProviderCapabilities {
+	return hunyuanCapabilities(c.options.Model)
+}
+
+// hunyuanCapabilities 返回混元指定模型支持的功能
+func hunyuanCapabilities(model string) ProviderCapabilities {
+	return ProviderCapabilities{
+		NegativePrompt:  true,
+		Seed:            true,
+		MaxImages:       4,
+		MaxPromptLength: 1024,
+		ResponseFormats: []ResponseFormat{FormatURL, FormatBase64},
+	}
+}
+
 // Close 关闭客户端连接
 func (c *HunyuanClient) Close() error {
 	return nil
@@ -91,16 +112,41 @@ func (c *HunyuanClient) Close() error {
 
 // Generate 生成图像
 func (c *HunyuanClient) Generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
-	// 验证请求
-	if req.Prompt == "" {
-		return ImageResponse{}, ErrInvalidPrompt
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return ImageResponse{}, err
 	}
+	return c.generatePrepared(ctx, prepared)
+}
+
+// prepare 按客户端配置规范化并验证请求
+func (c *HunyuanClient) prepare(req ImageRequest) (preparedRequest, error) {
+	return c.options.prepareRequest(req, c.Name(), c.SupportedSizes(), c.Capabilities())
+}
+
+// generatePrepared 使用规范化后的请求生成图像
+func (c *HunyuanClient) generatePrepared(ctx context.Context, prepared preparedRequest) (ImageResponse, error) {
+	req := prepared.req
+	resp, err := generateWithFilterRetry(ctx, c.options, req, c.generate)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	prepared.applyTo(&resp)
+	fillContentTypes(&resp)
+	fillSeeds(&resp, req)
+	return resp, nil
+}
 
+// generate 执行单次生成（带重试）
+func (c *HunyuanClient) generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
 	// 执行请求（带重试）
 	var resp ImageResponse
 	var err error
 
 	err = c.retry(ctx, func() error {
+		if err := c.options.waitRateLimit(ctx); err != nil {
+			return err
+		}
 		resp, err = c.doRequest(ctx, req)
 		return err
 	})
@@ -113,6 +159,38 @@ func (c *HunyuanClient) Generate(ctx context.Context, req ImageRequest) (ImageRe
 	return resp, nil
 }
 
+// GenerateStream 以事件流方式生成图像
+func (c *HunyuanClient) GenerateStream(ctx context.Context, req ImageRequest) (<-chan GenerationEvent, error) {
+	// 与 Generate 相同地先规范化再验证
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	return StreamFromGenerate(ctx, prepared.req, func(ctx context.Context, _ ImageRequest) (ImageResponse, error) {
+		return c.generatePrepared(ctx, prepared)
+	}), nil
+}
+
+// GenerateToWriter 生成图像并将第一张图像流式写入 w
+func (c *HunyuanClient) GenerateToWriter(ctx context.Context, req ImageRequest, w io.Writer) (ImageResponse, error) {
+	return generateToWriter(ctx, req, w, c.Generate, c.httpClient)
+}
+
+// downloadClient 返回下载生成图像时使用的 HTTP 客户端
+func (c *HunyuanClient) downloadClient() *http.Client {
+	return c.httpClient
+}
+
+// EstimateCost 估算请求费用
+func (c *HunyuanClient) EstimateCost(req ImageRequest) (Cost, error) {
+	size := resolveSize(req.Size, c.options.DefaultSize)
+	if !containsSize(hunyuanSizes, size) {
+		return Cost{}, ErrUnsupportedSize
+	}
+
+	return hunyuanPricing.estimate(c.options.Model, ImageSize{}, "", imageCount(req.N, 4))
+}
+
 // hunyuanRequest 腾讯混元请求
 type hunyuanRequest struct {
 	Prompt         string `json:"Prompt"`
@@ -178,7 +256,8 @@ func (c *HunyuanClient) doRequest(ctx context.Context, req ImageRequest) (ImageR
 
 	// 检查错误
 	if apiResp.Response.Error != nil {
-		return ImageResponse{}, c.mapError(apiResp.Response.Error.Code, apiResp.Response.Error.Message)
+		return ImageResponse{}, newAPIError(httpResp, apiResp.Response.Error.Message, apiResp.Response.RequestID,
+			c.mapError(apiResp.Response.Error.Code, apiResp.Response.Error.Message))
 	}
 
 	return c.parseResponse(apiResp), nil
@@ -186,50 +265,101 @@ func (c *HunyuanClient) doRequest(ctx context.Context, req ImageRequest) (ImageR
 
 // createSignedRequest 创建带 TC3 签名的请求
 func (c *HunyuanClient) createSignedRequest(ctx context.Context, body []byte, timestamp int64) (*http.Request, error) {
-	host := defaultHunyuanHost
-	algorithm := "TC3-HMAC-SHA256"
-	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
-	credentialScope := date + "/" + hunyuanService + "/tc3_request"
-
-	// 计算请求签名
-	hashedPayload := sha256Hex(body)
-
-	// 规范请求
-	canonicalHeaders := "content-type:application/json\nhost:" + host + "\nx-tc-action:" + strings.ToLower(hunyuanAction) + "\n"
-	signedHeaders := "content-type;host;x-tc-action"
-	canonicalRequest := "POST\n/\n\n" + canonicalHeaders + "\n" + signedHeaders + "\n" + hashedPayload
-
-	// 待签名字符串
-	stringToSign := algorithm + "\n" + fmt.Sprintf("%d", timestamp) + "\n" + credentialScope + "\n" + sha256Hex([]byte(canonicalRequest))
-
-	// 计算签名
-	secretDate := hmacSHA256([]byte("TC3"+c.options.SecretKey), date)
-	secretService := hmacSHA256(secretDate, hunyuanService)
-	secretSigning := hmacSHA256(secretService, "tc3_request")
-	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
-
-	// 构建 Authorization
-	authorization := algorithm + " Credential=" + c.options.APIKey + "/" + credentialScope +
-		", SignedHeaders=" + signedHeaders + ", Signature=" + signature
-
 	// 创建请求
-	url := "https://" + host
+	url := "https://" + defaultHunyuanHost
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, WrapError(err, "failed to create request")
 	}
 
+	setRequestIDHeader(httpReq)
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Host", host)
 	httpReq.Header.Set("X-TC-Action", hunyuanAction)
 	httpReq.Header.Set("X-TC-Version", hunyuanVersion)
 	httpReq.Header.Set("X-TC-Timestamp", fmt.Sprintf("%d", timestamp))
 	httpReq.Header.Set("X-TC-Region", hunyuanRegion)
-	httpReq.Header.Set("Authorization", authorization)
+
+	sig, err := hunyuanSign(httpReq, c.options.APIKey, c.options.SecretKey, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", sig.authorization)
 
 	return httpReq, nil
 }
 
+// hunyuanSignature TC3-HMAC-SHA256 签名结果及中间产物
+type hunyuanSignature struct {
+	// canonicalRequest 规范请求串
+	canonicalRequest string
+	// stringToSign 待签名字符串
+	stringToSign string
+	// authorization Authorization 请求头的值
+	authorization string
+}
+
+// hunyuanSign 按腾讯云 API 3.0 的 TC3-HMAC-SHA256 算法为请求签名
+//
+// 签名头固定包含 content-type 与 host，请求设置了 X-TC-Action 时一并签名；
+// 头的值按规范转为小写。服务名取自主机名的第一段（如 hunyuan.tencentcloudapi.com
+// 对应 hunyuan），请求体通过 GetBody 读取以计算载荷哈希，不消耗 req.Body。
+func hunyuanSign(req *http.Request, secretID, secretKey string, timestamp int64) (hunyuanSignature, error) {
+	const algorithm = "TC3-HMAC-SHA256"
+
+	var body []byte
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return hunyuanSignature{}, WrapError(err, "failed to read request body")
+		}
+		body, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return hunyuanSignature{}, WrapError(err, "failed to read request body")
+		}
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	service, _, _ := strings.Cut(host, ".")
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	credentialScope := date + "/" + service + "/tc3_request"
+
+	// 规范请求
+	canonicalHeaders := "content-type:" + strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Type"))) + "\n" +
+		"host:" + strings.ToLower(host) + "\n"
+	signedHeaders := "content-type;host"
+	if action := req.Header.Get("X-TC-Action"); action != "" {
+		canonicalHeaders += "x-tc-action:" + strings.ToLower(strings.TrimSpace(action)) + "\n"
+		signedHeaders += ";x-tc-action"
+	}
+	uri := req.URL.Path
+	if uri == "" {
+		uri = "/"
+	}
+	canonicalRequest := req.Method + "\n" + uri + "\n" + req.URL.RawQuery + "\n" +
+		canonicalHeaders + "\n" + signedHeaders + "\n" + sha256Hex(body)
+
+	// 待签名字符串
+	stringToSign := algorithm + "\n" + fmt.Sprintf("%d", timestamp) + "\n" + credentialScope + "\n" +
+		sha256Hex([]byte(canonicalRequest))
+
+	// 计算签名
+	secretDate := hmacSHA256([]byte("TC3"+secretKey), date)
+	secretService := hmacSHA256(secretDate, service)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return hunyuanSignature{
+		canonicalRequest: canonicalRequest,
+		stringToSign:     stringToSign,
+		authorization: algorithm + " Credential=" + secretID + "/" + credentialScope +
+			", SignedHeaders=" + signedHeaders + ", Signature=" + signature,
+	}, nil
+}
+
 // buildRequest 构建混元请求
 func (c *HunyuanClient) buildRequest(req ImageRequest) hunyuanRequest {
 	apiReq := hunyuanRequest{
@@ -360,6 +490,7 @@ func (c *HunyuanClient) retry(ctx context.Context, fn func() error) error {
 			if delay > 30*time.Second {
 				delay = 30 * time.Second
 			}
+			delay = retryDelay(err, delay)
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -385,4 +516,8 @@ func hmacSHA256(key []byte, data string) []byte {
 }
 
 // compile-time interface check
-var _ ImageProvider = (*HunyuanClient)(nil)
+var (
+	_ ImageProvider   = (*HunyuanClient)(nil)
+	_ CostEstimator   = (*HunyuanClient)(nil)
+	_ WriterGenerator = (*HunyuanClient)(nil)
+)
@@ -0,0 +1,121 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// 腾讯云 API 3.0 签名文档中的示例（CVM DescribeInstances）
+const (
+	tc3ExampleSecretID  = "AKIDz8krbsJ5yKBZQpn74WFkmLPx3*******"
+	tc3ExampleSecretKey = "Gu5t9xGARNpq86cd98joQYCN3*******"
+	tc3ExampleTimestamp = 1551113065
+	tc3ExampleBody      = `{"Limit": 1, "Filters": [{"Values": ["\u672a\u547d\u540d"], "Name": "instance-name"}]}`
+	tc3ExamplePayload   = "35e9c5b0e3ae67532d3c9f17ead6c90222632e5b1ff7f6e89887f1398934f064"
+)
+
+func newTC3ExampleRequest(t *testing.T, action string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "https://cvm.tencentcloudapi.com", bytes.NewReader([]byte(tc3ExampleBody)))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if action != "" {
+		req.Header.Set("X-TC-Action", action)
+	}
+	return req
+}
+
+func TestHunyuanSign_DocumentedExample(t *testing.T) {
+	tests := []struct {
+		name             string
+		action           string
+		canonicalRequest string
+		stringToSign     string
+		authorization    string
+	}{
+		{
+			name:   "content-type and host",
+			action: "",
+			canonicalRequest: "POST\n/\n\n" +
+				"content-type:application/json; charset=utf-8\nhost:cvm.tencentcloudapi.com\n\n" +
+				"content-type;host\n" + tc3ExamplePayload,
+			stringToSign: "TC3-HMAC-SHA256\n1551113065\n2019-02-25/cvm/tc3_request\n" +
+				"5ffe6a04c0664d6b969fab9a13bdab201d63ee709638e2749d62a09ca18d7031",
+			authorization: "TC3-HMAC-SHA256 Credential=" + tc3ExampleSecretID + "/2019-02-25/cvm/tc3_request, " +
+				"SignedHeaders=content-type;host, " +
+				"Signature=2230eefd229f582d8b1b891af7107b91597240707d778ab3738f756258d7652c",
+		},
+		{
+			name:   "with x-tc-action",
+			action: "DescribeInstances",
+			canonicalRequest: "POST\n/\n\n" +
+				"content-type:application/json; charset=utf-8\nhost:cvm.tencentcloudapi.com\nx-tc-action:describeinstances\n\n" +
+				"content-type;host;x-tc-action\n" + tc3ExamplePayload,
+			stringToSign: "TC3-HMAC-SHA256\n1551113065\n2019-02-25/cvm/tc3_request\n" +
+				"7019a55be8395899b900fb5564e4200d984910f34794a27cb3fb7d10ff6a1e84",
+			authorization: "TC3-HMAC-SHA256 Credential=" + tc3ExampleSecretID + "/2019-02-25/cvm/tc3_request, " +
+				"SignedHeaders=content-type;host;x-tc-action, " +
+				"Signature=be4f67d323c78ab9acb7395e43c0dbcf822a9cfac32fea2449a7bc7726b770a3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := hunyuanSign(newTC3ExampleRequest(t, tt.action), tc3ExampleSecretID, tc3ExampleSecretKey, tc3ExampleTimestamp)
+			if err != nil {
+				t.Fatalf("hunyuanSign() error = %v", err)
+			}
+			if sig.canonicalRequest != tt.canonicalRequest {
+				t.Errorf("canonical request =\n%s\nwant\n%s", sig.canonicalRequest, tt.canonicalRequest)
+			}
+			if sig.stringToSign != tt.stringToSign {
+				t.Errorf("string to sign =\n%s\nwant\n%s", sig.stringToSign, tt.stringToSign)
+			}
+			if sig.authorization != tt.authorization {
+				t.Errorf("authorization =\n%s\nwant\n%s", sig.authorization, tt.authorization)
+			}
+		})
+	}
+}
+
+func TestHunyuanClient_CreateSignedRequest(t *testing.T) {
+	client, err := NewHunyuan(WithAPIKey("secret-id"), WithSecretKey("secret-key"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	body := []byte(`{"Prompt":"a cat"}`)
+	req, err := client.createSignedRequest(context.Background(), body, tc3ExampleTimestamp)
+	if err != nil {
+		t.Fatalf("createSignedRequest() error = %v", err)
+	}
+
+	sig, err := hunyuanSign(req, "secret-id", "secret-key", tc3ExampleTimestamp)
+	if err != nil {
+		t.Fatalf("hunyuanSign() error = %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != sig.authorization {
+		t.Errorf("Authorization = %s, want %s", got, sig.authorization)
+	}
+	if !strings.Contains(sig.authorization, "/2019-02-25/hunyuan/tc3_request") {
+		t.Errorf("expected hunyuan credential scope, got %s", sig.authorization)
+	}
+	if !strings.HasSuffix(sig.canonicalRequest, "\n"+sha256Hex(body)) {
+		t.Errorf("canonical request should end with the JSON body hash, got %s", sig.canonicalRequest)
+	}
+
+	// 签名不应消耗请求体
+	sent, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	if !bytes.Equal(sent, body) {
+		t.Errorf("request body = %s, want %s", sent, body)
+	}
+}
@@ -0,0 +1,34 @@
+package image
+
+import "strings"
+
+// WithNegativePromptEmulation 设置是否为不支持负面提示词的提供商模拟 NegativePrompt（默认关闭）
+//
+// 开启后，对 Capabilities().NegativePrompt 为 false 的提供商（如 OpenAI），将负面提示词
+// 以 "Avoid: …" 子句追加到提示词末尾；原生支持的提供商不受影响。
+func WithNegativePromptEmulation(enabled bool) Option {
+	return func(o *Options) {
+		o.NegativePromptEmulation = enabled
+	}
+}
+
+// EmulateNegativePrompt 将负面提示词以 "Avoid: …" 子句追加到提示词末尾
+//
+// negative 为空时原样返回 prompt。
+func EmulateNegativePrompt(prompt, negative string) string {
+	negative = strings.TrimSpace(negative)
+	if negative == "" {
+		return prompt
+	}
+	return strings.TrimSpace(prompt) + "\n\nAvoid: " + negative
+}
+
+// emulateNegativePrompt 按配置为不支持负面提示词的提供商改写请求
+func (o *Options) emulateNegativePrompt(req ImageRequest, caps ProviderCapabilities) ImageRequest {
+	if !o.NegativePromptEmulation || caps.NegativePrompt || req.NegativePrompt == "" {
+		return req
+	}
+	req.Prompt = EmulateNegativePrompt(req.Prompt, req.NegativePrompt)
+	req.NegativePrompt = ""
+	return req
+}
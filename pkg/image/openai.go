@@ -12,7 +12,8 @@ import (
 
 // OpenAIClient OpenAI 图像生成客户端
 //
-// 支持 DALL-E 3 和 GPT Image 系列模型。
+// 支持 DALL-E 3 和 GPT Image 系列模型。接口不支持随机种子，ImageRequest.Seed 被忽略，
+// 返回图像的 Seed 始终为空。
 type OpenAIClient struct {
 	httpClient *http.Client
 	options    *Options
@@ -47,6 +48,37 @@ var openAIGPTImageSizes = []ImageSize{
 	{Width: 1536, Height: 1024},
 }
 
+// OpenAI 价格表（美元/张，GPT Image 的 standard/hd 对应 medium/high）
+var openAIPricing = priceTable{
+	currency: CurrencyUSD,
+	prices: map[priceKey]float64{
+		{Model: ModelDALLE3, Size: ImageSize{Width: 1024, Height: 1024}, Quality: QualityStandard}: 0.040,
+		{Model: ModelDALLE3, Size: ImageSize{Width: 1024, Height: 1792}, Quality: QualityStandard}: 0.080,
+		{Model: ModelDALLE3, Size: ImageSize{Width: 1792, Height: 1024}, Quality: QualityStandard}: 0.080,
+		{Model: ModelDALLE3, Size: ImageSize{Width: 1024, Height: 1024}, Quality: QualityHD}:       0.080,
+		{Model: ModelDALLE3, Size: ImageSize{Width: 1024, Height: 1792}, Quality: QualityHD}:       0.120,
+		{Model: ModelDALLE3, Size: ImageSize{Width: 1792, Height: 1024}, Quality: QualityHD}:       0.120,
+
+		{Model: ModelDALLE2, Size: ImageSize{Width: 256, Height: 256}}:   0.016,
+		{Model: ModelDALLE2, Size: ImageSize{Width: 512, Height: 512}}:   0.018,
+		{Model: ModelDALLE2, Size: ImageSize{Width: 1024, Height: 1024}}: 0.020,
+
+		{Model: ModelGPTImage1, Size: ImageSize{Width: 1024, Height: 1024}, Quality: QualityStandard}: 0.042,
+		{Model: ModelGPTImage1, Size: ImageSize{Width: 1024, Height: 1536}, Quality: QualityStandard}: 0.063,
+		{Model: ModelGPTImage1, Size: ImageSize{Width: 1536, Height: 1024}, Quality: QualityStandard}: 0.063,
+		{Model: ModelGPTImage1, Size: ImageSize{Width: 1024, Height: 1024}, Quality: QualityHD}:       0.167,
+		{Model: ModelGPTImage1, Size: ImageSize{Width: 1024, Height: 1536}, Quality: QualityHD}:       0.250,
+		{Model: ModelGPTImage1, Size: ImageSize{Width: 1536, Height: 1024}, Quality: QualityHD}:       0.250,
+
+		{Model: ModelGPTImage1Min, Size: ImageSize{Width: 1024, Height: 1024}, Quality: QualityStandard}: 0.011,
+		{Model: ModelGPTImage1Min, Size: ImageSize{Width: 1024, Height: 1536}, Quality: QualityStandard}: 0.015,
+		{Model: ModelGPTImage1Min, Size: ImageSize{Width: 1536, Height: 1024}, Quality: QualityStandard}: 0.015,
+		{Model: ModelGPTImage1Min, Size: ImageSize{Width: 1024, Height: 1024}, Quality: QualityHD}:       0.036,
+		{Model: ModelGPTImage1Min, Size: ImageSize{Width: 1024, Height: 1536}, Quality: QualityHD}:       0.052,
+		{Model: ModelGPTImage1Min, Size: ImageSize{Width: 1536, Height: 1024}, Quality: QualityHD}:       0.052,
+	},
+}
+
 // NewOpenAI 创建 OpenAI 图像生成客户端
 func NewOpenAI(opts ...Option) (*OpenAIClient, error) {
 	options := DefaultOptions()
@@ -66,9 +98,7 @@ func NewOpenAI(opts ...Option) (*OpenAIClient, error) {
 
 	httpClient := options.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: options.Timeout,
-		}
+		httpClient = NewHTTPClient(options)
 	}
 
 	return &OpenAIClient{
@@ -95,6 +125,39 @@ func (c *OpenAIClient) SupportedSizes() []ImageSize {
 	return openAIDALLE3Sizes
 }
 
+// Capabilities 返回当前模型支持的功能
+func (c *OpenAIClient) Capabilities() ProviderCapabilities {
+	return openAICapabilities(c.options.Model)
+}
+
+// openAICapabilities 返回 OpenAI 指定模型支持的功能
+func openAICapabilities(model string) ProviderCapabilities {
+	caps := ProviderCapabilities{
+		MaxImages:       10,
+		MaxPromptLength: openAIMaxPromptLength(model),
+		ResponseFormats: []ResponseFormat{FormatURL, FormatBase64},
+	}
+	// 质量与风格仅对 DALL-E 3 传递，且 DALL-E 3 只支持 n=1
+	if model == ModelDALLE3 {
+		caps.MaxImages = 1
+		caps.Styles = []ImageStyle{StyleNatural, StyleVivid}
+		caps.Qualities = []ImageQuality{QualityHD, QualityStandard}
+	}
+	return caps
+}
+
+// openAIMaxPromptLength 返回 OpenAI 指定模型的提示词长度上限
+func openAIMaxPromptLength(model string) int {
+	switch {
+	case model == ModelDALLE2:
+		return 1000
+	case isGPTImageModel(model):
+		return 32000
+	default:
+		return 4000
+	}
+}
+
 // Close 关闭客户端连接
 func (c *OpenAIClient) Close() error {
 	return nil
@@ -102,11 +165,32 @@ func (c *OpenAIClient) Close() error {
 
 // Generate 生成图像
 func (c *OpenAIClient) Generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
-	// 验证请求
-	if req.Prompt == "" {
-		return ImageResponse{}, ErrInvalidPrompt
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	return c.generatePrepared(ctx, prepared)
+}
+
+// prepare 按客户端配置规范化并验证请求
+func (c *OpenAIClient) prepare(req ImageRequest) (preparedRequest, error) {
+	return c.options.prepareRequest(req, c.Name(), c.SupportedSizes(), c.Capabilities())
+}
+
+// generatePrepared 使用规范化后的请求生成图像
+func (c *OpenAIClient) generatePrepared(ctx context.Context, prepared preparedRequest) (ImageResponse, error) {
+	req := prepared.req
+	resp, err := generateWithFilterRetry(ctx, c.options, req, c.generate)
+	if err != nil {
+		return ImageResponse{}, err
 	}
+	prepared.applyTo(&resp)
+	fillContentTypes(&resp)
+	return resp, nil
+}
 
+// generate 执行单次生成（带重试）
+func (c *OpenAIClient) generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
 	// 构建请求
 	apiReq := c.buildRequest(req)
 
@@ -115,6 +199,9 @@ func (c *OpenAIClient) Generate(ctx context.Context, req ImageRequest) (ImageRes
 	var err error
 
 	err = c.retry(ctx, func() error {
+		if err := c.options.waitRateLimit(ctx); err != nil {
+			return err
+		}
 		resp, err = c.doRequest(ctx, apiReq)
 		return err
 	})
@@ -127,6 +214,45 @@ func (c *OpenAIClient) Generate(ctx context.Context, req ImageRequest) (ImageRes
 	return resp, nil
 }
 
+// GenerateStream 以事件流方式生成图像
+func (c *OpenAIClient) GenerateStream(ctx context.Context, req ImageRequest) (<-chan GenerationEvent, error) {
+	// 与 Generate 相同地先规范化再验证
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	return StreamFromGenerate(ctx, prepared.req, func(ctx context.Context, _ ImageRequest) (ImageResponse, error) {
+		return c.generatePrepared(ctx, prepared)
+	}), nil
+}
+
+// GenerateToWriter 生成图像并将第一张图像流式写入 w
+func (c *OpenAIClient) GenerateToWriter(ctx context.Context, req ImageRequest, w io.Writer) (ImageResponse, error) {
+	return generateToWriter(ctx, req, w, c.Generate, c.httpClient)
+}
+
+// downloadClient 返回下载生成图像时使用的 HTTP 客户端
+func (c *OpenAIClient) downloadClient() *http.Client {
+	return c.httpClient
+}
+
+// EstimateCost 估算请求费用
+func (c *OpenAIClient) EstimateCost(req ImageRequest) (Cost, error) {
+	size := resolveSize(req.Size, c.options.DefaultSize)
+	quality := req.Quality
+	if quality == "" {
+		quality = c.options.DefaultQuality
+	}
+
+	// DALL-E 3 只支持 n=1
+	count := imageCount(req.N, 0)
+	if c.options.Model == ModelDALLE3 {
+		count = 1
+	}
+
+	return openAIPricing.estimate(c.options.Model, size, quality, count)
+}
+
 // openAIImageRequest OpenAI 图像生成 API 请求
 type openAIImageRequest struct {
 	Model          string `json:"model"`
@@ -260,6 +386,7 @@ func (c *OpenAIClient) doRequest(ctx context.Context, apiReq openAIImageRequest)
 		return ImageResponse{}, WrapError(err, "failed to create request")
 	}
 
+	setRequestIDHeader(httpReq)
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+c.options.APIKey)
 
@@ -282,17 +409,20 @@ func (c *OpenAIClient) doRequest(ctx context.Context, apiReq openAIImageRequest)
 	// 解析响应
 	var apiResp openAIImageResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		if httpResp.StatusCode != http.StatusOK {
+			return ImageResponse{}, newAPIError(httpResp, "", "", statusError(httpResp.StatusCode))
+		}
 		return ImageResponse{}, WrapError(err, "failed to parse response")
 	}
 
 	// 检查错误
 	if apiResp.Error != nil {
-		return ImageResponse{}, c.mapError(httpResp.StatusCode, apiResp.Error)
+		return ImageResponse{}, newAPIError(httpResp, apiResp.Error.Message, "",
+			c.mapError(httpResp.StatusCode, apiResp.Error))
 	}
 
 	if httpResp.StatusCode != http.StatusOK {
-		return ImageResponse{}, WrapError(ErrGenerationFailed,
-			fmt.Sprintf("unexpected status code: %d", httpResp.StatusCode))
+		return ImageResponse{}, newAPIError(httpResp, "", "", statusError(httpResp.StatusCode))
 	}
 
 	// 转换响应
@@ -365,6 +495,7 @@ func (c *OpenAIClient) retry(ctx context.Context, fn func() error) error {
 			if delay > 30*time.Second {
 				delay = 30 * time.Second
 			}
+			delay = retryDelay(err, delay)
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -392,4 +523,8 @@ func abs(x int) int {
 }
 
 // compile-time interface check
-var _ ImageProvider = (*OpenAIClient)(nil)
+var (
+	_ ImageProvider   = (*OpenAIClient)(nil)
+	_ CostEstimator   = (*OpenAIClient)(nil)
+	_ WriterGenerator = (*OpenAIClient)(nil)
+)
@@ -3,6 +3,8 @@ package image
 import (
 	"net/http"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // Option 图像生成配置选项函数
@@ -18,6 +20,10 @@ type Options struct {
 	BaseURL string
 	// Model 模型名称
 	Model string
+	// ProjectID 云项目 ID（Google Vertex AI 需要）
+	ProjectID string
+	// Region 服务区域（Google Vertex AI 需要）
+	Region string
 	// Timeout 请求超时
 	Timeout time.Duration
 	// MaxRetries 最大重试次数
@@ -26,6 +32,24 @@ type Options struct {
 	RetryDelay time.Duration
 	// HTTPClient 自定义 HTTP 客户端
 	HTTPClient *http.Client
+	// RateLimiter 请求限流器（实例内共享）
+	RateLimiter *rate.Limiter
+	// Transport 连接池调优参数（未设置 HTTPClient 时生效）
+	Transport *TransportTuning
+	// FilterRetries 内容过滤后的重试次数
+	FilterRetries int
+	// PromptRewriter 内容过滤重试时的提示词改写函数
+	PromptRewriter PromptRewriter
+	// PromptSanitization 是否清洗提示词中的控制字符（默认开启）
+	PromptSanitization bool
+	// NegativePromptEmulation 是否为不支持负面提示词的提供商模拟 NegativePrompt（默认关闭）
+	NegativePromptEmulation bool
+	// SizeSnapping 是否将不支持的尺寸吸附到最接近的支持尺寸（默认关闭）
+	SizeSnapping bool
+	// PromptTruncation 提示词超出提供商长度上限时是否截断（默认关闭，即返回错误）
+	PromptTruncation bool
+	// StrictExtra 是否严格校验 ImageRequest.Extra（默认关闭，仅记录警告）
+	StrictExtra bool
 	// DefaultSize 默认图像尺寸
 	DefaultSize ImageSize
 	// DefaultQuality 默认质量
@@ -45,6 +69,8 @@ func DefaultOptions() *Options {
 		DefaultSize:    ImageSize{Width: 1024, Height: 1024},
 		DefaultQuality: QualityStandard,
 		DefaultFormat:  FormatURL,
+
+		PromptSanitization: true,
 	}
 }
 
@@ -76,6 +102,20 @@ func WithModel(model string) Option {
 	}
 }
 
+// WithProject 设置云项目 ID（Google Vertex AI）
+func WithProject(projectID string) Option {
+	return func(o *Options) {
+		o.ProjectID = projectID
+	}
+}
+
+// WithRegion 设置服务区域（Google Vertex AI）
+func WithRegion(region string) Option {
+	return func(o *Options) {
+		o.Region = region
+	}
+}
+
 // WithTimeout 设置超时时间
 func WithTimeout(d time.Duration) Option {
 	return func(o *Options) {
@@ -132,6 +172,47 @@ func WithDefaultFormat(format ResponseFormat) Option {
 	}
 }
 
+// TransportTuning HTTP 连接池调优参数
+type TransportTuning struct {
+	// MaxIdleConns 最大空闲连接数
+	MaxIdleConns int
+	// MaxConnsPerHost 每个主机的最大连接数（同时作为每主机最大空闲连接数）
+	MaxConnsPerHost int
+	// IdleConnTimeout 空闲连接超时
+	IdleConnTimeout time.Duration
+}
+
+// WithTransportTuning 设置连接池调优参数
+//
+// 仅在未通过 WithHTTPClient 提供自定义客户端时生效，用于高吞吐场景减少连接重建。
+func WithTransportTuning(maxIdleConns, maxConnsPerHost int, idleTimeout time.Duration) Option {
+	return func(o *Options) {
+		o.Transport = &TransportTuning{
+			MaxIdleConns:    maxIdleConns,
+			MaxConnsPerHost: maxConnsPerHost,
+			IdleConnTimeout: idleTimeout,
+		}
+	}
+}
+
+// NewHTTPClient 根据选项创建提供商默认使用的 HTTP 客户端
+func NewHTTPClient(o *Options) *http.Client {
+	client := &http.Client{
+		Timeout: o.Timeout,
+	}
+
+	if o.Transport != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConns = o.Transport.MaxIdleConns
+		transport.MaxIdleConnsPerHost = o.Transport.MaxConnsPerHost
+		transport.MaxConnsPerHost = o.Transport.MaxConnsPerHost
+		transport.IdleConnTimeout = o.Transport.IdleConnTimeout
+		client.Transport = transport
+	}
+
+	return client
+}
+
 // ApplyOptions 应用选项到 Options
 func ApplyOptions(opts *Options, options ...Option) {
 	for _, opt := range options {
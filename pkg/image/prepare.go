@@ -0,0 +1,60 @@
+package image
+
+// preparedRequest 规范化后的请求及规范化过程中的改写记录
+type preparedRequest struct {
+	req ImageRequest
+
+	// sanitized 提示词是否被清洗修改
+	sanitized bool
+
+	// truncated 提示词是否被截断
+	truncated bool
+
+	// requestedSize 尺寸被吸附时的原始尺寸，未吸附时为零值
+	requestedSize ImageSize
+}
+
+// prepareRequest 按配置和提供商能力规范化请求并验证
+//
+// 依次清洗提示词、吸附尺寸、模拟负面提示词、限制提示词长度并校验厂商特定参数，最后调用
+// Validate。各提供商的 Generate 与 GenerateStream 均经由该方法处理请求，两者对同一请求
+// 的接受与拒绝保持一致。
+func (o *Options) prepareRequest(req ImageRequest, provider string, sizes []ImageSize, caps ProviderCapabilities) (preparedRequest, error) {
+	var prepared preparedRequest
+
+	// 清洗提示词
+	req, prepared.sanitized = o.sanitizeRequest(req)
+
+	// 按配置将不支持的尺寸吸附到最接近的支持尺寸
+	req, prepared.requestedSize = o.snapSize(req, sizes)
+
+	// 不支持负面提示词的提供商按配置将其追加到提示词中
+	req = o.emulateNegativePrompt(req, caps)
+
+	// 按提供商上限检查提示词长度，按配置截断
+	req, truncated, err := o.limitPrompt(req, provider, caps.MaxPromptLength)
+	if err != nil {
+		return preparedRequest{}, err
+	}
+	prepared.truncated = truncated
+
+	// 校验厂商特定参数，严格模式下拒绝未知或类型错误的参数
+	if err := o.checkExtra(provider, caps.ExtraParams, req.Extra); err != nil {
+		return preparedRequest{}, err
+	}
+
+	// 验证请求
+	if err := req.Validate(); err != nil {
+		return preparedRequest{}, err
+	}
+
+	prepared.req = req
+	return prepared, nil
+}
+
+// applyTo 在响应中记录规范化过程中的改写
+func (p preparedRequest) applyTo(resp *ImageResponse) {
+	resp.PromptSanitized = p.sanitized
+	resp.PromptTruncated = p.truncated
+	recordSizeSnap(resp, p.requestedSize, p.req.Size)
+}
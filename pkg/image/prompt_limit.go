@@ -0,0 +1,80 @@
+package image
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithPromptTruncation 设置提示词超出提供商长度上限时是否截断（默认关闭）
+//
+// 关闭时超长提示词在发起请求前返回 *PromptLengthError；开启时截断到上限，
+// 并设置 ImageResponse.PromptTruncated。
+func WithPromptTruncation(enabled bool) Option {
+	return func(o *Options) {
+		o.PromptTruncation = enabled
+	}
+}
+
+// PromptLengthError 提示词超出提供商的长度上限
+//
+// 可用 errors.Is(err, ErrInvalidPrompt) 判断。
+type PromptLengthError struct {
+	// Provider 提供商名称
+	Provider string
+
+	// Limit 长度上限（字符数）
+	Limit int
+
+	// Length 实际长度（字符数）
+	Length int
+}
+
+// Error 实现 error 接口
+func (e *PromptLengthError) Error() string {
+	return fmt.Sprintf("invalid prompt: %d characters exceeds %s limit of %d", e.Length, e.Provider, e.Limit)
+}
+
+// Unwrap 返回 ErrInvalidPrompt
+func (e *PromptLengthError) Unwrap() error {
+	return ErrInvalidPrompt
+}
+
+// checkPromptLength 检查提示词是否超出长度上限（按字符计），limit 非正表示不限制
+func checkPromptLength(provider, prompt string, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+	if n := utf8.RuneCountInString(prompt); n > limit {
+		return &PromptLengthError{Provider: provider, Limit: limit, Length: n}
+	}
+	return nil
+}
+
+// limitPrompt 按提供商长度上限处理提示词，返回处理后的请求及是否截断
+//
+// 未开启截断时超长提示词返回 *PromptLengthError。
+func (o *Options) limitPrompt(req ImageRequest, provider string, limit int) (ImageRequest, bool, error) {
+	err := checkPromptLength(provider, req.Prompt, limit)
+	if err == nil {
+		return req, false, nil
+	}
+	if !o.PromptTruncation {
+		return req, false, err
+	}
+	req.Prompt = truncateRunes(req.Prompt, limit)
+	return req, true, nil
+}
+
+// truncateRunes 将字符串截断为最多 limit 个字符，并去除截断处的尾部空白
+func truncateRunes(s string, limit int) string {
+	count := 0
+	for i := range s {
+		if count == limit {
+			return strings.TrimRightFunc(s[:i], unicode.IsSpace)
+		}
+		count++
+	}
+	return s
+}
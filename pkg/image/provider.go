@@ -20,6 +20,12 @@ type ImageProvider interface {
 	//   - error: 调用错误
 	Generate(ctx context.Context, req ImageRequest) (ImageResponse, error)
 
+	// GenerateStream 以事件流方式生成图像
+	//
+	// 返回的 channel 依次产生进度事件，并以一个终止事件（完成或失败）结束，随后关闭。
+	// 不支持原生进度的提供商只发送开始事件和最终结果。
+	GenerateStream(ctx context.Context, req ImageRequest) (<-chan GenerationEvent, error)
+
 	// Name 返回提供商名称
 	Name() string
 
@@ -140,6 +146,15 @@ type ImageResponse struct {
 
 	// Model 使用的模型
 	Model string `json:"model,omitempty"`
+
+	// PromptSanitized 提示词是否经过清洗改动
+	PromptSanitized bool `json:"prompt_sanitized,omitempty"`
+
+	// PromptTruncated 提示词是否因超出长度上限被截断（见 WithPromptTruncation）
+	PromptTruncated bool `json:"prompt_truncated,omitempty"`
+
+	// Extra 额外元数据（如开启尺寸吸附时的 requested_size、snapped_size）
+	Extra map[string]interface{} `json:"extra,omitempty"`
 }
 
 // GeneratedImage 生成的单张图像
@@ -158,6 +173,9 @@ type GeneratedImage struct {
 
 	// ContentType 图像内容类型，如 "image/png"
 	ContentType string `json:"content_type,omitempty"`
+
+	// Score 厂商返回的候选图像质量评分（越高越好，未提供时为 nil）
+	Score *float64 `json:"score,omitempty"`
 }
 
 // formatSize 格式化尺寸为字符串
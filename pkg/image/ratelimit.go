@@ -0,0 +1,33 @@
+package image
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit 设置请求速率限制
+//
+// rps 为每秒允许的请求数，burst 为突发请求上限（小于 1 时按 1 处理）。
+// 限流器在提供商实例内共享，并发的 Generate 调用（包括 GenerateBatch 的工作协程）
+// 共同受同一个限流器约束。
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *Options) {
+		if rps <= 0 {
+			o.RateLimiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		o.RateLimiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// waitRateLimit 等待限流器放行，未配置限流时直接返回
+func (o *Options) waitRateLimit(ctx context.Context) error {
+	if o.RateLimiter == nil {
+		return nil
+	}
+	return o.RateLimiter.Wait(ctx)
+}
@@ -0,0 +1,33 @@
+package image
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestIDHeader 携带调用方请求 ID 的 HTTP 请求头
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey 请求 ID 的 context 键
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求 ID 的 context
+//
+// 使用该 context 调用 Generate 时，发往提供商的请求会带上 X-Request-ID 头；
+// 提供商未返回自身的请求 ID 时，APIError.RequestID 回退为该 ID，便于跨服务关联日志。
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext 从 context 中读取请求 ID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// setRequestIDHeader 将请求 context 中的请求 ID 写入请求头
+func setRequestIDHeader(req *http.Request) {
+	if id, ok := RequestIDFromContext(req.Context()); ok {
+		req.Header.Set(RequestIDHeader, id)
+	}
+}
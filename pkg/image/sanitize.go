@@ -0,0 +1,52 @@
+package image
+
+import (
+	"strings"
+	"unicode"
+)
+
+// WithPromptSanitization 设置是否清洗提示词（默认开启）
+//
+// 开启后生成前移除提示词中的控制字符与零宽字符（保留换行），
+// 将制表符等空白统一为空格并去除首尾空白。
+func WithPromptSanitization(enabled bool) Option {
+	return func(o *Options) {
+		o.PromptSanitization = enabled
+	}
+}
+
+// SanitizePrompt 清洗提示词
+//
+// 移除控制字符（保留 \n）和零宽等格式字符，\r\n 与 \r 统一为 \n，
+// 其他空白字符统一为空格，最后去除首尾空白。
+func SanitizePrompt(prompt string) string {
+	prompt = strings.ReplaceAll(prompt, "\r\n", "\n")
+
+	var sb strings.Builder
+	sb.Grow(len(prompt))
+	for _, r := range prompt {
+		switch {
+		case r == '\n' || r == '\r':
+			sb.WriteRune('\n')
+		case unicode.IsSpace(r):
+			sb.WriteRune(' ')
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r), r == unicode.ReplacementChar:
+			// 丢弃控制字符、零宽字符及无效编码
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// sanitizeRequest 按配置清洗请求提示词，返回清洗后的请求及提示词是否改变
+func (o *Options) sanitizeRequest(req ImageRequest) (ImageRequest, bool) {
+	if !o.PromptSanitization {
+		return req, false
+	}
+	sanitized := SanitizePrompt(req.Prompt)
+	changed := sanitized != req.Prompt
+	req.Prompt = sanitized
+	return req, changed
+}
@@ -0,0 +1,16 @@
+package image
+
+import "sort"
+
+// SortImagesByScore 按厂商质量评分从高到低排序图像
+//
+// 未提供评分的图像排在最后，相同评分保持原有顺序。
+func SortImagesByScore(images []GeneratedImage) {
+	sort.SliceStable(images, func(i, j int) bool {
+		a, b := images[i].Score, images[j].Score
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a > *b
+	})
+}
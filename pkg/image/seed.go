@@ -0,0 +1,102 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotReproducible 固定种子的两次生成未返回一致的种子
+var ErrNotReproducible = errors.New("generation not reproducible with fixed seed")
+
+// defaultReproducibleSeed AssertReproducible 在请求未指定种子时使用的固定种子
+const defaultReproducibleSeed int64 = 42
+
+// fillSeeds 为未回传种子的图像填充请求中指定的种子
+//
+// 仅用于会将 Seed 透传给厂商接口的提供商：厂商未在响应中回传实际种子时，
+// 请求指定的种子即为实际使用的种子。
+func fillSeeds(resp *ImageResponse, req ImageRequest) {
+	if req.Seed == nil {
+		return
+	}
+	for i := range resp.Images {
+		if resp.Images[i].Seed == nil {
+			seed := *req.Seed
+			resp.Images[i].Seed = &seed
+		}
+	}
+}
+
+// AssertReproducible 验证提供商在固定种子下的可复现性
+//
+// 以固定种子（req.Seed 为空时使用 42）生成两次，要求两次返回的每张图像都带有种子，
+// 且与请求的种子一致。不支持种子的提供商（如 OpenAI DALL-E）返回 ErrNotReproducible。
+func AssertReproducible(ctx context.Context, p ImageProvider, req ImageRequest) error {
+	if req.Seed == nil {
+		seed := defaultReproducibleSeed
+		req.Seed = &seed
+	}
+
+	var runs [2]ImageResponse
+	for i := range runs {
+		resp, err := p.Generate(ctx, req)
+		if err != nil {
+			return WrapError(err, fmt.Sprintf("run %d failed", i+1))
+		}
+		runs[i] = resp
+	}
+
+	if len(runs[0].Images) != len(runs[1].Images) {
+		return WrapError(ErrNotReproducible, fmt.Sprintf("image count differs: %d vs %d",
+			len(runs[0].Images), len(runs[1].Images)))
+	}
+	for i := range runs[0].Images {
+		first, second := runs[0].Images[i].Seed, runs[1].Images[i].Seed
+		if first == nil || second == nil {
+			return WrapError(ErrNotReproducible, fmt.Sprintf("image %d: provider %s did not return a seed", i, p.Name()))
+		}
+		if *first != *req.Seed || *second != *req.Seed {
+			return WrapError(ErrNotReproducible, fmt.Sprintf("image %d: seeds %d and %d, requested %d",
+				i, *first, *second, *req.Seed))
+		}
+	}
+	return nil
+}
+
+// GenerateSeedSweep 以同一请求遍历多个种子生成图像，用于研究种子带来的差异
+//
+// 每个种子发起一次请求（覆盖 req.Seed），经 GenerateBatch 最多 concurrency 个并行，
+// 因此 WithRateLimit 配置的限流器同样生效。返回的响应与 seeds 一一对应；失败的种子
+// 对应位置为零值，错误以 "seed N: ..." 的形式合并返回。ctx 取消时返回 ctx.Err()。
+// 提供商实现 CapabilityReporter 且不支持种子时，不发起任何请求，直接返回 ErrModelNotSupported。
+func GenerateSeedSweep(ctx context.Context, provider ImageProvider, req ImageRequest, seeds []int64, concurrency int) ([]ImageResponse, error) {
+	if reporter, ok := provider.(CapabilityReporter); ok && !reporter.Capabilities().Seed {
+		return nil, WrapError(ErrModelNotSupported,
+			fmt.Sprintf("%s model %s ignores seeds", provider.Name(), provider.Model()))
+	}
+
+	reqs := make([]ImageRequest, len(seeds))
+	for i := range seeds {
+		seed := seeds[i]
+		reqs[i] = req
+		reqs[i].Seed = &seed
+	}
+
+	results, err := GenerateBatch(ctx, provider, reqs, concurrency)
+	responses := make([]ImageResponse, len(results))
+	for i, r := range results {
+		responses[i] = r.Response
+	}
+	if err != nil {
+		return responses, err
+	}
+
+	var errs []error
+	for i, r := range results {
+		if r.Error != nil {
+			errs = append(errs, fmt.Errorf("seed %d: %w", seeds[i], r.Error))
+		}
+	}
+	return responses, errors.Join(errs...)
+}
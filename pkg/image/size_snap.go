@@ -0,0 +1,77 @@
+package image
+
+import (
+	"fmt"
+	"math"
+)
+
+// ImageResponse.Extra 中记录尺寸替换的键
+const (
+	// ExtraRequestedSize 原始请求尺寸（如 "1000x1000"）
+	ExtraRequestedSize = "requested_size"
+	// ExtraSnappedSize 实际使用的支持尺寸（如 "1024x1024"）
+	ExtraSnappedSize = "snapped_size"
+)
+
+// WithSizeSnapping 设置是否将不支持的尺寸吸附到最接近的支持尺寸（默认关闭）
+//
+// 开启后，请求的 Size 不在 SupportedSizes 中时改用 NearestSize 选出的尺寸，
+// 并在 ImageResponse.Extra 中记录 requested_size 与 snapped_size；
+// 关闭时保持严格行为，尺寸原样发送给提供商。
+func WithSizeSnapping(enabled bool) Option {
+	return func(o *Options) {
+		o.SizeSnapping = enabled
+	}
+}
+
+// NearestSize 返回 supported 中与 size 最接近的尺寸
+//
+// 距离为像素数与宽高比在对数尺度上的偏差之和，因此放大/缩小同样比例的代价相同；
+// 距离相同时取靠前的尺寸。supported 为空或 size 宽高非正时返回 false。
+func NearestSize(supported []ImageSize, size ImageSize) (ImageSize, bool) {
+	if len(supported) == 0 || size.Width <= 0 || size.Height <= 0 {
+		return ImageSize{}, false
+	}
+
+	var best ImageSize
+	bestDist := math.Inf(1)
+	for _, s := range supported {
+		if s.Width <= 0 || s.Height <= 0 {
+			continue
+		}
+		dist := math.Abs(math.Log(float64(s.Pixels())/float64(size.Pixels()))) +
+			math.Abs(math.Log(s.AspectRatio()/size.AspectRatio()))
+		if dist < bestDist {
+			best, bestDist = s, dist
+		}
+	}
+	return best, !math.IsInf(bestDist, 1)
+}
+
+// snapSize 按配置将请求尺寸吸附到最接近的支持尺寸，返回被替换的原始尺寸
+//
+// 未开启吸附、未指定尺寸或尺寸已受支持时原样返回，原始尺寸为零值。
+func (o *Options) snapSize(req ImageRequest, supported []ImageSize) (ImageRequest, ImageSize) {
+	if !o.SizeSnapping || req.Size == (ImageSize{}) || containsSize(supported, req.Size) {
+		return req, ImageSize{}
+	}
+	nearest, ok := NearestSize(supported, req.Size)
+	if !ok {
+		return req, ImageSize{}
+	}
+	requested := req.Size
+	req.Size = nearest
+	return req, requested
+}
+
+// recordSizeSnap 在响应中记录尺寸替换
+func recordSizeSnap(resp *ImageResponse, requested, snapped ImageSize) {
+	if requested == (ImageSize{}) {
+		return
+	}
+	if resp.Extra == nil {
+		resp.Extra = make(map[string]interface{})
+	}
+	resp.Extra[ExtraRequestedSize] = fmt.Sprintf("%dx%d", requested.Width, requested.Height)
+	resp.Extra[ExtraSnappedSize] = fmt.Sprintf("%dx%d", snapped.Width, snapped.Height)
+}
@@ -9,13 +9,17 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 )
 
 // StabilityClient Stability AI 图像生成客户端
 //
-// 支持 Stable Diffusion 3.5 系列模型。
+// 支持 Stable Diffusion 3.5 系列模型，以及仅接受宽高比的 SD3 接口（ModelSD3）。
+// SD3 系列模型支持图生图：在 Extra 中提供 "init_image"（[]byte 图像数据或文件路径）
+// 和 "image_strength"（0~1，越大与初始图像差异越大），未提供初始图像时为文生图。
 type StabilityClient struct {
 	httpClient *http.Client
 	options    *Options
@@ -29,6 +33,7 @@ const (
 	ModelSD3Large        = "sd3-large"
 	ModelSD3LargeTurbo   = "sd3-large-turbo"
 	ModelSD3Medium       = "sd3-medium"
+	ModelSD3             = "sd3"
 	ModelStableImageCore = "stable-image-core"
 )
 
@@ -57,6 +62,21 @@ var stabilityAspectRatioSizes = map[string]ImageSize{
 	"2:3":  {Width: 832, Height: 1216},
 }
 
+// Stability AI 价格表（美元/张，1 credit = $0.01）
+var stabilityPricing = priceTable{
+	currency: CurrencyUSD,
+	prices: map[priceKey]float64{
+		{Model: ModelSD35Large}:       0.065,
+		{Model: ModelSD35LargeTurbo}:  0.040,
+		{Model: ModelSD35Medium}:      0.035,
+		{Model: ModelSD3Large}:        0.065,
+		{Model: ModelSD3LargeTurbo}:   0.040,
+		{Model: ModelSD3Medium}:       0.035,
+		{Model: ModelSD3}:             0.065,
+		{Model: ModelStableImageCore}: 0.030,
+	},
+}
+
 // NewStability 创建 Stability AI 图像生成客户端
 func NewStability(opts ...Option) (*StabilityClient, error) {
 	options := DefaultOptions()
@@ -76,9 +96,7 @@ func NewStability(opts ...Option) (*StabilityClient, error) {
 
 	httpClient := options.HTTPClient
 	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: options.Timeout,
-		}
+		httpClient = NewHTTPClient(options)
 	}
 
 	return &StabilityClient{
@@ -106,6 +124,50 @@ func (c *StabilityClient) SupportedSizes() []ImageSize {
 	return sizes
 }
 
+// Capabilities 返回当前模型支持的功能
+func (c *StabilityClient) Capabilities() ProviderCapabilities {
+	return stabilityCapabilities(c.options.Model)
+}
+
+// stabilityCapabilities 返回 Stability 指定模型支持的功能
+func stabilityCapabilities(model string) ProviderCapabilities {
+	return ProviderCapabilities{
+		NegativePrompt:  true,
+		Seed:            true,
+		Edit:            model != ModelStableImageCore, // 图生图需要 SD3 系列模型
+		MaxImages:       1,
+		MaxPromptLength: 10000,
+		ResponseFormats: []ResponseFormat{FormatURL, FormatBase64},
+		ExtraParams:     stabilityExtraParams(model),
+	}
+}
+
+// stabilityExtraParams 返回 Stability 指定模型可识别的 Extra 参数
+func stabilityExtraParams(model string) []ExtraParam {
+	if model == ModelStableImageCore {
+		return nil
+	}
+	// SD3 系列接口支持 cfg_scale 与图生图
+	params := []ExtraParam{
+		{Name: "cfg_scale", Type: ExtraParamNumber},
+		{Name: "image_strength", Type: ExtraParamNumber},
+		{Name: "init_image", Type: ExtraParamBytes},
+	}
+	if model == ModelSD3 {
+		params = append(params, ExtraParam{Name: "output_format", Type: ExtraParamString, Values: []string{"png", "jpeg", "webp"}})
+	}
+	return params
+}
+
+// SupportedAspectRatios 返回支持的宽高比列表
+//
+// Stability 接口按宽高比而非像素尺寸生成，仅指定 Size 时映射到最接近的宽高比。
+func (c *StabilityClient) SupportedAspectRatios() []string {
+	ratios := make([]string, len(stabilityAspectRatios))
+	copy(ratios, stabilityAspectRatios)
+	return ratios
+}
+
 // Close 关闭客户端连接
 func (c *StabilityClient) Close() error {
 	return nil
@@ -113,16 +175,46 @@ func (c *StabilityClient) Close() error {
 
 // Generate 生成图像
 func (c *StabilityClient) Generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
-	// 验证请求
-	if req.Prompt == "" {
-		return ImageResponse{}, ErrInvalidPrompt
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	return c.generatePrepared(ctx, prepared)
+}
+
+// prepare 按客户端配置规范化并验证请求
+func (c *StabilityClient) prepare(req ImageRequest) (preparedRequest, error) {
+	return c.options.prepareRequest(req, c.Name(), c.SupportedSizes(), c.Capabilities())
+}
+
+// generatePrepared 使用规范化后的请求生成图像
+func (c *StabilityClient) generatePrepared(ctx context.Context, prepared preparedRequest) (ImageResponse, error) {
+	// 解析图生图参数（读取初始图像文件，避免重试时重复读取）
+	req, err := c.resolveInitImage(prepared.req)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+
+	resp, err := generateWithFilterRetry(ctx, c.options, req, c.generate)
+	if err != nil {
+		return ImageResponse{}, err
 	}
+	prepared.applyTo(&resp)
+	fillContentTypes(&resp)
+	fillSeeds(&resp, req)
+	return resp, nil
+}
 
+// generate 执行单次生成（带重试）
+func (c *StabilityClient) generate(ctx context.Context, req ImageRequest) (ImageResponse, error) {
 	// 执行请求（带重试）
 	var resp ImageResponse
 	var err error
 
 	err = c.retry(ctx, func() error {
+		if err := c.options.waitRateLimit(ctx); err != nil {
+			return err
+		}
 		resp, err = c.doRequest(ctx, req)
 		return err
 	})
@@ -135,6 +227,41 @@ func (c *StabilityClient) Generate(ctx context.Context, req ImageRequest) (Image
 	return resp, nil
 }
 
+// GenerateStream 以事件流方式生成图像
+func (c *StabilityClient) GenerateStream(ctx context.Context, req ImageRequest) (<-chan GenerationEvent, error) {
+	// 与 Generate 相同地先规范化再验证
+	prepared, err := c.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	return StreamFromGenerate(ctx, prepared.req, func(ctx context.Context, _ ImageRequest) (ImageResponse, error) {
+		return c.generatePrepared(ctx, prepared)
+	}), nil
+}
+
+// GenerateToWriter 生成图像并将第一张图像流式写入 w
+func (c *StabilityClient) GenerateToWriter(ctx context.Context, req ImageRequest, w io.Writer) (ImageResponse, error) {
+	return generateToWriter(ctx, req, w, c.Generate, c.httpClient)
+}
+
+// downloadClient 返回下载生成图像时使用的 HTTP 客户端
+func (c *StabilityClient) downloadClient() *http.Client {
+	return c.httpClient
+}
+
+// EstimateCost 估算请求费用
+func (c *StabilityClient) EstimateCost(req ImageRequest) (Cost, error) {
+	if _, ok := stabilityAspectRatioSizes[req.AspectRatio]; !ok {
+		size := resolveSize(req.Size, c.options.DefaultSize)
+		if !containsSize(c.SupportedSizes(), size) {
+			return Cost{}, ErrUnsupportedSize
+		}
+	}
+
+	// Stability 每次请求生成 1 张
+	return stabilityPricing.estimate(c.options.Model, ImageSize{}, "", 1)
+}
+
 // doRequest 执行 HTTP 请求
 func (c *StabilityClient) doRequest(ctx context.Context, req ImageRequest) (ImageResponse, error) {
 	// 构建 multipart form
@@ -153,10 +280,18 @@ func (c *StabilityClient) doRequest(ctx context.Context, req ImageRequest) (Imag
 		}
 	}
 
-	// 添加 aspect_ratio
-	aspectRatio := c.mapAspectRatio(req)
-	if err := writer.WriteField("aspect_ratio", aspectRatio); err != nil {
-		return ImageResponse{}, WrapError(err, "failed to write aspect_ratio")
+	initImage, strength, img2img := stabilityInitImage(req)
+	if img2img {
+		// 图生图：输出宽高比与初始图像一致，不传 aspect_ratio
+		if err := c.writeInitImage(writer, initImage, strength); err != nil {
+			return ImageResponse{}, err
+		}
+	} else {
+		// 添加 aspect_ratio
+		aspectRatio := c.mapAspectRatio(req)
+		if err := writer.WriteField("aspect_ratio", aspectRatio); err != nil {
+			return ImageResponse{}, WrapError(err, "failed to write aspect_ratio")
+		}
 	}
 
 	// 添加 seed
@@ -168,16 +303,44 @@ func (c *StabilityClient) doRequest(ctx context.Context, req ImageRequest) (Imag
 
 	// 添加 output_format
 	outputFormat := "png"
-	if req.ResponseFormat == FormatBase64 {
-		outputFormat = "png" // Stability 返回 base64 时也是 png
+	if c.isSD3() {
+		// SD3 接口支持通过 Extra 指定 png/jpeg/webp
+		if format, ok := req.Extra["output_format"].(string); ok && format != "" {
+			outputFormat = format
+		}
 	}
 	if err := writer.WriteField("output_format", outputFormat); err != nil {
 		return ImageResponse{}, WrapError(err, "failed to write output_format")
 	}
 
-	// 添加 model
-	if err := writer.WriteField("model", c.options.Model); err != nil {
-		return ImageResponse{}, WrapError(err, "failed to write model")
+	// 添加 cfg_scale（SD3 系列接口支持，控制对提示词的遵循程度）
+	if c.options.Model != ModelStableImageCore {
+		if cfgScale, ok := extraNumber(req.Extra["cfg_scale"]); ok {
+			if err := writer.WriteField("cfg_scale", strconv.FormatFloat(cfgScale, 'f', -1, 64)); err != nil {
+				return ImageResponse{}, WrapError(err, "failed to write cfg_scale")
+			}
+		}
+	}
+
+	if c.isSD3() {
+		// SD3 接口不传 model 字段，通过 mode 区分文生图与图生图
+		mode := "text-to-image"
+		if img2img {
+			mode = "image-to-image"
+		}
+		if err := writer.WriteField("mode", mode); err != nil {
+			return ImageResponse{}, WrapError(err, "failed to write mode")
+		}
+	} else {
+		if img2img {
+			if err := writer.WriteField("mode", "image-to-image"); err != nil {
+				return ImageResponse{}, WrapError(err, "failed to write mode")
+			}
+		}
+		// 添加 model
+		if err := writer.WriteField("model", c.options.Model); err != nil {
+			return ImageResponse{}, WrapError(err, "failed to write model")
+		}
 	}
 
 	if err := writer.Close(); err != nil {
@@ -197,11 +360,12 @@ func (c *StabilityClient) doRequest(ctx context.Context, req ImageRequest) (Imag
 		return ImageResponse{}, WrapError(err, "failed to create request")
 	}
 
+	setRequestIDHeader(httpReq)
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 	httpReq.Header.Set("Authorization", "Bearer "+c.options.APIKey)
 
-	// 设置接受格式
-	if req.ResponseFormat == FormatBase64 {
+	// 设置接受格式（SD3 接口始终返回 base64 JSON）
+	if req.ResponseFormat == FormatBase64 || c.isSD3() {
 		httpReq.Header.Set("Accept", "application/json")
 	} else {
 		httpReq.Header.Set("Accept", "image/*")
@@ -225,13 +389,109 @@ func (c *StabilityClient) doRequest(ctx context.Context, req ImageRequest) (Imag
 
 	// 检查错误
 	if httpResp.StatusCode != http.StatusOK {
-		return ImageResponse{}, c.mapError(httpResp.StatusCode, respBody)
+		var errResp struct {
+			Name    string `json:"name"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &errResp)
+		return ImageResponse{}, newAPIError(httpResp, errResp.Message, "",
+			c.mapError(httpResp.StatusCode, errResp.Name, errResp.Message))
 	}
 
 	// 解析响应
 	return c.parseResponse(httpResp, respBody, req)
 }
 
+// resolveInitImage 校验图生图参数，并将文件路径形式的初始图像读取为 []byte
+//
+// 未提供 "init_image" 和 "image_strength" 时原样返回（文生图）。
+func (c *StabilityClient) resolveInitImage(req ImageRequest) (ImageRequest, error) {
+	rawImage, hasImage := req.Extra["init_image"]
+	rawStrength, hasStrength := req.Extra["image_strength"]
+	if rawImage == nil {
+		hasImage = false
+	}
+	if !hasImage && !hasStrength {
+		return req, nil
+	}
+	if !hasImage {
+		return req, ErrMissingInitImage
+	}
+
+	if c.options.Model == ModelStableImageCore {
+		return req, WrapError(ErrModelNotSupported, "image-to-image requires an SD3 model")
+	}
+
+	var data []byte
+	switch v := rawImage.(type) {
+	case []byte:
+		data = v
+	case string:
+		if v == "" {
+			return req, ErrMissingInitImage
+		}
+		b, err := os.ReadFile(filepath.Clean(v))
+		if err != nil {
+			return req, WrapError(err, "failed to read init image")
+		}
+		data = b
+	default:
+		return req, WrapError(ErrMissingInitImage, fmt.Sprintf("unsupported init_image type %T", rawImage))
+	}
+	if len(data) == 0 {
+		return req, ErrMissingInitImage
+	}
+
+	strength, ok := extraNumber(rawStrength)
+	if !ok || strength < 0 || strength > 1 {
+		return req, ErrInvalidImageStrength
+	}
+
+	// 复制 Extra，避免修改调用方的 map
+	extra := make(map[string]interface{}, len(req.Extra))
+	for k, v := range req.Extra {
+		extra[k] = v
+	}
+	extra["init_image"] = data
+	extra["image_strength"] = strength
+	req.Extra = extra
+	return req, nil
+}
+
+// stabilityInitImage 返回已解析的初始图像和强度，ok 表示是否为图生图
+func stabilityInitImage(req ImageRequest) (data []byte, strength float64, ok bool) {
+	data, _ = req.Extra["init_image"].([]byte)
+	if len(data) == 0 {
+		return nil, 0, false
+	}
+	strength, _ = req.Extra["image_strength"].(float64)
+	return data, strength, true
+}
+
+// writeInitImage 写入图生图的 image 文件和 strength 字段
+func (c *StabilityClient) writeInitImage(writer *multipart.Writer, data []byte, strength float64) error {
+	filename := "init_image"
+	if ext := ExtensionForContentType(DetectContentType(data)); ext != "" {
+		filename += ext
+	}
+	part, err := writer.CreateFormFile("image", filename)
+	if err != nil {
+		return WrapError(err, "failed to create image part")
+	}
+	if _, err := part.Write(data); err != nil {
+		return WrapError(err, "failed to write image")
+	}
+	if err := writer.WriteField("strength", strconv.FormatFloat(strength, 'f', -1, 64)); err != nil {
+		return WrapError(err, "failed to write strength")
+	}
+	return nil
+}
+
+// isSD3 是否使用仅接受宽高比的 SD3 接口
+func (c *StabilityClient) isSD3() bool {
+	return c.options.Model == ModelSD3
+}
+
 // mapAspectRatio 映射尺寸到宽高比
 func (c *StabilityClient) mapAspectRatio(req ImageRequest) string {
 	// 如果指定了宽高比，直接使用
@@ -274,7 +534,7 @@ func (c *StabilityClient) parseResponse(httpResp *http.Response, body []byte, re
 
 	contentType := httpResp.Header.Get("Content-Type")
 
-	if req.ResponseFormat == FormatBase64 || contentType == "application/json" {
+	if req.ResponseFormat == FormatBase64 || c.isSD3() || contentType == "application/json" {
 		// JSON 响应
 		var jsonResp struct {
 			Image        string `json:"image"`
@@ -310,13 +570,7 @@ func (c *StabilityClient) parseResponse(httpResp *http.Response, body []byte, re
 }
 
 // mapError 映射 Stability 错误到框架错误
-func (c *StabilityClient) mapError(statusCode int, body []byte) error {
-	var errResp struct {
-		Name    string `json:"name"`
-		Message string `json:"message"`
-	}
-	_ = json.Unmarshal(body, &errResp)
-
+func (c *StabilityClient) mapError(statusCode int, name, message string) error {
 	switch statusCode {
 	case 401:
 		return ErrInvalidAPIKey
@@ -325,14 +579,14 @@ func (c *StabilityClient) mapError(statusCode int, body []byte) error {
 	case 429:
 		return ErrQuotaExceeded
 	case 400:
-		if errResp.Name == "content_moderation" {
+		if name == "content_moderation" {
 			return ErrContentFiltered
 		}
-		return WrapError(ErrGenerationFailed, errResp.Message)
+		return WrapError(ErrGenerationFailed, message)
 	case 500, 502, 503:
 		return ErrProviderUnavailable
 	default:
-		msg := errResp.Message
+		msg := message
 		if msg == "" {
 			msg = fmt.Sprintf("status code: %d", statusCode)
 		}
@@ -368,6 +622,7 @@ func (c *StabilityClient) retry(ctx context.Context, fn func() error) error {
 			if delay > 30*time.Second {
 				delay = 30 * time.Second
 			}
+			delay = retryDelay(err, delay)
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -388,4 +643,8 @@ func absFloat(x float64) float64 {
 }
 
 // compile-time interface check
-var _ ImageProvider = (*StabilityClient)(nil)
+var (
+	_ ImageProvider   = (*StabilityClient)(nil)
+	_ CostEstimator   = (*StabilityClient)(nil)
+	_ WriterGenerator = (*StabilityClient)(nil)
+)
@@ -0,0 +1,82 @@
+package image
+
+import (
+	"context"
+)
+
+// 生成阶段
+const (
+	// StageStarted 任务已开始
+	StageStarted = "started"
+	// StageInProgress 任务进行中
+	StageInProgress = "in_progress"
+	// StageCompleted 任务完成（终止事件）
+	StageCompleted = "completed"
+	// StageFailed 任务失败（终止事件）
+	StageFailed = "failed"
+)
+
+// GenerationEvent 流式生成事件
+//
+// 每次 GenerateStream 调用会产生若干进度事件，并以一个终止事件结束：
+// 成功时 Stage 为 StageCompleted 且 Response 非空，失败时 Stage 为 StageFailed 且 Error 非空。
+type GenerationEvent struct {
+	// Stage 当前阶段
+	Stage string `json:"stage"`
+
+	// Percent 进度百分比（0-100）
+	Percent float64 `json:"percent"`
+
+	// Response 最终生成结果（仅终止事件）
+	Response *ImageResponse `json:"response,omitempty"`
+
+	// Error 生成错误（仅终止事件）
+	Error error `json:"-"`
+}
+
+// IsTerminal 判断是否为终止事件
+func (e GenerationEvent) IsTerminal() bool {
+	return e.Stage == StageCompleted || e.Stage == StageFailed
+}
+
+// GenerateFunc 单次图像生成函数
+type GenerateFunc func(ctx context.Context, req ImageRequest) (ImageResponse, error)
+
+// StreamFromGenerate 将同步生成函数包装为事件流
+//
+// 适用于没有原生进度上报的提供商：先发送一个开始事件，再发送最终结果。
+// 返回的 channel 总会被关闭；ctx 取消时立即发送失败事件并结束，不等待 generate 返回。
+func StreamFromGenerate(ctx context.Context, req ImageRequest, generate GenerateFunc) <-chan GenerationEvent {
+	// 缓冲区容纳开始事件和终止事件，保证发送不会阻塞
+	events := make(chan GenerationEvent, 2)
+
+	go func() {
+		defer close(events)
+
+		events <- GenerationEvent{Stage: StageStarted, Percent: 0}
+
+		type result struct {
+			resp ImageResponse
+			err  error
+		}
+		done := make(chan result, 1)
+		go func() {
+			resp, err := generate(ctx, req)
+			done <- result{resp: resp, err: err}
+		}()
+
+		select {
+		case <-ctx.Done():
+			events <- GenerationEvent{Stage: StageFailed, Error: ctx.Err()}
+		case r := <-done:
+			if r.err != nil {
+				events <- GenerationEvent{Stage: StageFailed, Error: r.err}
+				return
+			}
+			resp := r.resp
+			events <- GenerationEvent{Stage: StageCompleted, Percent: 100, Response: &resp}
+		}
+	}()
+
+	return events
+}
@@ -0,0 +1,74 @@
+package image
+
+import (
+	"strings"
+	"text/template"
+)
+
+// PromptTemplate 图像提示词模板
+//
+// 使用 Go text/template 语法，变量以 {{.name}} 引用，例如
+// "{{.subject}}, {{.style}}, high detail"。
+type PromptTemplate struct {
+	tmpl   *template.Template
+	strict bool
+}
+
+// TemplateOption 提示词模板选项
+type TemplateOption func(*PromptTemplate)
+
+// WithStrictVars 开启严格模式：模板引用的变量未提供时 Render 返回 ErrTemplateVarMissing
+//
+// 默认（非严格模式）下缺失的变量渲染为空字符串。
+func WithStrictVars() TemplateOption {
+	return func(t *PromptTemplate) {
+		t.strict = true
+	}
+}
+
+// NewPromptTemplate 解析提示词模板
+//
+// 模板语法错误时返回错误。
+func NewPromptTemplate(tmpl string, opts ...TemplateOption) (*PromptTemplate, error) {
+	t := &PromptTemplate{}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	missingKey := "missingkey=zero"
+	if t.strict {
+		missingKey = "missingkey=error"
+	}
+	parsed, err := template.New("prompt").Option(missingKey).Parse(tmpl)
+	if err != nil {
+		return nil, WrapError(err, "failed to parse prompt template")
+	}
+	t.tmpl = parsed
+	return t, nil
+}
+
+// Render 使用变量渲染模板，返回去除首尾空白的提示词
+func (t *PromptTemplate) Render(vars map[string]string) (string, error) {
+	if vars == nil {
+		vars = map[string]string{}
+	}
+
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, vars); err != nil {
+		if t.strict {
+			return "", WrapError(ErrTemplateVarMissing, err.Error())
+		}
+		return "", WrapError(err, "failed to render prompt template")
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// BuildRequest 渲染模板并填入 req.Prompt，返回新的请求
+func (t *PromptTemplate) BuildRequest(vars map[string]string, req ImageRequest) (ImageRequest, error) {
+	prompt, err := t.Render(vars)
+	if err != nil {
+		return ImageRequest{}, err
+	}
+	req.Prompt = prompt
+	return req, nil
+}
@@ -0,0 +1,160 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	goimage "image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/webp"
+)
+
+// DefaultTranscodeQuality 有损格式的默认编码质量
+const DefaultTranscodeQuality = 90
+
+// TranscodeOption 转码选项
+type TranscodeOption func(*transcodeOptions)
+
+// transcodeOptions 转码配置
+type transcodeOptions struct {
+	ctx     context.Context
+	client  *http.Client
+	quality int
+}
+
+// WithTranscodeQuality 设置有损格式（jpeg）的编码质量，取值 1-100（webp 为无损编码，不受影响）
+func WithTranscodeQuality(quality int) TranscodeOption {
+	return func(o *transcodeOptions) {
+		o.quality = quality
+	}
+}
+
+// WithTranscodeContext 设置下载 URL 图像时使用的上下文
+func WithTranscodeContext(ctx context.Context) TranscodeOption {
+	return func(o *transcodeOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithTranscodeHTTPClient 设置下载 URL 图像时使用的 HTTP 客户端
+//
+// 默认使用超时为 DefaultDownloadTimeout 的客户端。
+func WithTranscodeHTTPClient(client *http.Client) TranscodeOption {
+	return func(o *transcodeOptions) {
+		o.client = client
+	}
+}
+
+// Transcode 将图像解码后重新编码为指定格式
+//
+// 图像数据优先取自 Base64，否则从 URL 下载，可解码 PNG、JPEG、GIF 与 WebP。
+// 支持的目标格式为 "png"、"jpeg"（"jpg"）和 "webp"：jpeg 质量可通过 WithTranscodeQuality
+// 设置，webp 使用无损（VP8L）编码。动图（多帧 GIF、APNG、动画 WebP）返回 ErrAnimatedImage。
+func (img GeneratedImage) Transcode(format string, opts ...TranscodeOption) ([]byte, error) {
+	options := transcodeOptions{ctx: context.Background(), quality: DefaultTranscodeQuality}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.quality < 1 || options.quality > 100 {
+		return nil, fmt.Errorf("invalid transcode quality %d: must be between 1 and 100", options.quality)
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "png", "jpeg", "jpg", "webp":
+	default:
+		return nil, WrapError(ErrUnsupportedTranscode, fmt.Sprintf("target %q", format))
+	}
+
+	data, err := img.data(options.ctx, options.client)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := decodeStill(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "png":
+		err = png.Encode(&buf, decoded)
+	case "webp":
+		err = nativewebp.Encode(&buf, decoded, nil)
+	default:
+		err = jpeg.Encode(&buf, decoded, &jpeg.Options{Quality: options.quality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// data 返回图像的原始字节，优先解码 Base64，否则使用 client 下载 URL
+func (img GeneratedImage) data(ctx context.Context, client *http.Client) ([]byte, error) {
+	switch {
+	case img.Base64 != "":
+		data, err := base64.StdEncoding.DecodeString(img.Base64)
+		if err != nil {
+			return nil, WrapError(err, "failed to decode image")
+		}
+		return data, nil
+	case img.URL != "":
+		data, _, err := downloadImage(ctx, client, img.URL)
+		if err != nil {
+			return nil, WrapError(err, "failed to download image")
+		}
+		return data, nil
+	default:
+		return nil, WrapError(ErrInvalidResponse, "image has neither URL nor base64 data")
+	}
+}
+
+// decodeStill 解码静态图像，动图返回 ErrAnimatedImage
+func decodeStill(data []byte) (goimage.Image, error) {
+	switch DetectContentType(data) {
+	case "image/gif":
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode gif: %w", err)
+		}
+		if len(g.Image) > 1 {
+			return nil, WrapError(ErrAnimatedImage, fmt.Sprintf("gif has %d frames", len(g.Image)))
+		}
+		return g.Image[0], nil
+	case "image/png":
+		if isAPNG(data) {
+			return nil, WrapError(ErrAnimatedImage, "apng")
+		}
+		return png.Decode(bytes.NewReader(data))
+	case "image/jpeg":
+		return jpeg.Decode(bytes.NewReader(data))
+	case "image/webp":
+		if bytes.Contains(data[:min(len(data), sniffLen)], []byte("ANIM")) {
+			return nil, WrapError(ErrAnimatedImage, "animated webp")
+		}
+		decoded, err := webp.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode webp: %w", err)
+		}
+		return decoded, nil
+	default:
+		return nil, WrapError(ErrUnsupportedTranscode, "unrecognized source image format")
+	}
+}
+
+// isAPNG 判断 PNG 数据是否包含动画控制块（acTL 位于首个 IDAT 之前）
+func isAPNG(data []byte) bool {
+	idat := bytes.Index(data, []byte("IDAT"))
+	if idat < 0 {
+		idat = len(data)
+	}
+	return bytes.Contains(data[:idat], []byte("acTL"))
+}
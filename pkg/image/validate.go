@@ -0,0 +1,87 @@
+package image
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxImagesPerRequest 单次请求允许的最大生成数量
+const MaxImagesPerRequest = 10
+
+// Validate 在发起网络请求前校验请求参数
+//
+// 校验规则:
+//   - Prompt 不能为空（ErrInvalidPrompt）
+//   - N 为 0（使用默认值）或在 1..10 之间（ErrInvalidImageCount）
+//   - Size 为零值或宽高均为正数（ErrInvalidSize）
+//   - AspectRatio 设置时必须为 "W:H" 格式（ErrInvalidAspectRatio）
+func (r ImageRequest) Validate() error {
+	if strings.TrimSpace(r.Prompt) == "" {
+		return ErrInvalidPrompt
+	}
+
+	if r.N < 0 || r.N > MaxImagesPerRequest {
+		return WrapError(ErrInvalidImageCount, fmt.Sprintf("n=%d", r.N))
+	}
+
+	if r.Size != (ImageSize{}) && (r.Size.Width <= 0 || r.Size.Height <= 0) {
+		return WrapError(ErrInvalidSize, fmt.Sprintf("%dx%d", r.Size.Width, r.Size.Height))
+	}
+
+	if r.AspectRatio != "" {
+		if _, _, err := ParseAspectRatio(r.AspectRatio); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseAspectRatio 解析宽高比字符串，如 "16:9"
+func ParseAspectRatio(s string) (int, int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, 0, WrapError(ErrInvalidAspectRatio, s)
+	}
+
+	w, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || w <= 0 {
+		return 0, 0, WrapError(ErrInvalidAspectRatio, s)
+	}
+	h, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || h <= 0 {
+		return 0, 0, WrapError(ErrInvalidAspectRatio, s)
+	}
+
+	return w, h, nil
+}
+
+// ValidateRequest 结合提供商能力校验请求
+//
+// 在 ImageRequest.Validate 的基础上，检查显式指定的尺寸是否在提供商的
+// SupportedSizes 中，不支持时返回 ErrUnsupportedSize；提供商实现 CapabilityReporter
+// 时，提示词超出 MaxPromptLength 返回 *PromptLengthError，Extra 包含未知或类型错误的
+// 参数返回 ErrInvalidExtra（见 ValidateExtra）。
+func ValidateRequest(provider ImageProvider, req ImageRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	if reporter, ok := provider.(CapabilityReporter); ok {
+		caps := reporter.Capabilities()
+		if err := checkPromptLength(provider.Name(), req.Prompt, caps.MaxPromptLength); err != nil {
+			return err
+		}
+		if err := ValidateExtra(provider.Name(), caps.ExtraParams, req.Extra); err != nil {
+			return err
+		}
+	}
+
+	if req.Size != (ImageSize{}) && !containsSize(provider.SupportedSizes(), req.Size) {
+		return WrapError(ErrUnsupportedSize,
+			fmt.Sprintf("%s does not support %dx%d", provider.Name(), req.Size.Width, req.Size.Height))
+	}
+
+	return nil
+}
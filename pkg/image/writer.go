@@ -0,0 +1,66 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WriterGenerator 支持将生成的图像直接写入 io.Writer 的提供商
+type WriterGenerator interface {
+	// GenerateToWriter 生成图像并将第一张图像的字节流式写入 w
+	GenerateToWriter(ctx context.Context, req ImageRequest, w io.Writer) (ImageResponse, error)
+}
+
+// GenerateToWriter 调用 generate 生成图像，并将第一张图像的解码字节流式写入 w
+//
+// Base64 数据边解码边写出，URL 图像边下载边写出，均不在内存中保留完整的解码结果。
+// 返回的 Images 仅包含第一张图像的元数据（URL、Base64 字段被清空），其余图像被丢弃。
+// URL 图像使用超时为 DefaultDownloadTimeout 的客户端下载；内置提供商的 GenerateToWriter
+// 方法改用提供商自身的 HTTP 客户端。
+func GenerateToWriter(ctx context.Context, req ImageRequest, w io.Writer, generate GenerateFunc) (ImageResponse, error) {
+	return generateToWriter(ctx, req, w, generate, nil)
+}
+
+// generateToWriter 同 GenerateToWriter，使用 client 下载 URL 图像（nil 时使用默认客户端）
+func generateToWriter(ctx context.Context, req ImageRequest, w io.Writer, generate GenerateFunc, client *http.Client) (ImageResponse, error) {
+	resp, err := generate(ctx, req)
+	if err != nil {
+		return ImageResponse{}, err
+	}
+	if len(resp.Images) == 0 {
+		return ImageResponse{}, WrapError(ErrInvalidResponse, "no image to write")
+	}
+
+	img := resp.Images[0]
+	switch {
+	case img.Base64 != "":
+		decoder := base64.NewDecoder(base64.StdEncoding, strings.NewReader(img.Base64))
+		if _, err := io.Copy(w, decoder); err != nil {
+			return ImageResponse{}, fmt.Errorf("write image: %w", err)
+		}
+	case img.URL != "":
+		body, err := openImageURL(ctx, client, img.URL)
+		if err != nil {
+			return ImageResponse{}, fmt.Errorf("download image: %w", err)
+		}
+		_, err = io.Copy(w, body.Body)
+		body.Body.Close()
+		if err != nil {
+			return ImageResponse{}, fmt.Errorf("download image: %w", err)
+		}
+		if img.ContentType == "" {
+			img.ContentType = NormalizeContentType(body.Header.Get("Content-Type"))
+		}
+	default:
+		return ImageResponse{}, WrapError(ErrInvalidResponse, "image has neither URL nor base64 data")
+	}
+
+	img.URL = ""
+	img.Base64 = ""
+	resp.Images = []GeneratedImage{img}
+	return resp, nil
+}
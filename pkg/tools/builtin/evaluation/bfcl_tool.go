@@ -24,6 +24,9 @@ type BFCLEvaluationTool struct {
 
 	// agent 待评估的智能体
 	agent agents.Agent
+
+	// jobs 异步评估任务
+	jobs *JobManager
 }
 
 // NewBFCLEvaluationTool 创建 BFCL 评估工具
@@ -37,6 +40,7 @@ func NewBFCLEvaluationTool(bfclDataDir, outputDir string, agent agents.Agent) *B
 		bfclDataDir: bfclDataDir,
 		outputDir:   outputDir,
 		agent:       agent,
+		jobs:        NewJobManager(),
 	}
 }
 
@@ -83,10 +87,36 @@ func (t *BFCLEvaluationTool) Parameters() tools.ParameterSchema {
 
 // Execute 执行评估
 func (t *BFCLEvaluationTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	_, output, err := t.run(ctx, args)
+	return output, err
+}
+
+// ExecuteAsync 在后台启动评估并立即返回任务 ID
+//
+// 通过 Status 轮询进度，完成后通过 Result 获取评估结果。
+func (t *BFCLEvaluationTool) ExecuteAsync(ctx context.Context, args map[string]interface{}) (string, error) {
+	jobID := t.jobs.Start(ctx, func(ctx context.Context, progress evaluation.ProgressCallback) (*evaluation.EvalResult, string, error) {
+		return t.run(ctx, args, evaluation.WithProgressCallback(progress))
+	})
+	return jobID, nil
+}
+
+// Status 返回异步评估任务状态
+func (t *BFCLEvaluationTool) Status(jobID string) (JobStatus, error) {
+	return t.jobs.Status(jobID)
+}
+
+// Result 返回异步评估任务的评估结果
+func (t *BFCLEvaluationTool) Result(jobID string) (*evaluation.EvalResult, error) {
+	return t.jobs.Result(jobID)
+}
+
+// run 执行评估并返回评估结果与工具输出
+func (t *BFCLEvaluationTool) run(ctx context.Context, args map[string]interface{}, extra ...evaluation.EvalOption) (*evaluation.EvalResult, string, error) {
 	// 解析参数
 	category, _ := args["category"].(string)
 	if category == "" {
-		return "", fmt.Errorf("category 参数是必需的")
+		return nil, "", fmt.Errorf("category 参数是必需的")
 	}
 
 	maxSamples := 0
@@ -109,7 +139,7 @@ func (t *BFCLEvaluationTool) Execute(ctx context.Context, args map[string]interf
 
 	// 加载数据集
 	if err := dataset.Load(ctx); err != nil {
-		return "", fmt.Errorf("加载数据集失败: %w", err)
+		return nil, "", fmt.Errorf("加载数据集失败: %w", err)
 	}
 
 	// 创建评估器
@@ -123,10 +153,12 @@ func (t *BFCLEvaluationTool) Execute(ctx context.Context, args map[string]interf
 		opts = append(opts, evaluation.WithMaxSamples(maxSamples))
 	}
 
+	opts = append(opts, extra...)
+
 	// 执行评估
 	result, err := evaluator.Evaluate(ctx, t.agent, opts...)
 	if err != nil {
-		return "", fmt.Errorf("评估失败: %w", err)
+		return nil, "", fmt.Errorf("评估失败: %w", err)
 	}
 
 	// 生成输出文件名
@@ -138,7 +170,7 @@ func (t *BFCLEvaluationTool) Execute(ctx context.Context, args map[string]interf
 		exporter := bfcl.NewExporter(true)
 		officialPath := filepath.Join(t.outputDir, baseName+"_official.jsonl")
 		if err := exporter.Export(result, officialPath); err != nil {
-			return "", fmt.Errorf("导出官方格式失败: %w", err)
+			return nil, "", fmt.Errorf("导出官方格式失败: %w", err)
 		}
 	}
 
@@ -146,7 +178,7 @@ func (t *BFCLEvaluationTool) Execute(ctx context.Context, args map[string]interf
 	exporter := bfcl.NewExporter(false)
 	reportPath := filepath.Join(t.outputDir, baseName+"_report.md")
 	if err := exporter.ExportMarkdownReport(result, reportPath); err != nil {
-		return "", fmt.Errorf("导出报告失败: %w", err)
+		return nil, "", fmt.Errorf("导出报告失败: %w", err)
 	}
 
 	// 构建响应
@@ -168,5 +200,5 @@ func (t *BFCLEvaluationTool) Execute(ctx context.Context, args map[string]interf
 	}
 
 	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
-	return string(jsonBytes), nil
+	return result, string(jsonBytes), nil
 }
@@ -0,0 +1,213 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	"github.com/ahhsitt/helloagents-go/pkg/tools"
+)
+
+// CompareAgentsTool 智能体对比评估工具
+//
+// 在同一基准数据集上分别评估 agentA（基线）和 agentB（候选），
+// 输出回退/改进报告以及分类别的胜负统计。
+type CompareAgentsTool struct {
+	// registry 基准注册表
+	registry *BenchmarkRegistry
+
+	// dataDir 数据根目录，基准数据位于 dataDir/<benchmark>
+	dataDir string
+
+	// outputDir 输出目录
+	outputDir string
+
+	// agentA 基线智能体
+	agentA agents.Agent
+
+	// agentB 候选智能体
+	agentB agents.Agent
+}
+
+// NewCompareAgentsTool 创建智能体对比评估工具
+//
+// 参数:
+//   - registry: 基准注册表（如 DefaultBenchmarkRegistry 提供的 gaia、bfcl）
+//   - dataDir: 数据根目录，各基准的数据目录为 dataDir/<benchmark>
+//   - outputDir: 评估结果输出目录
+//   - agentA: 基线智能体
+//   - agentB: 候选智能体
+func NewCompareAgentsTool(registry *BenchmarkRegistry, dataDir, outputDir string, agentA, agentB agents.Agent) *CompareAgentsTool {
+	return &CompareAgentsTool{
+		registry:  registry,
+		dataDir:   dataDir,
+		outputDir: outputDir,
+		agentA:    agentA,
+		agentB:    agentB,
+	}
+}
+
+// Name 返回工具名称
+func (t *CompareAgentsTool) Name() string {
+	return "compare_agents"
+}
+
+// Description 返回工具描述
+func (t *CompareAgentsTool) Description() string {
+	return "智能体对比评估工具。在同一基准上评估两个智能体版本，输出回退/改进报告和分类别胜负统计。"
+}
+
+// Parameters 返回参数 Schema
+func (t *CompareAgentsTool) Parameters() tools.ParameterSchema {
+	return tools.ParameterSchema{
+		Type: "object",
+		Properties: map[string]tools.PropertySchema{
+			"benchmark": {
+				Type:        "string",
+				Description: "评估基准名称",
+				Enum:        t.registry.List(),
+			},
+			"data_dir": {
+				Type:        "string",
+				Description: "基准数据目录（为空时使用 <数据根目录>/<benchmark>）",
+			},
+			"max_samples": {
+				Type:        "integer",
+				Description: "最大评估样本数（0 表示全部）",
+				Default:     0,
+			},
+		},
+		Required: []string{"benchmark"},
+	}
+}
+
+// Execute 执行对比评估
+func (t *CompareAgentsTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	// 解析参数
+	benchmark, _ := args["benchmark"].(string)
+	factory, err := t.registry.Get(benchmark)
+	if err != nil {
+		return "", fmt.Errorf("基准 %q 不可用: %w", benchmark, err)
+	}
+
+	dataDir := filepath.Join(t.dataDir, benchmark)
+	if v, ok := args["data_dir"].(string); ok && v != "" {
+		dataDir = v
+	}
+
+	maxSamples := 0
+	if v, ok := args["max_samples"].(float64); ok {
+		maxSamples = int(v)
+	}
+
+	// 两个智能体共享同一数据集和评估器
+	dataset, evaluator := factory(dataDir)
+	if err := dataset.Load(ctx); err != nil {
+		return "", fmt.Errorf("加载数据集失败: %w", err)
+	}
+
+	opts := []evaluation.EvalOption{
+		evaluation.WithVerbose(true),
+	}
+	if maxSamples > 0 {
+		opts = append(opts, evaluation.WithMaxSamples(maxSamples))
+	}
+
+	resultA, err := evaluator.Evaluate(ctx, t.agentA, opts...)
+	if err != nil {
+		return "", fmt.Errorf("评估智能体 %s 失败: %w", t.agentA.Name(), err)
+	}
+	resultB, err := evaluator.Evaluate(ctx, t.agentB, opts...)
+	if err != nil {
+		return "", fmt.Errorf("评估智能体 %s 失败: %w", t.agentB.Name(), err)
+	}
+
+	diff, err := evaluation.Diff(resultA, resultB)
+	if err != nil {
+		return "", err
+	}
+
+	// 导出对比报告
+	timestamp := time.Now().Format("20060102_150405")
+	reportPath := filepath.Join(t.outputDir, fmt.Sprintf("%s_%s_compare.md", benchmark, timestamp))
+	if err := evaluation.ExportDiffReport(diff, reportPath); err != nil {
+		return "", fmt.Errorf("导出报告失败: %w", err)
+	}
+
+	// 构建响应（胜负以 agent_b 为视角）
+	overall := diff.Overall()
+	stronger := strongerAgent(diff, overall)
+	strongerName := ""
+	switch stronger {
+	case "agent_a":
+		strongerName = t.agentA.Name()
+	case "agent_b":
+		strongerName = t.agentB.Name()
+	}
+
+	response := map[string]interface{}{
+		"status":    "success",
+		"benchmark": benchmark,
+		"dataset":   dataset.Name(),
+		"agent_a": map[string]interface{}{
+			"name":          t.agentA.Name(),
+			"total_samples": resultA.TotalSamples,
+			"success_count": resultA.SuccessCount,
+			"accuracy":      fmt.Sprintf("%.2f%%", resultA.OverallAccuracy*100),
+		},
+		"agent_b": map[string]interface{}{
+			"name":          t.agentB.Name(),
+			"total_samples": resultB.TotalSamples,
+			"success_count": resultB.SuccessCount,
+			"accuracy":      fmt.Sprintf("%.2f%%", resultB.OverallAccuracy*100),
+		},
+		"accuracy_delta":      fmt.Sprintf("%+.2f%%", diff.AccuracyDelta*100),
+		"regressions":         len(diff.NewlyFailing),
+		"improvements":        len(diff.NewlyPassing),
+		"agent_b_wins":        overall.Wins,
+		"ties":                overall.Ties,
+		"agent_b_losses":      overall.Losses,
+		"categories":          sortedCategories(diff),
+		"stronger_agent":      stronger,
+		"stronger_agent_name": strongerName,
+		"report_path":         reportPath,
+	}
+
+	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
+	return string(jsonBytes), nil
+}
+
+// strongerAgent 判断更强的智能体
+//
+// 先比较准确率，准确率相同时比较逐样本胜负，仍相同时返回 "tie"。
+func strongerAgent(diff *evaluation.EvalDiff, overall evaluation.HeadToHead) string {
+	switch {
+	case diff.AccuracyDelta > 0:
+		return "agent_b"
+	case diff.AccuracyDelta < 0:
+		return "agent_a"
+	case overall.Wins > overall.Losses:
+		return "agent_b"
+	case overall.Wins < overall.Losses:
+		return "agent_a"
+	default:
+		return "tie"
+	}
+}
+
+// sortedCategories 返回按类别名称排序的胜负统计
+func sortedCategories(diff *evaluation.EvalDiff) []evaluation.HeadToHead {
+	categories := make([]evaluation.HeadToHead, 0, len(diff.Categories))
+	for _, h := range diff.Categories {
+		categories = append(categories, *h)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Category < categories[j].Category
+	})
+	return categories
+}
@@ -0,0 +1,213 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	"github.com/ahhsitt/helloagents-go/pkg/tools"
+)
+
+// EvaluationTool 通用评估工具
+//
+// 根据 benchmark 参数从 BenchmarkRegistry 中查找基准并执行评估，
+// 第三方基准注册到注册表后即可使用，无需编写新的工具。
+type EvaluationTool struct {
+	// registry 基准注册表
+	registry *BenchmarkRegistry
+
+	// dataDir 数据根目录，基准数据位于 dataDir/<benchmark>
+	dataDir string
+
+	// outputDir 输出目录
+	outputDir string
+
+	// agent 待评估的智能体
+	agent agents.Agent
+
+	// jobs 异步评估任务
+	jobs *JobManager
+}
+
+// NewEvaluationTool 创建通用评估工具
+//
+// 参数:
+//   - registry: 基准注册表
+//   - dataDir: 数据根目录，各基准的数据目录为 dataDir/<benchmark>
+//   - outputDir: 评估结果输出目录
+//   - agent: 待评估的智能体
+func NewEvaluationTool(registry *BenchmarkRegistry, dataDir, outputDir string, agent agents.Agent) *EvaluationTool {
+	return &EvaluationTool{
+		registry:  registry,
+		dataDir:   dataDir,
+		outputDir: outputDir,
+		agent:     agent,
+		jobs:      NewJobManager(),
+	}
+}
+
+// Name 返回工具名称
+func (t *EvaluationTool) Name() string {
+	return "evaluation"
+}
+
+// Description 返回工具描述
+func (t *EvaluationTool) Description() string {
+	return "通用智能体评估工具。按 benchmark 参数选择已注册的评估基准执行评估。"
+}
+
+// Parameters 返回参数 Schema
+func (t *EvaluationTool) Parameters() tools.ParameterSchema {
+	return tools.ParameterSchema{
+		Type: "object",
+		Properties: map[string]tools.PropertySchema{
+			"benchmark": {
+				Type:        "string",
+				Description: "评估基准名称",
+				Enum:        t.registry.List(),
+			},
+			"data_dir": {
+				Type:        "string",
+				Description: "基准数据目录（为空时使用 <数据根目录>/<benchmark>）",
+			},
+			"max_samples": {
+				Type:        "integer",
+				Description: "最大评估样本数（0 表示全部）",
+				Default:     0,
+			},
+		},
+		Required: []string{"benchmark"},
+	}
+}
+
+// Execute 执行评估
+func (t *EvaluationTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	_, output, err := t.run(ctx, args)
+	return output, err
+}
+
+// ExecuteAsync 在后台启动评估并立即返回任务 ID
+//
+// 通过 Status 轮询进度，完成后通过 Result 获取评估结果。
+func (t *EvaluationTool) ExecuteAsync(ctx context.Context, args map[string]interface{}) (string, error) {
+	jobID := t.jobs.Start(ctx, func(ctx context.Context, progress evaluation.ProgressCallback) (*evaluation.EvalResult, string, error) {
+		return t.run(ctx, args, evaluation.WithProgressCallback(progress))
+	})
+	return jobID, nil
+}
+
+// Status 返回异步评估任务状态
+func (t *EvaluationTool) Status(jobID string) (JobStatus, error) {
+	return t.jobs.Status(jobID)
+}
+
+// Result 返回异步评估任务的评估结果
+func (t *EvaluationTool) Result(jobID string) (*evaluation.EvalResult, error) {
+	return t.jobs.Result(jobID)
+}
+
+// run 执行评估并返回评估结果与工具输出
+func (t *EvaluationTool) run(ctx context.Context, args map[string]interface{}, extra ...evaluation.EvalOption) (*evaluation.EvalResult, string, error) {
+	// 解析参数
+	benchmark, _ := args["benchmark"].(string)
+	dataDir, _ := args["data_dir"].(string)
+	maxSamples := 0
+	if v, ok := args["max_samples"].(float64); ok {
+		maxSamples = int(v)
+	}
+
+	run, err := t.evaluate(ctx, benchmark, dataDir, maxSamples, extra...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	jsonBytes, _ := json.MarshalIndent(run.summary(), "", "  ")
+	return run.result, string(jsonBytes), nil
+}
+
+// benchmarkRun 单个基准的评估结果
+type benchmarkRun struct {
+	// benchmark 基准名称
+	benchmark string
+
+	// dataset 数据集名称
+	dataset string
+
+	// result 评估结果
+	result *evaluation.EvalResult
+
+	// reportPath 报告路径
+	reportPath string
+}
+
+// summary 返回评估结果摘要（工具输出）
+func (r *benchmarkRun) summary() map[string]interface{} {
+	return map[string]interface{}{
+		"status":          "success",
+		"benchmark":       r.benchmark,
+		"dataset":         r.dataset,
+		"total_samples":   r.result.TotalSamples,
+		"success_count":   r.result.SuccessCount,
+		"accuracy":        fmt.Sprintf("%.2f%%", r.result.OverallAccuracy*100),
+		"duration":        r.result.TotalDuration.String(),
+		"report_path":     r.reportPath,
+		"evaluation_time": r.result.EvaluationTime.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// evaluate 评估单个已注册基准并导出完整报告
+//
+// dataDir 为空时使用 <数据根目录>/<benchmark>，maxSamples 为 0 时评估全部样本。
+func (t *EvaluationTool) evaluate(ctx context.Context, benchmark, dataDir string, maxSamples int,
+	extra ...evaluation.EvalOption) (*benchmarkRun, error) {
+	factory, err := t.registry.Get(benchmark)
+	if err != nil {
+		return nil, fmt.Errorf("基准 %q 不可用: %w", benchmark, err)
+	}
+
+	if dataDir == "" {
+		dataDir = filepath.Join(t.dataDir, benchmark)
+	}
+
+	// 创建数据集和评估器
+	dataset, evaluator := factory(dataDir)
+
+	// 加载数据集
+	if err := dataset.Load(ctx); err != nil {
+		return nil, fmt.Errorf("加载数据集失败: %w", err)
+	}
+
+	// 配置评估选项
+	opts := []evaluation.EvalOption{
+		evaluation.WithVerbose(true),
+	}
+	if maxSamples > 0 {
+		opts = append(opts, evaluation.WithMaxSamples(maxSamples))
+	}
+
+	opts = append(opts, extra...)
+
+	// 执行评估
+	result, err := evaluator.Evaluate(ctx, t.agent, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("评估失败: %w", err)
+	}
+
+	// 导出完整报告
+	timestamp := time.Now().Format("20060102_150405")
+	reportPath := filepath.Join(t.outputDir, fmt.Sprintf("%s_%s_report.md", benchmark, timestamp))
+	if err := evaluation.ExportFullReport(result, reportPath); err != nil {
+		return nil, fmt.Errorf("导出报告失败: %w", err)
+	}
+
+	return &benchmarkRun{
+		benchmark:  benchmark,
+		dataset:    dataset.Name(),
+		result:     result,
+		reportPath: reportPath,
+	}, nil
+}
@@ -23,6 +23,9 @@ type GAIAEvaluationTool struct {
 
 	// agent 待评估的智能体
 	agent agents.Agent
+
+	// jobs 异步评估任务
+	jobs *JobManager
 }
 
 // NewGAIAEvaluationTool 创建 GAIA 评估工具
@@ -36,6 +39,7 @@ func NewGAIAEvaluationTool(dataDir, outputDir string, agent agents.Agent) *GAIAE
 		dataDir:   dataDir,
 		outputDir: outputDir,
 		agent:     agent,
+		jobs:      NewJobManager(),
 	}
 }
 
@@ -70,12 +74,43 @@ func (t *GAIAEvaluationTool) Parameters() tools.ParameterSchema {
 				Description: "最大评估样本数（0 表示全部）",
 				Default:     0,
 			},
+			"verify_submission": {
+				Type:        "boolean",
+				Description: "导出后校验提交文件的 task_id 与样本是否一一对应",
+				Default:     false,
+			},
 		},
 	}
 }
 
 // Execute 执行评估
 func (t *GAIAEvaluationTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	_, output, err := t.run(ctx, args)
+	return output, err
+}
+
+// ExecuteAsync 在后台启动评估并立即返回任务 ID
+//
+// 通过 Status 轮询进度，完成后通过 Result 获取评估结果。
+func (t *GAIAEvaluationTool) ExecuteAsync(ctx context.Context, args map[string]interface{}) (string, error) {
+	jobID := t.jobs.Start(ctx, func(ctx context.Context, progress evaluation.ProgressCallback) (*evaluation.EvalResult, string, error) {
+		return t.run(ctx, args, evaluation.WithProgressCallback(progress))
+	})
+	return jobID, nil
+}
+
+// Status 返回异步评估任务状态
+func (t *GAIAEvaluationTool) Status(jobID string) (JobStatus, error) {
+	return t.jobs.Status(jobID)
+}
+
+// Result 返回异步评估任务的评估结果
+func (t *GAIAEvaluationTool) Result(jobID string) (*evaluation.EvalResult, error) {
+	return t.jobs.Result(jobID)
+}
+
+// run 执行评估并返回评估结果与工具输出
+func (t *GAIAEvaluationTool) run(ctx context.Context, args map[string]interface{}, extra ...evaluation.EvalOption) (*evaluation.EvalResult, string, error) {
 	// 解析参数
 	level := 0
 	if v, ok := args["level"].(float64); ok {
@@ -92,12 +127,14 @@ func (t *GAIAEvaluationTool) Execute(ctx context.Context, args map[string]interf
 		maxSamples = int(v)
 	}
 
+	verify, _ := args["verify_submission"].(bool)
+
 	// 创建数据集
 	dataset := gaia.NewDataset(t.dataDir, level, split)
 
 	// 加载数据集
 	if err := dataset.Load(ctx); err != nil {
-		return "", fmt.Errorf("加载数据集失败: %w", err)
+		return nil, "", fmt.Errorf("加载数据集失败: %w", err)
 	}
 
 	// 创建评估器
@@ -111,10 +148,12 @@ func (t *GAIAEvaluationTool) Execute(ctx context.Context, args map[string]interf
 		opts = append(opts, evaluation.WithMaxSamples(maxSamples))
 	}
 
+	opts = append(opts, extra...)
+
 	// 执行评估
 	result, err := evaluator.Evaluate(ctx, t.agent, opts...)
 	if err != nil {
-		return "", fmt.Errorf("评估失败: %w", err)
+		return nil, "", fmt.Errorf("评估失败: %w", err)
 	}
 
 	// 生成输出文件名
@@ -125,13 +164,18 @@ func (t *GAIAEvaluationTool) Execute(ctx context.Context, args map[string]interf
 	exporter := gaia.NewExporter()
 	officialPath := filepath.Join(t.outputDir, baseName+"_submission.jsonl")
 	if err := exporter.Export(result, officialPath); err != nil {
-		return "", fmt.Errorf("导出官方格式失败: %w", err)
+		return nil, "", fmt.Errorf("导出官方格式失败: %w", err)
+	}
+	if verify {
+		if err := exporter.VerifyExport(officialPath, result); err != nil {
+			return nil, "", fmt.Errorf("校验提交文件失败: %w", err)
+		}
 	}
 
 	// 导出 Markdown 报告
 	reportPath := filepath.Join(t.outputDir, baseName+"_report.md")
 	if err := exporter.ExportMarkdownReport(result, reportPath); err != nil {
-		return "", fmt.Errorf("导出报告失败: %w", err)
+		return nil, "", fmt.Errorf("导出报告失败: %w", err)
 	}
 
 	// 构建响应
@@ -147,6 +191,9 @@ func (t *GAIAEvaluationTool) Execute(ctx context.Context, args map[string]interf
 		"submission_path": officialPath,
 		"evaluation_time": result.EvaluationTime.Format("2006-01-02 15:04:05"),
 	}
+	if verify {
+		response["submission_verified"] = true
+	}
 
 	// 添加级别分布
 	if len(result.LevelMetrics) > 0 {
@@ -162,7 +209,7 @@ func (t *GAIAEvaluationTool) Execute(ctx context.Context, args map[string]interf
 	}
 
 	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
-	return string(jsonBytes), nil
+	return result, string(jsonBytes), nil
 }
 
 // GetDatasetInfo 获取数据集信息
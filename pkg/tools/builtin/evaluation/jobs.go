@@ -0,0 +1,200 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+)
+
+// 异步任务相关错误
+var (
+	// ErrJobNotFound 任务不存在
+	ErrJobNotFound = errors.New("evaluation job not found")
+
+	// ErrJobNotFinished 任务尚未完成
+	ErrJobNotFinished = errors.New("evaluation job not finished")
+)
+
+// JobState 异步评估任务状态
+type JobState string
+
+const (
+	// JobPending 等待执行
+	JobPending JobState = "pending"
+	// JobRunning 执行中
+	JobRunning JobState = "running"
+	// JobCompleted 已完成
+	JobCompleted JobState = "completed"
+	// JobFailed 执行失败
+	JobFailed JobState = "failed"
+)
+
+// JobStatus 异步评估任务状态快照
+type JobStatus struct {
+	// ID 任务 ID
+	ID string `json:"id"`
+
+	// State 任务状态
+	State JobState `json:"state"`
+
+	// Done 已完成样本数
+	Done int `json:"done"`
+
+	// Total 样本总数
+	Total int `json:"total"`
+
+	// Output 评估完成后的工具输出（JSON 字符串）
+	Output string `json:"output,omitempty"`
+
+	// Error 失败原因
+	Error string `json:"error,omitempty"`
+
+	// StartedAt 开始时间
+	StartedAt time.Time `json:"started_at"`
+
+	// FinishedAt 结束时间
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished 返回任务是否已结束（成功或失败）
+func (s JobStatus) Finished() bool {
+	return s.State == JobCompleted || s.State == JobFailed
+}
+
+// JobRunFunc 异步任务执行函数
+//
+// progress 应传递给评估器（如 evaluation.WithProgressCallback）以上报进度。
+type JobRunFunc func(ctx context.Context, progress evaluation.ProgressCallback) (*evaluation.EvalResult, string, error)
+
+// job 内部任务记录
+type job struct {
+	status JobStatus
+	result *evaluation.EvalResult
+}
+
+// JobManager 异步评估任务管理器
+//
+// 任务状态与结果保存在内存中，进程退出后丢失。
+type JobManager struct {
+	mu   sync.RWMutex
+	jobs map[string]*job
+}
+
+// NewJobManager 创建异步评估任务管理器
+func NewJobManager() *JobManager {
+	return &JobManager{
+		jobs: make(map[string]*job),
+	}
+}
+
+// Start 在后台启动任务并返回任务 ID
+//
+// 任务使用与 ctx 解除取消关联的上下文执行，调用方返回后任务仍会继续运行。
+func (m *JobManager) Start(ctx context.Context, run JobRunFunc) string {
+	id := uuid.New().String()
+
+	m.mu.Lock()
+	m.jobs[id] = &job{
+		status: JobStatus{
+			ID:        id,
+			State:     JobPending,
+			StartedAt: time.Now(),
+		},
+	}
+	m.mu.Unlock()
+
+	runCtx := context.WithoutCancel(ctx)
+	go m.execute(runCtx, id, run)
+
+	return id
+}
+
+// execute 执行任务并记录结果
+func (m *JobManager) execute(ctx context.Context, id string, run JobRunFunc) {
+	m.update(id, func(j *job) {
+		j.status.State = JobRunning
+	})
+
+	progress := func(done, total int) {
+		m.update(id, func(j *job) {
+			j.status.Done = done
+			j.status.Total = total
+		})
+	}
+
+	var (
+		result *evaluation.EvalResult
+		output string
+		err    error
+	)
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("评估任务异常: %v", r)
+			}
+		}()
+		result, output, err = run(ctx, progress)
+	}()
+
+	m.update(id, func(j *job) {
+		j.status.FinishedAt = time.Now()
+		if err != nil {
+			j.status.State = JobFailed
+			j.status.Error = err.Error()
+			return
+		}
+		j.status.State = JobCompleted
+		j.status.Output = output
+		j.result = result
+		if result != nil {
+			j.status.Done = result.TotalSamples
+			j.status.Total = result.TotalSamples
+		}
+	})
+}
+
+// update 在锁内修改任务记录
+func (m *JobManager) update(id string, fn func(j *job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if j, ok := m.jobs[id]; ok {
+		fn(j)
+	}
+}
+
+// Status 返回任务状态快照
+func (m *JobManager) Status(id string) (JobStatus, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return JobStatus{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return j.status, nil
+}
+
+// Result 返回已完成任务的评估结果
+//
+// 任务未结束时返回 ErrJobNotFinished，任务失败时返回失败原因。
+func (m *JobManager) Result(id string) (*evaluation.EvalResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	switch j.status.State {
+	case JobCompleted:
+		return j.result, nil
+	case JobFailed:
+		return nil, fmt.Errorf("评估失败: %s", j.status.Error)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFinished, id)
+	}
+}
@@ -20,6 +20,9 @@ type LLMJudgeTool struct {
 
 	// outputDir 输出目录
 	outputDir string
+
+	// jobs 异步评估任务
+	jobs *JobManager
 }
 
 // NewLLMJudgeTool 创建 LLM Judge 工具
@@ -31,6 +34,7 @@ func NewLLMJudgeTool(llmProvider llm.Provider, outputDir string) *LLMJudgeTool {
 	return &LLMJudgeTool{
 		llmProvider: llmProvider,
 		outputDir:   outputDir,
+		jobs:        NewJobManager(),
 	}
 }
 
@@ -69,10 +73,36 @@ func (t *LLMJudgeTool) Parameters() tools.ParameterSchema {
 
 // Execute 执行评估
 func (t *LLMJudgeTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	_, output, err := t.run(ctx, args)
+	return output, err
+}
+
+// ExecuteAsync 在后台启动评估并立即返回任务 ID
+//
+// 通过 Status 轮询进度，完成后通过 Result 获取评估结果。
+func (t *LLMJudgeTool) ExecuteAsync(ctx context.Context, args map[string]interface{}) (string, error) {
+	jobID := t.jobs.Start(ctx, func(ctx context.Context, progress evaluation.ProgressCallback) (*evaluation.EvalResult, string, error) {
+		return t.run(ctx, args, evaluation.WithProgressCallback(progress))
+	})
+	return jobID, nil
+}
+
+// Status 返回异步评估任务状态
+func (t *LLMJudgeTool) Status(jobID string) (JobStatus, error) {
+	return t.jobs.Status(jobID)
+}
+
+// Result 返回异步评估任务的评估结果
+func (t *LLMJudgeTool) Result(jobID string) (*evaluation.EvalResult, error) {
+	return t.jobs.Result(jobID)
+}
+
+// run 执行评估并返回评估结果与工具输出
+func (t *LLMJudgeTool) run(ctx context.Context, args map[string]interface{}, extra ...evaluation.EvalOption) (*evaluation.EvalResult, string, error) {
 	// 解析参数
 	dataPath, ok := args["data_path"].(string)
 	if !ok || dataPath == "" {
-		return "", fmt.Errorf("data_path 参数是必需的")
+		return nil, "", fmt.Errorf("data_path 参数是必需的")
 	}
 
 	referencePath, _ := args["reference_path"].(string)
@@ -85,7 +115,7 @@ func (t *LLMJudgeTool) Execute(ctx context.Context, args map[string]interface{})
 	// 创建数据集
 	dataset := datagen.NewDataset(dataPath)
 	if err := dataset.Load(ctx); err != nil {
-		return "", fmt.Errorf("加载数据集失败: %w", err)
+		return nil, "", fmt.Errorf("加载数据集失败: %w", err)
 	}
 
 	// 加载参考数据（如果有）
@@ -93,7 +123,7 @@ func (t *LLMJudgeTool) Execute(ctx context.Context, args map[string]interface{})
 	if referencePath != "" {
 		refDataset := datagen.NewDataset(referencePath)
 		if err := refDataset.Load(ctx); err != nil {
-			return "", fmt.Errorf("加载参考数据集失败: %w", err)
+			return nil, "", fmt.Errorf("加载参考数据集失败: %w", err)
 		}
 		config.ReferenceSamples = refDataset.GetSamples()
 	}
@@ -109,10 +139,12 @@ func (t *LLMJudgeTool) Execute(ctx context.Context, args map[string]interface{})
 		opts = append(opts, evaluation.WithMaxSamples(maxSamples))
 	}
 
+	opts = append(opts, extra...)
+
 	// 执行评估
 	result, err := judge.Evaluate(ctx, opts...)
 	if err != nil {
-		return "", fmt.Errorf("评估失败: %w", err)
+		return nil, "", fmt.Errorf("评估失败: %w", err)
 	}
 
 	// 生成输出文件名
@@ -123,13 +155,13 @@ func (t *LLMJudgeTool) Execute(ctx context.Context, args map[string]interface{})
 	exporter := datagen.NewExporter()
 	reportPath := filepath.Join(t.outputDir, baseName+"_report.md")
 	if err := exporter.ExportJudgeReport(result, reportPath); err != nil {
-		return "", fmt.Errorf("导出报告失败: %w", err)
+		return nil, "", fmt.Errorf("导出报告失败: %w", err)
 	}
 
 	// 导出 JSON 结果
 	jsonPath := filepath.Join(t.outputDir, baseName+"_result.json")
 	if err := exporter.ExportJSON(result, jsonPath); err != nil {
-		return "", fmt.Errorf("导出 JSON 失败: %w", err)
+		return nil, "", fmt.Errorf("导出 JSON 失败: %w", err)
 	}
 
 	// 构建响应
@@ -158,5 +190,5 @@ func (t *LLMJudgeTool) Execute(ctx context.Context, args map[string]interface{})
 	}
 
 	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
-	return string(jsonBytes), nil
+	return result, string(jsonBytes), nil
 }
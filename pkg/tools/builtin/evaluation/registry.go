@@ -0,0 +1,119 @@
+package evaluation
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation/benchmarks/bfcl"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation/benchmarks/gaia"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation/benchmarks/mmlu"
+)
+
+// 基准注册相关错误
+var (
+	// ErrBenchmarkNotFound 基准未注册
+	ErrBenchmarkNotFound = errors.New("benchmark not found")
+
+	// ErrBenchmarkExists 基准已注册
+	ErrBenchmarkExists = errors.New("benchmark already registered")
+
+	// ErrInvalidBenchmark 基准名称或工厂无效
+	ErrInvalidBenchmark = errors.New("invalid benchmark")
+)
+
+// BenchmarkFactory 基准工厂函数
+//
+// 根据数据目录创建数据集及对应的评估器。
+type BenchmarkFactory func(dataDir string) (evaluation.Dataset, evaluation.Evaluator)
+
+// BenchmarkRegistry 基准注册表
+//
+// 按名称管理基准工厂，供通用评估工具按 benchmark 参数分发。支持并发安全的注册和查询。
+type BenchmarkRegistry struct {
+	factories map[string]BenchmarkFactory
+	mu        sync.RWMutex
+}
+
+// NewBenchmarkRegistry 创建空的基准注册表
+func NewBenchmarkRegistry() *BenchmarkRegistry {
+	return &BenchmarkRegistry{
+		factories: make(map[string]BenchmarkFactory),
+	}
+}
+
+// DefaultBenchmarkRegistry 创建包含内置基准的注册表
+//
+// 内置基准：
+//   - gaia: GAIA validation 全部级别
+//   - bfcl: BFCL simple_python 类别，AST 模式
+//   - mmlu: MMLU 全部学科
+func DefaultBenchmarkRegistry() *BenchmarkRegistry {
+	r := NewBenchmarkRegistry()
+	r.MustRegister("gaia", func(dataDir string) (evaluation.Dataset, evaluation.Evaluator) {
+		dataset := gaia.NewDataset(dataDir, 0, "validation")
+		return dataset, gaia.NewEvaluator(dataset)
+	})
+	r.MustRegister("bfcl", func(dataDir string) (evaluation.Dataset, evaluation.Evaluator) {
+		dataset := bfcl.NewDataset(dataDir, "simple_python")
+		return dataset, bfcl.NewEvaluator(dataset, bfcl.ModeAST)
+	})
+	r.MustRegister("mmlu", func(dataDir string) (evaluation.Dataset, evaluation.Evaluator) {
+		dataset := mmlu.NewDataset(dataDir, "")
+		return dataset, mmlu.NewEvaluator(dataset)
+	})
+	return r
+}
+
+// Register 注册基准
+//
+// 名称为空或工厂为 nil 时返回 ErrInvalidBenchmark，名称已存在时返回 ErrBenchmarkExists。
+func (r *BenchmarkRegistry) Register(name string, factory BenchmarkFactory) error {
+	if name == "" || factory == nil {
+		return ErrInvalidBenchmark
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.factories[name]; exists {
+		return ErrBenchmarkExists
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// MustRegister 注册基准，失败则 panic
+func (r *BenchmarkRegistry) MustRegister(name string, factory BenchmarkFactory) {
+	if err := r.Register(name, factory); err != nil {
+		panic(err)
+	}
+}
+
+// Get 获取基准工厂
+//
+// 如果基准不存在，返回 nil 和 ErrBenchmarkNotFound。
+func (r *BenchmarkRegistry) Get(name string) (BenchmarkFactory, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, ErrBenchmarkNotFound
+	}
+	return factory, nil
+}
+
+// List 返回已注册的基准名称（按字母排序）
+func (r *BenchmarkRegistry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
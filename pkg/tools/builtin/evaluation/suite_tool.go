@@ -0,0 +1,263 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	"github.com/ahhsitt/helloagents-go/pkg/tools"
+)
+
+// SuiteEvaluationTool 多基准评估工具
+//
+// 在一次调用中依次（或并发）评估多个已注册基准，汇总为一个 JSON 摘要，
+// 并生成链接到各基准报告的总报告。单个基准失败不会中断其他基准。
+type SuiteEvaluationTool struct {
+	// single 单基准评估工具（共享注册表、目录和智能体）
+	single *EvaluationTool
+}
+
+// NewSuiteEvaluationTool 创建多基准评估工具
+//
+// 参数:
+//   - registry: 基准注册表
+//   - dataDir: 数据根目录，各基准的数据目录默认为 dataDir/<benchmark>
+//   - outputDir: 评估结果输出目录
+//   - agent: 待评估的智能体
+func NewSuiteEvaluationTool(registry *BenchmarkRegistry, dataDir, outputDir string, agent agents.Agent) *SuiteEvaluationTool {
+	return &SuiteEvaluationTool{
+		single: NewEvaluationTool(registry, dataDir, outputDir, agent),
+	}
+}
+
+// Name 返回工具名称
+func (t *SuiteEvaluationTool) Name() string {
+	return "evaluation_suite"
+}
+
+// Description 返回工具描述
+func (t *SuiteEvaluationTool) Description() string {
+	return "多基准评估工具。在一次调用中评估多个已注册的基准，汇总结果并生成总报告。"
+}
+
+// Parameters 返回参数 Schema
+func (t *SuiteEvaluationTool) Parameters() tools.ParameterSchema {
+	return tools.ParameterSchema{
+		Type: "object",
+		Properties: map[string]tools.PropertySchema{
+			"benchmarks": {
+				Type:        "array",
+				Description: "要评估的基准列表",
+				Items: &tools.PropertySchema{
+					Type: "object",
+					Properties: map[string]tools.PropertySchema{
+						"benchmark": {
+							Type:        "string",
+							Description: "评估基准名称",
+							Enum:        t.single.registry.List(),
+						},
+						"data_dir": {
+							Type:        "string",
+							Description: "基准数据目录（为空时使用 <数据根目录>/<benchmark>）",
+						},
+						"max_samples": {
+							Type:        "integer",
+							Description: "最大评估样本数（0 表示全部）",
+							Default:     0,
+						},
+					},
+					Required: []string{"benchmark"},
+				},
+			},
+			"concurrent": {
+				Type:        "boolean",
+				Description: "是否并发评估各基准",
+				Default:     false,
+			},
+		},
+		Required: []string{"benchmarks"},
+	}
+}
+
+// suiteEntry 单个基准的评估配置
+type suiteEntry struct {
+	benchmark  string
+	dataDir    string
+	maxSamples int
+}
+
+// Execute 执行多基准评估
+//
+// 所有基准均失败时返回错误；部分失败时 status 为 partial，失败原因记录在对应条目中。
+func (t *SuiteEvaluationTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	entries, err := parseSuiteEntries(args)
+	if err != nil {
+		return "", err
+	}
+	concurrent, _ := args["concurrent"].(bool)
+
+	startTime := time.Now()
+	runs := make([]*benchmarkRun, len(entries))
+	errs := make([]error, len(entries))
+
+	runEntry := func(i int) {
+		e := entries[i]
+		runs[i], errs[i] = t.single.evaluate(ctx, e.benchmark, e.dataDir, e.maxSamples)
+	}
+	if concurrent {
+		var wg sync.WaitGroup
+		for i := range entries {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				runEntry(i)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range entries {
+			runEntry(i)
+		}
+	}
+
+	// 汇总结果
+	suite := evaluation.NewSuiteResult(t.single.agent.Name())
+	suite.EvaluationTime = startTime
+	benchmarks := make([]map[string]interface{}, len(entries))
+	for i, e := range entries {
+		if errs[i] != nil {
+			suite.AddError(e.benchmark, errs[i])
+			benchmarks[i] = map[string]interface{}{
+				"status":    "failed",
+				"benchmark": e.benchmark,
+				"error":     errs[i].Error(),
+			}
+			continue
+		}
+		suite.Add(e.benchmark, runs[i].result)
+		benchmarks[i] = runs[i].summary()
+	}
+	suite.TotalDuration = time.Since(startTime)
+
+	if len(suite.Results) == 0 {
+		return "", fmt.Errorf("所有基准评估均失败: %w", errors.Join(errs...))
+	}
+
+	// 导出总报告
+	timestamp := startTime.Format("20060102_150405")
+	reportPath := filepath.Join(t.single.outputDir, fmt.Sprintf("suite_%s_report.md", timestamp))
+	if err := exportSuiteReport(suite, entries, runs, reportPath); err != nil {
+		return "", fmt.Errorf("导出总报告失败: %w", err)
+	}
+
+	status := "success"
+	if len(suite.Errors) > 0 {
+		status = "partial"
+	}
+	response := map[string]interface{}{
+		"status":          status,
+		"agent":           suite.AgentName,
+		"benchmarks":      benchmarks,
+		"succeeded":       len(suite.Results),
+		"failed":          len(suite.Errors),
+		"duration":        suite.TotalDuration.String(),
+		"report_path":     reportPath,
+		"evaluation_time": startTime.Format("2006-01-02 15:04:05"),
+	}
+
+	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
+	return string(jsonBytes), nil
+}
+
+// parseSuiteEntries 解析 benchmarks 参数（同一基准不可重复）
+func parseSuiteEntries(args map[string]interface{}) ([]suiteEntry, error) {
+	raw, _ := args["benchmarks"].([]interface{})
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("benchmarks 不能为空")
+	}
+
+	entries := make([]suiteEntry, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for i, item := range raw {
+		cfg, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("benchmarks[%d] 格式错误", i)
+		}
+
+		var e suiteEntry
+		e.benchmark, _ = cfg["benchmark"].(string)
+		if e.benchmark == "" {
+			return nil, fmt.Errorf("benchmarks[%d] 缺少 benchmark", i)
+		}
+		if seen[e.benchmark] {
+			return nil, fmt.Errorf("基准 %q 重复", e.benchmark)
+		}
+		seen[e.benchmark] = true
+
+		e.dataDir, _ = cfg["data_dir"].(string)
+		if v, ok := cfg["max_samples"].(float64); ok {
+			e.maxSamples = int(v)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// exportSuiteReport 导出多基准总报告（Markdown），各基准报告以相对链接引用
+func exportSuiteReport(suite *evaluation.SuiteResult, entries []suiteEntry, runs []*benchmarkRun, path string) error {
+	var sb strings.Builder
+
+	sb.WriteString("# 多基准评估报告\n\n")
+	sb.WriteString("## 概览\n\n")
+	fmt.Fprintf(&sb, "- **智能体**: %s\n", suite.AgentName)
+	fmt.Fprintf(&sb, "- **评估时间**: %s\n", suite.EvaluationTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&sb, "- **总耗时**: %s\n", suite.TotalDuration)
+	fmt.Fprintf(&sb, "- **成功/失败**: %d / %d\n\n", len(suite.Results), len(suite.Errors))
+
+	sb.WriteString("## 基准结果\n\n")
+	sb.WriteString("| 基准 | 数据集 | 样本数 | 成功数 | 准确率 | 报告 |\n")
+	sb.WriteString("|------|--------|--------|--------|--------|------|\n")
+	for i, e := range entries {
+		run := runs[i]
+		if run == nil {
+			fmt.Fprintf(&sb, "| %s | - | - | - | 失败 | - |\n", e.benchmark)
+			continue
+		}
+		rel, err := filepath.Rel(filepath.Dir(path), run.reportPath)
+		if err != nil {
+			rel = run.reportPath
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %d | %d | %.2f%% | [%s](%s) |\n",
+			e.benchmark, run.dataset, run.result.TotalSamples, run.result.SuccessCount,
+			run.result.OverallAccuracy*100, filepath.Base(run.reportPath), filepath.ToSlash(rel))
+	}
+	sb.WriteString("\n")
+
+	if len(suite.Errors) > 0 {
+		sb.WriteString("## 失败基准\n\n")
+		for _, e := range entries {
+			if msg, ok := suite.Errors[e.benchmark]; ok {
+				fmt.Fprintf(&sb, "- **%s**: %s\n", e.benchmark, msg)
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// 确保目录存在
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	return nil
+}
@@ -20,6 +20,9 @@ type WinRateTool struct {
 
 	// outputDir 输出目录
 	outputDir string
+
+	// jobs 异步评估任务
+	jobs *JobManager
 }
 
 // NewWinRateTool 创建 Win Rate 工具
@@ -31,6 +34,7 @@ func NewWinRateTool(llmProvider llm.Provider, outputDir string) *WinRateTool {
 	return &WinRateTool{
 		llmProvider: llmProvider,
 		outputDir:   outputDir,
+		jobs:        NewJobManager(),
 	}
 }
 
@@ -67,6 +71,11 @@ func (t *WinRateTool) Parameters() tools.ParameterSchema {
 				Description: "随机种子（用于位置随机化）",
 				Default:     0,
 			},
+			"double_pass": {
+				Type:        "boolean",
+				Description: "是否交换顺序评估两轮以消除位置偏差（两轮不一致计为平局）",
+				Default:     false,
+			},
 		},
 		Required: []string{"candidate_path", "reference_path"},
 	}
@@ -74,15 +83,41 @@ func (t *WinRateTool) Parameters() tools.ParameterSchema {
 
 // Execute 执行评估
 func (t *WinRateTool) Execute(ctx context.Context, args map[string]interface{}) (string, error) {
+	_, output, err := t.run(ctx, args)
+	return output, err
+}
+
+// ExecuteAsync 在后台启动评估并立即返回任务 ID
+//
+// 通过 Status 轮询进度，完成后通过 Result 获取评估结果。
+func (t *WinRateTool) ExecuteAsync(ctx context.Context, args map[string]interface{}) (string, error) {
+	jobID := t.jobs.Start(ctx, func(ctx context.Context, progress evaluation.ProgressCallback) (*evaluation.EvalResult, string, error) {
+		return t.run(ctx, args, evaluation.WithProgressCallback(progress))
+	})
+	return jobID, nil
+}
+
+// Status 返回异步评估任务状态
+func (t *WinRateTool) Status(jobID string) (JobStatus, error) {
+	return t.jobs.Status(jobID)
+}
+
+// Result 返回异步评估任务的评估结果
+func (t *WinRateTool) Result(jobID string) (*evaluation.EvalResult, error) {
+	return t.jobs.Result(jobID)
+}
+
+// run 执行评估并返回评估结果与工具输出
+func (t *WinRateTool) run(ctx context.Context, args map[string]interface{}, extra ...evaluation.EvalOption) (*evaluation.EvalResult, string, error) {
 	// 解析参数
 	candidatePath, ok := args["candidate_path"].(string)
 	if !ok || candidatePath == "" {
-		return "", fmt.Errorf("candidate_path 参数是必需的")
+		return nil, "", fmt.Errorf("candidate_path 参数是必需的")
 	}
 
 	referencePath, ok := args["reference_path"].(string)
 	if !ok || referencePath == "" {
-		return "", fmt.Errorf("reference_path 参数是必需的")
+		return nil, "", fmt.Errorf("reference_path 参数是必需的")
 	}
 
 	maxSamples := 0
@@ -95,20 +130,23 @@ func (t *WinRateTool) Execute(ctx context.Context, args map[string]interface{})
 		randomSeed = int64(v)
 	}
 
+	doublePass, _ := args["double_pass"].(bool)
+
 	// 创建数据集
 	candidateDataset := datagen.NewDataset(candidatePath)
 	if err := candidateDataset.Load(ctx); err != nil {
-		return "", fmt.Errorf("加载候选数据集失败: %w", err)
+		return nil, "", fmt.Errorf("加载候选数据集失败: %w", err)
 	}
 
 	referenceDataset := datagen.NewDataset(referencePath)
 	if err := referenceDataset.Load(ctx); err != nil {
-		return "", fmt.Errorf("加载参考数据集失败: %w", err)
+		return nil, "", fmt.Errorf("加载参考数据集失败: %w", err)
 	}
 
 	// 创建评估器
 	config := datagen.WinRateConfig{
 		RandomSeed: randomSeed,
+		DoublePass: doublePass,
 	}
 	evaluator := datagen.NewWinRateEvaluator(t.llmProvider, candidateDataset, referenceDataset, config)
 
@@ -120,10 +158,12 @@ func (t *WinRateTool) Execute(ctx context.Context, args map[string]interface{})
 		opts = append(opts, evaluation.WithMaxSamples(maxSamples))
 	}
 
+	opts = append(opts, extra...)
+
 	// 执行评估
 	result, err := evaluator.Evaluate(ctx, opts...)
 	if err != nil {
-		return "", fmt.Errorf("评估失败: %w", err)
+		return nil, "", fmt.Errorf("评估失败: %w", err)
 	}
 
 	// 生成输出文件名
@@ -134,13 +174,13 @@ func (t *WinRateTool) Execute(ctx context.Context, args map[string]interface{})
 	exporter := datagen.NewExporter()
 	reportPath := filepath.Join(t.outputDir, baseName+"_report.md")
 	if err := exporter.ExportWinRateReport(result, reportPath); err != nil {
-		return "", fmt.Errorf("导出报告失败: %w", err)
+		return nil, "", fmt.Errorf("导出报告失败: %w", err)
 	}
 
 	// 导出 JSON 结果
 	jsonPath := filepath.Join(t.outputDir, baseName+"_result.json")
 	if err := exporter.ExportJSON(result, jsonPath); err != nil {
-		return "", fmt.Errorf("导出 JSON 失败: %w", err)
+		return nil, "", fmt.Errorf("导出 JSON 失败: %w", err)
 	}
 
 	// 构建响应
@@ -185,5 +225,5 @@ func (t *WinRateTool) Execute(ctx context.Context, args map[string]interface{})
 	}
 
 	jsonBytes, _ := json.MarshalIndent(response, "", "  ")
-	return string(jsonBytes), nil
+	return result, string(jsonBytes), nil
 }
@@ -0,0 +1,107 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// rateLimitedHandler 前 failures 次请求返回 429，之后正常返回
+func rateLimitedHandler(failures int32, calls *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.Header().Set("Retry-After", "1")
+			w.Header().Set("X-Request-Id", "req_123")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{"message": "Rate limit reached", "type": "requests"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data":    []map[string]interface{}{{"url": "https://example.com/image.png"}},
+		})
+	}
+}
+
+func TestAPIError_RateLimited(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(rateLimitedHandler(1, &calls))
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithMaxRetries(0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Generate(context.Background(), image.ImageRequest{Prompt: "a cat"})
+	if !errors.Is(err, image.ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if !image.IsRetryable(err) {
+		t.Error("expected 429 to be retryable")
+	}
+
+	var apiErr *image.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("StatusCode = %d, want 429", apiErr.StatusCode)
+	}
+	if apiErr.RetryAfter != time.Second {
+		t.Errorf("RetryAfter = %v, want 1s", apiErr.RetryAfter)
+	}
+	if apiErr.RequestID != "req_123" {
+		t.Errorf("RequestID = %q, want req_123", apiErr.RequestID)
+	}
+	if apiErr.ProviderMessage != "Rate limit reached" {
+		t.Errorf("ProviderMessage = %q, want %q", apiErr.ProviderMessage, "Rate limit reached")
+	}
+}
+
+func TestAPIError_RetryHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(rateLimitedHandler(1, &calls))
+	defer server.Close()
+
+	// 指数退避延迟远大于 Retry-After，重试应按 Retry-After 等待
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithMaxRetries(1),
+		image.WithRetryDelay(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.Generate(ctx, image.ImageRequest{Prompt: "a cat"}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+	if elapsed < time.Second || elapsed > 5*time.Second {
+		t.Errorf("expected retry after ~1s, took %v", elapsed)
+	}
+}
@@ -0,0 +1,145 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestGenerateBatch_SharedRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data": []map[string]interface{}{
+				{"url": "https://example.com/image.png"},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithRateLimit(2, 1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	reqs := make([]image.ImageRequest, 6)
+	for i := range reqs {
+		reqs[i] = image.ImageRequest{Prompt: fmt.Sprintf("prompt %d", i)}
+	}
+
+	start := time.Now()
+	results, err := image.GenerateBatch(context.Background(), client, reqs, 3)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+
+	if len(results) != len(reqs) {
+		t.Fatalf("expected %d results, got %d", len(reqs), len(results))
+	}
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("result %d has index %d", i, r.Index)
+		}
+		if r.Error != nil {
+			t.Errorf("result %d failed: %v", i, r.Error)
+		}
+	}
+
+	// 6 个请求在 2 rps、突发 1 的限流下至少需要 2.5s
+	if elapsed < 2*time.Second {
+		t.Errorf("expected batch to be throttled to >= 2s, took %v", elapsed)
+	}
+}
+
+func TestGenerateBatch_ContextCanceled(t *testing.T) {
+	provider := &fakeProvider{
+		generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			return image.ImageResponse{}, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := image.GenerateBatch(ctx, provider, make([]image.ImageRequest, 3), 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GenerateBatch() error = %v, want context.Canceled", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+}
+
+func TestGenerateBatch_CanceledAfterDispatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 最后一个请求执行时取消 ctx：所有请求均已派发并完成
+	provider := &fakeProvider{
+		generate: func(_ context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			if req.Prompt == "prompt 1" {
+				cancel()
+			}
+			return image.ImageResponse{}, nil
+		},
+	}
+
+	reqs := []image.ImageRequest{{Prompt: "prompt 0"}, {Prompt: "prompt 1"}}
+	results, err := image.GenerateBatch(ctx, provider, reqs, 1)
+	if err != nil {
+		t.Fatalf("GenerateBatch() error = %v, want nil when every request was dispatched", err)
+	}
+	for _, r := range results {
+		if r.Error != nil {
+			t.Errorf("result %d error = %v, want nil", r.Index, r.Error)
+		}
+	}
+}
+
+func TestGenerateBatch_PartialFailure(t *testing.T) {
+	provider := &fakeProvider{
+		generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			if req.Prompt == "prompt 1" {
+				return image.ImageResponse{}, image.ErrContentFiltered
+			}
+			return image.ImageResponse{Images: []image.GeneratedImage{{URL: "https://example.com/" + req.Prompt}}}, nil
+		},
+	}
+
+	reqs := []image.ImageRequest{{Prompt: "prompt 0"}, {Prompt: "prompt 1"}, {Prompt: "prompt 2"}}
+	results, err := image.GenerateBatch(context.Background(), provider, reqs, 2)
+	if err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	if !errors.Is(results[1].Error, image.ErrContentFiltered) {
+		t.Errorf("result 1 error = %v, want ErrContentFiltered", results[1].Error)
+	}
+	for _, i := range []int{0, 2} {
+		r := results[i]
+		if r.Error != nil || len(r.Response.Images) != 1 {
+			t.Errorf("result %d = %+v, want one image without error", i, r)
+			continue
+		}
+		if want := fmt.Sprintf("https://example.com/prompt %d", i); r.Response.Images[0].URL != want {
+			t.Errorf("result %d URL = %q, want %q", i, r.Response.Images[0].URL, want)
+		}
+	}
+}
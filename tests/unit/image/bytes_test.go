@@ -0,0 +1,153 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// urlOnlyProvider 只支持 URL 响应的测试提供商
+type urlOnlyProvider struct {
+	fakeProvider
+}
+
+func (p *urlOnlyProvider) Capabilities() image.ProviderCapabilities {
+	return image.ProviderCapabilities{MaxImages: 2, ResponseFormats: []image.ResponseFormat{image.FormatURL}}
+}
+
+func TestGenerateBytes_Base64(t *testing.T) {
+	want := [][]byte{pngFixture(t, 4, 4), pngFixture(t, 8, 2)}
+	var gotFormat image.ResponseFormat
+	provider := &fakeProvider{
+		generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			gotFormat = req.ResponseFormat
+			resp := image.ImageResponse{}
+			for _, data := range want {
+				resp.Images = append(resp.Images, image.GeneratedImage{Base64: base64.StdEncoding.EncodeToString(data)})
+			}
+			return resp, nil
+		},
+	}
+
+	images, err := image.GenerateBytes(context.Background(), provider, image.ImageRequest{Prompt: "cat", N: 2})
+	if err != nil {
+		t.Fatalf("GenerateBytes() error = %v", err)
+	}
+	if gotFormat != image.FormatBase64 {
+		t.Errorf("ResponseFormat = %q, want %q", gotFormat, image.FormatBase64)
+	}
+	if len(images) != len(want) {
+		t.Fatalf("expected %d images, got %d", len(want), len(images))
+	}
+	for i := range want {
+		if !bytes.Equal(images[i], want[i]) {
+			t.Errorf("image %d bytes differ from source", i)
+		}
+	}
+}
+
+func TestGenerateBytes_URL(t *testing.T) {
+	want := pngFixture(t, 5, 5)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(want)
+	}))
+	defer server.Close()
+
+	var gotFormat image.ResponseFormat
+	provider := &urlOnlyProvider{fakeProvider{
+		generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			gotFormat = req.ResponseFormat
+			return image.ImageResponse{Images: []image.GeneratedImage{{URL: server.URL + "/image.png"}}}, nil
+		},
+	}}
+
+	images, err := image.GenerateBytes(context.Background(), provider, image.ImageRequest{Prompt: "cat"})
+	if err != nil {
+		t.Fatalf("GenerateBytes() error = %v", err)
+	}
+	if gotFormat != "" {
+		t.Errorf("ResponseFormat = %q, want unchanged for URL-only provider", gotFormat)
+	}
+	if len(images) != 1 || !bytes.Equal(images[0], want) {
+		t.Errorf("expected downloaded image bytes, got %d images", len(images))
+	}
+}
+
+func TestGenerateBytes_Errors(t *testing.T) {
+	provider := &fakeProvider{
+		generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			return image.ImageResponse{}, image.ErrContentFiltered
+		},
+	}
+	if _, err := image.GenerateBytes(context.Background(), provider, image.ImageRequest{Prompt: "cat"}); !errors.Is(err, image.ErrContentFiltered) {
+		t.Errorf("expected ErrContentFiltered, got %v", err)
+	}
+
+	provider.generate = func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+		return image.ImageResponse{Images: []image.GeneratedImage{{Base64: "not base64!"}}}, nil
+	}
+	if _, err := image.GenerateBytes(context.Background(), provider, image.ImageRequest{Prompt: "cat"}); err == nil {
+		t.Error("expected decode error for invalid base64")
+	}
+}
+
+// newStalledImageServer 创建返回图像 URL、但图像 URL 在客户端断开前不响应的 OpenAI 兼容服务
+func newStalledImageServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/images/generations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data":    []map[string]interface{}{{"url": server.URL + "/stalled.png"}},
+		})
+	})
+	mux.HandleFunc("/stalled.png", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	return server
+}
+
+func TestGenerateBytes_ProviderTimeout(t *testing.T) {
+	server := newStalledImageServer(t)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithTimeout(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// 下载图像同样遵循提供商的超时配置，调用方 ctx 无截止时间也不会一直阻塞
+	start := time.Now()
+	req := image.ImageRequest{Prompt: "a cat", ResponseFormat: image.FormatURL}
+	if _, err := image.GenerateBytes(context.Background(), client, req); err == nil {
+		t.Error("GenerateBytes() expected timeout error for stalled image URL")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("download took %v, want provider timeout to apply", elapsed)
+	}
+
+	// 转码可指定下载使用的客户端
+	stalled := image.GeneratedImage{URL: server.URL + "/stalled.png"}
+	start = time.Now()
+	if _, err := stalled.Transcode("png", image.WithTranscodeHTTPClient(&http.Client{Timeout: 100 * time.Millisecond})); err == nil {
+		t.Error("Transcode() expected timeout error for stalled image URL")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("transcode download took %v, want client timeout to apply", elapsed)
+	}
+}
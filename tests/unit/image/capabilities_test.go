@@ -0,0 +1,79 @@
+package image
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestCapabilityMatrix_MatchesClients(t *testing.T) {
+	matrix := image.CapabilityMatrix()
+
+	for _, providerType := range image.SupportedProviders() {
+		want, ok := matrix[providerType]
+		if !ok {
+			t.Errorf("CapabilityMatrix() missing %s", providerType)
+			continue
+		}
+
+		provider, err := image.NewImageProvider(providerType,
+			image.WithAPIKey("test-key"), image.WithSecretKey("test-secret"), image.WithProject("demo"))
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", providerType, err)
+		}
+		reporter, ok := provider.(image.CapabilityReporter)
+		if !ok {
+			t.Errorf("%s does not implement CapabilityReporter", providerType)
+			continue
+		}
+		if got := reporter.Capabilities(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s Capabilities() = %+v, matrix has %+v", providerType, got, want)
+		}
+		if want.MaxImages < 1 || len(want.ResponseFormats) == 0 {
+			t.Errorf("%s reports incomplete capabilities: %+v", providerType, want)
+		}
+	}
+}
+
+func TestCapabilities_NegativePrompt(t *testing.T) {
+	matrix := image.CapabilityMatrix()
+
+	if matrix[image.ProviderOpenAI].NegativePrompt {
+		t.Error("OpenAI should not report NegativePrompt support")
+	}
+	if !matrix[image.ProviderStability].NegativePrompt {
+		t.Error("Stability should report NegativePrompt support")
+	}
+}
+
+func TestCapabilities_DependOnModel(t *testing.T) {
+	dalle3, _ := image.NewOpenAI(image.WithAPIKey("test-key"), image.WithModel(image.ModelDALLE3))
+	if caps := dalle3.Capabilities(); caps.MaxImages != 1 || !caps.SupportsStyle(image.StyleNatural) || !caps.SupportsQuality(image.QualityHD) {
+		t.Errorf("unexpected DALL-E 3 capabilities: %+v", caps)
+	}
+
+	dalle2, _ := image.NewOpenAI(image.WithAPIKey("test-key"), image.WithModel(image.ModelDALLE2))
+	if caps := dalle2.Capabilities(); caps.MaxImages != 10 || len(caps.Styles) != 0 || len(caps.Qualities) != 0 {
+		t.Errorf("unexpected DALL-E 2 capabilities: %+v", caps)
+	}
+
+	core, _ := image.NewStability(image.WithAPIKey("test-key"), image.WithModel(image.ModelStableImageCore))
+	if core.Capabilities().Edit {
+		t.Error("stable-image-core should not report Edit support")
+	}
+	sd3, _ := image.NewStability(image.WithAPIKey("test-key"), image.WithModel(image.ModelSD3))
+	if !sd3.Capabilities().Edit {
+		t.Error("SD3 should report Edit support")
+	}
+}
+
+func TestCapabilities_StylesFollowMapping(t *testing.T) {
+	caps := image.CapabilityMatrix()[image.ProviderDashScope]
+	if !caps.SupportsStyle(image.StyleAnime) || !caps.SupportsStyle(image.StyleInkWash) {
+		t.Errorf("expected DashScope to support mapped styles, got %v", caps.Styles)
+	}
+	if image.CapabilityMatrix()[image.ProviderGoogle].SupportsStyle(image.StyleAnime) {
+		t.Error("Google does not pass styles and should report none")
+	}
+}
@@ -0,0 +1,143 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+var (
+	pngHeader  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegHeader = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	webpHeader = []byte("RIFF\x24\x00\x00\x00WEBPVP8 ")
+	gifHeader  = []byte("GIF89a\x01\x00\x01\x00")
+)
+
+func TestExtensionForContentType(t *testing.T) {
+	tests := []struct {
+		ct   string
+		want string
+	}{
+		{"image/png", ".png"},
+		{"image/jpeg", ".jpg"},
+		{"IMAGE/JPEG; q=0.9", ".jpg"},
+		{"image/webp", ".webp"},
+		{"image/svg+xml", ".svg"},
+		{"application/octet-stream", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := image.ExtensionForContentType(tt.ct); got != tt.want {
+			t.Errorf("ExtensionForContentType(%q) = %q, want %q", tt.ct, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate_SniffsContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantCT  string
+		wantExt string
+	}{
+		{"jpeg", jpegHeader, "image/jpeg", ".jpg"},
+		{"png", pngHeader, "image/png", ".png"},
+		{"webp", webpHeader, "image/webp", ".webp"},
+		{"gif", gifHeader, "image/gif", ".gif"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"created": time.Now().Unix(),
+					"data":    []map[string]interface{}{{"b64_json": base64.StdEncoding.EncodeToString(tt.data)}},
+				})
+			}))
+			defer server.Close()
+
+			client, err := image.NewOpenAI(
+				image.WithAPIKey("test-api-key"),
+				image.WithBaseURL(server.URL),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			resp, err := client.Generate(context.Background(), image.ImageRequest{
+				Prompt:         "a cat",
+				ResponseFormat: image.FormatBase64,
+			})
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+
+			img := resp.Images[0]
+			if img.ContentType != tt.wantCT {
+				t.Errorf("ContentType = %q, want %q", img.ContentType, tt.wantCT)
+			}
+			if ext := image.ExtensionForContentType(img.ContentType); ext != tt.wantExt {
+				t.Errorf("extension = %q, want %q", ext, tt.wantExt)
+			}
+		})
+	}
+}
+
+func TestDetectContentType_SVG(t *testing.T) {
+	svg := []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+	if got := image.DetectContentType(svg); got != "image/svg+xml" {
+		t.Errorf("DetectContentType(svg) = %q, want image/svg+xml", got)
+	}
+	if got := image.DetectContentType([]byte("not an image")); got != "" {
+		t.Errorf("DetectContentType(text) = %q, want empty", got)
+	}
+}
+
+func TestDetectContentType_MagicBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"png", pngHeader, "image/png"},
+		{"jpeg", jpegHeader, "image/jpeg"},
+		{"webp", webpHeader, "image/webp"},
+		{"gif87a", []byte("GIF87a\x01\x00\x01\x00"), "image/gif"},
+		{"gif89a", gifHeader, "image/gif"},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := image.DetectContentType(tt.data); got != tt.want {
+			t.Errorf("DetectContentType(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeContentType(t *testing.T) {
+	tests := []struct {
+		ct   string
+		want string
+	}{
+		{"image/png", "image/png"},
+		{"IMAGE/JPG", "image/jpeg"},
+		{"image/pjpeg; q=0.9", "image/jpeg"},
+		{"application/octet-stream", ""},
+		{"binary/octet-stream", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := image.NormalizeContentType(tt.ct); got != tt.want {
+			t.Errorf("NormalizeContentType(%q) = %q, want %q", tt.ct, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestNormalizeResponseFormat_Base64ToURL(t *testing.T) {
+	var uploaded []byte
+	uploader := image.URLUploaderFunc(func(ctx context.Context, data []byte, contentType string) (string, error) {
+		uploaded = data
+		if contentType != "image/png" {
+			t.Errorf("unexpected content type: %s", contentType)
+		}
+		return "https://cdn.example.com/1.png", nil
+	})
+
+	resp := image.ImageResponse{Images: []image.GeneratedImage{
+		{Base64: base64.StdEncoding.EncodeToString([]byte("png-bytes")), ContentType: "image/png"},
+	}}
+
+	got, err := image.NormalizeResponseFormat(context.Background(), resp, image.FormatURL, uploader)
+	if err != nil {
+		t.Fatalf("NormalizeResponseFormat() error = %v", err)
+	}
+	if got.Images[0].URL != "https://cdn.example.com/1.png" {
+		t.Errorf("unexpected URL: %s", got.Images[0].URL)
+	}
+	if string(uploaded) != "png-bytes" {
+		t.Errorf("unexpected uploaded data: %q", uploaded)
+	}
+	if resp.Images[0].URL != "" {
+		t.Error("original response should not be modified")
+	}
+}
+
+func TestNormalizeResponseFormat_URLToBase64(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("jpeg-bytes"))
+	}))
+	defer server.Close()
+
+	resp := image.ImageResponse{Images: []image.GeneratedImage{{URL: server.URL + "/1.jpg"}}}
+
+	got, err := image.NormalizeResponseFormat(context.Background(), resp, image.FormatBase64, nil)
+	if err != nil {
+		t.Fatalf("NormalizeResponseFormat() error = %v", err)
+	}
+	if got.Images[0].Base64 != base64.StdEncoding.EncodeToString([]byte("jpeg-bytes")) {
+		t.Errorf("unexpected base64: %s", got.Images[0].Base64)
+	}
+	if got.Images[0].ContentType != "image/jpeg" {
+		t.Errorf("unexpected content type: %s", got.Images[0].ContentType)
+	}
+}
+
+func TestNormalizeResponseFormat_AlreadyMatching(t *testing.T) {
+	uploader := image.URLUploaderFunc(func(ctx context.Context, data []byte, contentType string) (string, error) {
+		t.Error("uploader should not be called")
+		return "", nil
+	})
+
+	resp := image.ImageResponse{Images: []image.GeneratedImage{{URL: "https://example.com/a.png"}}}
+	got, err := image.NormalizeResponseFormat(context.Background(), resp, image.FormatURL, uploader)
+	if err != nil {
+		t.Fatalf("NormalizeResponseFormat() error = %v", err)
+	}
+	if got.Images[0] != resp.Images[0] {
+		t.Errorf("expected image unchanged, got %+v", got.Images[0])
+	}
+}
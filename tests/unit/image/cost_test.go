@@ -0,0 +1,113 @@
+package image
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestOpenAIClient_EstimateCost_DALLE3(t *testing.T) {
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithModel(image.ModelDALLE3),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     image.ImageRequest
+		want    float64
+		wantErr error
+	}{
+		{
+			name: "standard square",
+			req:  image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 1024, Height: 1024}, Quality: image.QualityStandard},
+			want: 0.04,
+		},
+		{
+			name: "hd square",
+			req:  image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 1024, Height: 1024}, Quality: image.QualityHD},
+			want: 0.08,
+		},
+		{
+			name: "standard wide",
+			req:  image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 1792, Height: 1024}, Quality: image.QualityStandard},
+			want: 0.08,
+		},
+		{
+			name: "hd wide",
+			req:  image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 1024, Height: 1792}, Quality: image.QualityHD},
+			want: 0.12,
+		},
+		{
+			name: "default size and quality",
+			req:  image.ImageRequest{Prompt: "cat"},
+			want: 0.04,
+		},
+		{
+			name:    "unpriced size",
+			req:     image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 512, Height: 512}},
+			wantErr: image.ErrUnsupportedSize,
+		},
+		{
+			name:    "unpriced quality",
+			req:     image.ImageRequest{Prompt: "cat", Quality: image.QualityUltra},
+			wantErr: image.ErrUnsupportedSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, err := client.EstimateCost(tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EstimateCost() error = %v", err)
+			}
+			if math.Abs(cost.Amount-tt.want) > 1e-9 {
+				t.Errorf("expected amount %v, got %v", tt.want, cost.Amount)
+			}
+			if cost.Currency != image.CurrencyUSD {
+				t.Errorf("expected currency USD, got %s", cost.Currency)
+			}
+			if cost.Unit != image.CostUnitPerImage {
+				t.Errorf("expected unit %s, got %s", image.CostUnitPerImage, cost.Unit)
+			}
+		})
+	}
+}
+
+func TestEstimateTotalCost(t *testing.T) {
+	client, err := image.NewOpenAI(image.WithAPIKey("test-api-key"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	total, err := image.EstimateTotalCost(client, []image.ImageRequest{
+		{Prompt: "a", Quality: image.QualityStandard},
+		{Prompt: "b", Quality: image.QualityHD},
+		{Prompt: "c", Size: image.ImageSize{Width: 1792, Height: 1024}, Quality: image.QualityHD},
+	})
+	if err != nil {
+		t.Fatalf("EstimateTotalCost() error = %v", err)
+	}
+	if math.Abs(total.Amount-0.24) > 1e-9 {
+		t.Errorf("expected total 0.24, got %v", total.Amount)
+	}
+
+	_, err = image.EstimateTotalCost(client, []image.ImageRequest{
+		{Prompt: "a"},
+		{Prompt: "b", Size: image.ImageSize{Width: 100, Height: 100}},
+	})
+	if !errors.Is(err, image.ErrUnsupportedSize) {
+		t.Errorf("expected ErrUnsupportedSize, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// newDashScopeServer 模拟 DashScope 异步接口：提交返回 PENDING，查询依次返回 statuses
+func newDashScopeServer(t *testing.T, statuses []map[string]interface{}, polls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/image-synthesis"):
+			if r.Header.Get("X-DashScope-Async") != "enable" {
+				t.Errorf("expected async header on submission")
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"request_id": "req-submit",
+				"output":     map[string]interface{}{"task_id": "task-1", "task_status": "PENDING"},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/tasks/task-1":
+			n := int(atomic.AddInt32(polls, 1)) - 1
+			if n >= len(statuses) {
+				n = len(statuses) - 1
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"request_id": "req-poll",
+				"output":     statuses[n],
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newTestDashScope(t *testing.T, baseURL string) *image.DashScopeClient {
+	t.Helper()
+	client, err := image.NewDashScope(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(baseURL),
+		image.WithRetryDelay(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestDashScope_Generate_PollsUntilSucceeded(t *testing.T) {
+	var polls int32
+	server := newDashScopeServer(t, []map[string]interface{}{
+		{"task_id": "task-1", "task_status": "PENDING"},
+		{"task_id": "task-1", "task_status": "RUNNING"},
+		{"task_id": "task-1", "task_status": "SUCCEEDED", "results": []map[string]interface{}{
+			{"url": "https://example.com/a.png"},
+			{"url": "https://example.com/b.png"},
+		}},
+	}, &polls)
+	defer server.Close()
+
+	resp, err := newTestDashScope(t, server.URL).Generate(context.Background(), image.ImageRequest{Prompt: "a cat", N: 2})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+	if len(resp.Images) != 2 || resp.Images[0].URL != "https://example.com/a.png" || resp.Images[1].URL != "https://example.com/b.png" {
+		t.Errorf("unexpected images: %+v", resp.Images)
+	}
+}
+
+func TestDashScope_Generate_TaskFailed(t *testing.T) {
+	var polls int32
+	server := newDashScopeServer(t, []map[string]interface{}{
+		{"task_id": "task-1", "task_status": "PENDING"},
+		{"task_id": "task-1", "task_status": "FAILED", "code": "InternalError", "message": "model overloaded"},
+	}, &polls)
+	defer server.Close()
+
+	_, err := newTestDashScope(t, server.URL).Generate(context.Background(), image.ImageRequest{Prompt: "a cat"})
+	if !errors.Is(err, image.ErrGenerationFailed) {
+		t.Fatalf("expected ErrGenerationFailed, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "task-1") || !strings.Contains(err.Error(), "model overloaded") {
+		t.Errorf("expected task id and failure reason in error, got %v", err)
+	}
+}
+
+func TestDashScope_Generate_PollTimeout(t *testing.T) {
+	var polls int32
+	server := newDashScopeServer(t, []map[string]interface{}{
+		{"task_id": "task-1", "task_status": "RUNNING"},
+	}, &polls)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := newTestDashScope(t, server.URL).Generate(ctx, image.ImageRequest{Prompt: "a cat"})
+	if !errors.Is(err, image.ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+}
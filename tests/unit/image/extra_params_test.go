@@ -0,0 +1,155 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// stabilityFormServer 记录 multipart 表单字段的 Stability SD3 兼容服务
+func stabilityFormServer(t *testing.T, forms *[]map[string][]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		*forms = append(*forms, r.MultipartForm.Value)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"image":         base64.StdEncoding.EncodeToString(pngHeader),
+			"finish_reason": "SUCCESS",
+		})
+	}))
+}
+
+// captureSlog 将默认 slog 输出重定向到缓冲区，测试结束后恢复
+func captureSlog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestExtraParams_Strict(t *testing.T) {
+	var forms []map[string][]string
+	server := stabilityFormServer(t, &forms)
+	defer server.Close()
+
+	client, err := image.NewStability(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithModel(image.ModelSD3),
+		image.WithStrictExtra(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Generate(context.Background(), image.ImageRequest{
+		Prompt: "a lighthouse",
+		Extra:  map[string]interface{}{"cfg_scale": "high", "guidance": 3},
+	})
+	if !errors.Is(err, image.ErrInvalidExtra) {
+		t.Fatalf("expected ErrInvalidExtra, got %v", err)
+	}
+	var paramErr *image.ExtraParamError
+	if !errors.As(err, &paramErr) {
+		t.Fatalf("expected *ExtraParamError, got %T", err)
+	}
+	for _, want := range []string{`"cfg_scale": must be a number, got string`, `"guidance": unknown parameter`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should contain %q", err, want)
+		}
+	}
+	if len(forms) != 0 {
+		t.Errorf("invalid request was sent to the provider")
+	}
+
+	// 类型正确的已知参数正常传递
+	_, err = client.Generate(context.Background(), image.ImageRequest{
+		Prompt: "a lighthouse",
+		Extra:  map[string]interface{}{"cfg_scale": 7, "output_format": "webp"},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := forms[0]["cfg_scale"]; len(got) != 1 || got[0] != "7" {
+		t.Errorf("cfg_scale = %v, want 7", got)
+	}
+	if got := forms[0]["output_format"]; len(got) != 1 || got[0] != "webp" {
+		t.Errorf("output_format = %v, want webp", got)
+	}
+}
+
+func TestExtraParams_WarnByDefault(t *testing.T) {
+	logs := captureSlog(t)
+
+	var forms []map[string][]string
+	server := stabilityFormServer(t, &forms)
+	defer server.Close()
+
+	client, err := image.NewStability(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithModel(image.ModelSD35Large),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Generate(context.Background(), image.ImageRequest{
+		Prompt: "a lighthouse",
+		Extra:  map[string]interface{}{"cfg_scale": "high"},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(forms) != 1 {
+		t.Fatalf("expected request to pass through, got %d requests", len(forms))
+	}
+	if _, ok := forms[0]["cfg_scale"]; ok {
+		t.Error("wrong-typed cfg_scale should not be sent")
+	}
+	if out := logs.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, "cfg_scale") {
+		t.Errorf("expected a warning mentioning cfg_scale, got %q", out)
+	}
+}
+
+func TestValidateExtra(t *testing.T) {
+	client, err := image.NewOpenAI(image.WithAPIKey("test-api-key"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	req := image.ImageRequest{Prompt: "a cat", Extra: map[string]interface{}{"cfg_scale": 7}}
+	if err := image.ValidateRequest(client, req); !errors.Is(err, image.ErrInvalidExtra) {
+		t.Errorf("expected ErrInvalidExtra for OpenAI extra, got %v", err)
+	}
+
+	sd3, err := image.NewStability(image.WithAPIKey("test-api-key"), image.WithModel(image.ModelSD3))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	caps := sd3.Capabilities()
+	if len(caps.ExtraParams) == 0 {
+		t.Fatal("expected Stability to expose extra params")
+	}
+	if err := image.ValidateExtra("stability", caps.ExtraParams, map[string]interface{}{"output_format": "gif"}); err == nil ||
+		!strings.Contains(err.Error(), "must be one of png/jpeg/webp") {
+		t.Errorf("expected enum error for output_format, got %v", err)
+	}
+	if err := image.ValidateExtra("stability", caps.ExtraParams, nil); err != nil {
+		t.Errorf("unexpected error for empty extra: %v", err)
+	}
+}
@@ -11,16 +11,20 @@ func TestNewImageProvider(t *testing.T) {
 		providerType image.ProviderType
 		apiKey       string
 		secretKey    string
+		projectID    string
 		expectError  bool
 	}{
-		{image.ProviderOpenAI, "test-key", "", false},
-		{image.ProviderOpenAI, "", "", true}, // missing API key
-		{image.ProviderStability, "test-key", "", false},
-		{image.ProviderDashScope, "test-key", "", false},
-		{image.ProviderERNIE, "test-key", "test-secret", false},
-		{image.ProviderERNIE, "test-key", "", true}, // missing secret key
-		{image.ProviderHunyuan, "test-id", "test-key", false},
-		{image.ProviderHunyuan, "test-id", "", true}, // missing secret key
+		{image.ProviderOpenAI, "test-key", "", "", false},
+		{image.ProviderOpenAI, "", "", "", true}, // missing API key
+		{image.ProviderStability, "test-key", "", "", false},
+		{image.ProviderDashScope, "test-key", "", "", false},
+		{image.ProviderERNIE, "test-key", "test-secret", "", false},
+		{image.ProviderERNIE, "test-key", "", "", true}, // missing secret key
+		{image.ProviderHunyuan, "test-id", "test-key", "", false},
+		{image.ProviderHunyuan, "test-id", "", "", true}, // missing secret key
+		{image.ProviderGoogle, "test-token", "", "demo", false},
+		{image.ProviderGoogle, "test-token", "", "", true}, // missing project and base URL
+		{image.ProviderGoogle, "", "", "demo", true},       // missing access token
 	}
 
 	for _, test := range tests {
@@ -28,6 +32,9 @@ func TestNewImageProvider(t *testing.T) {
 		if test.secretKey != "" {
 			opts = append(opts, image.WithSecretKey(test.secretKey))
 		}
+		if test.projectID != "" {
+			opts = append(opts, image.WithProject(test.projectID))
+		}
 
 		provider, err := image.NewImageProvider(test.providerType, opts...)
 
@@ -65,6 +72,9 @@ func TestParseProviderType(t *testing.T) {
 		{"baidu", image.ProviderERNIE, false},
 		{"hunyuan", image.ProviderHunyuan, false},
 		{"tencent", image.ProviderHunyuan, false},
+		{"google", image.ProviderGoogle, false},
+		{"imagen", image.ProviderGoogle, false},
+		{"Vertex", image.ProviderGoogle, false},
 		{"unknown", "", true},
 		{"", "", true},
 	}
@@ -89,8 +99,8 @@ func TestParseProviderType(t *testing.T) {
 func TestSupportedProviders(t *testing.T) {
 	providers := image.SupportedProviders()
 
-	if len(providers) != 5 {
-		t.Errorf("expected 5 providers, got %d", len(providers))
+	if len(providers) != 6 {
+		t.Errorf("expected 6 providers, got %d", len(providers))
 	}
 
 	expectedProviders := map[image.ProviderType]bool{
@@ -99,6 +109,7 @@ func TestSupportedProviders(t *testing.T) {
 		image.ProviderDashScope: true,
 		image.ProviderERNIE:     true,
 		image.ProviderHunyuan:   true,
+		image.ProviderGoogle:    true,
 	}
 
 	for _, p := range providers {
@@ -0,0 +1,104 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// newFilteringServer 创建对包含 blocked 的提示词返回内容过滤错误的模拟服务器
+func newFilteringServer(t *testing.T, blocked string, prompts *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		prompt, _ := req["prompt"].(string)
+		*prompts = append(*prompts, prompt)
+
+		w.Header().Set("Content-Type", "application/json")
+		if prompt == blocked {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    "content_policy_violation",
+					"message": "rejected by safety system",
+				},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data":    []map[string]interface{}{{"url": "https://example.com/image.png"}},
+		})
+	}))
+}
+
+func TestGenerate_FilterRetryRewritesPrompt(t *testing.T) {
+	var prompts []string
+	server := newFilteringServer(t, "a knight with a sword", &prompts)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithFilterRetry(2, func(prompt string) string {
+			return "a knight in armor"
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Generate(context.Background(), image.ImageRequest{Prompt: "a knight with a sword"})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if len(resp.Images) != 1 {
+		t.Errorf("expected 1 image, got %d", len(resp.Images))
+	}
+
+	want := []string{"a knight with a sword", "a knight in armor"}
+	if len(prompts) != len(want) {
+		t.Fatalf("expected %d requests, got %d: %v", len(want), len(prompts), prompts)
+	}
+	for i := range want {
+		if prompts[i] != want[i] {
+			t.Errorf("request %d prompt = %q, want %q", i, prompts[i], want[i])
+		}
+	}
+}
+
+func TestGenerate_FilterRetryPersistent(t *testing.T) {
+	var prompts []string
+	server := newFilteringServer(t, "blocked", &prompts)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithFilterRetry(2, nil),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Generate(context.Background(), image.ImageRequest{Prompt: "blocked"})
+	if !errors.Is(err, image.ErrContentFiltered) {
+		t.Fatalf("expected ErrContentFiltered, got %v", err)
+	}
+	if len(prompts) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(prompts))
+	}
+	for _, p := range prompts {
+		if p != "blocked" {
+			t.Errorf("expected unchanged prompt, got %q", p)
+		}
+	}
+}
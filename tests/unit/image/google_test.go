@@ -0,0 +1,189 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestNewGoogle_MissingEndpoint(t *testing.T) {
+	if _, err := image.NewGoogle(image.WithAPIKey("test-token")); !errors.Is(err, image.ErrMissingEndpoint) {
+		t.Errorf("NewGoogle() without project or base URL error = %v, want ErrMissingEndpoint", err)
+	}
+
+	client, err := image.NewGoogle(image.WithAPIKey("test-token"), image.WithProject("demo"))
+	if err != nil {
+		t.Fatalf("NewGoogle() with project error = %v", err)
+	}
+	if client.Model() != image.ModelImagen3 {
+		t.Errorf("Model() = %q, want %q", client.Model(), image.ModelImagen3)
+	}
+}
+
+func TestGoogleClient_Generate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/publishers/google/models/"+image.ModelImagen3V1+":predict") {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("invalid authorization header")
+		}
+
+		var req struct {
+			Instances []struct {
+				Prompt string `json:"prompt"`
+			} `json:"instances"`
+			Parameters map[string]interface{} `json:"parameters"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		if len(req.Instances) != 1 || req.Instances[0].Prompt != "a lighthouse" {
+			t.Errorf("unexpected instances: %+v", req.Instances)
+		}
+		if req.Parameters["negativePrompt"] != "fog" {
+			t.Errorf("expected negativePrompt 'fog', got %v", req.Parameters["negativePrompt"])
+		}
+		if req.Parameters["seed"] != float64(42) {
+			t.Errorf("expected seed 42, got %v", req.Parameters["seed"])
+		}
+		if req.Parameters["aspectRatio"] != "16:9" {
+			t.Errorf("expected aspectRatio 16:9, got %v", req.Parameters["aspectRatio"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"predictions":[{"bytesBase64Encoded":"aW1n","mimeType":"image/png"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := image.NewGoogle(
+		image.WithAPIKey("test-token"),
+		image.WithBaseURL(server.URL+"/v1/projects/demo/locations/us-central1"),
+		image.WithModel(image.ModelImagen3V1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	seed := int64(42)
+	resp, err := client.Generate(context.Background(), image.ImageRequest{
+		Prompt:         "a lighthouse",
+		NegativePrompt: "fog",
+		Size:           image.ImageSize{Width: 1408, Height: 768},
+		Seed:           &seed,
+	})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	if len(resp.Images) != 1 || resp.Images[0].Base64 != "aW1n" {
+		t.Errorf("unexpected images: %+v", resp.Images)
+	}
+	if resp.Model != image.ModelImagen3V1 {
+		t.Errorf("expected model %s, got %s", image.ModelImagen3V1, resp.Model)
+	}
+}
+
+func TestGoogleClient_NegativePromptByModel(t *testing.T) {
+	tests := []struct {
+		model        string
+		emulate      bool
+		wantNative   bool
+		wantPrompt   string
+		wantNegative interface{}
+	}{
+		{model: image.ModelImagen3V1, wantNative: true, wantPrompt: "a lighthouse", wantNegative: "fog"},
+		{model: image.ModelImagen3Fast, wantNative: true, wantPrompt: "a lighthouse", wantNegative: "fog"},
+		{model: image.ModelImagen3, wantPrompt: "a lighthouse"},
+		{model: image.ModelImagen4, wantPrompt: "a lighthouse"},
+		{model: image.ModelImagen4, emulate: true, wantPrompt: "a lighthouse\n\nAvoid: fog"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/emulate=%v", tt.model, tt.emulate), func(t *testing.T) {
+			var req struct {
+				Instances []struct {
+					Prompt string `json:"prompt"`
+				} `json:"instances"`
+				Parameters map[string]interface{} `json:"parameters"`
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"predictions":[{"bytesBase64Encoded":"aW1n","mimeType":"image/png"}]}`))
+			}))
+			defer server.Close()
+
+			client, err := image.NewGoogle(
+				image.WithAPIKey("test-token"),
+				image.WithBaseURL(server.URL+"/v1/projects/demo/locations/us-central1"),
+				image.WithModel(tt.model),
+				image.WithNegativePromptEmulation(tt.emulate),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+			if got := client.Capabilities().NegativePrompt; got != tt.wantNative {
+				t.Errorf("Capabilities().NegativePrompt = %v, want %v", got, tt.wantNative)
+			}
+
+			if _, err := client.Generate(context.Background(), image.ImageRequest{Prompt: "a lighthouse", NegativePrompt: "fog"}); err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+			if len(req.Instances) != 1 || req.Instances[0].Prompt != tt.wantPrompt {
+				t.Errorf("prompt = %+v, want %q", req.Instances, tt.wantPrompt)
+			}
+			if got := req.Parameters["negativePrompt"]; got != tt.wantNegative {
+				t.Errorf("negativePrompt = %v, want %v", got, tt.wantNegative)
+			}
+		})
+	}
+}
+
+func TestGoogleClient_Generate_CandidateScores(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"predictions":[
+			{"bytesBase64Encoded":"YQ==","mimeType":"image/png","score":0.42},
+			{"bytesBase64Encoded":"Yg==","mimeType":"image/png"},
+			{"bytesBase64Encoded":"Yw==","mimeType":"image/png","score":0.91}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := image.NewGoogle(
+		image.WithAPIKey("test-token"),
+		image.WithBaseURL(server.URL+"/v1/projects/demo/locations/us-central1"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Generate(context.Background(), image.ImageRequest{Prompt: "a lighthouse", N: 3})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if len(resp.Images) != 3 {
+		t.Fatalf("expected 3 images, got %d", len(resp.Images))
+	}
+	if resp.Images[0].Score == nil || *resp.Images[0].Score != 0.42 {
+		t.Errorf("expected first image score 0.42, got %v", resp.Images[0].Score)
+	}
+	if resp.Images[1].Score != nil {
+		t.Errorf("expected nil score for image without score, got %v", *resp.Images[1].Score)
+	}
+
+	image.SortImagesByScore(resp.Images)
+	order := []string{resp.Images[0].Base64, resp.Images[1].Base64, resp.Images[2].Base64}
+	if order[0] != "Yw==" || order[1] != "YQ==" || order[2] != "Yg==" {
+		t.Errorf("unexpected order after sorting by score: %v", order)
+	}
+}
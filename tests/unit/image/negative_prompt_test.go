@@ -0,0 +1,103 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestEmulateNegativePrompt(t *testing.T) {
+	if got := image.EmulateNegativePrompt("a cat ", " dogs "); got != "a cat\n\nAvoid: dogs" {
+		t.Errorf("EmulateNegativePrompt() = %q", got)
+	}
+	if got := image.EmulateNegativePrompt("a cat", ""); got != "a cat" {
+		t.Errorf("EmulateNegativePrompt() with empty negative = %q, want unchanged", got)
+	}
+}
+
+func TestNegativePromptEmulation_OpenAI(t *testing.T) {
+	tests := []struct {
+		name       string
+		emulate    bool
+		wantPrompt string
+	}{
+		{"enabled", true, "a cat\n\nAvoid: dogs, text"},
+		{"disabled", false, "a cat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"created": time.Now().Unix(),
+					"data":    []map[string]interface{}{{"url": "https://example.com/image.png"}},
+				})
+			}))
+			defer server.Close()
+
+			client, err := image.NewOpenAI(
+				image.WithAPIKey("test-api-key"),
+				image.WithBaseURL(server.URL),
+				image.WithNegativePromptEmulation(tt.emulate),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			_, err = client.Generate(context.Background(), image.ImageRequest{Prompt: "a cat", NegativePrompt: "dogs, text"})
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+			if body["prompt"] != tt.wantPrompt {
+				t.Errorf("prompt = %q, want %q", body["prompt"], tt.wantPrompt)
+			}
+		})
+	}
+}
+
+func TestNegativePromptEmulation_StabilityUsesNativeField(t *testing.T) {
+	var form map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		form = r.MultipartForm.Value
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"image":         base64.StdEncoding.EncodeToString(pngHeader),
+			"finish_reason": "SUCCESS",
+		})
+	}))
+	defer server.Close()
+
+	client, err := image.NewStability(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithModel(image.ModelSD3),
+		image.WithNegativePromptEmulation(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Generate(context.Background(), image.ImageRequest{Prompt: "a cat", NegativePrompt: "dogs, text"})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if got := form["prompt"]; len(got) != 1 || got[0] != "a cat" {
+		t.Errorf("prompt = %v, want unchanged", got)
+	}
+	if got := form["negative_prompt"]; len(got) != 1 || got[0] != "dogs, text" {
+		t.Errorf("negative_prompt = %v, want native field", got)
+	}
+}
@@ -0,0 +1,43 @@
+package image
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestWithTransportTuning(t *testing.T) {
+	options := image.DefaultOptions()
+	image.ApplyOptions(options, image.WithTransportTuning(200, 50, 90*time.Second))
+
+	client := image.NewHTTPClient(options)
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.Transport)
+	}
+
+	if transport.MaxIdleConns != 200 {
+		t.Errorf("MaxIdleConns = %d, want 200", transport.MaxIdleConns)
+	}
+	if transport.MaxConnsPerHost != 50 {
+		t.Errorf("MaxConnsPerHost = %d, want 50", transport.MaxConnsPerHost)
+	}
+	if transport.MaxIdleConnsPerHost != 50 {
+		t.Errorf("MaxIdleConnsPerHost = %d, want 50", transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != 90*time.Second {
+		t.Errorf("IdleConnTimeout = %v, want 90s", transport.IdleConnTimeout)
+	}
+	if client.Timeout != options.Timeout {
+		t.Errorf("Timeout = %v, want %v", client.Timeout, options.Timeout)
+	}
+}
+
+func TestNewHTTPClient_DefaultTransport(t *testing.T) {
+	client := image.NewHTTPClient(image.DefaultOptions())
+	if client.Transport != nil {
+		t.Errorf("expected default transport, got %T", client.Transport)
+	}
+}
@@ -0,0 +1,93 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestPromptLength_Error(t *testing.T) {
+	var prompts []string
+	server := newPromptRecordingServer(t, &prompts)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithModel(image.ModelDALLE2),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Generate(context.Background(), image.ImageRequest{Prompt: strings.Repeat("猫", 1001)})
+	if !errors.Is(err, image.ErrInvalidPrompt) {
+		t.Fatalf("expected ErrInvalidPrompt, got %v", err)
+	}
+	var lengthErr *image.PromptLengthError
+	if !errors.As(err, &lengthErr) || lengthErr.Limit != 1000 || lengthErr.Length != 1001 {
+		t.Fatalf("expected PromptLengthError with limit 1000 and length 1001, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "1000") || !strings.Contains(err.Error(), "1001") {
+		t.Errorf("error message %q should name the limit and length", err)
+	}
+	if len(prompts) != 0 {
+		t.Errorf("over-long prompt was sent to the provider")
+	}
+
+	// 恰好达到上限的提示词正常发送
+	resp, err := client.Generate(context.Background(), image.ImageRequest{Prompt: strings.Repeat("猫", 1000)})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.PromptTruncated {
+		t.Error("PromptTruncated = true for a prompt within the limit")
+	}
+}
+
+func TestPromptLength_Truncation(t *testing.T) {
+	var prompts []string
+	server := newPromptRecordingServer(t, &prompts)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithModel(image.ModelDALLE2),
+		image.WithPromptTruncation(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Generate(context.Background(), image.ImageRequest{Prompt: strings.Repeat("猫", 1500)})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !resp.PromptTruncated {
+		t.Error("PromptTruncated = false, want true")
+	}
+	if len(prompts) != 1 || utf8.RuneCountInString(prompts[0]) != 1000 || !utf8.ValidString(prompts[0]) {
+		t.Errorf("sent prompt has %d characters, want 1000", utf8.RuneCountInString(prompts[0]))
+	}
+}
+
+func TestValidateRequest_PromptLength(t *testing.T) {
+	client, err := image.NewOpenAI(image.WithAPIKey("test-api-key"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	req := image.ImageRequest{Prompt: strings.Repeat("a", 4001)}
+	if err := image.ValidateRequest(client, req); !errors.Is(err, image.ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt for 4001 characters, got %v", err)
+	}
+	req.Prompt = strings.Repeat("a", 4000)
+	if err := image.ValidateRequest(client, req); err != nil {
+		t.Errorf("unexpected error for 4000 characters: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// newRateLimitServer 创建统计请求数的 OpenAI 模拟服务器
+func newRateLimitServer(requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data": []map[string]interface{}{
+				{"url": "https://example.com/image.png"},
+			},
+		})
+	}))
+}
+
+func TestRateLimit_ConcurrentGenerate(t *testing.T) {
+	var requests int32
+	server := newRateLimitServer(&requests)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithRateLimit(2, 1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	const n = 10
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = client.Generate(context.Background(), image.ImageRequest{Prompt: fmt.Sprintf("prompt %d", i)})
+		}(i)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("generation %d failed: %v", i, err)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != n {
+		t.Errorf("expected %d requests, got %d", n, got)
+	}
+
+	// 10 个请求在 2 rps、突发 1 的共享限流下至少需要 4.5s
+	if elapsed < 4*time.Second {
+		t.Errorf("expected concurrent generations to be throttled to >= 4s, took %v", elapsed)
+	}
+}
+
+func TestRateLimit_ContextCanceledWhileWaiting(t *testing.T) {
+	var requests int32
+	server := newRateLimitServer(&requests)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithRateLimit(0.1, 1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// 首个请求消耗突发令牌，第二个请求需等待约 10s，超出 ctx 截止时间
+	if _, err := client.Generate(context.Background(), image.ImageRequest{Prompt: "first"}); err != nil {
+		t.Fatalf("first generation failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.Generate(ctx, image.ImageRequest{Prompt: "second"})
+	if err == nil {
+		t.Fatal("expected error when context is canceled while waiting")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected wait to stop on cancellation, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected throttled request not to be sent, got %d requests", got)
+	}
+}
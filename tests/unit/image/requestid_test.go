@@ -0,0 +1,77 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestRequestID_Header(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(image.RequestIDHeader))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data":    []map[string]interface{}{{"url": "https://example.com/image.png"}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := image.NewOpenAI(image.WithAPIKey("test-api-key"), image.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := image.WithRequestID(context.Background(), "trace-42")
+	if _, err := client.Generate(ctx, image.ImageRequest{Prompt: "a cat"}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := client.Generate(context.Background(), image.ImageRequest{Prompt: "a cat"}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if len(got) != 2 || got[0] != "trace-42" || got[1] != "" {
+		t.Errorf("request ID headers = %q, want [trace-42 \"\"]", got)
+	}
+}
+
+func TestRequestID_APIErrorFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]interface{}{"message": "bad prompt", "type": "invalid_request_error"},
+		})
+	}))
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithMaxRetries(0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := image.WithRequestID(context.Background(), "trace-43")
+	_, err = client.Generate(ctx, image.ImageRequest{Prompt: "a cat"})
+	var apiErr *image.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.RequestID != "trace-43" {
+		t.Errorf("RequestID = %q, want trace-43", apiErr.RequestID)
+	}
+
+	if id, ok := image.RequestIDFromContext(context.Background()); ok || id != "" {
+		t.Errorf("RequestIDFromContext(empty) = (%q, %v), want none", id, ok)
+	}
+}
@@ -0,0 +1,112 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// newPromptRecordingServer 创建记录请求提示词的模拟服务器
+func newPromptRecordingServer(t *testing.T, prompts *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		prompt, _ := req["prompt"].(string)
+		*prompts = append(*prompts, prompt)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data":    []map[string]interface{}{{"url": "https://example.com/image.png"}},
+		})
+	}))
+}
+
+func TestSanitizePrompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{"clean", "a red fox", "a red fox"},
+		{"zero width space", "a red\u200b fox", "a red fox"},
+		{"null byte", "a red\x00 fox", "a red fox"},
+		{"keeps newlines", "line one\r\nline two\n", "line one\nline two"},
+		{"normalizes tabs", "\ta\tred fox ", "a red fox"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := image.SanitizePrompt(tt.prompt); got != tt.want {
+				t.Errorf("SanitizePrompt(%q) = %q, want %q", tt.prompt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_SanitizesPrompt(t *testing.T) {
+	var prompts []string
+	server := newPromptRecordingServer(t, &prompts)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Generate(context.Background(), image.ImageRequest{Prompt: " a cat\u200b on a\x00 mat "})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if len(prompts) != 1 || prompts[0] != "a cat on a mat" {
+		t.Errorf("unexpected prompts sent: %q", prompts)
+	}
+	if !resp.PromptSanitized {
+		t.Error("expected PromptSanitized to be true")
+	}
+
+	resp, err = client.Generate(context.Background(), image.ImageRequest{Prompt: "a cat on a mat"})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if resp.PromptSanitized {
+		t.Error("expected PromptSanitized to be false for a clean prompt")
+	}
+}
+
+func TestGenerate_SanitizationDisabled(t *testing.T) {
+	var prompts []string
+	server := newPromptRecordingServer(t, &prompts)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithPromptSanitization(false),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	prompt := "a cat\u200b on a mat"
+	resp, err := client.Generate(context.Background(), image.ImageRequest{Prompt: prompt})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if len(prompts) != 1 || prompts[0] != prompt {
+		t.Errorf("expected prompt to be sent unchanged, got %q", prompts)
+	}
+	if resp.PromptSanitized {
+		t.Error("expected PromptSanitized to be false when disabled")
+	}
+}
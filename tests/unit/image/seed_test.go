@@ -0,0 +1,232 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// newDashScopeSeedServer 创建返回同步结果的 DashScope 模拟服务器
+//
+// echo 为 true 时在结果中回传请求的种子。
+func newDashScopeSeedServer(t *testing.T, echo bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Parameters struct {
+				Seed *int64 `json:"seed"`
+			} `json:"parameters"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		result := map[string]interface{}{"url": "https://example.com/wanx.png"}
+		if echo && req.Parameters.Seed != nil {
+			result["seed"] = *req.Parameters.Seed
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"request_id": "req-1",
+			"output": map[string]interface{}{
+				"task_id":     "task-1",
+				"task_status": "SUCCEEDED",
+				"results":     []interface{}{result},
+			},
+		})
+	}))
+}
+
+func TestDashScopeClient_SeedEchoBack(t *testing.T) {
+	seed := int64(1234)
+
+	tests := []struct {
+		name     string
+		echo     bool
+		seed     *int64
+		wantSeed *int64
+	}{
+		{name: "echoed by provider", echo: true, seed: &seed, wantSeed: &seed},
+		{name: "copied from request", echo: false, seed: &seed, wantSeed: &seed},
+		{name: "no seed requested", echo: false, seed: nil, wantSeed: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newDashScopeSeedServer(t, tt.echo)
+			defer server.Close()
+
+			client, err := image.NewDashScope(
+				image.WithAPIKey("test-api-key"),
+				image.WithBaseURL(server.URL),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			resp, err := client.Generate(context.Background(), image.ImageRequest{Prompt: "a mountain lake", Seed: tt.seed})
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+			if len(resp.Images) != 1 {
+				t.Fatalf("expected 1 image, got %d", len(resp.Images))
+			}
+
+			got := resp.Images[0].Seed
+			switch {
+			case tt.wantSeed == nil && got != nil:
+				t.Errorf("expected nil seed, got %d", *got)
+			case tt.wantSeed != nil && (got == nil || *got != *tt.wantSeed):
+				t.Errorf("seed = %v, want %d", got, *tt.wantSeed)
+			}
+		})
+	}
+}
+
+func TestAssertReproducible_DashScope(t *testing.T) {
+	server := newDashScopeSeedServer(t, false)
+	defer server.Close()
+
+	client, err := image.NewDashScope(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if err := image.AssertReproducible(context.Background(), client, image.ImageRequest{Prompt: "a mountain lake"}); err != nil {
+		t.Errorf("expected reproducible, got %v", err)
+	}
+}
+
+func TestOpenAIClient_SeedNotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if _, ok := req["seed"]; ok {
+			t.Error("unexpected seed field in OpenAI request")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data": []map[string]interface{}{
+				{"url": "https://example.com/image.png"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithModel(image.ModelDALLE3),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	seed := int64(1234)
+	req := image.ImageRequest{Prompt: "a cute cat", Seed: &seed}
+	resp, err := client.Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if got := resp.Images[0].Seed; got != nil {
+		t.Errorf("expected nil seed for OpenAI, got %d", *got)
+	}
+
+	if err := image.AssertReproducible(context.Background(), client, req); !errors.Is(err, image.ErrNotReproducible) {
+		t.Errorf("expected ErrNotReproducible, got %v", err)
+	}
+}
+
+// seedCapableProvider 声明种子支持情况的测试提供商
+type seedCapableProvider struct {
+	fakeProvider
+	seed bool
+}
+
+func (p *seedCapableProvider) Capabilities() image.ProviderCapabilities {
+	return image.ProviderCapabilities{Seed: p.seed, MaxImages: 1}
+}
+
+// seedEchoProvider 返回一张带有请求种子的图像
+func seedEchoProvider(supportsSeed bool, calls *int32) *seedCapableProvider {
+	return &seedCapableProvider{
+		seed: supportsSeed,
+		fakeProvider: fakeProvider{
+			generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+				atomic.AddInt32(calls, 1)
+				if *req.Seed < 0 {
+					return image.ImageResponse{}, image.ErrGenerationFailed
+				}
+				return image.ImageResponse{Images: []image.GeneratedImage{{URL: "https://example.com/seed.png", Seed: req.Seed}}}, nil
+			},
+		},
+	}
+}
+
+func TestGenerateSeedSweep(t *testing.T) {
+	var calls int32
+	provider := seedEchoProvider(true, &calls)
+	seeds := []int64{7, 1, 42, 1000, 3}
+
+	responses, err := image.GenerateSeedSweep(context.Background(), provider, image.ImageRequest{Prompt: "cat"}, seeds, 3)
+	if err != nil {
+		t.Fatalf("GenerateSeedSweep() error = %v", err)
+	}
+	if len(responses) != len(seeds) || int(calls) != len(seeds) {
+		t.Fatalf("got %d responses from %d calls, want %d", len(responses), calls, len(seeds))
+	}
+	for i, resp := range responses {
+		if len(resp.Images) != 1 || resp.Images[0].Seed == nil || *resp.Images[0].Seed != seeds[i] {
+			t.Errorf("response %d = %+v, want seed %d", i, resp.Images, seeds[i])
+		}
+	}
+}
+
+func TestGenerateSeedSweep_PartialFailure(t *testing.T) {
+	var calls int32
+	responses, err := image.GenerateSeedSweep(context.Background(), seedEchoProvider(true, &calls),
+		image.ImageRequest{Prompt: "cat"}, []int64{1, -1, 2}, 2)
+	if !errors.Is(err, image.ErrGenerationFailed) {
+		t.Fatalf("GenerateSeedSweep() error = %v, want ErrGenerationFailed", err)
+	}
+	if len(responses) != 3 || len(responses[1].Images) != 0 || *responses[2].Images[0].Seed != 2 {
+		t.Errorf("responses not aligned to seeds: %+v", responses)
+	}
+}
+
+func TestGenerateSeedSweep_SeedNotSupported(t *testing.T) {
+	var calls int32
+	_, err := image.GenerateSeedSweep(context.Background(), seedEchoProvider(false, &calls),
+		image.ImageRequest{Prompt: "cat"}, []int64{1, 2}, 2)
+	if !errors.Is(err, image.ErrModelNotSupported) {
+		t.Fatalf("GenerateSeedSweep() error = %v, want ErrModelNotSupported", err)
+	}
+	if calls != 0 {
+		t.Errorf("provider called %d times, want 0", calls)
+	}
+}
+
+func TestGenerateSeedSweep_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int32
+	_, err := image.GenerateSeedSweep(ctx, seedEchoProvider(true, &calls), image.ImageRequest{Prompt: "cat"}, []int64{1, 2, 3}, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GenerateSeedSweep() error = %v, want context.Canceled", err)
+	}
+}
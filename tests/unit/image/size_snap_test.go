@@ -0,0 +1,120 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// sizeRecordingServer 记录请求体中 size 字段的 OpenAI 兼容服务
+func sizeRecordingServer(t *testing.T, sizes *[]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		size, _ := body["size"].(string)
+		*sizes = append(*sizes, size)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"created": time.Now().Unix(),
+			"data":    []map[string]interface{}{{"url": "https://example.com/image.png"}},
+		})
+	}))
+}
+
+func TestSizeSnapping_OpenAI(t *testing.T) {
+	var sizes []string
+	server := sizeRecordingServer(t, &sizes)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithSizeSnapping(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Generate(context.Background(), image.ImageRequest{
+		Prompt: "a cat",
+		Size:   image.ImageSize{Width: 1000, Height: 1000},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(sizes) != 1 || sizes[0] != "1024x1024" {
+		t.Errorf("sent sizes = %v, want [1024x1024]", sizes)
+	}
+	if resp.Extra[image.ExtraRequestedSize] != "1000x1000" || resp.Extra[image.ExtraSnappedSize] != "1024x1024" {
+		t.Errorf("Extra = %v, want requested 1000x1000 snapped to 1024x1024", resp.Extra)
+	}
+
+	// 已支持的尺寸不替换
+	resp, err = client.Generate(context.Background(), image.ImageRequest{
+		Prompt: "a cat",
+		Size:   image.ImageSize{Width: 1792, Height: 1024},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if sizes[1] != "1792x1024" || resp.Extra != nil {
+		t.Errorf("supported size changed: sent %s, Extra %v", sizes[1], resp.Extra)
+	}
+}
+
+func TestSizeSnapping_DisabledByDefault(t *testing.T) {
+	var sizes []string
+	// 未开启吸附时不记录替换（OpenAI 客户端自身的尺寸映射保持原样）
+	server := sizeRecordingServer(t, &sizes)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(image.WithAPIKey("test-api-key"), image.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Generate(context.Background(), image.ImageRequest{
+		Prompt: "a cat",
+		Size:   image.ImageSize{Width: 1000, Height: 1000},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Extra != nil {
+		t.Errorf("Extra = %v, want no substitution recorded without snapping", resp.Extra)
+	}
+}
+
+func TestNearestSize(t *testing.T) {
+	supported := []image.ImageSize{
+		{Width: 1024, Height: 1024},
+		{Width: 1792, Height: 1024},
+		{Width: 1024, Height: 1792},
+	}
+
+	tests := []struct {
+		size image.ImageSize
+		want image.ImageSize
+	}{
+		{image.ImageSize{Width: 1000, Height: 1000}, image.ImageSize{Width: 1024, Height: 1024}},
+		{image.ImageSize{Width: 512, Height: 512}, image.ImageSize{Width: 1024, Height: 1024}},
+		{image.ImageSize{Width: 1920, Height: 1080}, image.ImageSize{Width: 1792, Height: 1024}},
+		{image.ImageSize{Width: 1000, Height: 1750}, image.ImageSize{Width: 1024, Height: 1792}},
+	}
+	for _, tt := range tests {
+		got, ok := image.NearestSize(supported, tt.size)
+		if !ok || got != tt.want {
+			t.Errorf("NearestSize(%dx%d) = %v (%v), want %v", tt.size.Width, tt.size.Height, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := image.NearestSize(nil, image.ImageSize{Width: 1000, Height: 1000}); ok {
+		t.Error("expected no match without supported sizes")
+	}
+}
@@ -0,0 +1,285 @@
+package image
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestStabilityClient_SD3Payload(t *testing.T) {
+	tests := []struct {
+		name            string
+		req             image.ImageRequest
+		wantAspectRatio string
+	}{
+		{
+			name:            "aspect ratio",
+			req:             image.ImageRequest{Prompt: "a lighthouse", AspectRatio: "16:9"},
+			wantAspectRatio: "16:9",
+		},
+		{
+			name:            "derived from size",
+			req:             image.ImageRequest{Prompt: "a lighthouse", Size: image.ImageSize{Width: 832, Height: 1216}},
+			wantAspectRatio: "2:3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var form map[string][]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2beta/stable-image/generate/sd3" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("failed to parse form: %v", err)
+				}
+				form = r.MultipartForm.Value
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"image":         base64.StdEncoding.EncodeToString(pngHeader),
+					"finish_reason": "SUCCESS",
+					"seed":          7,
+				})
+			}))
+			defer server.Close()
+
+			client, err := image.NewStability(
+				image.WithAPIKey("test-api-key"),
+				image.WithBaseURL(server.URL),
+				image.WithModel(image.ModelSD3),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			resp, err := client.Generate(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+
+			if got := form["aspect_ratio"]; len(got) != 1 || got[0] != tt.wantAspectRatio {
+				t.Errorf("aspect_ratio = %v, want %s", got, tt.wantAspectRatio)
+			}
+			for _, field := range []string{"size", "width", "height", "model"} {
+				if _, ok := form[field]; ok {
+					t.Errorf("unexpected %s field in SD3 payload", field)
+				}
+			}
+			if got := form["mode"]; len(got) != 1 || got[0] != "text-to-image" {
+				t.Errorf("mode = %v, want text-to-image", got)
+			}
+			if got := form["output_format"]; len(got) != 1 || got[0] != "png" {
+				t.Errorf("output_format = %v, want png", got)
+			}
+
+			if len(resp.Images) != 1 || resp.Images[0].Base64 == "" {
+				t.Fatalf("expected base64 image, got %+v", resp.Images)
+			}
+			if seed := resp.Images[0].Seed; seed == nil || *seed != 7 {
+				t.Errorf("expected seed 7, got %v", seed)
+			}
+		})
+	}
+}
+
+func TestStabilityClient_SupportedAspectRatios(t *testing.T) {
+	client, err := image.NewStability(image.WithAPIKey("test-api-key"), image.WithModel(image.ModelSD3))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ratios := client.SupportedAspectRatios()
+	found := false
+	for _, ar := range ratios {
+		if ar == "21:9" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected 21:9 in supported aspect ratios, got %v", ratios)
+	}
+}
+
+func TestStabilityClient_ImageToImage(t *testing.T) {
+	initPath := filepath.Join(t.TempDir(), "init.png")
+	if err := os.WriteFile(initPath, pngHeader, 0644); err != nil {
+		t.Fatalf("failed to write init image: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		extra     map[string]interface{}
+		wantMode  string
+		wantImage bool
+	}{
+		{
+			name:      "init image bytes",
+			extra:     map[string]interface{}{"init_image": pngHeader, "image_strength": 0.6},
+			wantMode:  "image-to-image",
+			wantImage: true,
+		},
+		{
+			name:      "init image path",
+			extra:     map[string]interface{}{"init_image": initPath, "image_strength": 0.6},
+			wantMode:  "image-to-image",
+			wantImage: true,
+		},
+		{
+			name:     "text-to-image fallback",
+			extra:    nil,
+			wantMode: "text-to-image",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				form      map[string][]string
+				imageData []byte
+				filename  string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2beta/stable-image/generate/sd3" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("failed to parse form: %v", err)
+				}
+				form = r.MultipartForm.Value
+				if files := r.MultipartForm.File["image"]; len(files) == 1 {
+					filename = files[0].Filename
+					f, err := files[0].Open()
+					if err == nil {
+						imageData, _ = io.ReadAll(f)
+						_ = f.Close()
+					}
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"image":         base64.StdEncoding.EncodeToString(pngHeader),
+					"finish_reason": "SUCCESS",
+					"seed":          7,
+				})
+			}))
+			defer server.Close()
+
+			client, err := image.NewStability(
+				image.WithAPIKey("test-api-key"),
+				image.WithBaseURL(server.URL),
+				image.WithModel(image.ModelSD3),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			req := image.ImageRequest{Prompt: "a lighthouse at dusk", AspectRatio: "16:9", Extra: tt.extra}
+			if _, err := client.Generate(context.Background(), req); err != nil {
+				t.Fatalf("generate failed: %v", err)
+			}
+
+			if got := form["mode"]; len(got) != 1 || got[0] != tt.wantMode {
+				t.Errorf("mode = %v, want %s", got, tt.wantMode)
+			}
+
+			if !tt.wantImage {
+				if imageData != nil {
+					t.Error("unexpected image part in text-to-image payload")
+				}
+				if _, ok := form["strength"]; ok {
+					t.Error("unexpected strength field in text-to-image payload")
+				}
+				if got := form["aspect_ratio"]; len(got) != 1 || got[0] != "16:9" {
+					t.Errorf("aspect_ratio = %v, want 16:9", got)
+				}
+				return
+			}
+
+			if string(imageData) != string(pngHeader) {
+				t.Errorf("image part = %q, want init image bytes", imageData)
+			}
+			if filename != "init_image.png" {
+				t.Errorf("image filename = %q, want init_image.png", filename)
+			}
+			if got := form["strength"]; len(got) != 1 || got[0] != "0.6" {
+				t.Errorf("strength = %v, want 0.6", got)
+			}
+			if _, ok := form["aspect_ratio"]; ok {
+				t.Error("unexpected aspect_ratio field in image-to-image payload")
+			}
+		})
+	}
+}
+
+func TestStabilityClient_ImageToImageValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   string
+		extra   map[string]interface{}
+		wantErr error
+	}{
+		{
+			name:    "strength out of range",
+			model:   image.ModelSD35Large,
+			extra:   map[string]interface{}{"init_image": pngHeader, "image_strength": 1.5},
+			wantErr: image.ErrInvalidImageStrength,
+		},
+		{
+			name:    "missing strength",
+			model:   image.ModelSD35Large,
+			extra:   map[string]interface{}{"init_image": pngHeader},
+			wantErr: image.ErrInvalidImageStrength,
+		},
+		{
+			name:    "missing init image",
+			model:   image.ModelSD35Large,
+			extra:   map[string]interface{}{"image_strength": 0.5},
+			wantErr: image.ErrMissingInitImage,
+		},
+		{
+			name:    "core model",
+			model:   image.ModelStableImageCore,
+			extra:   map[string]interface{}{"init_image": pngHeader, "image_strength": 0.5},
+			wantErr: image.ErrModelNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			client, err := image.NewStability(
+				image.WithAPIKey("test-api-key"),
+				image.WithBaseURL(server.URL),
+				image.WithModel(tt.model),
+			)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			_, err = client.Generate(context.Background(), image.ImageRequest{Prompt: "a lighthouse", Extra: tt.extra})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if requests != 0 {
+				t.Errorf("expected no request, got %d", requests)
+			}
+		})
+	}
+}
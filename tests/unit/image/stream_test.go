@@ -0,0 +1,184 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// fakeProvider 用于测试的图像生成提供商
+type fakeProvider struct {
+	generate func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error)
+}
+
+func (p *fakeProvider) Generate(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+	return p.generate(ctx, req)
+}
+
+func (p *fakeProvider) GenerateStream(ctx context.Context, req image.ImageRequest) (<-chan image.GenerationEvent, error) {
+	return image.StreamFromGenerate(ctx, req, p.Generate), nil
+}
+
+func (p *fakeProvider) Name() string                      { return "fake" }
+func (p *fakeProvider) Model() string                     { return "fake-model" }
+func (p *fakeProvider) SupportedSizes() []image.ImageSize { return nil }
+func (p *fakeProvider) Close() error                      { return nil }
+
+func drainEvents(t *testing.T, events <-chan image.GenerationEvent) []image.GenerationEvent {
+	t.Helper()
+	var collected []image.GenerationEvent
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return collected
+			}
+			collected = append(collected, ev)
+		case <-timeout:
+			t.Fatal("event channel was not closed")
+		}
+	}
+}
+
+func TestGenerateStream_Success(t *testing.T) {
+	provider := &fakeProvider{
+		generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			return image.ImageResponse{
+				Images: []image.GeneratedImage{{URL: "https://example.com/a.png"}},
+			}, nil
+		},
+	}
+
+	events, err := provider.GenerateStream(context.Background(), image.ImageRequest{Prompt: "a cat"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	collected := drainEvents(t, events)
+	if len(collected) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(collected))
+	}
+	if collected[0].Stage != image.StageStarted {
+		t.Errorf("expected first stage %q, got %q", image.StageStarted, collected[0].Stage)
+	}
+
+	last := collected[len(collected)-1]
+	if !last.IsTerminal() || last.Stage != image.StageCompleted {
+		t.Fatalf("expected terminal completed event, got %+v", last)
+	}
+	if last.Response == nil || len(last.Response.Images) != 1 {
+		t.Errorf("expected final response with 1 image, got %+v", last.Response)
+	}
+	if last.Percent != 100 {
+		t.Errorf("expected percent 100, got %v", last.Percent)
+	}
+}
+
+func TestGenerateStream_Failure(t *testing.T) {
+	provider := &fakeProvider{
+		generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			return image.ImageResponse{}, image.ErrContentFiltered
+		},
+	}
+
+	events, _ := provider.GenerateStream(context.Background(), image.ImageRequest{Prompt: "a cat"})
+	collected := drainEvents(t, events)
+
+	last := collected[len(collected)-1]
+	if last.Stage != image.StageFailed {
+		t.Fatalf("expected failed terminal event, got %q", last.Stage)
+	}
+	if !errors.Is(last.Error, image.ErrContentFiltered) {
+		t.Errorf("expected ErrContentFiltered, got %v", last.Error)
+	}
+}
+
+func TestGenerateStream_ContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	provider := &fakeProvider{
+		generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+			// 模拟不响应 ctx 的慢速提供商
+			<-release
+			return image.ImageResponse{}, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, _ := provider.GenerateStream(ctx, image.ImageRequest{Prompt: "a cat"})
+
+	first := <-events
+	if first.Stage != image.StageStarted {
+		t.Fatalf("expected started event, got %q", first.Stage)
+	}
+
+	cancel()
+	collected := drainEvents(t, events)
+	if len(collected) != 1 {
+		t.Fatalf("expected 1 remaining event, got %d", len(collected))
+	}
+	if collected[0].Stage != image.StageFailed || !errors.Is(collected[0].Error, context.Canceled) {
+		t.Errorf("expected failed event with context.Canceled, got %+v", collected[0])
+	}
+}
+
+func TestOpenAIClient_GenerateStream_EmptyPrompt(t *testing.T) {
+	client, err := image.NewOpenAI(image.WithAPIKey("test-api-key"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.GenerateStream(context.Background(), image.ImageRequest{}); err != image.ErrInvalidPrompt {
+		t.Errorf("expected ErrInvalidPrompt, got %v", err)
+	}
+}
+
+func TestOpenAIClient_GenerateStream_NormalizesRequest(t *testing.T) {
+	var prompts []string
+	server := newPromptRecordingServer(t, &prompts)
+	defer server.Close()
+
+	newClient := func(opts ...image.Option) *image.OpenAIClient {
+		client, err := image.NewOpenAI(append([]image.Option{
+			image.WithAPIKey("test-api-key"),
+			image.WithBaseURL(server.URL),
+			image.WithModel(image.ModelDALLE2),
+		}, opts...)...)
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		return client
+	}
+	req := image.ImageRequest{Prompt: strings.Repeat("猫", 1500)}
+
+	// 超长提示词与 Generate 一样在发起流之前被拒绝
+	if _, err := newClient().GenerateStream(context.Background(), req); !errors.Is(err, image.ErrInvalidPrompt) {
+		t.Fatalf("GenerateStream() error = %v, want ErrInvalidPrompt", err)
+	}
+	if len(prompts) != 0 {
+		t.Fatalf("over-long prompt was sent to the provider")
+	}
+
+	// 开启截断时流式生成发送截断后的提示词并记录截断
+	events, err := newClient(image.WithPromptTruncation(true)).GenerateStream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+	var final image.GenerationEvent
+	for event := range events {
+		final = event
+	}
+	if final.Stage != image.StageCompleted || final.Response == nil || !final.Response.PromptTruncated {
+		t.Fatalf("expected completed event with truncated prompt, got %+v", final)
+	}
+	if len(prompts) != 1 || utf8.RuneCountInString(prompts[0]) != 1000 {
+		t.Errorf("sent prompts = %d, want one prompt of 1000 characters", len(prompts))
+	}
+}
@@ -0,0 +1,96 @@
+package image
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestPromptTemplate_Render(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []image.TemplateOption
+		vars    map[string]string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "all variables",
+			vars: map[string]string{"subject": "a red fox", "style": "watercolor"},
+			want: "a red fox, watercolor, high detail",
+		},
+		{
+			name: "missing variable renders empty",
+			vars: map[string]string{"subject": "a red fox"},
+			want: "a red fox, , high detail",
+		},
+		{
+			name: "strict with all variables",
+			opts: []image.TemplateOption{image.WithStrictVars()},
+			vars: map[string]string{"subject": "a red fox", "style": "watercolor"},
+			want: "a red fox, watercolor, high detail",
+		},
+		{
+			name:    "strict with missing variable",
+			opts:    []image.TemplateOption{image.WithStrictVars()},
+			vars:    map[string]string{"subject": "a red fox"},
+			wantErr: image.ErrTemplateVarMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := image.NewPromptTemplate("{{.subject}}, {{.style}}, high detail", tt.opts...)
+			if err != nil {
+				t.Fatalf("NewPromptTemplate() error = %v", err)
+			}
+
+			got, err := tmpl.Render(tt.vars)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptTemplate_ParseError(t *testing.T) {
+	if _, err := image.NewPromptTemplate("{{.subject"); err == nil {
+		t.Error("expected parse error for unterminated action")
+	}
+}
+
+func TestPromptTemplate_BuildRequest(t *testing.T) {
+	tmpl, err := image.NewPromptTemplate("{{.subject}} in {{.style}} style", image.WithStrictVars())
+	if err != nil {
+		t.Fatalf("NewPromptTemplate() error = %v", err)
+	}
+
+	base := image.ImageRequest{AspectRatio: "16:9", N: 2}
+	req, err := tmpl.BuildRequest(map[string]string{"subject": "a lighthouse", "style": "ink-wash"}, base)
+	if err != nil {
+		t.Fatalf("BuildRequest() error = %v", err)
+	}
+	if req.Prompt != "a lighthouse in ink-wash style" {
+		t.Errorf("Prompt = %q", req.Prompt)
+	}
+	if req.AspectRatio != "16:9" || req.N != 2 {
+		t.Errorf("expected base fields to be preserved, got %+v", req)
+	}
+	if err := req.Validate(); err != nil {
+		t.Errorf("built request should be valid: %v", err)
+	}
+
+	if _, err := tmpl.BuildRequest(map[string]string{"subject": "a lighthouse"}, base); !errors.Is(err, image.ErrTemplateVarMissing) {
+		t.Errorf("expected ErrTemplateVarMissing, got %v", err)
+	}
+}
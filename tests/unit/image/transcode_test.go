@@ -0,0 +1,156 @@
+package image
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	stdimage "image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/image/webp"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+// pngFixture 生成指定尺寸的 PNG 图像
+func pngFixture(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := stdimage.NewRGBA(stdimage.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 7), G: uint8(y * 11), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode fixture: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestGeneratedImage_Transcode_RoundTrip(t *testing.T) {
+	src := image.GeneratedImage{
+		Base64:      base64.StdEncoding.EncodeToString(pngFixture(t, 37, 23)),
+		ContentType: "image/png",
+	}
+
+	jpegData, err := src.Transcode("jpeg", image.WithTranscodeQuality(80))
+	if err != nil {
+		t.Fatalf("Transcode(jpeg) error = %v", err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(jpegData))
+	if err != nil {
+		t.Fatalf("output is not a jpeg: %v", err)
+	}
+	if cfg.Width != 37 || cfg.Height != 23 {
+		t.Errorf("jpeg size = %dx%d, want 37x23", cfg.Width, cfg.Height)
+	}
+
+	back := image.GeneratedImage{Base64: base64.StdEncoding.EncodeToString(jpegData)}
+	pngData, err := back.Transcode("png")
+	if err != nil {
+		t.Fatalf("Transcode(png) error = %v", err)
+	}
+	cfg, err = png.DecodeConfig(bytes.NewReader(pngData))
+	if err != nil {
+		t.Fatalf("output is not a png: %v", err)
+	}
+	if cfg.Width != 37 || cfg.Height != 23 {
+		t.Errorf("png size = %dx%d, want 37x23", cfg.Width, cfg.Height)
+	}
+}
+
+func TestGeneratedImage_Transcode_WebP(t *testing.T) {
+	fixture := pngFixture(t, 19, 11)
+	src := image.GeneratedImage{Base64: base64.StdEncoding.EncodeToString(fixture)}
+
+	webpData, err := src.Transcode("webp")
+	if err != nil {
+		t.Fatalf("Transcode(webp) error = %v", err)
+	}
+	if got := image.DetectContentType(webpData); got != "image/webp" {
+		t.Errorf("DetectContentType() = %q, want image/webp", got)
+	}
+
+	// 无损编码：解码后像素与原图一致
+	decoded, err := webp.Decode(bytes.NewReader(webpData))
+	if err != nil {
+		t.Fatalf("output is not a webp: %v", err)
+	}
+	original, _ := png.Decode(bytes.NewReader(fixture))
+	if decoded.Bounds() != original.Bounds() {
+		t.Fatalf("webp bounds = %v, want %v", decoded.Bounds(), original.Bounds())
+	}
+	for _, p := range []stdimage.Point{{0, 0}, {18, 10}, {7, 3}} {
+		wr, wg, wb, wa := original.At(p.X, p.Y).RGBA()
+		gr, gg, gb, ga := decoded.At(p.X, p.Y).RGBA()
+		if wr != gr || wg != gg || wb != gb || wa != ga {
+			t.Errorf("pixel %v = %v, want %v", p, decoded.At(p.X, p.Y), original.At(p.X, p.Y))
+		}
+	}
+
+	// WebP 输入可转码为其他格式
+	back := image.GeneratedImage{Base64: base64.StdEncoding.EncodeToString(webpData), ContentType: "image/webp"}
+	pngData, err := back.Transcode("png")
+	if err != nil {
+		t.Fatalf("Transcode(webp -> png) error = %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(pngData))
+	if err != nil {
+		t.Fatalf("output is not a png: %v", err)
+	}
+	if cfg.Width != 19 || cfg.Height != 11 {
+		t.Errorf("png size = %dx%d, want 19x11", cfg.Width, cfg.Height)
+	}
+}
+
+func TestGeneratedImage_Transcode_FromURL(t *testing.T) {
+	fixture := pngFixture(t, 8, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	data, err := image.GeneratedImage{URL: server.URL + "/a.png"}.Transcode("jpg")
+	if err != nil {
+		t.Fatalf("Transcode() error = %v", err)
+	}
+	if got := image.DetectContentType(data); got != "image/jpeg" {
+		t.Errorf("DetectContentType() = %q, want image/jpeg", got)
+	}
+}
+
+func TestGeneratedImage_Transcode_Errors(t *testing.T) {
+	fixture := image.GeneratedImage{Base64: base64.StdEncoding.EncodeToString(pngFixture(t, 4, 4))}
+
+	if _, err := fixture.Transcode("bmp"); !errors.Is(err, image.ErrUnsupportedTranscode) {
+		t.Errorf("Transcode(bmp) error = %v, want ErrUnsupportedTranscode", err)
+	}
+	if _, err := fixture.Transcode("jpeg", image.WithTranscodeQuality(0)); err == nil {
+		t.Error("expected error for quality 0")
+	}
+
+	// 两帧 GIF 动图
+	anim := &gif.GIF{Delay: []int{10, 10}}
+	for i := 0; i < 2; i++ {
+		frame := stdimage.NewPaletted(stdimage.Rect(0, 0, 4, 4), palette.Plan9)
+		frame.SetColorIndex(i, i, uint8(i+1))
+		anim.Image = append(anim.Image, frame)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatalf("failed to encode gif: %v", err)
+	}
+	animated := image.GeneratedImage{Base64: base64.StdEncoding.EncodeToString(buf.Bytes())}
+	if _, err := animated.Transcode("png"); !errors.Is(err, image.ErrAnimatedImage) {
+		t.Errorf("Transcode(animated gif) error = %v, want ErrAnimatedImage", err)
+	}
+}
@@ -0,0 +1,87 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestImageRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     image.ImageRequest
+		wantErr error
+	}{
+		{name: "valid minimal", req: image.ImageRequest{Prompt: "cat"}},
+		{name: "valid full", req: image.ImageRequest{Prompt: "cat", N: 10, Size: image.ImageSize{Width: 512, Height: 512}, AspectRatio: "16:9"}},
+		{name: "empty prompt", req: image.ImageRequest{}, wantErr: image.ErrInvalidPrompt},
+		{name: "blank prompt", req: image.ImageRequest{Prompt: "   "}, wantErr: image.ErrInvalidPrompt},
+		{name: "n too large", req: image.ImageRequest{Prompt: "cat", N: 11}, wantErr: image.ErrInvalidImageCount},
+		{name: "n negative", req: image.ImageRequest{Prompt: "cat", N: -1}, wantErr: image.ErrInvalidImageCount},
+		{name: "zero width", req: image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 0, Height: 512}}, wantErr: image.ErrInvalidSize},
+		{name: "negative height", req: image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 512, Height: -1}}, wantErr: image.ErrInvalidSize},
+		{name: "malformed aspect ratio", req: image.ImageRequest{Prompt: "cat", AspectRatio: "wide"}, wantErr: image.ErrInvalidAspectRatio},
+		{name: "zero aspect ratio", req: image.ImageRequest{Prompt: "cat", AspectRatio: "16:0"}, wantErr: image.ErrInvalidAspectRatio},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRequest_UnsupportedSize(t *testing.T) {
+	client, err := image.NewOpenAI(image.WithAPIKey("test-api-key"), image.WithModel(image.ModelDALLE3))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if err := image.ValidateRequest(client, image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 1024, Height: 1024}}); err != nil {
+		t.Errorf("expected supported size to pass, got %v", err)
+	}
+
+	err = image.ValidateRequest(client, image.ImageRequest{Prompt: "cat", Size: image.ImageSize{Width: 333, Height: 333}})
+	if !errors.Is(err, image.ErrUnsupportedSize) {
+		t.Errorf("expected ErrUnsupportedSize, got %v", err)
+	}
+
+	err = image.ValidateRequest(client, image.ImageRequest{})
+	if !errors.Is(err, image.ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt, got %v", err)
+	}
+}
+
+func TestGenerate_ValidatesBeforeNetwork(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client, err := image.NewOpenAI(image.WithAPIKey("test-api-key"), image.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Generate(context.Background(), image.ImageRequest{Prompt: "cat", N: 20})
+	if !errors.Is(err, image.ErrInvalidImageCount) {
+		t.Errorf("expected ErrInvalidImageCount, got %v", err)
+	}
+	if called {
+		t.Error("expected no network call for invalid request")
+	}
+}
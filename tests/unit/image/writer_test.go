@@ -0,0 +1,92 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/image"
+)
+
+func TestGenerateToWriter_Base64(t *testing.T) {
+	payload := bytes.Repeat([]byte{0x89, 'P', 'N', 'G', 0x00, 0xff}, 4096)
+	provider := &fakeProvider{generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+		return image.ImageResponse{
+			Model: "fake",
+			Images: []image.GeneratedImage{
+				{Base64: base64.StdEncoding.EncodeToString(payload), ContentType: "image/png"},
+				{Base64: "aWdub3JlZA=="},
+			},
+		}, nil
+	}}
+
+	var buf bytes.Buffer
+	resp, err := image.GenerateToWriter(context.Background(), image.ImageRequest{Prompt: "a cat"}, &buf, provider.Generate)
+	if err != nil {
+		t.Fatalf("GenerateToWriter() error = %v", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), payload) {
+		t.Errorf("written bytes differ from decoded image (got %d bytes, want %d)", buf.Len(), len(payload))
+	}
+	if len(resp.Images) != 1 {
+		t.Fatalf("expected metadata for 1 image, got %d", len(resp.Images))
+	}
+	if resp.Images[0].Base64 != "" || resp.Images[0].ContentType != "image/png" {
+		t.Errorf("expected metadata-only image, got %+v", resp.Images[0])
+	}
+}
+
+func TestGenerateToWriter_URL(t *testing.T) {
+	payload := []byte("jpeg-bytes")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	provider := &fakeProvider{generate: func(ctx context.Context, req image.ImageRequest) (image.ImageResponse, error) {
+		return image.ImageResponse{Images: []image.GeneratedImage{{URL: server.URL + "/image.jpg"}}}, nil
+	}}
+
+	var buf bytes.Buffer
+	resp, err := image.GenerateToWriter(context.Background(), image.ImageRequest{Prompt: "a cat"}, &buf, provider.Generate)
+	if err != nil {
+		t.Fatalf("GenerateToWriter() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), payload) {
+		t.Errorf("unexpected bytes: %q", buf.Bytes())
+	}
+	if resp.Images[0].URL != "" || resp.Images[0].ContentType != "image/jpeg" {
+		t.Errorf("expected metadata-only image with content type, got %+v", resp.Images[0])
+	}
+}
+
+func TestGenerateToWriter_ProviderTimeout(t *testing.T) {
+	server := newStalledImageServer(t)
+	defer server.Close()
+
+	client, err := image.NewOpenAI(
+		image.WithAPIKey("test-api-key"),
+		image.WithBaseURL(server.URL),
+		image.WithTimeout(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// 流式写出时下载图像同样遵循提供商的超时配置
+	start := time.Now()
+	req := image.ImageRequest{Prompt: "a cat", ResponseFormat: image.FormatURL}
+	if _, err := client.GenerateToWriter(context.Background(), req, io.Discard); err == nil {
+		t.Error("GenerateToWriter() expected timeout error for stalled image URL")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("download took %v, want provider timeout to apply", elapsed)
+	}
+}
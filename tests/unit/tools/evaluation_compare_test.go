@@ -0,0 +1,163 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	evaltools "github.com/ahhsitt/helloagents-go/pkg/tools/builtin/evaluation"
+)
+
+// quizDataset 两个类别各 4 题的测试数据集
+type quizDataset struct {
+	stubDataset
+}
+
+func (d *quizDataset) Len() int { return 8 }
+
+func (d *quizDataset) Get(index int) (evaluation.Sample, error) {
+	category := "math"
+	if index >= 4 {
+		category = "code"
+	}
+	return evaluation.Sample{
+		ID:       fmt.Sprintf("q%d", index),
+		Input:    fmt.Sprintf("q%d", index),
+		Expected: "right",
+		Category: category,
+	}, nil
+}
+
+// quizEvaluator 调用智能体并按答案是否等于期望判断成功的测试评估器
+type quizEvaluator struct {
+	dataset *quizDataset
+}
+
+func (e *quizEvaluator) Name() string { return "quiz" }
+
+func (e *quizEvaluator) EvaluateSample(ctx context.Context, agent agents.Agent, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+	output, err := agent.Run(ctx, agents.Input{Query: sample.Input})
+	if err != nil {
+		return nil, err
+	}
+	success := output.Response == sample.Expected
+	result := &evaluation.SampleResult{SampleID: sample.ID, Category: sample.Category, Success: success}
+	if success {
+		result.Score = 1
+	}
+	return result, nil
+}
+
+func (e *quizEvaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...evaluation.EvalOption) (*evaluation.EvalResult, error) {
+	config := evaluation.DefaultEvalConfig()
+	config.ApplyOptions(opts...)
+	results, err := evaluation.RunSamples(ctx, config, e.dataset, e.dataset.Len(),
+		func(ctx context.Context, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+			return e.EvaluateSample(ctx, agent, sample)
+		})
+	if err != nil {
+		return nil, err
+	}
+	result := &evaluation.EvalResult{BenchmarkName: e.Name(), AgentName: agent.Name(), TotalSamples: len(results), DetailedResults: results}
+	for _, r := range results {
+		if r.Success {
+			result.SuccessCount++
+		}
+	}
+	result.OverallAccuracy = float64(result.SuccessCount) / float64(result.TotalSamples)
+	return result, nil
+}
+
+// quizAgent 对 wrong 中的问题答错的测试智能体
+type quizAgent struct {
+	stubAgent
+	name  string
+	wrong map[string]bool
+}
+
+func (a *quizAgent) Name() string { return a.name }
+
+func (a *quizAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	if a.wrong[input.Query] {
+		return agents.Output{Response: "wrong"}, nil
+	}
+	return agents.Output{Response: "right"}, nil
+}
+
+func TestCompareAgentsTool_StrongerAgent(t *testing.T) {
+	registry := evaltools.NewBenchmarkRegistry()
+	registry.MustRegister("quiz", func(dataDir string) (evaluation.Dataset, evaluation.Evaluator) {
+		dataset := &quizDataset{}
+		return dataset, &quizEvaluator{dataset: dataset}
+	})
+
+	// v1 答错 4 题，v2 只答错 1 题（其中 q1 为 v1 答对而 v2 答错的回退）
+	v1 := &quizAgent{name: "v1", wrong: map[string]bool{"q0": true, "q2": true, "q4": true, "q5": true}}
+	v2 := &quizAgent{name: "v2", wrong: map[string]bool{"q1": true}}
+
+	tool := evaltools.NewCompareAgentsTool(registry, t.TempDir(), t.TempDir(), v1, v2)
+	output, err := tool.Execute(context.Background(), map[string]interface{}{"benchmark": "quiz"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var response struct {
+		StrongerAgent     string                  `json:"stronger_agent"`
+		StrongerAgentName string                  `json:"stronger_agent_name"`
+		Regressions       int                     `json:"regressions"`
+		Improvements      int                     `json:"improvements"`
+		Wins              int                     `json:"agent_b_wins"`
+		Ties              int                     `json:"ties"`
+		Losses            int                     `json:"agent_b_losses"`
+		Categories        []evaluation.HeadToHead `json:"categories"`
+		ReportPath        string                  `json:"report_path"`
+	}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		t.Fatalf("invalid output JSON: %v", err)
+	}
+
+	if response.StrongerAgent != "agent_b" || response.StrongerAgentName != "v2" {
+		t.Errorf("stronger agent = %s (%s), want agent_b (v2)", response.StrongerAgent, response.StrongerAgentName)
+	}
+	if response.Regressions != 1 || response.Improvements != 4 {
+		t.Errorf("regressions = %d, improvements = %d, want 1 and 4", response.Regressions, response.Improvements)
+	}
+	if response.Wins != 4 || response.Ties != 3 || response.Losses != 1 {
+		t.Errorf("wins/ties/losses = %d/%d/%d, want 4/3/1", response.Wins, response.Ties, response.Losses)
+	}
+
+	// 类别按名称排序：code（q4-q7）、math（q0-q3）
+	if len(response.Categories) != 2 {
+		t.Fatalf("expected 2 categories, got %+v", response.Categories)
+	}
+	code, math := response.Categories[0], response.Categories[1]
+	if code.Category != "code" || code.Wins != 2 || code.Ties != 2 || code.Losses != 0 {
+		t.Errorf("code = %+v, want 2 wins 2 ties", code)
+	}
+	if math.Category != "math" || math.Wins != 2 || math.Ties != 1 || math.Losses != 1 {
+		t.Errorf("math = %+v, want 2 wins 1 tie 1 loss", math)
+	}
+
+	report, err := os.ReadFile(response.ReportPath)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	if !strings.Contains(string(report), "| math | 2 | 1 | 1 |") {
+		t.Errorf("report missing category table:\n%s", report)
+	}
+
+	// 交换顺序后更强的一方为 agent_a
+	tool = evaltools.NewCompareAgentsTool(registry, t.TempDir(), t.TempDir(), v2, v1)
+	output, err = tool.Execute(context.Background(), map[string]interface{}{"benchmark": "quiz"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(output, `"stronger_agent": "agent_a"`) {
+		t.Errorf("expected agent_a to be stronger after swapping:\n%s", output)
+	}
+}
@@ -0,0 +1,107 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	evaltools "github.com/ahhsitt/helloagents-go/pkg/tools/builtin/evaluation"
+)
+
+// waitJob 轮询任务直到结束
+func waitJob(t *testing.T, status func(string) (evaltools.JobStatus, error), jobID string) evaltools.JobStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		st, err := status(jobID)
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if st.Finished() {
+			return st
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish in time", jobID)
+	return evaltools.JobStatus{}
+}
+
+func TestJobManager_PollToCompletion(t *testing.T) {
+	manager := evaltools.NewJobManager()
+	release := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobID := manager.Start(ctx, func(ctx context.Context, progress evaluation.ProgressCallback) (*evaluation.EvalResult, string, error) {
+		progress(1, 3)
+		<-release
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		progress(3, 3)
+		return &evaluation.EvalResult{TotalSamples: 3, SuccessCount: 2}, `{"status":"success"}`, nil
+	})
+	// 调用方上下文取消不应影响后台任务
+	cancel()
+
+	if _, err := manager.Result(jobID); !errors.Is(err, evaltools.ErrJobNotFinished) {
+		t.Errorf("expected ErrJobNotFinished before completion, got %v", err)
+	}
+	close(release)
+
+	st := waitJob(t, manager.Status, jobID)
+	if st.State != evaltools.JobCompleted {
+		t.Fatalf("expected state completed, got %s (error: %s)", st.State, st.Error)
+	}
+	if st.Done != 3 || st.Total != 3 {
+		t.Errorf("expected progress 3/3, got %d/%d", st.Done, st.Total)
+	}
+	if st.Output != `{"status":"success"}` {
+		t.Errorf("unexpected output: %s", st.Output)
+	}
+
+	result, err := manager.Result(jobID)
+	if err != nil {
+		t.Fatalf("Result() error = %v", err)
+	}
+	if result.SuccessCount != 2 {
+		t.Errorf("expected SuccessCount 2, got %d", result.SuccessCount)
+	}
+}
+
+func TestJobManager_Failure(t *testing.T) {
+	manager := evaltools.NewJobManager()
+	jobID := manager.Start(context.Background(), func(ctx context.Context, progress evaluation.ProgressCallback) (*evaluation.EvalResult, string, error) {
+		return nil, "", errors.New("boom")
+	})
+
+	st := waitJob(t, manager.Status, jobID)
+	if st.State != evaltools.JobFailed || st.Error != "boom" {
+		t.Errorf("expected failed state with error 'boom', got %s / %q", st.State, st.Error)
+	}
+	if _, err := manager.Result(jobID); err == nil {
+		t.Error("expected error for failed job")
+	}
+}
+
+func TestJobManager_UnknownJob(t *testing.T) {
+	manager := evaltools.NewJobManager()
+	if _, err := manager.Status("missing"); !errors.Is(err, evaltools.ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestGAIAEvaluationTool_ExecuteAsync(t *testing.T) {
+	// 数据目录不存在时任务应以失败结束
+	tool := evaltools.NewGAIAEvaluationTool(t.TempDir(), t.TempDir(), nil)
+	jobID, err := tool.ExecuteAsync(context.Background(), map[string]interface{}{"level": float64(1)})
+	if err != nil {
+		t.Fatalf("ExecuteAsync() error = %v", err)
+	}
+
+	st := waitJob(t, tool.Status, jobID)
+	if st.State != evaltools.JobFailed {
+		t.Errorf("expected failed state, got %s", st.State)
+	}
+}
@@ -0,0 +1,120 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	evaltools "github.com/ahhsitt/helloagents-go/pkg/tools/builtin/evaluation"
+)
+
+// stubDataset 固定样本的测试数据集
+type stubDataset struct {
+	dataDir string
+	loaded  bool
+}
+
+func (d *stubDataset) Load(ctx context.Context) error { d.loaded = true; return nil }
+func (d *stubDataset) Len() int                       { return 2 }
+func (d *stubDataset) Name() string                   { return "stub" }
+func (d *stubDataset) Get(index int) (evaluation.Sample, error) {
+	return evaluation.Sample{ID: "s" + string(rune('0'+index))}, nil
+}
+func (d *stubDataset) Iterator() <-chan evaluation.Sample {
+	ch := make(chan evaluation.Sample)
+	close(ch)
+	return ch
+}
+
+// stubEvaluator 第一个样本成功、第二个失败的测试评估器
+type stubEvaluator struct {
+	dataset *stubDataset
+}
+
+func (e *stubEvaluator) Name() string { return "stub" }
+
+func (e *stubEvaluator) EvaluateSample(ctx context.Context, agent agents.Agent, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+	return &evaluation.SampleResult{SampleID: sample.ID, Success: sample.ID == "s0"}, nil
+}
+
+func (e *stubEvaluator) Evaluate(ctx context.Context, agent agents.Agent, opts ...evaluation.EvalOption) (*evaluation.EvalResult, error) {
+	config := evaluation.DefaultEvalConfig()
+	config.ApplyOptions(opts...)
+	total := e.dataset.Len()
+	if config.MaxSamples > 0 && config.MaxSamples < total {
+		total = config.MaxSamples
+	}
+	results, err := evaluation.RunSamples(ctx, config, e.dataset, total,
+		func(ctx context.Context, sample evaluation.Sample) (*evaluation.SampleResult, error) {
+			return e.EvaluateSample(ctx, agent, sample)
+		})
+	if err != nil {
+		return nil, err
+	}
+	result := &evaluation.EvalResult{
+		BenchmarkName:   e.Name(),
+		TotalSamples:    len(results),
+		DetailedResults: results,
+	}
+	for _, r := range results {
+		if r.Success {
+			result.SuccessCount++
+		}
+	}
+	result.OverallAccuracy = float64(result.SuccessCount) / float64(result.TotalSamples)
+	return result, nil
+}
+
+func TestEvaluationTool_RegisteredBenchmark(t *testing.T) {
+	registry := evaltools.NewBenchmarkRegistry()
+	var created *stubDataset
+	err := registry.Register("stub", func(dataDir string) (evaluation.Dataset, evaluation.Evaluator) {
+		created = &stubDataset{dataDir: dataDir}
+		return created, &stubEvaluator{dataset: created}
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register("stub", nil); !errors.Is(err, evaltools.ErrInvalidBenchmark) {
+		t.Errorf("expected ErrInvalidBenchmark for nil factory, got %v", err)
+	}
+
+	dataRoot := t.TempDir()
+	tool := evaltools.NewEvaluationTool(registry, dataRoot, t.TempDir(), nil)
+
+	output, err := tool.Execute(context.Background(), map[string]interface{}{"benchmark": "stub"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if created == nil || !created.loaded {
+		t.Fatal("expected factory dataset to be created and loaded")
+	}
+	if want := filepath.Join(dataRoot, "stub"); created.dataDir != want {
+		t.Errorf("dataDir = %s, want %s", created.dataDir, want)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		t.Fatalf("invalid output JSON: %v", err)
+	}
+	if response["benchmark"] != "stub" || response["total_samples"] != 2.0 || response["success_count"] != 1.0 {
+		t.Errorf("unexpected response: %v", response)
+	}
+
+	if _, err := tool.Execute(context.Background(), map[string]interface{}{"benchmark": "missing"}); !errors.Is(err, evaltools.ErrBenchmarkNotFound) {
+		t.Errorf("expected ErrBenchmarkNotFound, got %v", err)
+	}
+}
+
+func TestDefaultBenchmarkRegistry(t *testing.T) {
+	names := evaltools.DefaultBenchmarkRegistry().List()
+	if strings.Join(names, ",") != "bfcl,gaia,mmlu" {
+		t.Errorf("List() = %v, want [bfcl gaia mmlu]", names)
+	}
+}
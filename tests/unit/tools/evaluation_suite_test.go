@@ -0,0 +1,141 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahhsitt/helloagents-go/pkg/agents"
+	"github.com/ahhsitt/helloagents-go/pkg/core/config"
+	"github.com/ahhsitt/helloagents-go/pkg/evaluation"
+	evaltools "github.com/ahhsitt/helloagents-go/pkg/tools/builtin/evaluation"
+)
+
+// stubAgent 不执行任何操作的测试智能体
+type stubAgent struct{}
+
+func (a *stubAgent) Name() string               { return "stub-agent" }
+func (a *stubAgent) Config() config.AgentConfig { return config.AgentConfig{} }
+
+func (a *stubAgent) Run(ctx context.Context, input agents.Input) (agents.Output, error) {
+	return agents.Output{}, nil
+}
+
+func (a *stubAgent) RunStream(ctx context.Context, input agents.Input) (<-chan agents.StreamChunk, <-chan error) {
+	ch := make(chan agents.StreamChunk)
+	errCh := make(chan error)
+	close(ch)
+	close(errCh)
+	return ch, errCh
+}
+
+// brokenDataset 加载失败的测试数据集
+type brokenDataset struct {
+	stubDataset
+}
+
+func (d *brokenDataset) Load(ctx context.Context) error { return errors.New("data missing") }
+
+// newSuiteRegistry 注册两个可用基准和一个加载失败的基准
+func newSuiteRegistry() *evaltools.BenchmarkRegistry {
+	registry := evaltools.NewBenchmarkRegistry()
+	stub := func(dataDir string) (evaluation.Dataset, evaluation.Evaluator) {
+		dataset := &stubDataset{dataDir: dataDir}
+		return dataset, &stubEvaluator{dataset: dataset}
+	}
+	registry.MustRegister("alpha", stub)
+	registry.MustRegister("beta", stub)
+	registry.MustRegister("broken", func(dataDir string) (evaluation.Dataset, evaluation.Evaluator) {
+		dataset := &brokenDataset{}
+		return dataset, &stubEvaluator{dataset: &dataset.stubDataset}
+	})
+	return registry
+}
+
+func TestSuiteEvaluationTool_Aggregates(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		outputDir := t.TempDir()
+		tool := evaltools.NewSuiteEvaluationTool(newSuiteRegistry(), t.TempDir(), outputDir, &stubAgent{})
+
+		output, err := tool.Execute(context.Background(), map[string]interface{}{
+			"benchmarks": []interface{}{
+				map[string]interface{}{"benchmark": "alpha"},
+				map[string]interface{}{"benchmark": "broken"},
+				map[string]interface{}{"benchmark": "beta", "max_samples": 1.0},
+			},
+			"concurrent": concurrent,
+		})
+		if err != nil {
+			t.Fatalf("Execute(concurrent=%v) error = %v", concurrent, err)
+		}
+
+		var response struct {
+			Status     string                   `json:"status"`
+			Agent      string                   `json:"agent"`
+			Succeeded  int                      `json:"succeeded"`
+			Failed     int                      `json:"failed"`
+			ReportPath string                   `json:"report_path"`
+			Benchmarks []map[string]interface{} `json:"benchmarks"`
+		}
+		if err := json.Unmarshal([]byte(output), &response); err != nil {
+			t.Fatalf("invalid output JSON: %v", err)
+		}
+
+		if response.Status != "partial" || response.Succeeded != 2 || response.Failed != 1 || response.Agent != "stub-agent" {
+			t.Errorf("unexpected aggregate: %+v", response)
+		}
+		if len(response.Benchmarks) != 3 {
+			t.Fatalf("expected 3 benchmark entries, got %d", len(response.Benchmarks))
+		}
+		alpha, broken, beta := response.Benchmarks[0], response.Benchmarks[1], response.Benchmarks[2]
+		if alpha["benchmark"] != "alpha" || alpha["status"] != "success" || alpha["total_samples"] != 2.0 {
+			t.Errorf("unexpected alpha entry: %v", alpha)
+		}
+		if beta["benchmark"] != "beta" || beta["status"] != "success" || beta["total_samples"] != 1.0 {
+			t.Errorf("unexpected beta entry: %v", beta)
+		}
+		if broken["status"] != "failed" || !strings.Contains(broken["error"].(string), "data missing") {
+			t.Errorf("unexpected broken entry: %v", broken)
+		}
+
+		report, err := os.ReadFile(response.ReportPath)
+		if err != nil {
+			t.Fatalf("failed to read suite report: %v", err)
+		}
+		alphaReport := filepath.Base(alpha["report_path"].(string))
+		if !strings.Contains(string(report), "]("+alphaReport+")") {
+			t.Errorf("suite report missing link to %s:\n%s", alphaReport, report)
+		}
+		if !strings.Contains(string(report), "**broken**") {
+			t.Errorf("suite report missing failed benchmark:\n%s", report)
+		}
+	}
+}
+
+func TestSuiteEvaluationTool_AllFailed(t *testing.T) {
+	tool := evaltools.NewSuiteEvaluationTool(newSuiteRegistry(), t.TempDir(), t.TempDir(), &stubAgent{})
+
+	_, err := tool.Execute(context.Background(), map[string]interface{}{
+		"benchmarks": []interface{}{
+			map[string]interface{}{"benchmark": "broken"},
+			map[string]interface{}{"benchmark": "missing"},
+		},
+	})
+	if !errors.Is(err, evaltools.ErrBenchmarkNotFound) {
+		t.Errorf("expected aggregated error wrapping ErrBenchmarkNotFound, got %v", err)
+	}
+
+	_, err = tool.Execute(context.Background(), map[string]interface{}{
+		"benchmarks": []interface{}{
+			map[string]interface{}{"benchmark": "alpha"},
+			map[string]interface{}{"benchmark": "alpha"},
+		},
+	})
+	if err == nil {
+		t.Error("expected error for duplicate benchmark")
+	}
+}